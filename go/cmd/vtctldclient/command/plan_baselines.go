@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ApplyPlanBaselines makes an ApplyPlanBaselines gRPC call to a vtctld.
+	ApplyPlanBaselines = &cobra.Command{
+		Use:   "ApplyPlanBaselines {--baselines BASELINES | --baselines-file BASELINES_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
+		Short: "Applies the provided query plan baselines, replacing the current ones.",
+		Long: `Applies the provided query plan baselines, replacing the current ones.
+
+A baseline pins the planning of a normalized query, as listed in the /debug/query_plans page of vtgate,
+with query directive comments that take precedence over the ones of the query, such as
+"/*vt+ PLANNER=Gen4 ALLOW_HASH_JOIN */", and an optional target, such as "commerce:-80".
+
+Example:
+  {"baselines": [{"query": "select * from t1 join t2 on t1.id = t2.t1_id where t1.id = :t1_id", "comments": "/*vt+ ALLOW_HASH_JOIN */"}]}`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyPlanBaselines,
+	}
+	// GetPlanBaselines makes a GetPlanBaselines gRPC call to a vtctld.
+	GetPlanBaselines = &cobra.Command{
+		Use:                   "GetPlanBaselines",
+		Short:                 "Displays the query plan baselines as a JSON document.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetPlanBaselines,
+	}
+)
+
+var applyPlanBaselinesOptions = struct {
+	Baselines         string
+	BaselinesFilePath string
+	Cells             []string
+	SkipRebuild       bool
+	DryRun            bool
+}{}
+
+func commandApplyPlanBaselines(cmd *cobra.Command, args []string) error {
+	if applyPlanBaselinesOptions.Baselines != "" && applyPlanBaselinesOptions.BaselinesFilePath != "" {
+		return fmt.Errorf("cannot pass both --baselines (=%s) and --baselines-file (=%s)", applyPlanBaselinesOptions.Baselines, applyPlanBaselinesOptions.BaselinesFilePath)
+	}
+
+	if applyPlanBaselinesOptions.Baselines == "" && applyPlanBaselinesOptions.BaselinesFilePath == "" {
+		return errors.New("must pass exactly one of --baselines or --baselines-file")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var baselinesBytes []byte
+	if applyPlanBaselinesOptions.BaselinesFilePath != "" {
+		data, err := os.ReadFile(applyPlanBaselinesOptions.BaselinesFilePath)
+		if err != nil {
+			return err
+		}
+
+		baselinesBytes = data
+	} else {
+		baselinesBytes = []byte(applyPlanBaselinesOptions.Baselines)
+	}
+
+	pb := &vschemapb.PlanBaselines{}
+	if err := json2.UnmarshalPB(baselinesBytes, pb); err != nil {
+		return err
+	}
+	// Round-trip so when we display the result it's readable.
+	data, err := cli.MarshalJSON(pb)
+	if err != nil {
+		return err
+	}
+
+	if applyPlanBaselinesOptions.DryRun {
+		fmt.Printf("[DRY RUN] Would have saved new PlanBaselines object:\n%s\n", data)
+
+		if applyPlanBaselinesOptions.SkipRebuild {
+			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
+		} else {
+			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			if len(applyPlanBaselinesOptions.Cells) == 0 {
+				fmt.Print(" in all cells\n")
+			} else {
+				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyPlanBaselinesOptions.Cells, ", "))
+			}
+		}
+
+		return nil
+	}
+
+	_, err = client.ApplyPlanBaselines(commandCtx, &vtctldatapb.ApplyPlanBaselinesRequest{
+		PlanBaselines: pb,
+		SkipRebuild:   applyPlanBaselinesOptions.SkipRebuild,
+		RebuildCells:  applyPlanBaselinesOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New PlanBaselines object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+
+	if applyPlanBaselinesOptions.SkipRebuild {
+		fmt.Println("Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
+	}
+
+	return nil
+}
+
+func commandGetPlanBaselines(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetPlanBaselines(commandCtx, &vtctldatapb.GetPlanBaselinesRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.PlanBaselines)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	ApplyPlanBaselines.Flags().StringVarP(&applyPlanBaselinesOptions.Baselines, "baselines", "b", "", "Query plan baselines, specified as a string")
+	ApplyPlanBaselines.Flags().StringVarP(&applyPlanBaselinesOptions.BaselinesFilePath, "baselines-file", "f", "", "Path to a file containing query plan baselines specified as JSON")
+	ApplyPlanBaselines.Flags().StringSliceVarP(&applyPlanBaselinesOptions.Cells, "cells", "c", nil, "Limit the VSchema graph rebuilding to the specified cells. Ignored if --skip-rebuild is specified.")
+	ApplyPlanBaselines.Flags().BoolVar(&applyPlanBaselinesOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvVSchema objects.")
+	ApplyPlanBaselines.Flags().BoolVarP(&applyPlanBaselinesOptions.DryRun, "dry-run", "d", false, "Validate the specified query plan baselines and note actions that would be taken, but do not actually apply them to the topo.")
+	Root.AddCommand(ApplyPlanBaselines)
+
+	Root.AddCommand(GetPlanBaselines)
+}
@@ -1434,6 +1434,64 @@ func (c *Conn) execQuery(query string, handler Handler, more bool) execResult {
 	return execSuccess
 }
 
+// RequestLocalInfile asks the client to send the content of fileName, as
+// part of a LOAD DATA LOCAL INFILE statement. It can only be called while
+// handling a ComQuery, before any result has been sent. The callback is
+// called for every packet of data the client sends, the slice is only valid
+// for the duration of the call. If the callback returns an error, the rest
+// of the file is still read so the connection stays usable, and the first
+// error is returned.
+func (c *Conn) RequestLocalInfile(fileName string, callback func([]byte) error) error {
+	if c.Capabilities&CapabilityClientLocalFiles == 0 {
+		return sqlerror.NewSQLError(sqlerror.ERNotAllowedCommand, sqlerror.SSClientError, "The used command is not allowed with this MySQL version")
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(1 + len(fileName))
+	pos = writeByte(data, pos, LocalInfilePacket)
+	_ = writeEOFString(data, pos, fileName)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+	if err := c.flushWriter(); err != nil {
+		return sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, "%v", err)
+	}
+
+	var cbErr error
+	for {
+		data, err := c.readEphemeralPacket()
+		if err != nil {
+			if c.currentEphemeralPolicy == ephemeralRead {
+				c.recycleReadPacket()
+			}
+			return sqlerror.NewSQLError(sqlerror.CRServerLost, sqlerror.SSUnknownSQLState, "%v", err)
+		}
+		// The client signals the end of the file with an empty packet.
+		if len(data) == 0 {
+			c.recycleReadPacket()
+			return cbErr
+		}
+		if cbErr == nil {
+			cbErr = callback(data)
+		}
+		c.recycleReadPacket()
+	}
+}
+
+// flushWriter flushes the buffered writer, if any. This is needed when the
+// server expects an answer from the client in the middle of a command.
+func (c *Conn) flushWriter() error {
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+
+	if c.bufferedWriter == nil {
+		return nil
+	}
+	if c.flushTimer != nil {
+		c.flushTimer.Stop()
+	}
+	return c.bufferedWriter.Flush()
+}
+
 //
 // Packet parsing methods, for generic packets.
 //
@@ -22,6 +22,7 @@ import (
 	crypto_rand "crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/rand/v2"
 	"net"
@@ -801,6 +802,57 @@ func TestIsEOFPacket(t *testing.T) {
 	}
 }
 
+func TestRequestLocalInfile(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// sendFile plays the client side of the protocol.
+	sendFile := func(chunks ...string) {
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		assert.Equal(t, append([]byte{LocalInfilePacket}, "data.csv"...), data)
+		for _, chunk := range chunks {
+			useWritePacket(t, cConn, []byte(chunk))
+		}
+		useWritePacket(t, cConn, nil)
+	}
+
+	err := sConn.RequestLocalInfile("data.csv", func([]byte) error { return nil })
+	require.ErrorContains(t, err, "The used command is not allowed with this MySQL version")
+
+	sConn.Capabilities |= CapabilityClientLocalFiles
+	sConn.startWriterBuffering()
+	defer sConn.endWriterBuffering()
+
+	go sendFile("1,a\n", "2,b\n")
+	var received []string
+	err = sConn.RequestLocalInfile("data.csv", func(data []byte) error {
+		received = append(received, string(data))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1,a\n", "2,b\n"}, received)
+
+	// An error in the callback does not stop the file from being read.
+	cConn.sequence = 0
+	sConn.sequence = 0
+	go sendFile("1,a\n", "2,b\n", "3,c\n")
+	calls := 0
+	err = sConn.RequestLocalInfile("data.csv", func([]byte) error {
+		calls++
+		return errors.New("bad row")
+	})
+	require.EqualError(t, err, "bad row")
+	assert.Equal(t, 1, calls)
+
+	// The connection is still in sync.
+	verifyPacketComms(t, cConn, sConn, []byte("after"))
+}
+
 func TestMultiStatementStopsOnError(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	sConn.Capabilities |= CapabilityClientMultiStatements
@@ -82,9 +82,9 @@ const (
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
 
-	// CLIENT_LOCAL_FILES 1 << 7
+	// CapabilityClientLocalFiles is CLIENT_LOCAL_FILES.
 	// Client can use LOCAL INFILE request of LOAD DATA|XML.
-	// We do not set it.
+	CapabilityClientLocalFiles = 1 << 7
 
 	// CLIENT_IGNORE_SPACE 1 << 8
 	// Parser can ignore spaces before '('.
@@ -255,6 +255,9 @@ const (
 	// ErrPacket is the header of the error packet.
 	ErrPacket = 0xff
 
+	// LocalInfilePacket is the header of the LOCAL INFILE request packet.
+	LocalInfilePacket = 0xfb
+
 	// NullValue is the encoded value of NULL.
 	NullValue = 0xfb
 )
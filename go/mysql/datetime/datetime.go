@@ -229,6 +229,20 @@ func (d Date) IsZero() bool {
 	return d.Year() == 0 && d.Month() == 0 && d.Day() == 0
 }
 
+// Valid reports whether d is a valid date. Zero dates, and dates with a zero month
+// or day, are only valid if they are explicitly allowed, as MySQL does depending on
+// the NO_ZERO_DATE and NO_ZERO_IN_DATE SQL modes.
+func (d Date) Valid(allowZero, allowZeroInDate bool) bool {
+	switch {
+	case d.IsZero():
+		return allowZero
+	case d.Month() == 0 || d.Day() == 0:
+		return allowZeroInDate
+	default:
+		return d.Day() <= daysIn(time.Month(d.Month()), d.Year())
+	}
+}
+
 func (d Date) Year() int {
 	return int(d.year)
 }
@@ -453,6 +467,36 @@ func (t Time) AddInterval(itv *Interval, stradd bool) (Time, uint8, bool) {
 	return dt.Time, itv.precision(stradd), ok
 }
 
+// AddTime returns t+t2, or t-t2 when sub is set, as computed by MySQL's ADDTIME and SUBTIME.
+// The result is clamped to the range of the TIME type.
+func (t Time) AddTime(t2 Time, sub bool) Time {
+	dur := t2.ToDuration()
+	if sub {
+		dur = -dur
+	}
+	return newTimeFromDuration(t.ToDuration() + dur)
+}
+
+func newTimeFromDuration(dur time.Duration) (t Time) {
+	var neg bool
+	if dur < 0 {
+		neg = true
+		dur = -dur
+	}
+	if dur/time.Hour > MaxHours {
+		t = Time{hour: MaxHours, minute: 59, second: 59}
+	} else {
+		t.nanosecond = uint32((dur % time.Second) / time.Nanosecond)
+		t.second = uint8((dur % time.Minute) / time.Second)
+		t.minute = uint8((dur % time.Hour) / time.Minute)
+		t.hour = uint16(dur / time.Hour)
+	}
+	if neg {
+		t.hour |= negMask
+	}
+	return t
+}
+
 func (t Time) toDuration() time.Duration {
 	dur := time.Duration(t.hour)*time.Hour + time.Duration(t.minute)*time.Minute + time.Duration(t.second)*time.Second + time.Duration(t.nanosecond)*time.Nanosecond
 	if t.Neg() {
@@ -568,6 +612,87 @@ func (dt DateTime) AddInterval(itv *Interval, prec uint8, stradd bool) (DateTime
 	return dt, max(prec, itv.precision(stradd)), ok
 }
 
+// AddTime returns dt+t, or dt-t when sub is set, as computed by MySQL's ADDTIME and SUBTIME.
+// It returns false if the result is not in the range of the DATETIME type.
+func (dt DateTime) AddTime(t Time, sub bool) (DateTime, bool) {
+	dur := t.ToDuration()
+	if sub {
+		dur = -dur
+	}
+	dur += dt.Time.ToDuration()
+
+	days := time.Duration(MysqlDayNumber(dt.Date.Year(), dt.Date.Month(), dt.Date.Day()))
+	days += dur / durationPerDay
+	dur %= durationPerDay
+	if dur < 0 {
+		dur += durationPerDay
+		days--
+	}
+
+	var r DateTime
+	r.Date.year, r.Date.month, r.Date.day = mysqlDateFromDayNumber(int(days))
+	if r.Date.IsZero() || r.Date.Year() > 9999 {
+		return DateTime{}, false
+	}
+	r.Time = newTimeFromDuration(dur)
+	return r, true
+}
+
+// TimestampDiff returns dt2-dt1 expressed as an integer number of the given unit,
+// truncated towards zero, as computed by MySQL's TIMESTAMPDIFF. Months, quarters
+// and years are only counted when they are complete: there is one month between
+// 2024-01-15 10:00 and 2024-02-15 10:00, but none between 2024-01-31 and 2024-02-29.
+func TimestampDiff(dt1, dt2 DateTime, unit IntervalType) int64 {
+	day1 := MysqlDayNumber(dt1.Date.Year(), dt1.Date.Month(), dt1.Date.Day())
+	day2 := MysqlDayNumber(dt2.Date.Year(), dt2.Date.Month(), dt2.Date.Day())
+
+	usec := int64(day2-day1)*24*3600*1e6 + dt2.Time.ToDuration().Microseconds() - dt1.Time.ToDuration().Microseconds()
+	sign := int64(1)
+	if usec < 0 {
+		sign = -1
+		usec = -usec
+		dt1, dt2 = dt2, dt1
+	}
+	seconds := usec / 1e6
+
+	var months int64
+	if unit.HasMonthParts() || unit == IntervalYear {
+		y1, m1, d1 := dt1.Date.Year(), dt1.Date.Month(), dt1.Date.Day()
+		y2, m2, d2 := dt2.Date.Year(), dt2.Date.Month(), dt2.Date.Day()
+
+		months = int64(y2-y1)*12 + int64(m2-m1)
+		switch {
+		case d2 < d1:
+			months--
+		case d2 == d1 && dt2.Time.Compare(dt1.Time) < 0:
+			months--
+		}
+	}
+
+	switch unit {
+	case IntervalYear:
+		return sign * (months / 12)
+	case IntervalQuarter:
+		return sign * (months / 3)
+	case IntervalMonth:
+		return sign * months
+	case IntervalWeek:
+		return sign * (seconds / (24 * 3600 * 7))
+	case IntervalDay:
+		return sign * (seconds / (24 * 3600))
+	case IntervalHour:
+		return sign * (seconds / 3600)
+	case IntervalMinute:
+		return sign * (seconds / 60)
+	case IntervalSecond:
+		return sign * seconds
+	case IntervalMicrosecond:
+		return sign * usec
+	default:
+		panic("unexpected IntervalType for TimestampDiff")
+	}
+}
+
 func (dt DateTime) Round(p int) (r DateTime) {
 	if dt.Time.nanosecond == 0 {
 		return dt
@@ -941,3 +941,115 @@ func TestHash(t *testing.T) {
 	}
 	assert.Equal(t, want, h.Sum128())
 }
+
+func TestDateValid(t *testing.T) {
+	testCases := []struct {
+		date            Date
+		allowZero       bool
+		allowZeroInDate bool
+		want            bool
+	}{
+		{Date{2024, 2, 29}, false, false, true},
+		{Date{2023, 2, 29}, true, true, false},
+		{Date{2023, 4, 31}, true, true, false},
+		{Date{0, 0, 0}, false, true, false},
+		{Date{0, 0, 0}, true, false, true},
+		{Date{2023, 0, 1}, true, false, false},
+		{Date{2023, 1, 0}, false, true, true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, tc.date.Valid(tc.allowZero, tc.allowZeroInDate), "%v", tc.date)
+	}
+}
+
+func TestAddTime(t *testing.T) {
+	parseTime := func(s string) Time {
+		tt, _, state := ParseTime(s, -1)
+		assert.Equal(t, TimeOK, state, s)
+		return tt
+	}
+
+	timeCases := []struct {
+		t1, t2 string
+		sub    bool
+		want   string
+	}{
+		{"10:00:00", "01:30:00.5", false, "11:30:00.500000"},
+		{"10:00:00", "11:00:00", true, "-01:00:00.000000"},
+		{"-10:00:00", "01:00:00", false, "-09:00:00.000000"},
+		{"-10:00:00", "-01:00:00", true, "-09:00:00.000000"},
+		{"-01:00:00", "01:00:00", false, "00:00:00.000000"},
+		{"800:00:00", "100:00:00", false, "838:59:59.000000"},
+		{"-800:00:00", "100:00:00", true, "-838:59:59.000000"},
+	}
+	for _, tc := range timeCases {
+		got := parseTime(tc.t1).AddTime(parseTime(tc.t2), tc.sub)
+		assert.Equal(t, tc.want, string(got.Format(6)), "%s, %s", tc.t1, tc.t2)
+	}
+
+	dateTimeCases := []struct {
+		dt, t string
+		sub   bool
+		want  string
+	}{
+		{"2024-02-28 23:00:00", "02:00:00", false, "2024-02-29 01:00:00.000000"},
+		{"2024-03-01 01:00:00", "02:00:00", true, "2024-02-29 23:00:00.000000"},
+		{"2024-03-01 01:00:00", "-50:00:00.25", false, "2024-02-27 22:59:59.750000"},
+		{"9999-12-31 23:00:00", "01:00:00", false, ""},
+		{"0001-01-01 01:00:00", "02:00:00", true, ""},
+	}
+	for _, tc := range dateTimeCases {
+		dt, _, ok := ParseDateTime(tc.dt, -1)
+		assert.True(t, ok)
+		got, ok := dt.AddTime(parseTime(tc.t), tc.sub)
+		if tc.want == "" {
+			assert.False(t, ok, "%s, %s", tc.dt, tc.t)
+			continue
+		}
+		assert.True(t, ok)
+		assert.Equal(t, tc.want, string(got.Format(6)), "%s, %s", tc.dt, tc.t)
+	}
+}
+
+func TestTimestampDiff(t *testing.T) {
+	testCases := []struct {
+		dt1, dt2 string
+		unit     IntervalType
+		want     int64
+	}{
+		{"2003-02-01", "2003-05-01", IntervalMonth, 3},
+		{"2002-05-01", "2001-01-01", IntervalYear, -1},
+		{"2003-02-01", "2003-05-01 12:05:55", IntervalMinute, 128885},
+		{"2024-01-31", "2024-02-29", IntervalMonth, 0},
+		{"2024-01-15 10:00:00", "2024-02-15 10:00:00", IntervalMonth, 1},
+		{"2024-01-15 10:00:00", "2024-02-15 09:59:59.999999", IntervalMonth, 0},
+		{"2024-02-15 10:00:00", "2024-01-15 10:00:00.000001", IntervalMonth, 0},
+		{"2020-01-01", "2024-12-31", IntervalQuarter, 19},
+		{"2024-01-01", "2024-01-15", IntervalWeek, 2},
+		{"2024-01-15", "2024-01-01", IntervalDay, -14},
+		{"2024-01-01 00:00:00", "2024-01-01 10:59:59", IntervalHour, 10},
+		{"2024-01-01 00:00:00", "2024-01-01 00:00:01.5", IntervalSecond, 1},
+		{"2024-01-01 00:00:00", "2024-01-01 00:00:01.5", IntervalMicrosecond, 1500000},
+	}
+
+	for _, tc := range testCases {
+		dt1, _, ok := ParseDateTime(tc.dt1, -1)
+		if !ok {
+			var d Date
+			d, ok = ParseDate(tc.dt1)
+			dt1 = DateTime{Date: d}
+		}
+		assert.True(t, ok, tc.dt1)
+
+		dt2, _, ok := ParseDateTime(tc.dt2, -1)
+		if !ok {
+			var d Date
+			d, ok = ParseDate(tc.dt2)
+			dt2 = DateTime{Date: d}
+		}
+		assert.True(t, ok, tc.dt2)
+
+		assert.Equal(t, tc.want, TimestampDiff(dt1, dt2, tc.unit), "%s, %s, %s", tc.dt1, tc.dt2, tc.unit.ToString())
+	}
+}
@@ -55,3 +55,18 @@ func TestFormattingFromMySQL(t *testing.T) {
 		})
 	}
 }
+
+func TestKnownFormat(t *testing.T) {
+	date, datetime, time, ok := KnownFormat("usa")
+	require.True(t, ok)
+	require.Equal(t, "%m.%d.%Y", date)
+	require.Equal(t, "%Y-%m-%d %H.%i.%s", datetime)
+	require.Equal(t, "%h:%i:%s %p", time)
+
+	date, _, _, ok = KnownFormat("Internal")
+	require.True(t, ok)
+	require.Equal(t, "%Y%m%d", date)
+
+	_, _, _, ok = KnownFormat("US")
+	require.False(t, ok)
+}
@@ -195,3 +195,25 @@ var Time_hhmmss = &Strftime{
 		fmtSecond{true, true},
 	},
 }
+
+// knownFormats are the date, datetime and time formats returned by GET_FORMAT.
+var knownFormats = [...]struct {
+	name, date, datetime, time string
+}{
+	{"USA", "%m.%d.%Y", "%Y-%m-%d %H.%i.%s", "%h:%i:%s %p"},
+	{"JIS", "%Y-%m-%d", "%Y-%m-%d %H:%i:%s", "%H:%i:%s"},
+	{"ISO", "%Y-%m-%d", "%Y-%m-%d %H:%i:%s", "%H:%i:%s"},
+	{"EUR", "%d.%m.%Y", "%Y-%m-%d %H.%i.%s", "%H.%i.%s"},
+	{"INTERNAL", "%Y%m%d", "%Y%m%d%H%i%s", "%H%i%s"},
+}
+
+// KnownFormat returns the date, datetime and time formats of a standard, as returned by
+// GET_FORMAT. The name of the standard (USA, JIS, ISO, EUR or INTERNAL) is case insensitive.
+func KnownFormat(name string) (date, datetime, time string, ok bool) {
+	for _, f := range knownFormats {
+		if len(name) == len(f.name) && match(name, f.name) {
+			return f.date, f.datetime, f.time, true
+		}
+	}
+	return "", "", "", false
+}
@@ -89,3 +89,34 @@ func DateFromDayNumber(daynr int) Date {
 	d.year, d.month, d.day = mysqlDateFromDayNumber(daynr)
 	return d
 }
+
+// ValidPeriod reports whether period is a valid period for PERIOD_ADD and PERIOD_DIFF,
+// i.e. a positive number in YYMM or YYYYMM format.
+func ValidPeriod(period int64) bool {
+	return period > 0 && period%100 != 0 && period%100 <= 12
+}
+
+// PeriodToMonth converts a period in YYMM or YYYYMM format into an absolute number of months.
+// Two-digit years are interpreted like MySQL does: 70-99 are 1970-1999 and 00-69 are 2000-2069.
+func PeriodToMonth(period int64) int64 {
+	if period == 0 {
+		return 0
+	}
+	year := period / 100
+	if year < 100 {
+		year = int64(year2000(int(year)))
+	}
+	return year*12 + period%100 - 1
+}
+
+// MonthToPeriod converts an absolute number of months into a period in YYYYMM format.
+func MonthToPeriod(months int64) int64 {
+	if months == 0 {
+		return 0
+	}
+	year := months / 12
+	if year < 100 {
+		year = int64(year2000(int(year)))
+	}
+	return year*100 + months%12 + 1
+}
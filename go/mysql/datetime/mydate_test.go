@@ -65,3 +65,33 @@ func TestDayNumberFields(t *testing.T) {
 		assert.Equal(t, wantDate, got)
 	}
 }
+
+func TestPeriods(t *testing.T) {
+	testCases := []struct {
+		period int64
+		valid  bool
+		months int64
+	}{
+		{200801, true, 2008*12 + 0},
+		{801, true, 2008*12 + 0},
+		{9912, true, 1999*12 + 11},
+		{6912, true, 2069*12 + 11},
+		{7001, true, 1970*12 + 0},
+		{200800, false, 0},
+		{200813, false, 0},
+		{-200801, false, 0},
+		{0, false, 0},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.valid, ValidPeriod(tc.period), tc.period)
+		if tc.valid {
+			assert.Equal(t, tc.months, PeriodToMonth(tc.period), tc.period)
+		}
+	}
+
+	assert.Equal(t, int64(200803), MonthToPeriod(PeriodToMonth(200801)+2))
+	assert.Equal(t, int64(200712), MonthToPeriod(PeriodToMonth(200801)-1))
+	assert.Equal(t, int64(200002), MonthToPeriod(1))
+	assert.Equal(t, int64(0), MonthToPeriod(0))
+}
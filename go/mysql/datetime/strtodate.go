@@ -0,0 +1,380 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datetime
+
+import (
+	"strings"
+	"time"
+)
+
+// FormatParts reports which parts of a temporal value are parsed by the given format
+// in STR_TO_DATE, which is how MySQL decides the type of its result: a DATETIME when
+// the format has both date and time parts, a TIME when it only has time parts and a
+// DATE otherwise. Note that MySQL does not consider the day specifiers (%d, %e and %D)
+// to be date parts for this purpose.
+func FormatParts(format string) (hasDate, hasTime, hasFrac bool) {
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		switch c := format[i]; {
+		case c == 'f':
+			hasFrac = true
+			hasTime = true
+		case strings.IndexByte("HISThiklrs", c) >= 0:
+			hasTime = true
+		case strings.IndexByte("MVUXYWabcjmvuxyw", c) >= 0:
+			hasDate = true
+		}
+	}
+	return
+}
+
+// strToDate holds the state of a STR_TO_DATE parse. The fields that are not part
+// of a DateTime are only resolved once the whole string has been parsed.
+type strToDate struct {
+	year, month, day      int
+	hour, minute, second  int
+	usec                  int
+	weekday, yearday      int
+	weekNumber            int
+	weekYear              int
+	sundayFirst           bool
+	strictWeekNumber      bool
+	strictWeekNumberYearX bool
+}
+
+// StrToDate parses s with the given MySQL format string following the rules of STR_TO_DATE.
+// The parsing is lenient in the same ways as MySQL: numeric fields can have fewer digits than
+// their width, whitespace in s is skipped between fields, parsing stops as soon as s has been
+// consumed even if the format has more specifiers left, and trailing characters in s are ignored.
+//
+// The returned value is not validated as a date: it can be a zero date, contain zero months
+// or days, or days that don't exist in their month. Use Date.Valid to check it.
+func StrToDate(format, s string) (DateTime, bool) {
+	st := strToDate{weekNumber: -1, weekYear: -1}
+	if _, ok := st.parse(format, s); !ok {
+		return DateTime{}, false
+	}
+	if !st.resolve() {
+		return DateTime{}, false
+	}
+	return DateTime{
+		Date: Date{year: uint16(st.year), month: uint8(st.month), day: uint8(st.day)},
+		Time: Time{hour: uint16(st.hour), minute: uint8(st.minute), second: uint8(st.second), nanosecond: uint32(st.usec * 1000)},
+	}, true
+}
+
+// StrToTime parses s like StrToDate does, but for formats that only produce a TIME:
+// any date parts are discarded, except for the day, which is added to the hours.
+func StrToTime(format, s string) (Time, bool) {
+	dt, ok := StrToDate(format, s)
+	if !ok {
+		return Time{}, false
+	}
+	t := dt.Time
+	t.hour += uint16(dt.Date.day) * 24
+	return t, true
+}
+
+// parse consumes s following format and returns the unparsed remainder of s.
+// It is called recursively for the specifiers that expand into a full time.
+func (st *strToDate) parse(format, s string) (string, bool) {
+	var usaTime bool
+	var daypart int
+
+	for ; len(format) > 0 && len(s) > 0; format = format[1:] {
+		s = skipSpaces(s)
+		if len(s) == 0 {
+			break
+		}
+
+		if format[0] != '%' || len(format) == 1 {
+			if !isSpace(format[0]) {
+				if s[0] != format[0] {
+					return s, false
+				}
+				s = s[1:]
+			}
+			continue
+		}
+
+		format = format[1:]
+
+		var ok bool
+		switch format[0] {
+		case 'Y':
+			rest := s
+			st.year, rest, ok = strToInt(s, 4)
+			if len(s)-len(rest) <= 2 {
+				st.year = year2000(st.year)
+			}
+			s = rest
+		case 'y':
+			st.year, s, ok = strToInt(s, 2)
+			st.year = year2000(st.year)
+		case 'm', 'c':
+			st.month, s, ok = strToInt(s, 2)
+		case 'M':
+			st.month, s, ok = strToWord(s, longMonthNames)
+		case 'b':
+			st.month, s, ok = strToWord(s, shortMonthNames)
+		case 'd', 'e':
+			st.day, s, ok = strToInt(s, 2)
+		case 'D':
+			st.day, s, ok = strToInt(s, 2)
+			// skip the English suffix of the day, whatever it is
+			s = s[min(len(s), 2):]
+		case 'h', 'I', 'l':
+			usaTime = true
+			st.hour, s, ok = strToInt(s, 2)
+		case 'k', 'H':
+			st.hour, s, ok = strToInt(s, 2)
+		case 'i':
+			st.minute, s, ok = strToInt(s, 2)
+		case 's', 'S':
+			st.second, s, ok = strToInt(s, 2)
+		case 'f':
+			rest := s
+			st.usec, rest, ok = strToInt(s, 6)
+			for n := len(s) - len(rest); n < 6; n++ {
+				st.usec *= 10
+			}
+			s = rest
+		case 'p':
+			if len(s) < 2 || !usaTime {
+				return s, false
+			}
+			switch {
+			case match(s[:2], "PM"):
+				daypart = 12
+			case !match(s[:2], "AM"):
+				return s, false
+			}
+			s, ok = s[2:], true
+		case 'W':
+			st.weekday, s, ok = strToWord(s, longDayNames)
+		case 'a':
+			st.weekday, s, ok = strToWord(s, shortDayNamesMonday)
+		case 'w':
+			st.weekday, s, ok = strToInt(s, 1)
+			if ok && st.weekday >= 7 {
+				return s, false
+			}
+			if st.weekday == 0 {
+				st.weekday = 7
+			}
+		case 'j':
+			st.yearday, s, ok = strToInt(s, 3)
+		case 'V', 'U', 'v', 'u':
+			st.sundayFirst = format[0] == 'U' || format[0] == 'V'
+			st.strictWeekNumber = format[0] == 'V' || format[0] == 'v'
+			st.weekNumber, s, ok = strToInt(s, 2)
+			if ok && ((st.strictWeekNumber && st.weekNumber == 0) || st.weekNumber > 53) {
+				return s, false
+			}
+		case 'X', 'x':
+			st.strictWeekNumberYearX = format[0] == 'X'
+			st.weekYear, s, ok = strToInt(s, 4)
+		case 'r':
+			s, ok = st.parse("%I:%i:%S %p", s)
+		case 'T':
+			s, ok = st.parse("%H:%i:%S", s)
+		case '.':
+			s, ok = skipWhile(s, isPunct), true
+		case '@':
+			s, ok = skipWhile(s, isAlpha), true
+		case '#':
+			s, ok = skipWhile(s, isDigitByte), true
+		}
+		if !ok {
+			return s, false
+		}
+	}
+
+	if usaTime {
+		if st.hour > 12 || st.hour < 1 {
+			return s, false
+		}
+		st.hour = st.hour%12 + daypart
+	}
+	return s, true
+}
+
+// resolve computes the date from the year day or the week number, if they
+// were parsed, and checks the ranges of all the fields.
+func (st *strToDate) resolve() bool {
+	if st.yearday > 0 {
+		daynr := MysqlDayNumber(st.year, 1, 1) + st.yearday - 1
+		if !st.setDayNumber(daynr) {
+			return false
+		}
+	}
+
+	if st.weekNumber >= 0 && st.weekday != 0 {
+		// %V and %v require %X and %x respectively, while %U and %u must be used with %Y
+		if st.strictWeekNumber && (st.weekYear < 0 || st.strictWeekNumberYearX != st.sundayFirst) {
+			return false
+		}
+		if !st.strictWeekNumber && st.weekYear >= 0 {
+			return false
+		}
+
+		year := st.year
+		if st.strictWeekNumber {
+			year = st.weekYear
+		}
+		daynr := MysqlDayNumber(year, 1, 1)
+		weekdayB := mysqlWeekday(daynr, st.sundayFirst)
+
+		if st.sundayFirst {
+			if weekdayB != 0 {
+				daynr += 7
+			}
+			daynr += -weekdayB + (st.weekNumber-1)*7 + st.weekday%7
+		} else {
+			if weekdayB > 3 {
+				daynr += 7
+			}
+			daynr += -weekdayB + (st.weekNumber-1)*7 + (st.weekday - 1)
+		}
+		if !st.setDayNumber(daynr) {
+			return false
+		}
+	}
+
+	return st.year <= 9999 && st.month <= 12 && st.day <= 31 && st.hour <= 23 && st.minute <= 59 && st.second <= 59
+}
+
+func (st *strToDate) setDayNumber(daynr int) bool {
+	year, month, day := mysqlDateFromDayNumber(daynr)
+	if year == 0 {
+		return false
+	}
+	st.year, st.month, st.day = int(year), int(month), int(day)
+	return true
+}
+
+// mysqlWeekday returns the day of the week for a day number from MysqlDayNumber,
+// where 0 is either Sunday or Monday.
+func mysqlWeekday(daynr int, sundayFirst bool) int {
+	if sundayFirst {
+		return (daynr + 6) % 7
+	}
+	return (daynr + 5) % 7
+}
+
+// year2000 converts a two-digit year into a full year, like MySQL does.
+func year2000(year int) int {
+	if year < 70 {
+		return year + 2000
+	}
+	if year < 100 {
+		return year + 1900
+	}
+	return year
+}
+
+// strToInt parses a non-negative number of at most width characters at the
+// start of s, with an optional sign.
+func strToInt(s string, width int) (int, string, bool) {
+	end := min(width, len(s))
+	i := 0
+	neg := false
+	if i < end && (s[i] == '-' || s[i] == '+') {
+		neg = s[i] == '-'
+		i++
+	}
+	start := i
+	n := 0
+	for ; i < end && isDigit(s, i); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	if i == start || (neg && n != 0) {
+		return 0, s, false
+	}
+	return n, s[i:], true
+}
+
+// strToWord matches the word at the start of s against the given names, ignoring case.
+// The word can be an abbreviation of one of the names, as long as it is not ambiguous.
+// It returns the position of the name, starting from 1.
+func strToWord(s string, names []string) (int, string, bool) {
+	n := 0
+	for n < len(s) && isAlpha(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return 0, s, false
+	}
+	word := s[:n]
+
+	found := -1
+	for i, name := range names {
+		if len(name) < len(word) || !match(word, name[:len(word)]) {
+			continue
+		}
+		if len(name) == len(word) {
+			return i + 1, s[n:], true
+		}
+		if found >= 0 {
+			// ambiguous abbreviation, but there could still be an exact match
+			found = len(names)
+			continue
+		}
+		found = i
+	}
+	if found < 0 || found == len(names) {
+		return 0, s, false
+	}
+	return found + 1, s[n:], true
+}
+
+var longMonthNames = func() (names []string) {
+	for m := time.January; m <= time.December; m++ {
+		names = append(names, m.String())
+	}
+	return
+}()
+
+var longDayNames = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+var shortDayNamesMonday = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+func skipSpaces(s string) string {
+	return skipWhile(s, isSpace)
+}
+
+func skipWhile(s string, f func(c byte) bool) string {
+	for len(s) > 0 && f(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isPunct(c byte) bool {
+	return c > ' ' && c < 0x7f && !isAlpha(c) && !isDigitByte(c)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datetime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrToDate(t *testing.T) {
+	testCases := []struct {
+		input  string
+		format string
+		want   string
+	}{
+		{"01,5,2013", "%d,%m,%Y", "2013-05-01 00:00:00.000000"},
+		{"May 1, 2013", "%M %d,%Y", "2013-05-01 00:00:00.000000"},
+		{"a09:30:17", "a%h:%i:%s", "0000-00-00 09:30:17.000000"},
+		{"a09:30:17", "%h:%i:%s", ""},
+		{"09:30:17a", "%h:%i:%s", "0000-00-00 09:30:17.000000"},
+		{"abc", "abc", "0000-00-00 00:00:00.000000"},
+		{"9", "%m", "0000-09-00 00:00:00.000000"},
+		{"9", "%s", "0000-00-00 00:00:09.000000"},
+		{"2020", "%Y-%m-%d", "2020-00-00 00:00:00.000000"},
+		{"200442 Monday", "%X%V %W", "2004-10-18 00:00:00.000000"},
+		{"200442 Monday", "%x%V %W", ""},
+		{"2004 42 Mon", "%Y %U %a", "2004-10-18 00:00:00.000000"},
+		{"10:30 PM", "%h:%i %p", "0000-00-00 22:30:00.000000"},
+		{"12:15 am", "%h:%i %p", "0000-00-00 00:15:00.000000"},
+		{"13:00 PM", "%h:%i %p", ""},
+		{"10:30 PM", "%H:%i %p", ""},
+		{"10:20:30 PM", "%r", "0000-00-00 22:20:30.000000"},
+		{"10:20:30", "%T", "0000-00-00 10:20:30.000000"},
+		{"2020-01-01 10:20:30.5", "%Y-%m-%d %H:%i:%s.%f", "2020-01-01 10:20:30.500000"},
+		{"2020-01-01 10:20:30.1234567", "%Y-%m-%d %H:%i:%s.%f", "2020-01-01 10:20:30.123456"},
+		{"15th March 2021", "%D %M %Y", "2021-03-15 00:00:00.000000"},
+		{"2021-032", "%Y-%j", "2021-02-01 00:00:00.000000"},
+		{"99-1-1", "%y-%m-%d", "1999-01-01 00:00:00.000000"},
+		{"9-1-1", "%Y-%m-%d", "2009-01-01 00:00:00.000000"},
+		{"0009-1-1", "%Y-%m-%d", "0009-01-01 00:00:00.000000"},
+		{"Ju 1 2020", "%M %d %Y", ""},
+		{"Marc 1 2020", "%M %d %Y", "2020-03-01 00:00:00.000000"},
+		{"feb 29 2020", "%b %d %Y", "2020-02-29 00:00:00.000000"},
+		{"  2020  -  1  -  2", "%Y-%m-%d", "2020-01-02 00:00:00.000000"},
+		{"2020/01/02", "%Y%.%m%.%d", "2020-01-02 00:00:00.000000"},
+		{"2020-13-01", "%Y-%m-%d", ""},
+		{"2020-12-32", "%Y-%m-%d", ""},
+		{"2020-12-01 24:00", "%Y-%m-%d %H:%i", ""},
+		{"2020-12-01", "%Y-%m-%d %Q", "2020-12-01 00:00:00.000000"},
+		{"2020-12-01 1", "%Y-%m-%d %Q", ""},
+		{"-1-12-01", "%Y-%m-%d", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input+"/"+tc.format, func(t *testing.T) {
+			got, ok := StrToDate(tc.format, tc.input)
+			if tc.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, string(got.Format(6)))
+		})
+	}
+}
+
+func TestStrToTime(t *testing.T) {
+	got, ok := StrToTime("%d %H:%i", "2 10:30")
+	assert.True(t, ok)
+	assert.Equal(t, "58:30:00", string(got.Format(0)))
+
+	_, ok = StrToTime("%H:%i", "10-30")
+	assert.False(t, ok)
+}
+
+func TestFormatParts(t *testing.T) {
+	testCases := []struct {
+		format                    string
+		hasDate, hasTime, hasFrac bool
+	}{
+		{"%Y-%m-%d", true, false, false},
+		{"%d", false, false, false},
+		{"%d %H", false, true, false},
+		{"%H:%i:%s.%f", false, true, true},
+		{"%Y-%m-%d %T", true, true, false},
+		{"%Y-%m-%d %T.%f", true, true, true},
+		{"%%H", false, false, false},
+		{"100%", false, false, false},
+	}
+
+	for _, tc := range testCases {
+		hasDate, hasTime, hasFrac := FormatParts(tc.format)
+		assert.Equal(t, tc.hasDate, hasDate, tc.format)
+		assert.Equal(t, tc.hasTime, hasTime, tc.format)
+		assert.Equal(t, tc.hasFrac, hasFrac, tc.format)
+	}
+}
@@ -217,41 +217,113 @@ func (jp *Path) Match(doc *Value, wrap bool, match func(value *Value)) {
 	m.value(jp, doc)
 }
 
-func (jp *Path) transform(v *Value, t func(pp *Path, vv *Value)) {
+// arrayIndex returns the position in an array of the given length that a single array
+// location refers to. The position can be negative or past the end of the array.
+func (jp *Path) arrayIndex(length int) int {
+	idx := int(jp.offset0)
+	if idx < 0 {
+		idx += length
+	}
+	return idx
+}
+
+// transform navigates the path up to its last leg and calls t with that leg and the value it applies to.
+// The replace callback of t swaps that value for a new one in the document.
+func (jp *Path) transform(v *Value, replace func(*Value), t func(pp *Path, vv *Value, replace func(*Value))) {
 	if v == nil {
 		return
 	}
 	if jp.next == nil {
-		t(jp, v)
+		t(jp, v, replace)
 		return
 	}
 	switch jp.kind {
 	case jpDocumentRoot:
-		jp.next.transform(v, t)
+		jp.next.transform(v, replace, t)
 	case jpMember:
 		if obj, ok := v.Object(); ok {
-			jp.next.transform(obj.Get(jp.name), t)
+			jp.next.transform(obj.Get(jp.name), func(n *Value) { obj.Set(jp.name, n, Set) }, t)
 		}
 	case jpArrayLocation:
 		if ary, ok := v.Array(); ok {
-			from, to := jp.arrayOffsets(ary)
-			if from != to {
-				panic("range in transformation path expression")
+			idx := jp.arrayIndex(len(ary))
+			if idx >= 0 && idx < len(ary) {
+				jp.next.transform(ary[idx], func(n *Value) { ary[idx] = n }, t)
 			}
-			if from >= 0 && from < len(ary) {
-				jp.next.transform(ary[from], t)
-			}
-		} else if jp.offset0 == 0 || jp.offset0 == -1 {
+		} else if jp.arrayIndex(1) == 0 {
 			/*
 				If the path is evaluated against a value that is not an array,
 				the result of the evaluation is the same as if the value had been
 				wrapped in a single-element array:
 			*/
-			jp.next.transform(v, t)
+			jp.next.transform(v, replace, t)
+		}
+	}
+}
+
+// apply performs the transformation t for the last leg of a path on the value v.
+func (jp *Path) apply(t Transformation, v *Value, replace func(*Value), value *Value) {
+	switch jp.kind {
+	case jpDocumentRoot:
+		switch t {
+		case Set, Replace:
+			replace(value)
+		case ArrayAppend:
+			replace(appendTo(v, value))
 		}
-	case jpMemberAny, jpArrayLocationAny, jpAny:
-		panic("wildcard in transformation path expression")
+	case jpMember:
+		obj, ok := v.Object()
+		if !ok {
+			return
+		}
+		switch t {
+		case Set, Insert, Replace:
+			obj.Set(jp.name, value, t)
+		case Remove:
+			obj.Del(jp.name)
+		case ArrayAppend:
+			if child := obj.Get(jp.name); child != nil {
+				obj.Set(jp.name, appendTo(child, value), Set)
+			}
+		}
+	case jpArrayLocation:
+		ary, ok := v.Array()
+		if !ok {
+			// A value that is not an array behaves like an array with the value as its only item
+			idx := jp.arrayIndex(1)
+			switch {
+			case idx == 0 && (t == Set || t == Replace):
+				replace(value)
+			case idx == 0 && t == ArrayAppend:
+				replace(appendTo(v, value))
+			case idx > 0 && (t == Set || t == Insert):
+				replace(appendTo(v, value))
+			}
+			return
+		}
+		idx := jp.arrayIndex(len(ary))
+		switch t {
+		case Set, Insert, Replace:
+			v.SetArrayItem(idx, value, t)
+		case Remove:
+			v.DelArrayItem(idx)
+		case ArrayAppend:
+			if idx >= 0 && idx < len(ary) {
+				ary[idx] = appendTo(ary[idx], value)
+			}
+		case ArrayInsert:
+			v.InsertArrayItem(idx, value)
+		}
+	}
+}
+
+// appendTo appends value to the array v. If v is not an array, it is wrapped in a new array with value.
+func appendTo(v *Value, value *Value) *Value {
+	if v.t == TypeArray {
+		v.a = append(v.a, value)
+		return v
 	}
+	return NewArray([]*Value{v, value})
 }
 
 type Transformation int
@@ -261,40 +333,80 @@ const (
 	Insert
 	Replace
 	Remove
+	ArrayAppend
+	ArrayInsert
+)
+
+var (
+	errTransformWildcard = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "In this situation, path expressions may not contain the * and ** tokens or an array range.")
+	errTransformRoot     = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "The path expression '$' is not allowed in this context.")
+	errTransformNotCell  = vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "A path expression is not a path to a cell in an array.")
 )
 
-func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) error {
+// ApplyTransform applies the transformation t to the document for each one of the paths in order,
+// using the value with the same index for all the transformations except Remove. It returns the
+// transformed document: arrays and objects of doc are modified in place, but the root of the document
+// can be replaced. The values are copied before being added to the document.
+func ApplyTransform(t Transformation, doc *Value, paths []*Path, values []*Value) (*Value, error) {
 	if t != Remove && len(paths) != len(values) {
 		panic("missing Values for transformation")
 	}
+	replace := func(n *Value) { doc = n }
 	for i, p := range paths {
-		transform := func(pp *Path, vv *Value) {
-			switch pp.kind {
-			case jpArrayLocation:
-				if ary, ok := vv.Array(); ok {
-					from, to := pp.arrayOffsets(ary)
-					if from != to {
-						return
-					}
-					if t == Remove {
-						vv.DelArrayItem(from)
-					} else {
-						vv.SetArrayItem(from, values[i], t)
-					}
-				}
-			case jpMember:
-				if obj, ok := vv.Object(); ok {
-					if t == Remove {
-						obj.Del(pp.name)
-					} else {
-						obj.Set(pp.name, values[i], t)
-					}
-				}
+		if p.ContainsWildcards() {
+			return nil, errTransformWildcard
+		}
+		var value *Value
+		switch t {
+		case Remove:
+			if p.next == nil {
+				return nil, errTransformRoot
+			}
+		case ArrayInsert:
+			last := p
+			for last.next != nil {
+				last = last.next
 			}
+			if last.kind != jpArrayLocation {
+				return nil, errTransformNotCell
+			}
+		}
+		if t != Remove {
+			value = values[i].Clone()
+		}
+		p.transform(doc, replace, func(pp *Path, vv *Value, replace func(*Value)) {
+			pp.apply(t, vv, replace, value)
+		})
+	}
+	return doc, nil
+}
+
+// Walk calls f for v and every value nested inside of it, in document order, with the path that leads
+// from v to that value. The path is only valid until f returns.
+func (v *Value) Walk(f func(path []byte, value *Value)) {
+	v.walk([]byte{'$'}, f)
+}
+
+func (v *Value) walk(path []byte, f func([]byte, *Value)) {
+	f(path, v)
+	switch v.t {
+	case TypeArray:
+		for i, item := range v.a {
+			p := append(path, '[')
+			p = strconv.AppendInt(p, int64(i), 10)
+			item.walk(append(p, ']'), f)
+		}
+	case TypeObject:
+		for _, kv := range v.o.kvs {
+			p := append(path, '.')
+			if jpIsIdentifier(kv.k) {
+				p = append(p, kv.k...)
+			} else {
+				p = strconv.AppendQuote(p, kv.k)
+			}
+			kv.v.walk(p, f)
 		}
-		p.transform(doc, transform)
 	}
-	return nil
 }
 
 func MatchPath(rawJSON, rawPath []byte, match func(value *Value)) error {
@@ -171,6 +171,34 @@ func TestTransformations(t *testing.T) {
 			Paths:    []string{`$[2]`, `$[1].b[1]`, `$[1].b[1]`},
 			Expected: `["a", {"b": [true]}]`,
 		},
+		{
+			T:        Set,
+			Document: `{"a": 1}`,
+			Paths:    []string{`$.a[0]`, `$.b`, `$.b[3]`},
+			Values:   []string{"2", "true", "null"},
+			Expected: `{"a": 2, "b": [true, null]}`,
+		},
+		{
+			T:        Replace,
+			Document: Document1,
+			Paths:    []string{`$`},
+			Values:   []string{`{"c": 3}`},
+			Expected: `{"c": 3}`,
+		},
+		{
+			T:        ArrayAppend,
+			Document: Document1,
+			Paths:    []string{`$[0]`, `$[2]`, `$`},
+			Values:   []string{"1", "30", "2"},
+			Expected: `[["a", 1], {"b": [true, false]}, [10, 20, 30], 2]`,
+		},
+		{
+			T:        ArrayInsert,
+			Document: Document1,
+			Paths:    []string{`$[1].b[1]`, `$[2][9]`, `$[0]`},
+			Values:   []string{"1", "2", "3"},
+			Expected: `[3, "a", {"b": [true, 1, false]}, [10, 20, 2]]`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -186,7 +214,7 @@ func TestTransformations(t *testing.T) {
 			values = append(values, json(t, v))
 		}
 
-		err := ApplyTransform(tc.T, doc, paths, values)
+		doc, err := ApplyTransform(tc.T, doc, paths, values)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -65,26 +65,40 @@ func (o *Object) Set(key string, value *Value, t Transformation) {
 }
 
 // SetArrayItem sets the value in the array v at idx position.
+// Setting or inserting past the end of the array appends the value, like MySQL does.
 //
 // The value must be unchanged during v lifetime.
 func (v *Value) SetArrayItem(idx int, value *Value, t Transformation) {
 	if v == nil || v.t != TypeArray || idx < 0 {
 		return
 	}
-	switch t {
-	case Insert:
-		if idx < len(v.a) {
-			return
-		}
-		fallthrough
-	case Set:
-		for idx >= len(v.a) {
-			v.a = append(v.a, ValueNull)
-		}
+	if value == nil {
+		value = ValueNull
 	}
 	if idx < len(v.a) {
-		v.a[idx] = value
+		if t != Insert {
+			v.a[idx] = value
+		}
+		return
+	}
+	if t != Replace {
+		v.a = append(v.a, value)
+	}
+}
+
+// InsertArrayItem inserts the value in the array v at idx position, shifting the following items.
+// The value is appended if idx is past the end of the array.
+func (v *Value) InsertArrayItem(idx int, value *Value) {
+	if v == nil || v.t != TypeArray || idx < 0 {
+		return
+	}
+	if value == nil {
+		value = ValueNull
 	}
+	if idx > len(v.a) {
+		idx = len(v.a)
+	}
+	v.a = slices.Insert(v.a, idx, value)
 }
 
 func (v *Value) DelArrayItem(n int) {
@@ -96,3 +110,24 @@ func (v *Value) DelArrayItem(n int) {
 	}
 	v.a = append(v.a[:n], v.a[n+1:]...)
 }
+
+// Clone returns a deep copy of v. Scalar values are immutable, so they are shared with v.
+func (v *Value) Clone() *Value {
+	switch v.t {
+	case TypeArray:
+		a := make([]*Value, len(v.a))
+		for i, item := range v.a {
+			a[i] = item.Clone()
+		}
+		return &Value{a: a, t: TypeArray}
+	case TypeObject:
+		kvs := make([]kv, len(v.o.kvs))
+		for i, kv := range v.o.kvs {
+			kvs[i].k = kv.k
+			kvs[i].v = kv.v.Clone()
+		}
+		return &Value{o: Object{kvs: kvs}, t: TypeObject}
+	default:
+		return v
+	}
+}
@@ -471,6 +471,31 @@ func TestClientFoundRows(t *testing.T) {
 	c.Close()
 }
 
+func TestHandshakeLocalFiles(t *testing.T) {
+	authServer := NewAuthServerStatic("", "", 0)
+	defer authServer.close()
+
+	for _, allow := range []bool{false, true} {
+		listener, sConn, cConn := createSocketPair(t)
+
+		_, err := sConn.writeHandshakeV10("8.0.30", authServer, uint8(collations.CollationUtf8mb4ID), false, allow)
+		require.NoError(t, err)
+		data, err := cConn.ReadPacket()
+		require.NoError(t, err)
+		capabilities, _, err := cConn.parseInitialHandshakePacket(data)
+		require.NoError(t, err)
+		if allow {
+			assert.NotZero(t, capabilities&CapabilityClientLocalFiles, "server must advertise LocalFiles when allowed")
+		} else {
+			assert.Zero(t, capabilities&CapabilityClientLocalFiles, "server must not advertise LocalFiles by default")
+		}
+
+		sConn.Close()
+		cConn.Close()
+		listener.Close()
+	}
+}
+
 func TestConnCounts(t *testing.T) {
 	th := &testHandler{}
 
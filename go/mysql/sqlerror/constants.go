@@ -274,6 +274,13 @@ const (
 	ERRegexpInvalidCaptureGroup = ErrorCode(3887)
 	ERRegexpInvalidFlag         = ErrorCode(3900)
 
+	ERCTERecursiveRequiresUnion             = ErrorCode(3573)
+	ERCTERecursiveRequiresNonRecursiveFirst = ErrorCode(3574)
+	ERCTERecursiveForbidsAggregation        = ErrorCode(3575)
+	ERCTERecursiveForbiddenJoinOrder        = ErrorCode(3576)
+	ERCTERecursiveRequiresSingleReference   = ErrorCode(3577)
+	ERCTEMaxRecursionDepth                  = ErrorCode(3636)
+
 	ERCharacterSetMismatch = ErrorCode(3995)
 
 	ERWrongParametersToNativeFct = ErrorCode(1583)
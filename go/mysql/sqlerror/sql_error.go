@@ -245,6 +245,13 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.KillDeniedError:              {num: ERKillDenied, state: SSUnknownSQLState},
 	vterrors.BadNullError:                 {num: ERBadNullError, state: SSConstraintViolation},
 	vterrors.InvalidGroupFuncUse:          {num: ERInvalidGroupFuncUse, state: SSUnknownSQLState},
+
+	vterrors.CTERecursiveRequiresUnion:             {num: ERCTERecursiveRequiresUnion, state: SSUnknownSQLState},
+	vterrors.CTERecursiveRequiresNonRecursiveFirst: {num: ERCTERecursiveRequiresNonRecursiveFirst, state: SSUnknownSQLState},
+	vterrors.CTERecursiveForbidsAggregation:        {num: ERCTERecursiveForbidsAggregation, state: SSUnknownSQLState},
+	vterrors.CTERecursiveForbiddenJoinOrder:        {num: ERCTERecursiveForbiddenJoinOrder, state: SSUnknownSQLState},
+	vterrors.CTERecursiveRequiresSingleReference:   {num: ERCTERecursiveRequiresSingleReference, state: SSUnknownSQLState},
+	vterrors.CTEMaxRecursionDepth:                  {num: ERCTEMaxRecursionDepth, state: SSUnknownSQLState},
 }
 
 func getStateToMySQLState(state vterrors.State) mysqlCode {
@@ -126,6 +126,11 @@ func TestGroupBy(t *testing.T) {
 			utils.BinaryIsAtLeastAtVersion(20, "vttablet") {
 			mcmp.Exec("select id6, id7, count(*) k from t3 group by id6, id7 with rollup")
 		}
+		if utils.BinaryIsAtLeastAtVersion(21, "vtgate") {
+			mcmp.Exec("select id6, id7, grouping(id6, id7), sum(id5) from t3 group by id6, id7 with rollup")
+			mcmp.Exec("select id7, grouping(id7) g, count(*) from t3 group by id7 with rollup having g = 1")
+			mcmp.Exec("select id6, count(*) from t3 group by id6 with rollup having id6 = 2")
+		}
 	}
 }
 
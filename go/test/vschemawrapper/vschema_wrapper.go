@@ -49,6 +49,7 @@ type VSchemaWrapper struct {
 	ForeignKeyChecksState *bool
 	Version               plancontext.PlannerVersion
 	EnableViews           bool
+	EnableLocalInfile     bool
 	TestBuilder           func(query string, vschema plancontext.VSchema, keyspace string) (*engine.Plan, error)
 	Env                   *vtenv.Environment
 }
@@ -151,6 +152,10 @@ func (vw *VSchemaWrapper) GetAggregateUDFs() (udfs []string) {
 	return vw.V.GetAggregateUDFs()
 }
 
+func (vw *VSchemaWrapper) FindFunction(keyspace, name string) *vindexes.Function {
+	return vw.V.FindFunction(keyspace, name)
+}
+
 func (vw *VSchemaWrapper) GetForeignKeyChecksState() *bool {
 	return vw.ForeignKeyChecksState
 }
@@ -342,3 +347,7 @@ func (vw *VSchemaWrapper) FindRoutedShard(keyspace, shard string) (string, error
 func (vw *VSchemaWrapper) IsViewsEnabled() bool {
 	return vw.EnableViews
 }
+
+func (vw *VSchemaWrapper) IsLocalInfileEnabled() bool {
+	return vw.EnableLocalInfile
+}
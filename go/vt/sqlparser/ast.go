@@ -564,8 +564,33 @@ type (
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
-	// Load represents a LOAD statement
+	// Load represents a LOAD statement.
+	// Only LOAD DATA LOCAL INFILE is parsed into its parts, all the other
+	// forms are skipped and Local is false.
 	Load struct {
+		Local       bool
+		FileName    *Literal
+		Action      InsertAction
+		Ignore      Ignore
+		Table       TableName
+		Fields      *LoadFields
+		Lines       *LoadLines
+		IgnoreLines *Literal
+		Columns     Columns
+	}
+
+	// LoadFields represents the FIELDS/COLUMNS clause of a LOAD DATA statement.
+	LoadFields struct {
+		TerminatedBy *Literal
+		Optionally   bool
+		EnclosedBy   *Literal
+		EscapedBy    *Literal
+	}
+
+	// LoadLines represents the LINES clause of a LOAD DATA statement.
+	LoadLines struct {
+		StartingBy   *Literal
+		TerminatedBy *Literal
 	}
 
 	// PurgeBinaryLogs represents a PURGE BINARY LOGS statement
@@ -2160,6 +2185,9 @@ type TrimFuncType int8
 // TrimType is an enum to get types of Trim
 type TrimType int8
 
+// GetFormatType is an enum to get the temporal types of GET_FORMAT
+type GetFormatType int8
+
 // Types for window functions
 type (
 
@@ -2391,6 +2419,12 @@ type (
 		Unit  IntervalType
 	}
 
+	// GetFormatExpr represents the function and arguments for GET_FORMAT(DATE, 'USA') type functions.
+	GetFormatExpr struct {
+		Type GetFormatType
+		Expr Expr
+	}
+
 	// ExtractFuncExpr represents the function and arguments for EXTRACT(YEAR FROM '2019-07-02') type functions.
 	ExtractFuncExpr struct {
 		IntervalType IntervalType
@@ -3032,6 +3066,14 @@ type (
 		Arg Expr
 	}
 
+	// GroupingFunc represents a call to GROUPING(), which tells apart the
+	// super-aggregate rows produced by GROUP BY ... WITH ROLLUP.
+	// Like ANY_VALUE, it is simpler to treat it as an aggregation function
+	// see https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_grouping
+	GroupingFunc struct {
+		Exprs Exprs
+	}
+
 	// RegexpInstrExpr represents REGEXP_INSTR()
 	// For more information, see https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
 	RegexpInstrExpr struct {
@@ -3211,6 +3253,7 @@ func (*IntroducerExpr) IsExpr()                     {}
 func (*CollateExpr) IsExpr()                        {}
 func (*FuncExpr) IsExpr()                           {}
 func (*TimestampDiffExpr) IsExpr()                  {}
+func (*GetFormatExpr) IsExpr()                      {}
 func (*ExtractFuncExpr) IsExpr()                    {}
 func (*WeightStringFuncExpr) IsExpr()               {}
 func (*CurTimeFuncExpr) IsExpr()                    {}
@@ -3275,6 +3318,7 @@ func (*CountStar) IsExpr()                          {}
 func (*Count) IsExpr()                              {}
 func (*GroupConcatExpr) IsExpr()                    {}
 func (*AnyValue) IsExpr()                           {}
+func (*GroupingFunc) IsExpr()                       {}
 func (*BitAnd) IsExpr()                             {}
 func (*BitOr) IsExpr()                              {}
 func (*BitXor) IsExpr()                             {}
@@ -3309,6 +3353,7 @@ func (*GeomFromGeoJSONExpr) IsExpr()                {}
 // iCallable marks all expressions that represent function calls
 func (*FuncExpr) iCallable()                           {}
 func (*TimestampDiffExpr) iCallable()                  {}
+func (*GetFormatExpr) iCallable()                      {}
 func (*ExtractFuncExpr) iCallable()                    {}
 func (*WeightStringFuncExpr) iCallable()               {}
 func (*CurTimeFuncExpr) iCallable()                    {}
@@ -3324,6 +3369,7 @@ func (*ConvertUsingExpr) iCallable()                   {}
 func (*MatchExpr) iCallable()                          {}
 func (*GroupConcatExpr) iCallable()                    {}
 func (*AnyValue) iCallable()                           {}
+func (*GroupingFunc) iCallable()                       {}
 func (*JSONSchemaValidFuncExpr) iCallable()            {}
 func (*JSONSchemaValidationReportFuncExpr) iCallable() {}
 func (*JSONPrettyExpr) iCallable()                     {}
@@ -3405,6 +3451,7 @@ func (varP *VarPop) GetArg() Expr               { return varP.Arg }
 func (varS *VarSamp) GetArg() Expr              { return varS.Arg }
 func (variance *Variance) GetArg() Expr         { return variance.Arg }
 func (av *AnyValue) GetArg() Expr               { return av.Arg }
+func (gf *GroupingFunc) GetArg() Expr           { return gf.Exprs[0] }
 func (jaa *JSONArrayAgg) GetArg() Expr          { return jaa.Expr }
 func (joa *JSONObjectAgg) GetArg() Expr         { return joa.Key }
 
@@ -3426,6 +3473,7 @@ func (varP *VarPop) GetArgs() Exprs               { return Exprs{varP.Arg} }
 func (varS *VarSamp) GetArgs() Exprs              { return Exprs{varS.Arg} }
 func (variance *Variance) GetArgs() Exprs         { return Exprs{variance.Arg} }
 func (av *AnyValue) GetArgs() Exprs               { return Exprs{av.Arg} }
+func (gf *GroupingFunc) GetArgs() Exprs           { return gf.Exprs }
 func (jaa *JSONArrayAgg) GetArgs() Exprs          { return Exprs{jaa.Expr} }
 func (joa *JSONObjectAgg) GetArgs() Exprs         { return Exprs{joa.Key, joa.Value} }
 
@@ -3447,6 +3495,7 @@ func (varP *VarPop) SetArg(expr Expr)               { varP.Arg = expr }
 func (varS *VarSamp) SetArg(expr Expr)              { varS.Arg = expr }
 func (variance *Variance) SetArg(expr Expr)         { variance.Arg = expr }
 func (av *AnyValue) SetArg(expr Expr)               { av.Arg = expr }
+func (gf *GroupingFunc) SetArg(expr Expr)           { gf.Exprs = Exprs{expr} }
 func (jaa *JSONArrayAgg) SetArg(expr Expr)          { jaa.Expr = expr }
 func (joa *JSONObjectAgg) SetArg(expr Expr)         { joa.Key = expr }
 
@@ -3484,6 +3533,10 @@ func (grpConcat *GroupConcatExpr) SetArgs(exprs Exprs) error {
 	grpConcat.Exprs = exprs
 	return nil
 }
+func (gf *GroupingFunc) SetArgs(exprs Exprs) error {
+	gf.Exprs = exprs
+	return nil
+}
 
 func (sum *Sum) IsDistinct() bool                   { return sum.Distinct }
 func (min *Min) IsDistinct() bool                   { return min.Distinct }
@@ -3517,6 +3570,7 @@ func (*VarPop) AggrName() string          { return "var_pop" }
 func (*VarSamp) AggrName() string         { return "var_samp" }
 func (*Variance) AggrName() string        { return "variance" }
 func (*AnyValue) AggrName() string        { return "any_value" }
+func (*GroupingFunc) AggrName() string    { return "grouping" }
 func (*JSONArrayAgg) AggrName() string    { return "json_arrayagg" }
 func (*JSONObjectAgg) AggrName() string   { return "json_objectagg" }
 
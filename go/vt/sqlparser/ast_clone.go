@@ -205,10 +205,14 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfGeomFromWKBExpr(in)
 	case *GeomPropertyFuncExpr:
 		return CloneRefOfGeomPropertyFuncExpr(in)
+	case *GetFormatExpr:
+		return CloneRefOfGetFormatExpr(in)
 	case *GroupBy:
 		return CloneRefOfGroupBy(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case IdentifierCI:
 		return CloneIdentifierCI(in)
 	case IdentifierCS:
@@ -307,6 +311,10 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfLiteral(in)
 	case *Load:
 		return CloneRefOfLoad(in)
+	case *LoadFields:
+		return CloneRefOfLoadFields(in)
+	case *LoadLines:
+		return CloneRefOfLoadLines(in)
 	case *LocateExpr:
 		return CloneRefOfLocateExpr(in)
 	case *LockOption:
@@ -1548,6 +1556,16 @@ func CloneRefOfGeomPropertyFuncExpr(n *GeomPropertyFuncExpr) *GeomPropertyFuncEx
 	return &out
 }
 
+// CloneRefOfGetFormatExpr creates a deep clone of the input.
+func CloneRefOfGetFormatExpr(n *GetFormatExpr) *GetFormatExpr {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Expr = CloneExpr(n.Expr)
+	return &out
+}
+
 // CloneRefOfGroupBy creates a deep clone of the input.
 func CloneRefOfGroupBy(n *GroupBy) *GroupBy {
 	if n == nil {
@@ -1570,6 +1588,16 @@ func CloneRefOfGroupConcatExpr(n *GroupConcatExpr) *GroupConcatExpr {
 	return &out
 }
 
+// CloneRefOfGroupingFunc creates a deep clone of the input.
+func CloneRefOfGroupingFunc(n *GroupingFunc) *GroupingFunc {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Exprs = CloneExprs(n.Exprs)
+	return &out
+}
+
 // CloneIdentifierCI creates a deep clone of the input.
 func CloneIdentifierCI(n IdentifierCI) IdentifierCI {
 	return *CloneRefOfIdentifierCI(&n)
@@ -2088,6 +2116,35 @@ func CloneRefOfLoad(n *Load) *Load {
 		return nil
 	}
 	out := *n
+	out.FileName = CloneRefOfLiteral(n.FileName)
+	out.Table = CloneTableName(n.Table)
+	out.Fields = CloneRefOfLoadFields(n.Fields)
+	out.Lines = CloneRefOfLoadLines(n.Lines)
+	out.IgnoreLines = CloneRefOfLiteral(n.IgnoreLines)
+	out.Columns = CloneColumns(n.Columns)
+	return &out
+}
+
+// CloneRefOfLoadFields creates a deep clone of the input.
+func CloneRefOfLoadFields(n *LoadFields) *LoadFields {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
+	out.EnclosedBy = CloneRefOfLiteral(n.EnclosedBy)
+	out.EscapedBy = CloneRefOfLiteral(n.EscapedBy)
+	return &out
+}
+
+// CloneRefOfLoadLines creates a deep clone of the input.
+func CloneRefOfLoadLines(n *LoadLines) *LoadLines {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.StartingBy = CloneRefOfLiteral(n.StartingBy)
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
 	return &out
 }
 
@@ -3487,6 +3544,8 @@ func CloneAggrFunc(in AggrFunc) AggrFunc {
 		return CloneRefOfCountStar(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case *JSONArrayAgg:
 		return CloneRefOfJSONArrayAgg(in)
 	case *JSONObjectAgg:
@@ -3627,8 +3686,12 @@ func CloneCallable(in Callable) Callable {
 		return CloneRefOfGeomFromWKBExpr(in)
 	case *GeomPropertyFuncExpr:
 		return CloneRefOfGeomPropertyFuncExpr(in)
+	case *GetFormatExpr:
+		return CloneRefOfGetFormatExpr(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case *InsertExpr:
 		return CloneRefOfInsertExpr(in)
 	case *IntervalDateExpr:
@@ -3923,8 +3986,12 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfGeomFromWKBExpr(in)
 	case *GeomPropertyFuncExpr:
 		return CloneRefOfGeomPropertyFuncExpr(in)
+	case *GetFormatExpr:
+		return CloneRefOfGetFormatExpr(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *GroupingFunc:
+		return CloneRefOfGroupingFunc(in)
 	case *InsertExpr:
 		return CloneRefOfInsertExpr(in)
 	case *IntervalDateExpr:
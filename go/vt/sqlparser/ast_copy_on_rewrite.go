@@ -204,10 +204,14 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfGeomFromWKBExpr(n, parent)
 	case *GeomPropertyFuncExpr:
 		return c.copyOnRewriteRefOfGeomPropertyFuncExpr(n, parent)
+	case *GetFormatExpr:
+		return c.copyOnRewriteRefOfGetFormatExpr(n, parent)
 	case *GroupBy:
 		return c.copyOnRewriteRefOfGroupBy(n, parent)
 	case *GroupConcatExpr:
 		return c.copyOnRewriteRefOfGroupConcatExpr(n, parent)
+	case *GroupingFunc:
+		return c.copyOnRewriteRefOfGroupingFunc(n, parent)
 	case IdentifierCI:
 		return c.copyOnRewriteIdentifierCI(n, parent)
 	case IdentifierCS:
@@ -306,6 +310,10 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfLiteral(n, parent)
 	case *Load:
 		return c.copyOnRewriteRefOfLoad(n, parent)
+	case *LoadFields:
+		return c.copyOnRewriteRefOfLoadFields(n, parent)
+	case *LoadLines:
+		return c.copyOnRewriteRefOfLoadLines(n, parent)
 	case *LocateExpr:
 		return c.copyOnRewriteRefOfLocateExpr(n, parent)
 	case *LockOption:
@@ -2643,6 +2651,28 @@ func (c *cow) copyOnRewriteRefOfGeomPropertyFuncExpr(n *GeomPropertyFuncExpr, pa
 	}
 	return
 }
+func (c *cow) copyOnRewriteRefOfGetFormatExpr(n *GetFormatExpr, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_Expr, changedExpr := c.copyOnRewriteExpr(n.Expr, n)
+		if changedExpr {
+			res := *n
+			res.Expr, _ = _Expr.(Expr)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
 func (c *cow) copyOnRewriteRefOfGroupBy(n *GroupBy, parent SQLNode) (out SQLNode, changed bool) {
 	if n == nil || c.cursor.stop {
 		return n, false
@@ -2699,6 +2729,28 @@ func (c *cow) copyOnRewriteRefOfGroupConcatExpr(n *GroupConcatExpr, parent SQLNo
 	}
 	return
 }
+func (c *cow) copyOnRewriteRefOfGroupingFunc(n *GroupingFunc, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_Exprs, changedExprs := c.copyOnRewriteExprs(n.Exprs, n)
+		if changedExprs {
+			res := *n
+			res.Exprs, _ = _Exprs.(Exprs)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
 func (c *cow) copyOnRewriteIdentifierCI(n IdentifierCI, parent SQLNode) (out SQLNode, changed bool) {
 	out = n
 	if c.pre == nil || c.pre(n, parent) {
@@ -3836,6 +3888,76 @@ func (c *cow) copyOnRewriteRefOfLoad(n *Load, parent SQLNode) (out SQLNode, chan
 	}
 	out = n
 	if c.pre == nil || c.pre(n, parent) {
+		_FileName, changedFileName := c.copyOnRewriteRefOfLiteral(n.FileName, n)
+		_Table, changedTable := c.copyOnRewriteTableName(n.Table, n)
+		_Fields, changedFields := c.copyOnRewriteRefOfLoadFields(n.Fields, n)
+		_Lines, changedLines := c.copyOnRewriteRefOfLoadLines(n.Lines, n)
+		_IgnoreLines, changedIgnoreLines := c.copyOnRewriteRefOfLiteral(n.IgnoreLines, n)
+		_Columns, changedColumns := c.copyOnRewriteColumns(n.Columns, n)
+		if changedFileName || changedTable || changedFields || changedLines || changedIgnoreLines || changedColumns {
+			res := *n
+			res.FileName, _ = _FileName.(*Literal)
+			res.Table, _ = _Table.(TableName)
+			res.Fields, _ = _Fields.(*LoadFields)
+			res.Lines, _ = _Lines.(*LoadLines)
+			res.IgnoreLines, _ = _IgnoreLines.(*Literal)
+			res.Columns, _ = _Columns.(Columns)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadFields(n *LoadFields, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		_EnclosedBy, changedEnclosedBy := c.copyOnRewriteRefOfLiteral(n.EnclosedBy, n)
+		_EscapedBy, changedEscapedBy := c.copyOnRewriteRefOfLiteral(n.EscapedBy, n)
+		if changedTerminatedBy || changedEnclosedBy || changedEscapedBy {
+			res := *n
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			res.EnclosedBy, _ = _EnclosedBy.(*Literal)
+			res.EscapedBy, _ = _EscapedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadLines(n *LoadLines, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_StartingBy, changedStartingBy := c.copyOnRewriteRefOfLiteral(n.StartingBy, n)
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		if changedStartingBy || changedTerminatedBy {
+			res := *n
+			res.StartingBy, _ = _StartingBy.(*Literal)
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
 	}
 	if c.post != nil {
 		out, changed = c.postVisit(out, parent, changed)
@@ -6760,6 +6882,8 @@ func (c *cow) copyOnRewriteAggrFunc(n AggrFunc, parent SQLNode) (out SQLNode, ch
 		return c.copyOnRewriteRefOfCountStar(n, parent)
 	case *GroupConcatExpr:
 		return c.copyOnRewriteRefOfGroupConcatExpr(n, parent)
+	case *GroupingFunc:
+		return c.copyOnRewriteRefOfGroupingFunc(n, parent)
 	case *JSONArrayAgg:
 		return c.copyOnRewriteRefOfJSONArrayAgg(n, parent)
 	case *JSONObjectAgg:
@@ -6896,8 +7020,12 @@ func (c *cow) copyOnRewriteCallable(n Callable, parent SQLNode) (out SQLNode, ch
 		return c.copyOnRewriteRefOfGeomFromWKBExpr(n, parent)
 	case *GeomPropertyFuncExpr:
 		return c.copyOnRewriteRefOfGeomPropertyFuncExpr(n, parent)
+	case *GetFormatExpr:
+		return c.copyOnRewriteRefOfGetFormatExpr(n, parent)
 	case *GroupConcatExpr:
 		return c.copyOnRewriteRefOfGroupConcatExpr(n, parent)
+	case *GroupingFunc:
+		return c.copyOnRewriteRefOfGroupingFunc(n, parent)
 	case *InsertExpr:
 		return c.copyOnRewriteRefOfInsertExpr(n, parent)
 	case *IntervalDateExpr:
@@ -7180,8 +7308,12 @@ func (c *cow) copyOnRewriteExpr(n Expr, parent SQLNode) (out SQLNode, changed bo
 		return c.copyOnRewriteRefOfGeomFromWKBExpr(n, parent)
 	case *GeomPropertyFuncExpr:
 		return c.copyOnRewriteRefOfGeomPropertyFuncExpr(n, parent)
+	case *GetFormatExpr:
+		return c.copyOnRewriteRefOfGetFormatExpr(n, parent)
 	case *GroupConcatExpr:
 		return c.copyOnRewriteRefOfGroupConcatExpr(n, parent)
+	case *GroupingFunc:
+		return c.copyOnRewriteRefOfGroupingFunc(n, parent)
 	case *InsertExpr:
 		return c.copyOnRewriteRefOfInsertExpr(n, parent)
 	case *IntervalDateExpr:
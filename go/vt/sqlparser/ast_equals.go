@@ -572,6 +572,12 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfGeomPropertyFuncExpr(a, b)
+	case *GetFormatExpr:
+		b, ok := inB.(*GetFormatExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGetFormatExpr(a, b)
 	case *GroupBy:
 		b, ok := inB.(*GroupBy)
 		if !ok {
@@ -584,6 +590,12 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfGroupConcatExpr(a, b)
+	case *GroupingFunc:
+		b, ok := inB.(*GroupingFunc)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGroupingFunc(a, b)
 	case IdentifierCI:
 		b, ok := inB.(IdentifierCI)
 		if !ok {
@@ -878,6 +890,18 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfLoad(a, b)
+	case *LoadFields:
+		b, ok := inB.(*LoadFields)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadFields(a, b)
+	case *LoadLines:
+		b, ok := inB.(*LoadLines)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadLines(a, b)
 	case *LocateExpr:
 		b, ok := inB.(*LocateExpr)
 		if !ok {
@@ -2805,6 +2829,18 @@ func (cmp *Comparator) RefOfGeomPropertyFuncExpr(a, b *GeomPropertyFuncExpr) boo
 		cmp.Expr(a.Geom, b.Geom)
 }
 
+// RefOfGetFormatExpr does deep equals between the two objects.
+func (cmp *Comparator) RefOfGetFormatExpr(a, b *GetFormatExpr) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Type == b.Type &&
+		cmp.Expr(a.Expr, b.Expr)
+}
+
 // RefOfGroupBy does deep equals between the two objects.
 func (cmp *Comparator) RefOfGroupBy(a, b *GroupBy) bool {
 	if a == b {
@@ -2832,6 +2868,17 @@ func (cmp *Comparator) RefOfGroupConcatExpr(a, b *GroupConcatExpr) bool {
 		cmp.RefOfLimit(a.Limit, b.Limit)
 }
 
+// RefOfGroupingFunc does deep equals between the two objects.
+func (cmp *Comparator) RefOfGroupingFunc(a, b *GroupingFunc) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.Exprs(a.Exprs, b.Exprs)
+}
+
 // IdentifierCI does deep equals between the two objects.
 func (cmp *Comparator) IdentifierCI(a, b IdentifierCI) bool {
 	return a.val == b.val &&
@@ -3419,7 +3466,41 @@ func (cmp *Comparator) RefOfLoad(a, b *Load) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return a.Local == b.Local &&
+		cmp.RefOfLiteral(a.FileName, b.FileName) &&
+		a.Action == b.Action &&
+		a.Ignore == b.Ignore &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.RefOfLoadFields(a.Fields, b.Fields) &&
+		cmp.RefOfLoadLines(a.Lines, b.Lines) &&
+		cmp.RefOfLiteral(a.IgnoreLines, b.IgnoreLines) &&
+		cmp.Columns(a.Columns, b.Columns)
+}
+
+// RefOfLoadFields does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadFields(a, b *LoadFields) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Optionally == b.Optionally &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy) &&
+		cmp.RefOfLiteral(a.EnclosedBy, b.EnclosedBy) &&
+		cmp.RefOfLiteral(a.EscapedBy, b.EscapedBy)
+}
+
+// RefOfLoadLines does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadLines(a, b *LoadLines) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.RefOfLiteral(a.StartingBy, b.StartingBy) &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy)
 }
 
 // RefOfLocateExpr does deep equals between the two objects.
@@ -5046,6 +5127,12 @@ func (cmp *Comparator) AggrFunc(inA, inB AggrFunc) bool {
 			return false
 		}
 		return cmp.RefOfGroupConcatExpr(a, b)
+	case *GroupingFunc:
+		b, ok := inB.(*GroupingFunc)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGroupingFunc(a, b)
 	case *JSONArrayAgg:
 		b, ok := inB.(*JSONArrayAgg)
 		if !ok {
@@ -5424,12 +5511,24 @@ func (cmp *Comparator) Callable(inA, inB Callable) bool {
 			return false
 		}
 		return cmp.RefOfGeomPropertyFuncExpr(a, b)
+	case *GetFormatExpr:
+		b, ok := inB.(*GetFormatExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGetFormatExpr(a, b)
 	case *GroupConcatExpr:
 		b, ok := inB.(*GroupConcatExpr)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfGroupConcatExpr(a, b)
+	case *GroupingFunc:
+		b, ok := inB.(*GroupingFunc)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGroupingFunc(a, b)
 	case *InsertExpr:
 		b, ok := inB.(*InsertExpr)
 		if !ok {
@@ -6186,12 +6285,24 @@ func (cmp *Comparator) Expr(inA, inB Expr) bool {
 			return false
 		}
 		return cmp.RefOfGeomPropertyFuncExpr(a, b)
+	case *GetFormatExpr:
+		b, ok := inB.(*GetFormatExpr)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGetFormatExpr(a, b)
 	case *GroupConcatExpr:
 		b, ok := inB.(*GroupConcatExpr)
 		if !ok {
 			return false
 		}
 		return cmp.RefOfGroupConcatExpr(a, b)
+	case *GroupingFunc:
+		b, ok := inB.(*GroupingFunc)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfGroupingFunc(a, b)
 	case *InsertExpr:
 		b, ok := inB.(*InsertExpr)
 		if !ok {
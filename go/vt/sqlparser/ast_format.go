@@ -1506,6 +1506,11 @@ func (node *TimestampDiffExpr) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "timestampdiff(%#s, %v, %v)", node.Unit.ToString(), node.Expr1, node.Expr2)
 }
 
+// Format formats the node.
+func (node *GetFormatExpr) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "get_format(%#s, %v)", node.Type.ToString(), node.Expr)
+}
+
 // Format formats the node.
 func (node *ExtractFuncExpr) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "extract(%#s from %v)", node.IntervalType.ToString(), node.Expr)
@@ -2118,7 +2123,55 @@ func (node IdentifierCS) Format(buf *TrackedBuffer) {
 
 // Format formats the node.
 func (node *Load) Format(buf *TrackedBuffer) {
-	buf.literal("AST node missing for Load type")
+	if !node.Local {
+		buf.literal("AST node missing for Load type")
+		return
+	}
+	buf.astPrintf(node, "load data local infile %v ", node.FileName)
+	if node.Action == ReplaceAct {
+		buf.literal("replace ")
+	}
+	buf.astPrintf(node, "%sinto table %v%v%v", node.Ignore.ToString(), node.Table, node.Fields, node.Lines)
+	if node.IgnoreLines != nil {
+		buf.astPrintf(node, " ignore %v lines", node.IgnoreLines)
+	}
+	if node.Columns != nil {
+		buf.astPrintf(node, " %v", node.Columns)
+	}
+}
+
+// Format formats the node.
+func (node *LoadFields) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" fields")
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
+	if node.EnclosedBy != nil {
+		if node.Optionally {
+			buf.literal(" optionally")
+		}
+		buf.astPrintf(node, " enclosed by %v", node.EnclosedBy)
+	}
+	if node.EscapedBy != nil {
+		buf.astPrintf(node, " escaped by %v", node.EscapedBy)
+	}
+}
+
+// Format formats the node.
+func (node *LoadLines) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" lines")
+	if node.StartingBy != nil {
+		buf.astPrintf(node, " starting by %v", node.StartingBy)
+	}
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
 }
 
 // Format formats the node.
@@ -2828,6 +2881,10 @@ func (node *AnyValue) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "any_value(%v)", node.Arg)
 }
 
+func (node *GroupingFunc) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "grouping(%v)", node.Exprs)
+}
+
 func (node *Avg) Format(buf *TrackedBuffer) {
 	buf.WriteString("avg(")
 	if node.Distinct {
@@ -1958,6 +1958,15 @@ func (node *TimestampDiffExpr) FormatFast(buf *TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+// FormatFast formats the node.
+func (node *GetFormatExpr) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("get_format(")
+	buf.WriteString(node.Type.ToString())
+	buf.WriteString(", ")
+	buf.printExpr(node, node.Expr, true)
+	buf.WriteByte(')')
+}
+
 // FormatFast formats the node.
 func (node *ExtractFuncExpr) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("extract(")
@@ -2796,7 +2805,69 @@ func (node IdentifierCS) FormatFast(buf *TrackedBuffer) {
 
 // FormatFast formats the node.
 func (node *Load) FormatFast(buf *TrackedBuffer) {
-	buf.WriteString("AST node missing for Load type")
+	if !node.Local {
+		buf.WriteString("AST node missing for Load type")
+		return
+	}
+	buf.WriteString("load data local infile ")
+	node.FileName.FormatFast(buf)
+	buf.WriteByte(' ')
+	if node.Action == ReplaceAct {
+		buf.WriteString("replace ")
+	}
+	buf.WriteString(node.Ignore.ToString())
+	buf.WriteString("into table ")
+	node.Table.FormatFast(buf)
+	node.Fields.FormatFast(buf)
+	node.Lines.FormatFast(buf)
+	if node.IgnoreLines != nil {
+		buf.WriteString(" ignore ")
+		node.IgnoreLines.FormatFast(buf)
+		buf.WriteString(" lines")
+	}
+	if node.Columns != nil {
+		buf.WriteByte(' ')
+		node.Columns.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadFields) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" fields")
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
+	if node.EnclosedBy != nil {
+		if node.Optionally {
+			buf.WriteString(" optionally")
+		}
+		buf.WriteString(" enclosed by ")
+		node.EnclosedBy.FormatFast(buf)
+	}
+	if node.EscapedBy != nil {
+		buf.WriteString(" escaped by ")
+		node.EscapedBy.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadLines) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" lines")
+	if node.StartingBy != nil {
+		buf.WriteString(" starting by ")
+		node.StartingBy.FormatFast(buf)
+	}
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
 }
 
 // FormatFast formats the node.
@@ -3720,6 +3791,12 @@ func (node *AnyValue) FormatFast(buf *TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (node *GroupingFunc) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("grouping(")
+	node.Exprs.FormatFast(buf)
+	buf.WriteByte(')')
+}
+
 func (node *Avg) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("avg(")
 	if node.Distinct {
@@ -1674,6 +1674,22 @@ func (ty TrimType) ToString() string {
 	}
 }
 
+// ToString returns the type as a string
+func (ty GetFormatType) ToString() string {
+	switch ty {
+	case GetFormatDate:
+		return GetFormatDateStr
+	case GetFormatTime:
+		return GetFormatTimeStr
+	case GetFormatDatetime:
+		return GetFormatDatetimeStr
+	case GetFormatTimestamp:
+		return GetFormatTimestampStr
+	default:
+		return "Unknown GetFormatType"
+	}
+}
+
 // ToString returns the type as a string
 func (ty FrameUnitType) ToString() string {
 	switch ty {
@@ -2175,6 +2191,11 @@ func ContainsAggregation(e SQLNode) bool {
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case AggrFunc:
+			if IsWindowFunc(node) {
+				// an aggregation with an OVER clause is a window function,
+				// but its arguments could still contain aggregations
+				return true, nil
+			}
 			hasAggregates = true
 			return false, io.EOF
 		}
@@ -2184,6 +2205,86 @@ func ContainsAggregation(e SQLNode) bool {
 	return hasAggregates
 }
 
+// GetOverClause returns the OVER clause of a window function call.
+// Aggregation functions only have an OVER clause when they are used as window functions,
+// so nil is returned for regular aggregations and for any other expression.
+func GetOverClause(node SQLNode) *OverClause {
+	switch node := node.(type) {
+	case *ArgumentLessWindowExpr:
+		return node.OverClause
+	case *FirstOrLastValueExpr:
+		return node.OverClause
+	case *NtileExpr:
+		return node.OverClause
+	case *NTHValueExpr:
+		return node.OverClause
+	case *LagLeadExpr:
+		return node.OverClause
+	case *Count:
+		return node.OverClause
+	case *CountStar:
+		return node.OverClause
+	case *Avg:
+		return node.OverClause
+	case *Max:
+		return node.OverClause
+	case *Min:
+		return node.OverClause
+	case *Sum:
+		return node.OverClause
+	case *BitAnd:
+		return node.OverClause
+	case *BitOr:
+		return node.OverClause
+	case *BitXor:
+		return node.OverClause
+	case *Std:
+		return node.OverClause
+	case *StdDev:
+		return node.OverClause
+	case *StdPop:
+		return node.OverClause
+	case *StdSamp:
+		return node.OverClause
+	case *VarPop:
+		return node.OverClause
+	case *VarSamp:
+		return node.OverClause
+	case *Variance:
+		return node.OverClause
+	case *JSONArrayAgg:
+		return node.OverClause
+	case *JSONObjectAgg:
+		return node.OverClause
+	}
+	return nil
+}
+
+// IsWindowFunc returns true if the node is a window function call
+func IsWindowFunc(node SQLNode) bool {
+	return GetOverClause(node) != nil
+}
+
+// ContainsWindowFunc returns true if the expression contains a window function call
+func ContainsWindowFunc(e SQLNode) bool {
+	hasWindowFunc := false
+	_ = Walk(func(node SQLNode) (kontinue bool, err error) {
+		switch node.(type) {
+		case *Offset:
+			return false, nil
+		case *Subquery:
+			// window functions inside subqueries are evaluated by the subquery
+			return false, nil
+		}
+		if IsWindowFunc(node) {
+			hasWindowFunc = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, e)
+	return hasWindowFunc
+}
+
 // setFuncArgs sets the arguments for the aggregation function, while checking that there is only one argument
 func setFuncArgs(aggr AggrFunc, exprs Exprs, name string) error {
 	if len(exprs) != 1 {
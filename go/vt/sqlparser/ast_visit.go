@@ -204,10 +204,14 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfGeomFromWKBExpr(in, f)
 	case *GeomPropertyFuncExpr:
 		return VisitRefOfGeomPropertyFuncExpr(in, f)
+	case *GetFormatExpr:
+		return VisitRefOfGetFormatExpr(in, f)
 	case *GroupBy:
 		return VisitRefOfGroupBy(in, f)
 	case *GroupConcatExpr:
 		return VisitRefOfGroupConcatExpr(in, f)
+	case *GroupingFunc:
+		return VisitRefOfGroupingFunc(in, f)
 	case IdentifierCI:
 		return VisitIdentifierCI(in, f)
 	case IdentifierCS:
@@ -306,6 +310,10 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfLiteral(in, f)
 	case *Load:
 		return VisitRefOfLoad(in, f)
+	case *LoadFields:
+		return VisitRefOfLoadFields(in, f)
+	case *LoadLines:
+		return VisitRefOfLoadLines(in, f)
 	case *LocateExpr:
 		return VisitRefOfLocateExpr(in, f)
 	case *LockOption:
@@ -1886,6 +1894,18 @@ func VisitRefOfGeomPropertyFuncExpr(in *GeomPropertyFuncExpr, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfGetFormatExpr(in *GetFormatExpr, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitExpr(in.Expr, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfGroupBy(in *GroupBy, f Visit) error {
 	if in == nil {
 		return nil
@@ -1918,6 +1938,18 @@ func VisitRefOfGroupConcatExpr(in *GroupConcatExpr, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfGroupingFunc(in *GroupingFunc, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitExprs(in.Exprs, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitIdentifierCI(in IdentifierCI, f Visit) error {
 	if cont, err := f(in); err != nil || !cont {
 		return err
@@ -2630,6 +2662,57 @@ func VisitRefOfLoad(in *Load, f Visit) error {
 	if cont, err := f(in); err != nil || !cont {
 		return err
 	}
+	if err := VisitRefOfLiteral(in.FileName, f); err != nil {
+		return err
+	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadFields(in.Fields, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadLines(in.Lines, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.IgnoreLines, f); err != nil {
+		return err
+	}
+	if err := VisitColumns(in.Columns, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadFields(in *LoadFields, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EnclosedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EscapedBy, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadLines(in *LoadLines, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.StartingBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
 	return nil
 }
 func VisitRefOfLocateExpr(in *LocateExpr, f Visit) error {
@@ -4494,6 +4577,8 @@ func VisitAggrFunc(in AggrFunc, f Visit) error {
 		return VisitRefOfCountStar(in, f)
 	case *GroupConcatExpr:
 		return VisitRefOfGroupConcatExpr(in, f)
+	case *GroupingFunc:
+		return VisitRefOfGroupingFunc(in, f)
 	case *JSONArrayAgg:
 		return VisitRefOfJSONArrayAgg(in, f)
 	case *JSONObjectAgg:
@@ -4630,8 +4715,12 @@ func VisitCallable(in Callable, f Visit) error {
 		return VisitRefOfGeomFromWKBExpr(in, f)
 	case *GeomPropertyFuncExpr:
 		return VisitRefOfGeomPropertyFuncExpr(in, f)
+	case *GetFormatExpr:
+		return VisitRefOfGetFormatExpr(in, f)
 	case *GroupConcatExpr:
 		return VisitRefOfGroupConcatExpr(in, f)
+	case *GroupingFunc:
+		return VisitRefOfGroupingFunc(in, f)
 	case *InsertExpr:
 		return VisitRefOfInsertExpr(in, f)
 	case *IntervalDateExpr:
@@ -4914,8 +5003,12 @@ func VisitExpr(in Expr, f Visit) error {
 		return VisitRefOfGeomFromWKBExpr(in, f)
 	case *GeomPropertyFuncExpr:
 		return VisitRefOfGeomPropertyFuncExpr(in, f)
+	case *GetFormatExpr:
+		return VisitRefOfGetFormatExpr(in, f)
 	case *GroupConcatExpr:
 		return VisitRefOfGroupConcatExpr(in, f)
+	case *GroupingFunc:
+		return VisitRefOfGroupingFunc(in, f)
 	case *InsertExpr:
 		return VisitRefOfInsertExpr(in, f)
 	case *IntervalDateExpr:
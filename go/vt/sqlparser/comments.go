@@ -55,6 +55,8 @@ const (
 	DirectiveConsolidator = "CONSOLIDATOR"
 	// DirectiveWorkloadName specifies the name of the client application workload issuing the query.
 	DirectiveWorkloadName = "WORKLOAD_NAME"
+	// DirectiveResultCache lets vtgate cache the results of a select query, invalidating them on changes to the tables it reads.
+	DirectiveResultCache = "RESULT_CACHE"
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
@@ -495,6 +497,18 @@ func (c *ParsedComments) Prepend(comment string) Comments {
 	return comments
 }
 
+// Append returns the comments followed by the given one, whose directives take precedence
+// over the ones of the comments.
+func (c *ParsedComments) Append(comment string) Comments {
+	if c == nil {
+		return Comments{comment}
+	}
+	comments := make(Comments, 0, len(c.comments)+1)
+	comments = append(comments, c.comments...)
+	comments = append(comments, comment)
+	return comments
+}
+
 // IsSet checks the directive map for the named directive and returns
 // true if the directive is set and has a true/false or 0/1 value
 func (d *CommentDirectives) IsSet(key string) bool {
@@ -554,6 +568,11 @@ func AllowScatterDirective(stmt Statement) bool {
 	return checkDirective(stmt, DirectiveAllowScatter)
 }
 
+// ResultCacheDirective returns true if the result cache directive is set to true in query.
+func ResultCacheDirective(stmt Statement) bool {
+	return checkDirective(stmt, DirectiveResultCache)
+}
+
 // ForeignKeyChecksState returns the state of foreign_key_checks variable if it is part of a SET_VAR optimizer hint in the comments.
 func ForeignKeyChecksState(stmt Statement) *bool {
 	cmt, ok := stmt.(Commented)
@@ -453,6 +453,28 @@ func TestIgnoreMaxMaxMemoryRowsDirective(t *testing.T) {
 	}
 }
 
+func TestResultCacheDirective(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{"select /*vt+ RESULT_CACHE */ * from users", true},
+		{"select /*vt+ RESULT_CACHE=1 */ * from users", true},
+		{"select /*vt+ RESULT_CACHE=0 */ * from users", false},
+		{"select * from users", false},
+		{"(select /*vt+ RESULT_CACHE */ * from users) union (select * from users)", true},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, _ := parser.Parse(test.query)
+			got := ResultCacheDirective(stmt)
+			assert.Equalf(t, test.expected, got, fmt.Sprintf("ResultCacheDirective(stmt) returned %v but expected %v", got, test.expected))
+		})
+	}
+}
+
 func TestConsolidator(t *testing.T) {
 	testCases := []struct {
 		query    string
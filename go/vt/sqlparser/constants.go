@@ -332,6 +332,12 @@ const (
 	LeadingTrimStr  = "leading"
 	TrailingTrimStr = "trailing"
 
+	// GetFormatType strings
+	GetFormatDateStr      = "date"
+	GetFormatTimeStr      = "time"
+	GetFormatDatetimeStr  = "datetime"
+	GetFormatTimestampStr = "timestamp"
+
 	// FrameUnitType strings
 	FrameRowsStr  = "rows"
 	FrameRangeStr = "range"
@@ -542,6 +548,14 @@ const (
 	TrailingTrimType
 )
 
+// Constants for Enum Type - GetFormatType
+const (
+	GetFormatDate GetFormatType = iota
+	GetFormatTime
+	GetFormatDatetime
+	GetFormatTimestamp
+)
+
 // Constants for Enum Type - TrimFuncType
 const (
 	NormalTrimType TrimFuncType = iota
@@ -301,6 +301,7 @@ var keywords = []keyword{
 	{"geomcollection", GEOMETRYCOLLECTION},
 	{"geometrycollection", GEOMETRYCOLLECTION},
 	{"get", UNUSED},
+	{"get_format", GET_FORMAT},
 	{"get_lock", GET_LOCK},
 	{"glength", ST_Length},
 	{"global", GLOBAL},
@@ -309,7 +310,7 @@ var keywords = []keyword{
 	{"gtid_subtract", GTID_SUBTRACT},
 	{"grant", UNUSED},
 	{"group", GROUP},
-	{"grouping", UNUSED},
+	{"grouping", GROUPING},
 	{"groups", UNUSED},
 	{"group_concat", GROUP_CONCAT},
 	{"hash", HASH},
@@ -327,7 +328,7 @@ var keywords = []keyword{
 	{"in", IN},
 	{"index", INDEX},
 	{"indexes", INDEXES},
-	{"infile", UNUSED},
+	{"infile", INFILE},
 	{"inout", UNUSED},
 	{"inner", INNER},
 	{"inplace", INPLACE},
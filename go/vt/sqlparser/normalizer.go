@@ -98,6 +98,8 @@ func (nz *normalizer) walkStatementDown(node, parent SQLNode) bool {
 		return false
 	case *ConvertType: // we should not rewrite the type description
 		return false
+	case *FuncExpr:
+		return nz.walkFuncExpr(node, nz.walkStatementDown, nz.walkStatementUp)
 	}
 	return nz.err == nil // only continue if we haven't found any errors
 }
@@ -133,10 +135,26 @@ func (nz *normalizer) walkDownSelect(node, parent SQLNode) bool {
 	case *ConvertType:
 		// we should not rewrite the type description
 		return false
+	case *FuncExpr:
+		return nz.walkFuncExpr(node, nz.walkDownSelect, nz.walkUpSelect)
 	}
 	return nz.err == nil // only continue if we haven't found any errors
 }
 
+// walkFuncExpr keeps the literal format of STR_TO_DATE out of normalization, since the type of its result
+// depends on the format. The string argument is still normalized using the given walk functions.
+// It returns true if the arguments of the function have to be walked.
+func (nz *normalizer) walkFuncExpr(node *FuncExpr, down func(node, parent SQLNode) bool, up ApplyFunc) bool {
+	if !node.Name.EqualString("str_to_date") || len(node.Exprs) != 2 {
+		return nz.err == nil
+	}
+	if _, isLiteral := node.Exprs[1].(*Literal); !isLiteral {
+		return nz.err == nil
+	}
+	node.Exprs[0] = SafeRewrite(node.Exprs[0], down, up).(Expr)
+	return false
+}
+
 // walkUpSelect normalizes the Literals in Select mode.
 func (nz *normalizer) walkUpSelect(cursor *Cursor) bool {
 	if nz.err != nil {
@@ -202,6 +202,20 @@ func TestNormalize(t *testing.T) {
 		outbv: map[string]*querypb.BindVariable{
 			"bv1": sqltypes.HexValBindVariable([]byte("x'7B7D'")),
 		},
+	}, {
+		// the format of str_to_date is kept, as the type of its result depends on it
+		in:      "select str_to_date('10:30', '%H:%i') from dual",
+		outstmt: "select str_to_date(:bv1 /* VARCHAR */, '%H:%i') from dual",
+		outbv: map[string]*querypb.BindVariable{
+			"bv1": sqltypes.StringBindVariable("10:30"),
+		},
+	}, {
+		// the format of str_to_date is kept in DMLs too
+		in:      "update a set d = str_to_date('2024-01-02', '%Y-%m-%d')",
+		outstmt: "update a set d = str_to_date(:bv1 /* VARCHAR */, '%Y-%m-%d')",
+		outbv: map[string]*querypb.BindVariable{
+			"bv1": sqltypes.StringBindVariable("2024-01-02"),
+		},
 	}, {
 		// Hex number values should work for DMLs
 		in:      "update a set foo = 0x12",
@@ -1154,6 +1154,11 @@ var (
 		input: "select /* order by asc */ 1 from t order by a asc",
 	}, {
 		input: "select a, b, c, count(*), sum(foo) from t group by a, b, c with rollup",
+	}, {
+		input: "select a, b, grouping(a, b), count(*) from t group by a, b with rollup having grouping(b) = 1 order by grouping(a) asc",
+	}, {
+		input:  "select GROUPING(a) from t group by a with rollup",
+		output: "select grouping(a) from t group by a with rollup",
 	}, {
 		input: "select /* order by desc */ 1 from t order by a desc",
 	}, {
@@ -1187,6 +1192,12 @@ var (
 	}, {
 		input:  "select /* TIMESTAMPDIFF */ TIMESTAMPDIFF(MINUTE, '2008-01-02', '2008-01-04') from t",
 		output: "select /* TIMESTAMPDIFF */ timestampdiff(minute, '2008-01-02', '2008-01-04') from t",
+	}, {
+		input:  "select /* GET_FORMAT */ GET_FORMAT(DATETIME, 'USA'), get_format(time, a), get_format(timestamp, 'ISO'), get_format(date, 'eur') from t",
+		output: "select /* GET_FORMAT */ get_format(datetime, 'USA'), get_format(time, a), get_format(timestamp, 'ISO'), get_format(date, 'eur') from t",
+	}, {
+		input:  "select get_format from t",
+		output: "select `get_format` from t",
 	}, {
 		input:  "select DATE_ADD(MIN(FROM_UNIXTIME(1673444922)),interval -DAYOFWEEK(MIN(FROM_UNIXTIME(1673444922)))+1 DAY)",
 		output: "select date_add(min(FROM_UNIXTIME(1673444922)), interval -DAYOFWEEK(min(FROM_UNIXTIME(1673444922))) + 1 day) from dual",
@@ -4710,12 +4721,53 @@ func TestLoadData(t *testing.T) {
 		"load data from s3 manifest 'x.txt'",
 		"load data from s3 file 'x.txt'",
 		"load data infile 'x.txt' into table 'c'",
-		"load data from s3 'x.txt' into table x"}
+		"load data from s3 'x.txt' into table x",
+		"load data concurrent local infile 'x.txt' into table t",
+		"load data local infile 'x.txt' into table t partition (p0)",
+		"load data local infile 'x.txt' into table t character set utf8mb4",
+		"load data local infile 'x.txt' into table t charset utf8mb4 fields terminated by ','",
+		"load data local infile 'x.txt' into table t (a, @b) set c = @b + 1",
+		"load data local infile 'x.txt' into table t (@a) set c = @a",
+		"load data local infile 'x.txt' into table t fields terminated by ',' ignore 1 lines (a, b) set c = now()"}
 
 	parser := NewTestParser()
 	for _, tcase := range validSQL {
-		_, err := parser.Parse(tcase)
+		tree, err := parser.Parse(tcase)
 		require.NoError(t, err)
+		// Statements that are not evaluated by vtgate are passed through as is.
+		load, ok := tree.(*Load)
+		require.True(t, ok)
+		require.False(t, load.Local)
+	}
+
+	localInfileSQL := []struct {
+		input, output string
+	}{{
+		input: "load data local infile 'x.txt' into table t",
+	}, {
+		input:  "LOAD DATA LOCAL INFILE 'x.txt' REPLACE INTO TABLE ks.t",
+		output: "load data local infile 'x.txt' replace into table ks.t",
+	}, {
+		input: "load data local infile 'x.txt' ignore into table t (a, b, c)",
+	}, {
+		input:  "load data local infile 'x.csv' into table t columns terminated by ',' optionally enclosed by '\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 rows (id, `name`)",
+		output: "load data local infile 'x.csv' into table t fields terminated by ',' optionally enclosed by '\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 lines (id, `name`)",
+	}, {
+		input:  "load data local infile 'x.csv' into table t fields enclosed by '\"' terminated by ';' lines terminated by '\\n' starting by '>'",
+		output: "load data local infile 'x.csv' into table t fields terminated by ';' enclosed by '\"' lines starting by '>' terminated by '\\n'",
+	}}
+	for _, tcase := range localInfileSQL {
+		t.Run(tcase.input, func(t *testing.T) {
+			if tcase.output == "" {
+				tcase.output = tcase.input
+			}
+			tree, err := parser.Parse(tcase.input)
+			require.NoError(t, err)
+			load, ok := tree.(*Load)
+			require.True(t, ok)
+			require.True(t, load.Local)
+			require.Equal(t, tcase.output, String(load))
+		})
 	}
 }
 
@@ -101,6 +101,9 @@ func markBindVariable(yylex yyLexer, bvar string) {
   showFilter    *ShowFilter
   optLike       *OptLike
   selectInto	  *SelectInto
+  load          *Load
+  loadFields    *LoadFields
+  loadLines     *LoadLines
   createDatabase  *CreateDatabase
   alterDatabase  *AlterDatabase
   createTable      *CreateTable
@@ -116,6 +119,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
   revertMigration *RevertMigration
   alterMigration  *AlterMigration
   trimType        TrimType
+  getFormatType   GetFormatType
   frameClause     *FrameClause
   framePoint 	  *FramePoint
   frameUnitType   FrameUnitType
@@ -249,7 +253,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> DISTINCT AS EXISTS ASC DESC INTO DUPLICATE DEFAULT SET LOCK UNLOCK KEYS DO CALL
 %left <str> ALL ANY SOME
 %token <str> DISTINCTROW PARSER GENERATED ALWAYS
-%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED
+%token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED INFILE
 %token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY
 %token <str> VALUES LAST_INSERT_ID
 %token <str> NEXT VALUE SHARE MODE
@@ -365,7 +369,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> CONVERT CAST
 %token <str> SUBSTR SUBSTRING MID
 %token <str> SEPARATOR
-%token <str> TIMESTAMPADD TIMESTAMPDIFF
+%token <str> TIMESTAMPADD TIMESTAMPDIFF GET_FORMAT
 %token <str> WEIGHT_STRING
 %token <str> LTRIM RTRIM TRIM
 %token <str> JSON_ARRAY JSON_OBJECT JSON_QUOTE
@@ -449,6 +453,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <explainType> explain_format_opt
 %type <vexplainType> vexplain_type_opt
 %type <trimType> trim_type
+%type <getFormatType> get_format_type
 %type <frameUnitType> frame_units
 %type <argumentLessWindowExprType> argument_less_window_expr_type
 %type <framePoint> frame_point
@@ -525,6 +530,12 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <orderDirection> asc_desc_opt
 %type <limit> limit_opt limit_clause
 %type <selectInto> into_clause
+%type <load> load_local_prefix
+%type <loadFields> load_fields_opt load_fields_opt_list
+%type <loadLines> load_lines_opt load_lines_opt_list
+%type <literal> load_ignore_lines_opt
+%type <insertAction> load_action_opt
+%type <columns> load_columns_opt
 %type <columnTypeOptions> column_attribute_list_opt generated_column_attribute_list_opt
 %type <str> header_opt export_options manifest_opt overwrite_opt format_opt optionally_opt regexp_symbol
 %type <str> fields_opts fields_opt_list fields_opt lines_opts lines_opt lines_opt_list
@@ -708,12 +719,167 @@ do_statement:
     $$ = &OtherAdmin{}
   }
 
+// Only LOAD DATA LOCAL INFILE is parsed, the other forms are skipped. The
+// token following DATA is consumed before skipping, as the parser needs it
+// as lookahead to tell the forms apart. LOCAL statements using clauses that
+// vtgate cannot evaluate itself (PARTITION, CHARACTER SET, user variables in
+// the column list and SET) are skipped as well, so they are still passed
+// through to unsharded or targeted keyspaces.
 load_statement:
-  LOAD DATA skip_to_end
+  LOAD DATA FROM skip_to_end
+  {
+    $$ = &Load{}
+  }
+| LOAD DATA INFILE skip_to_end
+  {
+    $$ = &Load{}
+  }
+| LOAD DATA LOW_PRIORITY skip_to_end
+  {
+    $$ = &Load{}
+  }
+| LOAD DATA ID skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix load_fields_opt load_lines_opt load_ignore_lines_opt load_columns_opt
+  {
+    $1.Fields = $2
+    $1.Lines = $3
+    $1.IgnoreLines = $4
+    $1.Columns = $5
+    $$ = $1
+  }
+| load_local_prefix PARTITION skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix CHARACTER skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix CHARSET skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix load_fields_opt load_lines_opt load_ignore_lines_opt load_columns_opt SET skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix load_fields_opt load_lines_opt load_ignore_lines_opt openb AT_ID skip_to_end
+  {
+    $$ = &Load{}
+  }
+| load_local_prefix load_fields_opt load_lines_opt load_ignore_lines_opt openb ins_column_list ',' AT_ID skip_to_end
   {
     $$ = &Load{}
   }
 
+load_local_prefix:
+  LOAD DATA LOCAL INFILE STRING load_action_opt ignore_opt INTO TABLE table_name
+  {
+    $$ = &Load{Local: true, FileName: NewStrLiteral($5), Action: $6, Ignore: $7, Table: $10}
+  }
+
+load_action_opt:
+  {
+    $$ = InsertAct
+  }
+| REPLACE
+  {
+    $$ = ReplaceAct
+  }
+
+load_fields_opt:
+  {
+    $$ = nil
+  }
+| columns_or_fields load_fields_opt_list
+  {
+    $$ = $2
+  }
+
+load_fields_opt_list:
+  TERMINATED BY STRING
+  {
+    $$ = &LoadFields{TerminatedBy: NewStrLiteral($3)}
+  }
+| optionally_opt ENCLOSED BY STRING
+  {
+    $$ = &LoadFields{Optionally: $1 != "", EnclosedBy: NewStrLiteral($4)}
+  }
+| ESCAPED BY STRING
+  {
+    $$ = &LoadFields{EscapedBy: NewStrLiteral($3)}
+  }
+| load_fields_opt_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_fields_opt_list optionally_opt ENCLOSED BY STRING
+  {
+    $1.Optionally = $2 != ""
+    $1.EnclosedBy = NewStrLiteral($5)
+    $$ = $1
+  }
+| load_fields_opt_list ESCAPED BY STRING
+  {
+    $1.EscapedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_lines_opt:
+  {
+    $$ = nil
+  }
+| LINES load_lines_opt_list
+  {
+    $$ = $2
+  }
+
+load_lines_opt_list:
+  STARTING BY STRING
+  {
+    $$ = &LoadLines{StartingBy: NewStrLiteral($3)}
+  }
+| TERMINATED BY STRING
+  {
+    $$ = &LoadLines{TerminatedBy: NewStrLiteral($3)}
+  }
+| load_lines_opt_list STARTING BY STRING
+  {
+    $1.StartingBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_lines_opt_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_ignore_lines_opt:
+  {
+    $$ = nil
+  }
+| IGNORE INTEGRAL LINES
+  {
+    $$ = NewIntLiteral($2)
+  }
+| IGNORE INTEGRAL ROWS
+  {
+    $$ = NewIntLiteral($2)
+  }
+
+load_columns_opt:
+  {
+    $$ = nil
+  }
+| openb ins_column_list closeb
+  {
+    $$ = $2
+  }
+
 with_clause:
   WITH with_list
   {
@@ -5572,6 +5738,24 @@ trim_type:
     $$ = TrailingTrimType
   }
 
+get_format_type:
+  DATE
+  {
+    $$ = GetFormatDate
+  }
+| TIME
+  {
+    $$ = GetFormatTime
+  }
+| DATETIME
+  {
+    $$ = GetFormatDatetime
+  }
+| TIMESTAMP
+  {
+    $$ = GetFormatTimestamp
+  }
+
 frame_units:
   ROWS
   {
@@ -6058,6 +6242,10 @@ UTC_DATE func_paren_opt
   {
     $$ = &AnyValue{Arg:$3}
   }
+| GROUPING openb expression_list closeb
+  {
+    $$ = &GroupingFunc{Exprs:$3}
+  }
 | TIMESTAMPADD openb timestampadd_interval ',' expression ',' expression closeb
   {
     $$ = &IntervalDateExpr{Syntax: IntervalDateExprTimestampadd, Date: $7, Interval: $5, Unit: $3}
@@ -6066,6 +6254,10 @@ UTC_DATE func_paren_opt
   {
     $$ = &TimestampDiffExpr{Unit:$3, Expr1:$5, Expr2:$7}
   }
+| GET_FORMAT openb get_format_type ',' expression closeb
+  {
+    $$ = &GetFormatExpr{Type:$3, Expr:$5}
+  }
 | EXTRACT openb interval FROM expression closeb
   {
     $$ = &ExtractFuncExpr{IntervalType: $3, Expr: $5}
@@ -8299,6 +8491,7 @@ non_reserved_keyword:
 | GEOMCOLLECTION
 | GEOMETRY
 | GEOMETRYCOLLECTION
+| GET_FORMAT %prec FUNCTION_CALL_NON_KEYWORD
 | GET_LOCK %prec FUNCTION_CALL_NON_KEYWORD
 | GET_MASTER_PUBLIC_KEY
 | GLOBAL
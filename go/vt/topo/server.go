@@ -82,6 +82,7 @@ const (
 	ShardRoutingRulesFile  = "ShardRoutingRules"
 	CommonRoutingRulesFile = "Rules"
 	MirrorRulesFile        = "MirrorRules"
+	PlanBaselinesFile      = "PlanBaselines"
 )
 
 // Path for all object types.
@@ -216,6 +216,12 @@ func (ts *Server) RebuildSrvVSchema(ctx context.Context, cells []string) error {
 	}
 	srvVSchema.MirrorRules = mr
 
+	pb, err := ts.GetPlanBaselines(ctx)
+	if err != nil {
+		return fmt.Errorf("GetPlanBaselines failed: %v", err)
+	}
+	srvVSchema.PlanBaselines = pb
+
 	// now save the SrvVSchema in all cells in parallel
 	for _, cell := range cells {
 		wg.Add(1)
@@ -30,6 +30,7 @@ import (
 func TestRebuildVSchema(t *testing.T) {
 	emptySrvVSchema := &vschemapb.SrvVSchema{
 		MirrorRules:       &vschemapb.MirrorRules{},
+		PlanBaselines:     &vschemapb.PlanBaselines{},
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
 	}
@@ -54,6 +55,7 @@ func TestRebuildVSchema(t *testing.T) {
 	// create a keyspace, rebuild, should see an empty entry
 	emptyKs1SrvVSchema := &vschemapb.SrvVSchema{
 		MirrorRules:       &vschemapb.MirrorRules{},
+		PlanBaselines:     &vschemapb.PlanBaselines{},
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -84,6 +86,7 @@ func TestRebuildVSchema(t *testing.T) {
 	}
 	wanted1 := &vschemapb.SrvVSchema{
 		MirrorRules:       &vschemapb.MirrorRules{},
+		PlanBaselines:     &vschemapb.PlanBaselines{},
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -126,6 +129,7 @@ func TestRebuildVSchema(t *testing.T) {
 	}
 	wanted2 := &vschemapb.SrvVSchema{
 		MirrorRules:       &vschemapb.MirrorRules{},
+		PlanBaselines:     &vschemapb.PlanBaselines{},
 		RoutingRules:      &vschemapb.RoutingRules{},
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -165,6 +169,7 @@ func TestRebuildVSchema(t *testing.T) {
 	}
 	wanted3 := &vschemapb.SrvVSchema{
 		MirrorRules:       &vschemapb.MirrorRules{},
+		PlanBaselines:     &vschemapb.PlanBaselines{},
 		RoutingRules:      rr,
 		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
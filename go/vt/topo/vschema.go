@@ -248,3 +248,38 @@ func (ts *Server) SaveMirrorRules(ctx context.Context, mirrorRules *vschemapb.Mi
 	_, err = ts.globalCell.Update(ctx, MirrorRulesFile, data, nil)
 	return err
 }
+
+// GetPlanBaselines fetches the query plan baselines from the topo.
+func (ts *Server) GetPlanBaselines(ctx context.Context) (*vschemapb.PlanBaselines, error) {
+	pb := &vschemapb.PlanBaselines{}
+	data, _, err := ts.globalCell.Get(ctx, PlanBaselinesFile)
+	if err != nil {
+		if IsErrType(err, NoNode) {
+			return pb, nil
+		}
+		return nil, err
+	}
+	err = pb.UnmarshalVT(data)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "bad plan baselines data: %q", data)
+	}
+	return pb, nil
+}
+
+// SavePlanBaselines saves the query plan baselines into the topo.
+func (ts *Server) SavePlanBaselines(ctx context.Context, planBaselines *vschemapb.PlanBaselines) error {
+	data, err := planBaselines.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		if err := ts.globalCell.Delete(ctx, PlanBaselinesFile, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+		return nil
+	}
+
+	_, err = ts.globalCell.Update(ctx, PlanBaselinesFile, data, nil)
+	return err
+}
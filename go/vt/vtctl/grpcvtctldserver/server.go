@@ -5382,3 +5382,88 @@ func (s *VtctldServer) GetKeyspaceRoutingRules(ctx context.Context, req *vtctlda
 		KeyspaceRoutingRules: rules,
 	}, nil
 }
+
+// ApplyPlanBaselines is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyPlanBaselines(ctx context.Context, req *vtctldatapb.ApplyPlanBaselinesRequest) (resp *vtctldatapb.ApplyPlanBaselinesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyPlanBaselines")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	if err := s.validatePlanBaselines(ctx, req.PlanBaselines); err != nil {
+		return nil, err
+	}
+
+	if err := s.ts.SavePlanBaselines(ctx, req.PlanBaselines); err != nil {
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ApplyPlanBaselinesResponse{}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema as requested, you will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err := s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		return nil, vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+	}
+
+	return resp, nil
+}
+
+// validatePlanBaselines checks that every baseline is for a query that parses, that its comments are
+// query directives, and that its target is in an existing keyspace.
+func (s *VtctldServer) validatePlanBaselines(ctx context.Context, planBaselines *vschemapb.PlanBaselines) error {
+	queries := make(map[string]bool, len(planBaselines.GetBaselines()))
+	for _, baseline := range planBaselines.GetBaselines() {
+		if baseline.Query == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline must have a query")
+		}
+		if queries[baseline.Query] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate plan baseline for query: %s", baseline.Query)
+		}
+		queries[baseline.Query] = true
+		if _, err := s.ws.SQLParser().Parse(baseline.Query); err != nil {
+			return vterrors.Wrapf(err, "invalid plan baseline query: %s", baseline.Query)
+		}
+		if baseline.Comments == "" && baseline.Target == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline for query %s must have comments or a target", baseline.Query)
+		}
+		if baseline.Comments != "" {
+			if !strings.HasPrefix(baseline.Comments, "/*vt+") || !strings.HasSuffix(baseline.Comments, "*/") {
+				return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "plan baseline comments must be a /*vt+ ... */ directive comment: %s", baseline.Comments)
+			}
+		}
+		if baseline.Target != "" {
+			keyspace, _, _, err := topoproto.ParseDestination(baseline.Target, topodatapb.TabletType_PRIMARY)
+			if err != nil {
+				return vterrors.Wrapf(err, "invalid plan baseline target: %s", baseline.Target)
+			}
+			if _, err := s.ts.GetKeyspace(ctx, keyspace); err != nil {
+				return vterrors.Wrapf(err, "invalid plan baseline target: %s", baseline.Target)
+			}
+		}
+	}
+	return nil
+}
+
+// GetPlanBaselines is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetPlanBaselines(ctx context.Context, req *vtctldatapb.GetPlanBaselinesRequest) (resp *vtctldatapb.GetPlanBaselinesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetPlanBaselines")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	pb, err := s.ts.GetPlanBaselines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetPlanBaselinesResponse{
+		PlanBaselines: pb,
+	}, nil
+}
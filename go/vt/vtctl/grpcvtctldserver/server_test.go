@@ -345,6 +345,118 @@ func TestApplyRoutingRules(t *testing.T) {
 	}
 }
 
+func TestApplyPlanBaselines(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tests := []struct {
+		name              string
+		req               *vtctldatapb.ApplyPlanBaselinesRequest
+		expectedBaselines *vschemapb.PlanBaselines
+		shouldErr         bool
+	}{
+		{
+			name: "success",
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{
+						{
+							Query:    "select * from t1 join t2 on t1.id = t2.id where t1.a = :t1_a /* INT64 */",
+							Comments: "/*vt+ ALLOW_HASH_JOIN */",
+						},
+						{
+							Query:  "select * from t1",
+							Target: "ks1:-80",
+						},
+					},
+				},
+			},
+			expectedBaselines: &vschemapb.PlanBaselines{
+				Baselines: []*vschemapb.PlanBaseline{
+					{
+						Query:    "select * from t1 join t2 on t1.id = t2.id where t1.a = :t1_a /* INT64 */",
+						Comments: "/*vt+ ALLOW_HASH_JOIN */",
+					},
+					{
+						Query:  "select * from t1",
+						Target: "ks1:-80",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid query",
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: "select * frm t1", Comments: "/*vt+ ALLOW_HASH_JOIN */"}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "duplicate query",
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{
+						{Query: "select * from t1", Comments: "/*vt+ ALLOW_HASH_JOIN */"},
+						{Query: "select * from t1", Target: "ks1"},
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "comments are not directives",
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: "select * from t1", Comments: "/* ALLOW_HASH_JOIN */"}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown target keyspace",
+			req: &vtctldatapb.ApplyPlanBaselinesRequest{
+				PlanBaselines: &vschemapb.PlanBaselines{
+					Baselines: []*vschemapb.PlanBaseline{{Query: "select * from t1", Target: "ks2:-80"}},
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := memorytopo.NewServer(ctx, "zone1")
+			require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ApplyPlanBaselines(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err, "ApplyPlanBaselines(%+v) failed", tt.req)
+
+			pb, err := ts.GetPlanBaselines(ctx)
+			require.NoError(t, err, "failed to get plan baselines from topo to compare")
+			utils.MustMatch(t, tt.expectedBaselines, pb)
+
+			srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expectedBaselines, srvVSchema.PlanBaselines)
+
+			resp, err := vtctld.GetPlanBaselines(ctx, &vtctldatapb.GetPlanBaselinesRequest{})
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expectedBaselines, resp.PlanBaselines)
+		})
+	}
+}
+
 func TestApplyVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -668,6 +780,9 @@ func TestApplyVSchema(t *testing.T) {
 					MirrorRules: &vschemapb.MirrorRules{
 						Rules: []*vschemapb.MirrorRule{},
 					},
+					PlanBaselines: &vschemapb.PlanBaselines{
+						Baselines: []*vschemapb.PlanBaseline{},
+					},
 					RoutingRules: &vschemapb.RoutingRules{
 						Rules: []*vschemapb.RoutingRule{},
 					},
@@ -44,6 +44,11 @@ func (client *localVtctldClient) ApplyKeyspaceRoutingRules(ctx context.Context,
 	return client.s.ApplyKeyspaceRoutingRules(ctx, in)
 }
 
+// ApplyPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyPlanBaselines(ctx context.Context, in *vtctldatapb.ApplyPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyPlanBaselinesResponse, error) {
+	return client.s.ApplyPlanBaselines(ctx, in)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	return client.s.ApplyRoutingRules(ctx, in)
@@ -316,6 +321,11 @@ func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctlda
 	return client.s.GetPermissions(ctx, in)
 }
 
+// GetPlanBaselines is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetPlanBaselines(ctx context.Context, in *vtctldatapb.GetPlanBaselinesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetPlanBaselinesResponse, error) {
+	return client.s.GetPlanBaselines(ctx, in)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	return client.s.GetRoutingRules(ctx, in)
@@ -59,6 +59,15 @@ var (
 	VT03031 = errorWithoutState("VT03031", vtrpcpb.Code_INVALID_ARGUMENT, "EXPLAIN is only supported for single keyspace", "EXPLAIN has to be sent down as a single query to the underlying MySQL, and this is not possible if it uses tables from multiple keyspaces")
 	VT03032 = errorWithState("VT03032", vtrpcpb.Code_INVALID_ARGUMENT, NonUpdateableTable, "the target table %s of the UPDATE is not updatable", "You cannot update a table that is not a real MySQL table.")
 	VT03033 = errorWithState("VT03033", vtrpcpb.Code_INVALID_ARGUMENT, ViewWrongList, "In definition of view, derived table or common table expression, SELECT list and column names list have different column counts", "The table column list and derived column list have different column counts.")
+	VT03034 = errorWithState("VT03034", vtrpcpb.Code_INVALID_ARGUMENT, CTERecursiveRequiresUnion, "Recursive Common Table Expression '%s' should contain a UNION", "A recursive common table expression must be written as a UNION of non-recursive and recursive query blocks.")
+	VT03035 = errorWithState("VT03035", vtrpcpb.Code_INVALID_ARGUMENT, CTERecursiveRequiresNonRecursiveFirst, "Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones", "The query blocks that do not reference the common table expression must come before the ones that do.")
+	VT03036 = errorWithState("VT03036", vtrpcpb.Code_INVALID_ARGUMENT, CTERecursiveForbidsAggregation, "Recursive Common Table Expression '%s' can contain neither aggregation nor window functions in recursive query block", "The recursive query block of a common table expression cannot use aggregation, window functions or GROUP BY.")
+	VT03037 = errorWithState("VT03037", vtrpcpb.Code_INVALID_ARGUMENT, CTERecursiveForbiddenJoinOrder, "In recursive query block of Recursive Common Table Expression '%s', the recursive table must neither be in the right argument of a LEFT JOIN, nor be forced to be non-first with join order hints", "The recursive reference cannot be on the inner side of an outer join.")
+	VT03038 = errorWithState("VT03038", vtrpcpb.Code_INVALID_ARGUMENT, CTERecursiveRequiresSingleReference, "In recursive query block of Recursive Common Table Expression '%s', the recursive table must be referenced only once, and not in any subquery", "The recursive query block can only reference the common table expression once, and not from a subquery.")
+	VT03039 = errorWithoutState("VT03039", vtrpcpb.Code_INVALID_ARGUMENT, "Row %d doesn't contain data for all columns", "A row of the LOAD DATA input has fewer fields than the number of columns being loaded.")
+	VT03040 = errorWithoutState("VT03040", vtrpcpb.Code_INVALID_ARGUMENT, "Row %d was truncated; it contained more data than there were input columns", "A row of the LOAD DATA input has more fields than the number of columns being loaded.")
+	VT03041 = errorWithoutState("VT03041", vtrpcpb.Code_INVALID_ARGUMENT, "Field separator argument is not what is expected; check the manual", "FIELDS ENCLOSED BY and FIELDS ESCAPED BY of LOAD DATA only accept an empty string or a single character.")
+	VT03042 = errorWithoutState("VT03042", vtrpcpb.Code_INVALID_ARGUMENT, "Argument #%d of GROUPING function is not in GROUP BY", "Every argument of GROUPING() must be one of the GROUP BY expressions.")
 
 	VT05001 = errorWithState("VT05001", vtrpcpb.Code_NOT_FOUND, DbDropExists, "cannot drop database '%s'; database does not exists", "The given database does not exist; Vitess cannot drop it.")
 	VT05002 = errorWithState("VT05002", vtrpcpb.Code_NOT_FOUND, BadDb, "cannot alter database '%s'; unknown database", "The given database does not exist; Vitess cannot alter it.")
@@ -96,6 +105,7 @@ var (
 	VT09022 = errorWithoutState("VT09022", vtrpcpb.Code_FAILED_PRECONDITION, "Destination does not have exactly one shard: %v", "Cannot send query to multiple shards.")
 	VT09023 = errorWithoutState("VT09023", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a keyspace id", "Unable to determine the shard for the given row.")
 	VT09024 = errorWithoutState("VT09024", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a unique keyspace id: %v", "Unable to determine the shard for the given row.")
+	VT09025 = errorWithoutState("VT09025", vtrpcpb.Code_FAILED_PRECONDITION, "LOAD DATA LOCAL INFILE needs a client connected over the MySQL protocol", "The content of the file is requested from the client, which is only possible when it is connected to vtgate over the MySQL protocol.")
 
 	VT10001 = errorWithoutState("VT10001", vtrpcpb.Code_ABORTED, "foreign key constraints are not allowed", "Foreign key constraints are not allowed, see https://vitess.io/blog/2021-06-15-online-ddl-why-no-fk/.")
 
@@ -148,6 +158,15 @@ var (
 		VT03031,
 		VT03032,
 		VT03033,
+		VT03034,
+		VT03035,
+		VT03036,
+		VT03037,
+		VT03038,
+		VT03039,
+		VT03040,
+		VT03041,
+		VT03042,
 		VT05001,
 		VT05002,
 		VT05003,
@@ -181,6 +200,7 @@ var (
 		VT09022,
 		VT09023,
 		VT09024,
+		VT09025,
 		VT10001,
 		VT12001,
 		VT12002,
@@ -50,6 +50,11 @@ const (
 	BadNullError
 	InvalidGroupFuncUse
 	ViewWrongList
+	CTERecursiveRequiresUnion
+	CTERecursiveRequiresNonRecursiveFirst
+	CTERecursiveForbidsAggregation
+	CTERecursiveForbiddenJoinOrder
+	CTERecursiveRequiresSingleReference
 
 	// failed precondition
 	NoDB
@@ -77,6 +82,7 @@ const (
 
 	// resource exhausted
 	NetPacketTooLarge
+	CTEMaxRecursionDepth
 
 	// cancelled
 	QueryInterrupted
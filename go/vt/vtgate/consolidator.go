@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vthash"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// consolidator merges the executions of identical read-only queries running at the same time outside
+// of transactions, so that only the first of them is sent to the shards and the others wait for its result.
+type consolidator struct {
+	*sync2.ConsolidatorCache
+
+	mu      sync.Mutex
+	queries map[theine.HashKey256]*pendingQuery
+}
+
+// pendingQuery is the execution of a query that identical queries can wait for.
+type pendingQuery struct {
+	done   chan struct{}
+	result *sqltypes.Result
+	err    error
+}
+
+func newConsolidator() *consolidator {
+	return &consolidator{
+		ConsolidatorCache: sync2.NewConsolidatorCache(1000),
+		queries:           make(map[theine.HashKey256]*pendingQuery),
+	}
+}
+
+// create returns the pending execution of the query with the given key. It returns true if there was
+// none, in which case the caller must execute the query and broadcast its result.
+func (co *consolidator) create(key theine.HashKey256) (*pendingQuery, bool) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+	if pq, ok := co.queries[key]; ok {
+		return pq, false
+	}
+	pq := &pendingQuery{done: make(chan struct{})}
+	co.queries[key] = pq
+	return pq, true
+}
+
+// broadcast sends the result of the pending query to the queries waiting for it.
+func (co *consolidator) broadcast(key theine.HashKey256, pq *pendingQuery, qr *sqltypes.Result, err error) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+	delete(co.queries, key)
+	pq.result, pq.err = qr, err
+	close(pq.done)
+}
+
+// wait returns the result of the pending query once it is broadcast.
+func (pq *pendingQuery) wait(ctx context.Context) (*sqltypes.Result, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-pq.done:
+	}
+	if pq.err != nil {
+		return nil, pq.err
+	}
+	return pq.result.ShallowCopy(), nil
+}
+
+// consolidationKey returns the key under which the execution of the plan can be merged with the
+// identical ones, and false if it cannot be.
+func (e *Executor) consolidationKey(ctx context.Context, safeSession *SafeSession, plan *engine.Plan, vcursor *vcursorImpl, bindVars map[string]*querypb.BindVariable) (theine.HashKey256, bool) {
+	var key theine.HashKey256
+	if e.consolidator == nil || plan.Type != sqlparser.StmtSelect || !plan.Deterministic || plan.Instructions.NeedsTransaction() {
+		return key, false
+	}
+	if safeSession.InTransaction() || safeSession.InReservedConn() {
+		return key, false
+	}
+	hasher := vthash.New256()
+	if err := hashExecution(ctx, vcursor, plan, bindVars, hasher); err != nil {
+		return key, false
+	}
+	hasher.Sum(key[:0])
+	return key, true
+}
+
+// executeConsolidated executes the plan, unless an identical execution is in progress, in which case
+// it waits for its result.
+func (e *Executor) executeConsolidated(ctx context.Context, plan *engine.Plan, vcursor *vcursorImpl, bindVars map[string]*querypb.BindVariable, key theine.HashKey256) (qr *sqltypes.Result, err error) {
+	pq, original := e.consolidator.create(key)
+	if !original {
+		e.consolidator.Record(plan.Original)
+		defer consolidatorWaits.Record(vcursor.keyspace, time.Now())
+		return pq.wait(ctx)
+	}
+	defer func() {
+		if qr == nil && err == nil {
+			// the execution panicked, the waiting queries must not be left without a result.
+			e.consolidator.broadcast(key, pq, nil, vterrors.VT13001("consolidated query did not complete"))
+			return
+		}
+		e.consolidator.broadcast(key, pq, qr, err)
+	}()
+	return vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+}
+
+// enableConsolidator merges the executions of the identical read-only queries running at the same time.
+func (e *Executor) enableConsolidator() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.consolidator = newConsolidator()
+}
+
+// writeConsolidations lists the most recent consolidated queries and how many times they were consolidated.
+func (e *Executor) writeConsolidations(response http.ResponseWriter) {
+	response.Header().Set("Content-Type", "text/plain")
+	if e.consolidator == nil {
+		_, _ = response.Write([]byte("empty\n"))
+		return
+	}
+	items := e.consolidator.Items()
+	if items == nil {
+		_, _ = response.Write([]byte("empty\n"))
+		return
+	}
+	_, _ = response.Write([]byte(fmt.Sprintf("Length: %d\n", len(items))))
+	for _, v := range items {
+		var query string
+		if streamlog.GetRedactDebugUIQueries() {
+			query, _ = e.env.Parser().RedactSQLQuery(v.Query)
+		} else {
+			query = v.Query
+		}
+		_, _ = response.Write([]byte(fmt.Sprintf("%v: %s\n", v.Count, query)))
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestConsolidator(t *testing.T) {
+	co := newConsolidator()
+	key := theine.HashKey256{1}
+
+	pq, original := co.create(key)
+	require.True(t, original)
+
+	var wg sync.WaitGroup
+	results := make([]*sqltypes.Result, 3)
+	for i := range results {
+		waiter, original := co.create(key)
+		require.False(t, original)
+		require.Equal(t, pq, waiter)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			qr, err := waiter.wait(context.Background())
+			assert.NoError(t, err)
+			results[i] = qr
+		}(i)
+	}
+
+	qr := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+	co.broadcast(key, pq, qr, nil)
+	wg.Wait()
+	for _, result := range results {
+		assert.Equal(t, qr, result)
+	}
+
+	// the next execution is not merged with the one that completed.
+	pq, original = co.create(key)
+	require.True(t, original)
+	waiter, _ := co.create(key)
+	co.broadcast(key, pq, nil, errors.New("failed"))
+	_, err := waiter.wait(context.Background())
+	assert.EqualError(t, err, "failed")
+
+	// a waiting query stops waiting when its context is done.
+	_, original = co.create(key)
+	require.True(t, original)
+	waiter, _ = co.create(key)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = waiter.wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConsolidatorExecute(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.enableConsolidator()
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	query := "select id from main1 where id = 1"
+	bindVars := map[string]*querypb.BindVariable{}
+
+	vcursor, err := newVCursorImpl(NewSafeSession(session), makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, executor.serv, false, executor.pv)
+	require.NoError(t, err)
+	stmt, reservedVars, err := parseAndValidateQuery(query, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	plan, err := executor.getPlan(ctx, vcursor, query, stmt, makeComments(""), bindVars, reservedVars, executor.normalize, logstats.NewLogStats(ctx, "Test", "", "", nil))
+	require.NoError(t, err)
+	key, ok := executor.consolidationKey(ctx, NewSafeSession(session), plan, vcursor, bindVars)
+	require.True(t, ok)
+
+	// an identical query in progress.
+	pq, original := executor.consolidator.create(key)
+	require.True(t, original)
+
+	before := sbclookup.ExecCount.Load()
+	done := make(chan *sqltypes.Result)
+	go func() {
+		qr, err := executorExec(ctx, executor, session, query, nil)
+		assert.NoError(t, err)
+		done <- qr
+	}()
+	assert.Eventually(t, func() bool {
+		return len(executor.consolidator.Items()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	executor.consolidator.broadcast(key, pq, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "42"), nil)
+	qr := <-done
+	assert.Equal(t, `[[INT64(42)]]`, fmt.Sprintf("%v", qr.Rows))
+	assert.EqualValues(t, before, sbclookup.ExecCount.Load())
+
+	// queries in a transaction are not consolidated.
+	txSession := NewSafeSession(&vtgatepb.Session{TargetString: "@primary", InTransaction: true})
+	_, ok = executor.consolidationKey(ctx, txSession, plan, vcursor, bindVars)
+	assert.False(t, ok)
+
+	// queries of sessions with other system variables are not merged.
+	tzSession := NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true})
+	tzSession.SetSystemVariable("time_zone", "'+08:00'")
+	tzVCursor, err := newVCursorImpl(tzSession, makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, executor.serv, false, executor.pv)
+	require.NoError(t, err)
+	tzKey, ok := executor.consolidationKey(ctx, tzSession, plan, tzVCursor, bindVars)
+	require.True(t, ok)
+	assert.NotEqual(t, key, tzKey)
+
+	response := httptest.NewRecorder()
+	executor.ServeHTTP(response, httptest.NewRequest("GET", pathConsolidations, nil))
+	assert.Equal(t, "Length: 1\n1: select id from main1 where id = 1\n", response.Body.String())
+}
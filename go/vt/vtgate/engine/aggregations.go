@@ -18,17 +18,22 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/colldata"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/sysvars"
 	"vitess.io/vitess/go/vt/vterrors"
 	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vthash"
 )
 
 // AggregateParams specify the parameters for each aggregation.
@@ -50,6 +55,22 @@ type AggregateParams struct {
 	// not what we use to aggregate at the engine primitive level.
 	OrigOpcode AggregateOpcode
 
+	// GroupingKeys is used only for the grouping opcode. It holds, for every
+	// argument of GROUPING(), the index of the matching GroupByKeys entry.
+	GroupingKeys []int `json:",omitempty"`
+
+	// DistinctCols is used only for distinct opcodes and GROUP_CONCAT(DISTINCT ...), when the input is not
+	// sorted on the distinct values. It holds the columns of all the arguments, and the values already
+	// aggregated in a group are kept in a hash set instead of comparing them to the previous row.
+	DistinctCols []CheckCol `json:",omitempty"`
+
+	// ArgCols is used only for the group_concat and json_objectagg opcodes, when the whole aggregation
+	// is evaluated at the vtgate level. It holds the columns of all the arguments.
+	// GroupConcatOrderBy is used only for the group_concat opcode: the rows of a group are sorted
+	// using it before being concatenated.
+	ArgCols            []int                 `json:",omitempty"`
+	GroupConcatOrderBy evalengine.Comparison `json:",omitempty"`
+
 	CollationEnv *collations.Environment
 }
 
@@ -76,9 +97,20 @@ func (ap *AggregateParams) String() string {
 	if ap.WAssigned() {
 		keyCol = fmt.Sprintf("%s|%d", keyCol, ap.WCol)
 	}
-	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
+	switch {
+	case len(ap.DistinctCols) > 0:
+		keyCol = strings.Join(slice.Map(ap.DistinctCols, func(col CheckCol) string { return col.String() }), ", ")
+		if ap.Opcode == AggregateGroupConcat {
+			keyCol = "distinct " + keyCol
+		}
+	case len(ap.ArgCols) > 0:
+		keyCol = strings.Join(slice.Map(ap.ArgCols, strconv.Itoa), ", ")
+	case sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()):
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if len(ap.GroupConcatOrderBy) > 0 {
+		keyCol += " order by " + strings.Join(slice.Map(ap.GroupConcatOrderBy, func(obp evalengine.OrderByParams) string { return obp.String() }), ", ")
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -102,15 +134,40 @@ type aggregator interface {
 	reset()
 }
 
+// spillingAggregator is implemented by the aggregators that spill the rows of a group to disk
+// when the values they keep in memory exceed the memory budget of the query.
+type spillingAggregator interface {
+	// flush aggregates the rows of the group that were spilled to disk
+	flush() error
+	// close removes the rows spilled to disk and gives back the memory used by the aggregator
+	close()
+}
+
 type aggregatorDistinct struct {
 	column       int
 	last         sqltypes.Value
 	coll         collations.ID
 	collationEnv *collations.Environment
 	values       *evalengine.EnumSetValues
+
+	// seen is used instead of last when the input is not sorted on the distinct values.
+	// Once the values seen exceed the memory budget of the query, the new rows of the group
+	// are spilled to disk and only aggregated when the group is finished.
+	seen     *probeTable
+	mem      *memoryTracker
+	spillDir string
+	spill    *spillPartitionSet
 }
 
 func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
+	if a.seen != nil {
+		for _, col := range a.seen.checkCols {
+			if row[col.Col].IsNull() {
+				return true, nil
+			}
+		}
+		return a.seenBefore(row)
+	}
 	if a.column >= 0 {
 		last := a.last
 		next := row[a.column]
@@ -130,8 +187,87 @@ func (a *aggregatorDistinct) shouldReturn(row []sqltypes.Value) (bool, error) {
 	return false, nil
 }
 
+// seenBefore returns true when the distinct values of the row have already been aggregated in the group,
+// or when the row has been spilled to disk to be aggregated once the group is finished.
+func (a *aggregatorDistinct) seenBefore(row []sqltypes.Value) (bool, error) {
+	code, err := a.seen.hashCodeForRow(row)
+	if err != nil {
+		return true, err
+	}
+	if _, found := a.seen.seenRows[code]; found {
+		return true, nil
+	}
+	if a.spill != nil {
+		return true, a.spill.write(code, row)
+	}
+	a.seen.seenRows[code] = struct{}{}
+	if !a.mem.add(seenRowSize) {
+		a.spill, err = newSpillPartitionSet(a.spillDir)
+		if err != nil {
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+// flush calls aggregate for every distinct row of the group that was spilled to disk. The spilled rows
+// have not been seen before, and equal rows are always in the same partition, so each partition can be
+// deduplicated on its own.
+func (a *aggregatorDistinct) flush(aggregate func(row []sqltypes.Value) error) error {
+	if a.spill == nil {
+		return nil
+	}
+	for _, file := range a.spill.files {
+		a.seen.seenRows = make(map[vthash.Hash]struct{})
+		err := file.forEach(func(row sqltypes.Row) error {
+			newRow, err := a.seen.exists(row)
+			if err != nil || newRow == nil {
+				return err
+			}
+			return aggregate(newRow)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *aggregatorDistinct) close() {
+	if a.seen == nil {
+		return
+	}
+	if a.spill != nil {
+		a.spill.close()
+		a.spill = nil
+	}
+	a.mem.releaseAll()
+}
+
 func (a *aggregatorDistinct) reset() {
 	a.last = sqltypes.NULL
+	if a.seen != nil {
+		a.seen.seenRows = make(map[vthash.Hash]struct{})
+		a.close()
+	}
+}
+
+func newAggregatorDistinct(vcursor VCursor, aggr *AggregateParams, column int) aggregatorDistinct {
+	distinct := aggregatorDistinct{
+		column:       column,
+		coll:         aggr.Type.Collation(),
+		collationEnv: aggr.CollationEnv,
+		values:       aggr.Type.Values(),
+	}
+	if len(aggr.DistinctCols) > 0 {
+		distinct.seen = newProbeTable(aggr.DistinctCols, aggr.CollationEnv)
+		distinct.mem = &memoryTracker{}
+		if vcursor != nil {
+			distinct.mem = newMemoryTracker(vcursor)
+			distinct.spillDir = vcursor.SpillDir()
+		}
+	}
+	return distinct
 }
 
 type aggregatorCount struct {
@@ -151,6 +287,13 @@ func (a *aggregatorCount) add(row []sqltypes.Value) error {
 	return nil
 }
 
+func (a *aggregatorCount) flush() error {
+	return a.distinct.flush(func([]sqltypes.Value) error {
+		a.n++
+		return nil
+	})
+}
+
 func (a *aggregatorCount) finish() sqltypes.Value {
 	return sqltypes.NewInt64(a.n)
 }
@@ -160,6 +303,10 @@ func (a *aggregatorCount) reset() {
 	a.distinct.reset()
 }
 
+func (a *aggregatorCount) close() {
+	a.distinct.close()
+}
+
 type aggregatorCountStar struct {
 	n int64
 }
@@ -222,6 +369,12 @@ func (a *aggregatorSum) add(row []sqltypes.Value) error {
 	return a.sum.Add(row[a.from])
 }
 
+func (a *aggregatorSum) flush() error {
+	return a.distinct.flush(func(row []sqltypes.Value) error {
+		return a.sum.Add(row[a.from])
+	})
+}
+
 func (a *aggregatorSum) finish() sqltypes.Value {
 	return a.sum.Result()
 }
@@ -231,6 +384,54 @@ func (a *aggregatorSum) reset() {
 	a.distinct.reset()
 }
 
+func (a *aggregatorSum) close() {
+	a.distinct.close()
+}
+
+type aggregatorBitwise struct {
+	from    int
+	bitwise evalengine.Bitwise
+}
+
+func (a *aggregatorBitwise) add(row []sqltypes.Value) error {
+	return a.bitwise.Add(row[a.from])
+}
+
+func (a *aggregatorBitwise) finish() sqltypes.Value {
+	return a.bitwise.Result()
+}
+
+func (a *aggregatorBitwise) reset() {
+	a.bitwise.Reset()
+}
+
+// aggregatorJSON implements JSON_ARRAYAGG and JSON_OBJECTAGG. When merge is set, the
+// input is made of the JSON documents aggregated by the shards, which are merged together.
+type aggregatorJSON struct {
+	cols  []int
+	merge bool
+	json  evalengine.JSONAggregation
+}
+
+func (a *aggregatorJSON) add(row []sqltypes.Value) error {
+	if a.merge {
+		return a.json.Merge(row[a.cols[0]])
+	}
+	values := make([]sqltypes.Value, 0, len(a.cols))
+	for _, col := range a.cols {
+		values = append(values, row[col])
+	}
+	return a.json.Add(values...)
+}
+
+func (a *aggregatorJSON) finish() sqltypes.Value {
+	return a.json.Result()
+}
+
+func (a *aggregatorJSON) reset() {
+	a.json.Reset()
+}
+
 type aggregatorScalar struct {
 	from    int
 	current sqltypes.Value
@@ -255,38 +456,135 @@ func (a *aggregatorScalar) reset() {
 }
 
 type aggregatorGroupConcat struct {
-	from      int
 	type_     sqltypes.Type
 	separator []byte
+	maxLen    int
+	// charset is the character set of the result, which is cut to maxLen bytes without splitting a character
+	charset colldata.Charset
+
+	// cols, orderBy and distinct are set when the whole GROUP_CONCAT is evaluated at the vtgate level.
+	// When ordering, the rows of the group are kept until the group is finished, and then sorted.
+	cols     []int
+	orderBy  evalengine.Comparison
+	distinct aggregatorDistinct
+	rows     []sqltypes.Row
 
 	concat []byte
 	n      int
 }
 
-func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
-	if row[a.from].IsNull() {
+func (a *aggregatorGroupConcat) add(row []sqltypes.Value) (err error) {
+	for _, col := range a.cols {
+		if row[col].IsNull() {
+			return nil
+		}
+	}
+	if ret, err := a.distinct.shouldReturn(row); ret {
+		return err
+	}
+	return a.aggregate(row)
+}
+
+// aggregate adds a row of the group that is not NULL and not a duplicate
+func (a *aggregatorGroupConcat) aggregate(row []sqltypes.Value) error {
+	if len(a.orderBy) == 0 {
+		a.appendRow(row)
 		return nil
 	}
-	if a.n > 0 {
+	a.rows = append(a.rows, row)
+	return nil
+}
+
+func (a *aggregatorGroupConcat) appendRow(row []sqltypes.Value) {
+	a.n++
+	if len(a.concat) >= a.maxLen {
+		// the result is cut to maxLen bytes, so the rest of the group doesn't need to be concatenated
+		return
+	}
+	if a.n > 1 {
 		a.concat = append(a.concat, a.separator...)
 	}
-	a.concat = append(a.concat, row[a.from].Raw()...)
-	a.n++
+	for _, col := range a.cols {
+		a.concat = append(a.concat, row[col].Raw()...)
+	}
+}
+
+// flush aggregates the rows kept by the distinct aggregator, and sorts the rows of the group
+// when ordering. It is called when the group is finished, before finish.
+func (a *aggregatorGroupConcat) flush() (err error) {
+	if err := a.distinct.flush(a.aggregate); err != nil {
+		return err
+	}
+	if len(a.orderBy) == 0 {
+		return nil
+	}
+
+	defer evalengine.PanicHandler(&err)
+	// rows that compare equal are kept in their input order
+	slices.SortStableFunc(a.rows, func(x, y sqltypes.Row) int {
+		return a.orderBy.Compare(x, y)
+	})
 	return nil
 }
 
 func (a *aggregatorGroupConcat) finish() sqltypes.Value {
+	for _, row := range a.rows {
+		a.appendRow(row)
+	}
 	if a.n == 0 {
 		return sqltypes.NULL
 	}
+	a.truncate()
 	return sqltypes.MakeTrusted(a.type_, a.concat)
 }
 
+// truncate cuts the result to group_concat_max_len bytes like MySQL does, without splitting a character
+func (a *aggregatorGroupConcat) truncate() {
+	if len(a.concat) <= a.maxLen {
+		return
+	}
+	if a.charset == nil {
+		a.concat = a.concat[:a.maxLen]
+		return
+	}
+	end := 0
+	for end < a.maxLen {
+		_, size := a.charset.DecodeRune(a.concat[end:])
+		if end+size > a.maxLen {
+			break
+		}
+		end += size
+	}
+	a.concat = a.concat[:end]
+}
+
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
+	a.rows = nil
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	a.distinct.reset()
+}
+
+func (a *aggregatorGroupConcat) close() {
+	a.distinct.close()
+}
+
+// aggregatorGrouping returns the value of GROUPING() for the rows of a
+// rollup level. The mask is set when the level is created.
+type aggregatorGrouping struct {
+	mask int64
 }
 
+func (a *aggregatorGrouping) add(_ []sqltypes.Value) error {
+	return nil
+}
+
+func (a *aggregatorGrouping) finish() sqltypes.Value {
+	return sqltypes.NewInt64(a.mask)
+}
+
+func (a *aggregatorGrouping) reset() {}
+
 type aggregatorGtid struct {
 	from   int
 	shards []*binlogdatapb.ShardGtid
@@ -321,12 +619,26 @@ func (a aggregationState) add(row []sqltypes.Value) error {
 	return nil
 }
 
-func (a aggregationState) finish() (row []sqltypes.Value) {
-	row = make([]sqltypes.Value, 0, len(a))
+func (a aggregationState) finish() ([]sqltypes.Value, error) {
+	row := make([]sqltypes.Value, 0, len(a))
 	for _, st := range a {
+		if sa, ok := st.(spillingAggregator); ok {
+			if err := sa.flush(); err != nil {
+				return nil, err
+			}
+		}
 		row = append(row, st.finish())
 	}
-	return
+	return row, nil
+}
+
+// close removes the rows the aggregators spilled to disk, when the query ends before their group is finished
+func (a aggregationState) close() {
+	for _, st := range a {
+		if sa, ok := st.(spillingAggregator); ok {
+			sa.close()
+		}
+	}
 }
 
 func (a aggregationState) reset() {
@@ -353,7 +665,38 @@ func isComparable(typ sqltypes.Type) bool {
 	return false
 }
 
-func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
+// defaultGroupConcatMaxLen is the default value of group_concat_max_len in MySQL
+const defaultGroupConcatMaxLen = 1024
+
+// groupConcatMaxLen returns the value of group_concat_max_len set in the session, or the MySQL default
+func groupConcatMaxLen(vcursor VCursor) int {
+	maxLen := defaultGroupConcatMaxLen
+	if vcursor == nil || !vcursor.Session().HasSystemVariables() {
+		return maxLen
+	}
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if !strings.EqualFold(k, sysvars.GroupConcatMaxLen) {
+			return
+		}
+		if n, err := strconv.ParseUint(v, 10, 32); err == nil {
+			maxLen = int(n)
+		}
+	})
+	return maxLen
+}
+
+// inputCollation returns the collation of the text values aggregated from the given field
+func inputCollation(aggr *AggregateParams, field *querypb.Field) collations.ID {
+	if coll := collations.ID(field.Charset); coll != collations.Unknown {
+		return coll
+	}
+	if aggr.CollationEnv == nil {
+		return collations.CollationUtf8mb4ID
+	}
+	return aggr.CollationEnv.DefaultConnectionCharset()
+}
+
+func newAggregation(vcursor VCursor, fields []*querypb.Field, aggregates []*AggregateParams) (aggregationState, []*querypb.Field, error) {
 	fields = slice.Map(fields, func(from *querypb.Field) *querypb.Field { return from.CloneVT() })
 
 	agstate := make([]aggregator, len(fields))
@@ -383,13 +726,8 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 
 		case AggregateCount, AggregateCountDistinct:
 			ag = &aggregatorCount{
-				from: aggr.Col,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
+				from:     aggr.Col,
+				distinct: newAggregatorDistinct(vcursor, aggr, distinct),
 			}
 
 		case AggregateSum, AggregateSumDistinct:
@@ -402,14 +740,9 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 			}
 
 			ag = &aggregatorSum{
-				from: aggr.Col,
-				sum:  sum,
-				distinct: aggregatorDistinct{
-					column:       distinct,
-					coll:         aggr.Type.Collation(),
-					collationEnv: aggr.CollationEnv,
-					values:       aggr.Type.Values(),
-				},
+				from:     aggr.Col,
+				sum:      sum,
+				distinct: newAggregatorDistinct(vcursor, aggr, distinct),
 			}
 
 		case AggregateMin:
@@ -434,14 +767,59 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 		case AggregateAnyValue:
 			ag = &aggregatorScalar{from: aggr.Col}
 
+		case AggregateGrouping:
+			ag = &aggregatorGrouping{}
+
+		case AggregateBitAnd, AggregateBitOr, AggregateBitXor:
+			coll := inputCollation(aggr, fields[aggr.Col])
+			var bitwise evalengine.Bitwise
+			switch aggr.Opcode {
+			case AggregateBitAnd:
+				bitwise = evalengine.NewAggregationBitAnd(coll)
+			case AggregateBitOr:
+				bitwise = evalengine.NewAggregationBitOr(coll)
+			default:
+				bitwise = evalengine.NewAggregationBitXor(coll)
+			}
+			ag = &aggregatorBitwise{from: aggr.Col, bitwise: bitwise}
+
+		case AggregateJSONArrayAgg, AggregateJSONObjectAgg, AggregateJSONMerge:
+			cols := aggr.ArgCols
+			if len(cols) == 0 {
+				cols = []int{aggr.Col}
+			}
+			coll := inputCollation(aggr, fields[cols[len(cols)-1]])
+			var json evalengine.JSONAggregation
+			if aggr.Opcode == AggregateJSONObjectAgg || aggr.OrigOpcode == AggregateJSONObjectAgg {
+				json = evalengine.NewAggregationJSONObject(coll)
+			} else {
+				json = evalengine.NewAggregationJSONArray(coll)
+			}
+			ag = &aggregatorJSON{
+				cols:  cols,
+				merge: aggr.Opcode == AggregateJSONMerge,
+				json:  json,
+			}
+
 		case AggregateGroupConcat:
 			gcFunc := aggr.Func.(*sqlparser.GroupConcatExpr)
 			separator := []byte(gcFunc.Separator)
-			ag = &aggregatorGroupConcat{
-				from:      aggr.Col,
+			cols := aggr.ArgCols
+			if len(cols) == 0 {
+				cols = []int{aggr.Col}
+			}
+			gc := &aggregatorGroupConcat{
 				type_:     targetType,
 				separator: separator,
+				maxLen:    groupConcatMaxLen(vcursor),
+				cols:      cols,
+				orderBy:   aggr.GroupConcatOrderBy,
+				distinct:  newAggregatorDistinct(vcursor, aggr, -1),
+			}
+			if sqltypes.IsText(targetType) {
+				gc.charset = colldata.Lookup(inputCollation(aggr, fields[aggr.Col])).Charset()
 			}
+			ag = gc
 
 		default:
 			panic("BUG: unexpected Aggregation opcode")
@@ -35,7 +35,7 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(208)
 	}
 	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
 	size += cached.Type.CachedSize(false)
@@ -47,6 +47,28 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	// field Original *vitess.io/vitess/go/vt/sqlparser.AliasedExpr
 	size += cached.Original.CachedSize(true)
+	// field GroupingKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.GroupingKeys)) * int64(8))
+	}
+	// field DistinctCols []vitess.io/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.DistinctCols)) * int64(48))
+		for _, elem := range cached.DistinctCols {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field ArgCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ArgCols)) * int64(8))
+	}
+	// field GroupConcatOrderBy vitess.io/vitess/go/vt/vtgate/evalengine.Comparison
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.GroupConcatOrderBy)) * int64(56))
+		for _, elem := range cached.GroupConcatOrderBy {
+			size += elem.CachedSize(false)
+		}
+	}
 	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
 	size += cached.CollationEnv.CachedSize(true)
 	return size
@@ -425,7 +447,7 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(128)
+		size += int64(208)
 	}
 	// field Left vitess.io/vitess/go/vt/vtgate/engine.Primitive
 	if cc, ok := cached.Left.(cachedObject); ok {
@@ -439,20 +461,35 @@ func (cached *HashJoin) CachedSize(alloc bool) int64 {
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
 	}
+	// field LHSKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.LHSKeys)) * int64(8))
+	}
+	// field RHSKeys []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.RHSKeys)) * int64(8))
+	}
 	// field ASTPred vitess.io/vitess/go/vt/sqlparser.Expr
 	if cc, ok := cached.ASTPred.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
-	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
-	size += cached.CollationEnv.CachedSize(true)
-	// field Values *vitess.io/vitess/go/vt/vtgate/evalengine.EnumSetValues
-	if cached.Values != nil {
-		size += int64(24)
-		size += hack.RuntimeAllocSize(int64(cap(*cached.Values)) * int64(16))
-		for _, elem := range *cached.Values {
-			size += hack.RuntimeAllocSize(int64(len(elem)))
+	// field ComparisonTypes []vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ComparisonTypes)) * int64(24))
+		for _, elem := range cached.ComparisonTypes {
+			size += elem.CachedSize(false)
 		}
 	}
+	// field Residual vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Residual.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ResidualCols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ResidualCols)) * int64(8))
+	}
+	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
+	size += cached.CollationEnv.CachedSize(true)
 	return size
 }
 func (cached *Insert) CachedSize(alloc bool) int64 {
@@ -542,7 +579,7 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(176)
+		size += int64(224)
 	}
 	// field InsertCommon vitess.io/vitess/go/vt/vtgate/engine.InsertCommon
 	size += cached.InsertCommon.CachedSize(false)
@@ -559,6 +596,43 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 			}
 		}
 	}
+	// field Replace vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Replace.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ReplaceKeys [][]int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.ReplaceKeys)) * int64(24))
+		for _, elem := range cached.ReplaceKeys {
+			{
+				size += hack.RuntimeAllocSize(int64(cap(elem)) * int64(8))
+			}
+		}
+	}
+	return size
+}
+func (cached *JSONTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Doc vitess.io/vitess/go/vt/vtgate/evalengine.Expr
+	if cc, ok := cached.Doc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field ASTDoc vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.ASTDoc.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Table *vitess.io/vitess/go/vt/vtgate/evalengine.JSONTable
+	size += cached.Table.CachedSize(true)
+	// field Cols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
+	}
 	return size
 }
 
@@ -621,6 +695,46 @@ func (cached *Limit) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *Load) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(160)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field TableName string
+	size += hack.RuntimeAllocSize(int64(len(cached.TableName)))
+	// field FileName string
+	size += hack.RuntimeAllocSize(int64(len(cached.FileName)))
+	// field Insert string
+	size += hack.RuntimeAllocSize(int64(len(cached.Insert)))
+	// field Format vitess.io/vitess/go/vt/vtgate/engine.LoadFormat
+	size += cached.Format.CachedSize(false)
+	return size
+}
+func (cached *LoadFormat) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field FieldsTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsTerminatedBy)))
+	// field FieldsEnclosedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEnclosedBy)))
+	// field FieldsEscapedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsEscapedBy)))
+	// field LinesStartingBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesStartingBy)))
+	// field LinesTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesTerminatedBy)))
+	return size
+}
 func (cached *Lock) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -801,7 +915,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(160)
 	}
 	// field Original string
 	size += hack.RuntimeAllocSize(int64(len(cached.Original)))
@@ -857,6 +971,47 @@ func (cached *Projection) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+
+//go:nocheckptr
+func (cached *RecurseCTE) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Seed vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Seed.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Term vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Term.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Vars map[string]int
+	if cached.Vars != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.Vars)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 208))
+		if len(cached.Vars) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 208))
+		}
+		for k := range cached.Vars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
+	// field CheckCols []vitess.io/vitess/go/vt/vtgate/engine.CheckCol
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.CheckCols)) * int64(48))
+		for _, elem := range cached.CheckCols {
+			size += elem.CachedSize(false)
+		}
+	}
+	return size
+}
 func (cached *RenameFields) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -998,6 +1153,27 @@ func (cached *RoutingParameters) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *RowMove) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Columns []string
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(16))
+		for _, elem := range cached.Columns {
+			size += hack.RuntimeAllocSize(int64(len(elem)))
+		}
+	}
+	// field DeleteQuery string
+	size += hack.RuntimeAllocSize(int64(len(cached.DeleteQuery)))
+	// field Insert *vitess.io/vitess/go/vt/sqlparser.Insert
+	size += cached.Insert.CachedSize(true)
+	return size
+}
 func (cached *Rows) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -1323,7 +1499,7 @@ func (cached *Update) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(16)
+		size += int64(24)
 	}
 	// field DML *vitess.io/vitess/go/vt/vtgate/engine.DML
 	size += cached.DML.CachedSize(true)
@@ -1342,6 +1518,8 @@ func (cached *Update) CachedSize(alloc bool) int64 {
 			size += v.CachedSize(true)
 		}
 	}
+	// field Move *vitess.io/vitess/go/vt/vtgate/engine.RowMove
+	size += cached.Move.CachedSize(true)
 	return size
 }
 func (cached *UpdateTarget) CachedSize(alloc bool) int64 {
@@ -1549,6 +1727,114 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *Window) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(112)
+	}
+	// field PartitionBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.PartitionBy)) * int64(8))
+		for _, elem := range cached.PartitionBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field OrderBy []*vitess.io/vitess/go/vt/vtgate/engine.GroupByParams
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.OrderBy)) * int64(8))
+		for _, elem := range cached.OrderBy {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Functions []*vitess.io/vitess/go/vt/vtgate/engine.WindowFunc
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Functions)) * int64(8))
+		for _, elem := range cached.Functions {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Cols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
+	}
+	// field Input vitess.io/vitess/go/vt/vtgate/engine.Primitive
+	if cc, ok := cached.Input.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	return size
+}
+func (cached *WindowFunc) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(96)
+	}
+	// field Frame *vitess.io/vitess/go/vt/vtgate/engine.WindowFrame
+	if cached.Frame != nil {
+		size += hack.RuntimeAllocSize(int64(40))
+	}
+	// field Alias string
+	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
+	// field Type vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	size += cached.Type.CachedSize(false)
+	// field CollationEnv *vitess.io/vitess/go/mysql/collations.Environment
+	size += cached.CollationEnv.CachedSize(true)
+	return size
+}
+
+//go:nocheckptr
+func (cached *loader) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(160)
+	}
+	// field Load *vitess.io/vitess/go/vt/vtgate/engine.Load
+	size += cached.Load.CachedSize(true)
+	// field vcursor vitess.io/vitess/go/vt/vtgate/engine.VCursor
+	if cc, ok := cached.vcursor.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field fieldTerm []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.fieldTerm)))
+	}
+	// field lineTerm []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.lineTerm)))
+	}
+	// field linePrefix []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.linePrefix)))
+	}
+	// field buf []byte
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.buf)))
+	}
+	// field bindVars map[string]*vitess.io/vitess/go/vt/proto/query.BindVariable
+	if cached.bindVars != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.bindVars)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 208))
+		if len(cached.bindVars) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 208))
+		}
+		for k, v := range cached.bindVars {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+			size += v.CachedSize(true)
+		}
+	}
+	return size
+}
 
 //go:nocheckptr
 func (cached *shardRoute) CachedSize(alloc bool) int64 {
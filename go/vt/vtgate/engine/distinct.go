@@ -134,6 +134,16 @@ func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVa
 	var mu sync.Mutex
 
 	pt := newProbeTable(d.CheckCols, vcursor.Environment().CollationEnv())
+	mem := newMemoryTracker(vcursor)
+	defer mem.releaseAll()
+	// spill is set when the rows seen don't fit in the memory budget of the query
+	var spill *spillPartitionSet
+	defer func() {
+		if spill != nil {
+			spill.close()
+		}
+	}()
+
 	err := vcursor.StreamExecutePrimitive(ctx, d.Source, bindVars, wantfields, func(input *sqltypes.Result) error {
 		result := &sqltypes.Result{
 			Fields:   input.Fields,
@@ -142,18 +152,76 @@ func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVa
 		mu.Lock()
 		defer mu.Unlock()
 		for _, row := range input.Rows {
+			if spill != nil {
+				if err := d.spillRow(pt, spill, row); err != nil {
+					return err
+				}
+				continue
+			}
 			appendRow, err := pt.exists(row)
 			if err != nil {
 				return err
 			}
-			if appendRow != nil {
-				result.Rows = append(result.Rows, appendRow)
+			if appendRow == nil {
+				continue
+			}
+			result.Rows = append(result.Rows, appendRow)
+			if !mem.add(seenRowSize) {
+				spill, err = newSpillPartitionSet(vcursor.SpillDir())
+				if err != nil {
+					return err
+				}
 			}
 		}
 		return callback(result.Truncate(len(d.CheckCols)))
 	})
+	if err != nil || spill == nil {
+		return err
+	}
+
+	// the rows that were spilled have not been seen before, but they can still be duplicates of each other.
+	// equal rows are always in the same partition, so each partition can be deduplicated on its own.
+	for _, file := range spill.files {
+		pt.seenRows = make(map[vthash.Hash]struct{})
+		result := &sqltypes.Result{}
+		err := file.forEach(func(row sqltypes.Row) error {
+			appendRow, err := pt.exists(row)
+			if err != nil || appendRow == nil {
+				return err
+			}
+			result.Rows = append(result.Rows, appendRow)
+			if len(result.Rows) < spillBatchSize {
+				return nil
+			}
+			err = callback(result.Truncate(len(d.CheckCols)))
+			result = &sqltypes.Result{}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Rows) > 0 {
+			if err := callback(result.Truncate(len(d.CheckCols))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// seenRowSize is the approximate number of bytes used by a row in the probe table of a Distinct or of a distinct aggregation
+const seenRowSize = 48
 
-	return err
+// spillRow writes a row to its partition on disk, unless it has already been seen and sent
+func (d *Distinct) spillRow(pt *probeTable, spill *spillPartitionSet, row sqltypes.Row) error {
+	code, err := pt.hashCodeForRow(row)
+	if err != nil {
+		return err
+	}
+	if _, found := pt.seenRows[code]; found {
+		return nil
+	}
+	return spill.write(code, row)
 }
 
 // RouteType implements the Primitive interface
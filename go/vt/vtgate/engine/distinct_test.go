@@ -184,6 +184,44 @@ func TestDistinctStreamAsync(t *testing.T) {
 [VARCHAR("a") INT64(1) INT64(1) VARCHAR("t")]]`, qr.Rows))
 }
 
+func TestDistinctStreamSpilling(t *testing.T) {
+	distinct := &Distinct{
+		Source: &fakePrimitive{
+			results: sqltypes.MakeTestStreamingResults(sqltypes.MakeTestFields("id|name", "int64|varchar"),
+				"1|a",
+				"1|a",
+				"2|b",
+				"---",
+				"3|c",
+				"2|b",
+				"null|d",
+				"---",
+				"3|c",
+				"null|d",
+				"1|a",
+			),
+			allResultsInOneCall: true,
+		},
+		CheckCols: []CheckCol{
+			{Col: 0, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID)},
+			{Col: 1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)},
+		},
+	}
+
+	// the budget only fits the first row seen, every new row after it is spilled to disk
+	vc := &loggingVCursor{memoryBudget: NewMemoryBudget(1), spillDir: t.TempDir()}
+	result, err := wrapStreamExecute(distinct, vc, nil, true)
+	require.NoError(t, err)
+	expectResultAnyOrder(t, result, sqltypes.MakeTestResult(sqltypes.MakeTestFields("id|name", "int64|varchar"),
+		"1|a",
+		"2|b",
+		"3|c",
+		"null|d",
+	))
+	require.Zero(t, vc.memoryBudget.Used())
+	requireEmptyDir(t, vc.spillDir)
+}
+
 func TestWeightStringFallBack(t *testing.T) {
 	offsetOne := 1
 	checkCols := []CheckCol{{
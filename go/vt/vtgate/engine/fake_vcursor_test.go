@@ -48,6 +48,7 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testCTEMaxRecursionDepth = 1000
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -160,7 +161,7 @@ func (t *noopVCursor) StreamExecutePrimitive(ctx context.Context, primitive Prim
 }
 
 func (t *noopVCursor) HasSystemVariables() bool {
-	panic("implement me")
+	return false
 }
 
 func (t *noopVCursor) GetSystemVariables(func(k string, v string)) {
@@ -337,6 +338,18 @@ func (t *noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t *noopVCursor) CTEMaxRecursionDepth() int {
+	return testCTEMaxRecursionDepth
+}
+
+func (t *noopVCursor) MemoryBudget() *MemoryBudget {
+	return nil
+}
+
+func (t *noopVCursor) SpillDir() string {
+	return ""
+}
+
 func (t *noopVCursor) GetKeyspace() string {
 	return ""
 }
@@ -421,6 +434,17 @@ type loggingVCursor struct {
 	shardSession []*srvtopo.ResolvedShard
 
 	parser *sqlparser.Parser
+
+	memoryBudget *MemoryBudget
+	spillDir     string
+}
+
+func (f *loggingVCursor) MemoryBudget() *MemoryBudget {
+	return f.memoryBudget
+}
+
+func (f *loggingVCursor) SpillDir() string {
+	return f.spillDir
 }
 
 func (f *loggingVCursor) HasCreatedTempTable() {
@@ -465,8 +489,10 @@ func (f *loggingVCursor) HasSystemVariables() bool {
 	return len(f.systemVariables) > 0
 }
 
-func (f *loggingVCursor) GetSystemVariables(func(k string, v string)) {
-	panic("implement me")
+func (f *loggingVCursor) GetSystemVariables(f2 func(k string, v string)) {
+	for k, v := range f.systemVariables {
+		f2(k, v)
+	}
 }
 
 func (f *loggingVCursor) SetFoundRows(u uint64) {
@@ -36,9 +36,9 @@ var _ Primitive = (*HashJoin)(nil)
 type (
 	// HashJoin specifies the parameters for a join primitive
 	// Hash joins work by fetch all the input from the LHS, and building a hash map, known as the probe table, for this input.
-	// The key to the map is the hashcode of the value for column that we are joining by.
+	// The key to the map is the hashcode of the values for the columns that we are joining by.
 	// Then the RHS is fetched, and we can check if the rows from the RHS matches any from the LHS.
-	// When they match by hash code, we double-check that we are not working with a false positive by comparing the values.
+	// The rows that match by hash code are then filtered using the residual predicate, if there is one.
 	HashJoin struct {
 		Opcode JoinOpcode
 
@@ -55,33 +55,37 @@ type (
 		// the returned result will be {Left0, Left1, Right0, Right1}.
 		Cols []int `json:",omitempty"`
 
-		// The keys correspond to the column offset in the inputs where
-		// the join columns can be found
-		LHSKey, RHSKey int
+		// The keys correspond to the column offsets in the inputs where
+		// the join columns can be found. Rows match when all their keys are equal.
+		LHSKeys, RHSKeys []int
 
 		// The join condition. Used for plan descriptions
 		ASTPred sqlparser.Expr
 
-		// collation and type are used to hash the incoming values correctly
-		Collation      collations.ID
-		ComparisonType querypb.Type
+		// ComparisonTypes are used to hash the incoming values of each key correctly
+		ComparisonTypes []evalengine.Type
 
-		CollationEnv *collations.Environment
+		// Residual is the part of the join condition that can't be hashed. It is evaluated
+		// for the rows that match by hash code, on the columns defined by ResidualCols,
+		// which uses the same encoding as Cols.
+		Residual     evalengine.Expr
+		ResidualCols []int `json:",omitempty"`
 
-		// Values for enum and set types
-		Values *evalengine.EnumSetValues
+		CollationEnv *collations.Environment
 	}
 
 	hashJoinProbeTable struct {
 		innerMap map[vthash.Hash]*probeTableEntry
 
-		coll           collations.ID
-		typ            querypb.Type
-		lhsKey, rhsKey int
-		cols           []int
-		hasher         vthash.Hasher
-		sqlmode        evalengine.SQLMode
-		values         *evalengine.EnumSetValues
+		types            []evalengine.Type
+		lhsKeys, rhsKeys []int
+		cols             []int
+		hasher           vthash.Hasher
+		sqlmode          evalengine.SQLMode
+
+		residual     evalengine.Expr
+		residualCols []int
+		env          *evalengine.ExpressionEnv
 	}
 
 	probeTableEntry struct {
@@ -98,7 +102,7 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		return nil, err
 	}
 
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	pt := hj.newProbeTable(evalengine.NewExpressionEnv(ctx, bindVars, vcursor))
 	// build the probe table from the LHS result
 	for _, row := range lresult.Rows {
 		err := pt.addLeftRow(row)
@@ -122,9 +126,12 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 			return nil, err
 		}
 		result.Rows = append(result.Rows, matches...)
+		if len(matches) == 0 && hj.Opcode.keepsRightRows() {
+			result.Rows = append(result.Rows, joinRows(nil, currentRHSRow, hj.Cols))
+		}
 	}
 
-	if hj.Opcode == LeftJoin {
+	if hj.Opcode.keepsLeftRows() {
 		result.Rows = append(result.Rows, pt.notFetched()...)
 	}
 
@@ -134,7 +141,19 @@ func (hj *HashJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 // TryStreamExecute implements the Primitive interface
 func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	// build the probe table from the LHS result
-	pt := newHashJoinProbeTable(hj.Collation, hj.ComparisonType, hj.LHSKey, hj.RHSKey, hj.Cols, hj.Values)
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	newProbeTable := func() *hashJoinProbeTable { return hj.newProbeTable(env) }
+	pt := newProbeTable()
+	mem := newMemoryTracker(vcursor)
+	defer mem.releaseAll()
+	// spill is set when the LHS rows don't fit in the memory budget of the query
+	var spill *hashJoinSpill
+	defer func() {
+		if spill != nil {
+			spill.close()
+		}
+	}()
+
 	var lfields []*querypb.Field
 	var mu sync.Mutex
 	err := vcursor.StreamExecutePrimitive(ctx, hj.Left, bindVars, wantfields, func(result *sqltypes.Result) error {
@@ -144,10 +163,22 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			lfields = result.Fields
 		}
 		for _, current := range result.Rows {
+			if spill != nil {
+				if err := spill.addLeftRow(pt, current); err != nil {
+					return err
+				}
+				continue
+			}
 			err := pt.addLeftRow(current)
 			if err != nil {
 				return err
 			}
+			if !mem.add(rowSize(current)) {
+				spill, err = newHashJoinSpill(vcursor.SpillDir())
+				if err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	})
@@ -172,6 +203,19 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 				return err
 			}
 			res.Rows = append(res.Rows, results...)
+			if spill != nil {
+				// the row could still match one of the spilled LHS rows
+				spilled, err := spill.addRightRow(pt, currentRHSRow, len(results) > 0)
+				if err != nil {
+					return err
+				}
+				if spilled {
+					continue
+				}
+			}
+			if len(results) == 0 && hj.Opcode.keepsRightRows() {
+				res.Rows = append(res.Rows, joinRows(nil, currentRHSRow, hj.Cols))
+			}
 		}
 		if len(res.Rows) != 0 || len(res.Fields) != 0 {
 			return callback(res)
@@ -182,23 +226,144 @@ func (hj *HashJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 		return err
 	}
 
-	if hj.Opcode == LeftJoin {
-		res := &sqltypes.Result{}
-		if sendFields.CompareAndSwap(true, false) {
-			// If we still have not sent the fields, we need to fetch
-			// the fields from the RHS to be able to build the result fields
-			rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+	if spill == nil && !hj.Opcode.keepsLeftRows() {
+		return nil
+	}
+
+	res := &sqltypes.Result{}
+	if sendFields.CompareAndSwap(true, false) {
+		// If we still have not sent the fields, we need to fetch
+		// the fields from the RHS to be able to build the result fields
+		rres, err := hj.Right.GetFields(ctx, vcursor, bindVars)
+		if err != nil {
+			return err
+		}
+		res.Fields = joinFields(lfields, rres.Fields, hj.Cols)
+	}
+	// this will only be called when all the concurrent access to the pt has
+	// ceased, so we don't need to lock it here
+	if hj.Opcode.keepsLeftRows() {
+		res.Rows = pt.notFetched()
+	}
+	if len(res.Rows) != 0 || len(res.Fields) != 0 || spill == nil {
+		if err := callback(res); err != nil {
+			return err
+		}
+	}
+	if spill == nil {
+		return nil
+	}
+	// the rows in memory have all been joined, the spilled partitions are now joined one at a time
+	mem.releaseAll()
+	return spill.join(newProbeTable, hj.Opcode, func(rows []sqltypes.Row) error {
+		return callback(&sqltypes.Result{Rows: rows})
+	})
+}
+
+func (hj *HashJoin) newProbeTable(env *evalengine.ExpressionEnv) *hashJoinProbeTable {
+	return &hashJoinProbeTable{
+		innerMap:     map[vthash.Hash]*probeTableEntry{},
+		types:        hj.ComparisonTypes,
+		lhsKeys:      hj.LHSKeys,
+		rhsKeys:      hj.RHSKeys,
+		cols:         hj.Cols,
+		hasher:       vthash.New(),
+		residual:     hj.Residual,
+		residualCols: hj.ResidualCols,
+		env:          env,
+	}
+}
+
+// hashJoinSpill holds the rows of a hash join that don't fit in the memory budget of the query.
+// Once the probe table has exhausted the budget, the next LHS rows are partitioned on disk using the hash of their
+// join key. All the RHS rows are also partitioned on disk the same way, so after the RHS has been probed against
+// the rows in memory, each pair of partitions can be joined on its own, like in a grace hash join.
+// Every spilled RHS row carries an extra value telling if it already matched one of the LHS rows in memory,
+// so that the RHS rows without any match can be found for right and full outer joins.
+type hashJoinSpill struct {
+	left, right *spillPartitionSet
+}
+
+func newHashJoinSpill(dir string) (*hashJoinSpill, error) {
+	left, err := newSpillPartitionSet(dir)
+	if err != nil {
+		return nil, err
+	}
+	right, err := newSpillPartitionSet(dir)
+	if err != nil {
+		left.close()
+		return nil, err
+	}
+	return &hashJoinSpill{left: left, right: right}, nil
+}
+
+func (s *hashJoinSpill) addLeftRow(pt *hashJoinProbeTable, row sqltypes.Row) error {
+	hash, _, err := pt.hash(row, pt.lhsKeys)
+	if err != nil {
+		return err
+	}
+	return s.left.write(hash, row)
+}
+
+// addRightRow spills a row of the RHS, and returns false when the row was not spilled because it can't match anything
+func (s *hashJoinSpill) addRightRow(pt *hashJoinProbeTable, row sqltypes.Row, matched bool) (bool, error) {
+	hash, hasNull, err := pt.hash(row, pt.rhsKeys)
+	if err != nil || hasNull {
+		// NULL never matches anything
+		return false, err
+	}
+	spilled := append(row[:len(row):len(row)], sqltypes.NewInt8(0))
+	if matched {
+		spilled[len(row)] = sqltypes.NewInt8(1)
+	}
+	return true, s.right.write(hash, spilled)
+}
+
+// join joins every pair of partitions, by building a probe table for the LHS partition and probing it with the RHS one
+func (s *hashJoinSpill) join(newProbeTable func() *hashJoinProbeTable, opcode JoinOpcode, callback func([]sqltypes.Row) error) error {
+	var batch []sqltypes.Row
+	flush := func(force bool) error {
+		if len(batch) == 0 || (!force && len(batch) < spillBatchSize) {
+			return nil
+		}
+		err := callback(batch)
+		batch = nil
+		return err
+	}
+
+	for i := range s.left.files {
+		pt := newProbeTable()
+		if err := s.left.files[i].forEach(pt.addLeftRow); err != nil {
+			return err
+		}
+		err := s.right.files[i].forEach(func(row sqltypes.Row) error {
+			row, matched := row[:len(row)-1], row[len(row)-1]
+			matches, err := pt.get(row)
 			if err != nil {
 				return err
 			}
-			res.Fields = joinFields(lfields, rres.Fields, hj.Cols)
+			batch = append(batch, matches...)
+			if len(matches) == 0 && opcode.keepsRightRows() && matched.ToString() == "0" {
+				batch = append(batch, joinRows(nil, row, pt.cols))
+			}
+			return flush(false)
+		})
+		if err != nil {
+			return err
+		}
+		if opcode.keepsLeftRows() {
+			batch = append(batch, pt.notFetched()...)
+		}
+		if err := flush(false); err != nil {
+			return err
 		}
-		// this will only be called when all the concurrent access to the pt has
-		// ceased, so we don't need to lock it here
-		res.Rows = pt.notFetched()
-		return callback(res)
 	}
-	return nil
+	return flush(true)
+}
+
+func (s *hashJoinSpill) close() {
+	s.left.close()
+	s.right.close()
 }
 
 // RouteType implements the Primitive interface
@@ -251,11 +416,17 @@ func (hj *HashJoin) description() PrimitiveDescription {
 		"TableName":         hj.GetTableName(),
 		"JoinColumnIndexes": strings.Trim(strings.Join(strings.Fields(fmt.Sprint(hj.Cols)), ","), "[]"),
 		"Predicate":         sqlparser.String(hj.ASTPred),
-		"ComparisonType":    hj.ComparisonType.String(),
 	}
-	coll := hj.Collation
-	if coll != collations.Unknown {
-		other["Collation"] = hj.CollationEnv.LookupName(coll)
+	var types, colls []string
+	for _, typ := range hj.ComparisonTypes {
+		types = append(types, typ.Type().String())
+		if coll := typ.Collation(); coll != collations.Unknown {
+			colls = append(colls, hj.CollationEnv.LookupName(coll))
+		}
+	}
+	other["ComparisonType"] = strings.Join(types, ", ")
+	if len(colls) > 0 {
+		other["Collation"] = strings.Join(colls, ", ")
 	}
 	return PrimitiveDescription{
 		OperatorType: "Join",
@@ -264,21 +435,8 @@ func (hj *HashJoin) description() PrimitiveDescription {
 	}
 }
 
-func newHashJoinProbeTable(coll collations.ID, typ querypb.Type, lhsKey, rhsKey int, cols []int, values *evalengine.EnumSetValues) *hashJoinProbeTable {
-	return &hashJoinProbeTable{
-		innerMap: map[vthash.Hash]*probeTableEntry{},
-		coll:     coll,
-		typ:      typ,
-		lhsKey:   lhsKey,
-		rhsKey:   rhsKey,
-		cols:     cols,
-		hasher:   vthash.New(),
-		values:   values,
-	}
-}
-
 func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
-	hash, err := pt.hash(r[pt.lhsKey])
+	hash, _, err := pt.hash(r, pt.lhsKeys)
 	if err != nil {
 		return err
 	}
@@ -290,29 +448,40 @@ func (pt *hashJoinProbeTable) addLeftRow(r sqltypes.Row) error {
 	return nil
 }
 
-func (pt *hashJoinProbeTable) hash(val sqltypes.Value) (vthash.Hash, error) {
-	err := evalengine.NullsafeHashcode128(&pt.hasher, val, pt.coll, pt.typ, pt.sqlmode, pt.values)
-	if err != nil {
-		return vthash.Hash{}, err
+// hash returns the hashcode of the join keys of the row, and whether any of them is NULL
+func (pt *hashJoinProbeTable) hash(row sqltypes.Row, keys []int) (hash vthash.Hash, hasNull bool, err error) {
+	for i, key := range keys {
+		val := row[key]
+		hasNull = hasNull || val.IsNull()
+		typ := pt.types[i]
+		err = evalengine.NullsafeHashcode128(&pt.hasher, val, typ.Collation(), typ.Type(), pt.sqlmode, typ.Values())
+		if err != nil {
+			pt.hasher.Reset()
+			return vthash.Hash{}, false, err
+		}
 	}
 
-	res := pt.hasher.Sum128()
+	hash = pt.hasher.Sum128()
 	pt.hasher.Reset()
-	return res, nil
+	return hash, hasNull, nil
 }
 
 func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) (result []sqltypes.Row, err error) {
-	val := rrow[pt.rhsKey]
-	if val.IsNull() {
-		return
-	}
-
-	hash, err := pt.hash(val)
-	if err != nil {
+	hash, hasNull, err := pt.hash(rrow, pt.rhsKeys)
+	if err != nil || hasNull {
 		return nil, err
 	}
 
 	for e := pt.innerMap[hash]; e != nil; e = e.next {
+		if pt.residual != nil {
+			match, err := pt.matchesResidual(e.row, rrow)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
 		e.seen = true
 		result = append(result, joinRows(e.row, rrow, pt.cols))
 	}
@@ -320,6 +489,16 @@ func (pt *hashJoinProbeTable) get(rrow sqltypes.Row) (result []sqltypes.Row, err
 	return
 }
 
+// matchesResidual evaluates the residual predicate for a pair of rows that have the same join keys
+func (pt *hashJoinProbeTable) matchesResidual(lrow, rrow sqltypes.Row) (bool, error) {
+	pt.env.Row = joinRows(lrow, rrow, pt.residualCols)
+	res, err := pt.env.Evaluate(pt.residual)
+	if err != nil {
+		return false, err
+	}
+	return res.ToBoolean(), nil
+}
+
 func (pt *hashJoinProbeTable) notFetched() (rows []sqltypes.Row) {
 	for _, e := range pt.innerMap {
 		for ; e != nil; e = e.next {
@@ -25,6 +25,8 @@ import (
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
@@ -110,6 +112,30 @@ func TestHashJoinVariations(t *testing.T) {
 		rhs:      1,
 		reverse:  true,
 		expected: rows("1|1|1|1", "3|2|null|null", "4|b|null|null", "5|null|null|null"),
+	}, {
+		name:     "right join opcode, same type",
+		typ:      RightJoin,
+		lhs:      0,
+		rhs:      0,
+		expected: rows("1|1|1|1", "3|b|3|2", "null|null|5|null", "null|null|4|b"),
+	}, {
+		name:     "right join opcode, coercion",
+		typ:      RightJoin,
+		lhs:      0,
+		rhs:      1,
+		expected: rows("1|1|1|1", "2|2|3|2", "null|null|5|null", "null|null|4|b"),
+	}, {
+		name:     "full outer join, same type",
+		typ:      FullOuterJoin,
+		lhs:      0,
+		rhs:      0,
+		expected: rows("1|1|1|1", "3|b|3|2", "2|2|null|null", "null|b|null|null", "null|null|5|null", "null|null|4|b"),
+	}, {
+		name:     "full outer join, coercion",
+		typ:      FullOuterJoin,
+		lhs:      0,
+		rhs:      1,
+		expected: rows("1|1|1|1", "2|2|3|2", "3|b|null|null", "null|b|null|null", "null|null|5|null", "null|null|4|b"),
 	}}
 
 	for _, tc := range tests {
@@ -136,13 +162,12 @@ func TestHashJoinVariations(t *testing.T) {
 		require.NoError(t, err)
 
 		jn := &HashJoin{
-			Opcode:         tc.typ,
-			Cols:           []int{-1, -2, 1, 2},
-			LHSKey:         tc.lhs,
-			RHSKey:         tc.rhs,
-			Collation:      typ.Collation(),
-			ComparisonType: typ.Type(),
-			CollationEnv:   collations.MySQL8(),
+			Opcode:          tc.typ,
+			Cols:            []int{-1, -2, 1, 2},
+			LHSKeys:         []int{tc.lhs},
+			RHSKeys:         []int{tc.rhs},
+			ComparisonTypes: []evalengine.Type{typ},
+			CollationEnv:    collations.MySQL8(),
 		}
 
 		t.Run(tc.name, func(t *testing.T) {
@@ -159,6 +184,102 @@ func TestHashJoinVariations(t *testing.T) {
 			require.NoError(t, err)
 			expectResultAnyOrder(t, r, expected)
 		})
+		t.Run("Spilling "+tc.name, func(t *testing.T) {
+			jn.Left = first()
+			jn.Right = last()
+			vc := &loggingVCursor{memoryBudget: NewMemoryBudget(1), spillDir: t.TempDir()}
+			r, err := wrapStreamExecute(jn, vc, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+			require.Zero(t, vc.memoryBudget.Used())
+			requireEmptyDir(t, vc.spillDir)
+		})
+	}
+}
+
+func TestHashJoinMultipleKeysAndResidual(t *testing.T) {
+	// joins on `a = x and b = y and c < z`, where the last comparison is the residual predicate
+	lhsFields := sqltypes.MakeTestFields("a|b|c", "int64|int64|int64")
+	rhsFields := sqltypes.MakeTestFields("x|y|z", "int64|int64|int64")
+	lhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(lhsFields, "1|1|10", "1|2|20", "2|1|30", "null|1|40"),
+			},
+		}
+	}
+	rhs := func() Primitive {
+		return &fakePrimitive{
+			results: []*sqltypes.Result{
+				sqltypes.MakeTestResult(rhsFields, "1|1|15", "1|1|5", "1|2|25", "2|2|50", "2|1|10"),
+			},
+		}
+	}
+
+	residualFields := sqltypes.MakeTestFields("c|z", "int64|int64")
+	residual, err := evalengine.Translate(&sqlparser.ComparisonExpr{
+		Operator: sqlparser.LessThanOp,
+		Left:     sqlparser.NewColName("c"),
+		Right:    sqlparser.NewColName("z"),
+	}, &evalengine.Config{
+		Collation:     collations.MySQL8().DefaultConnectionCharset(),
+		ResolveColumn: evalengine.FieldResolver(residualFields).Column,
+		Environment:   vtenv.NewTestEnv(),
+	})
+	require.NoError(t, err)
+
+	matched := []string{"1|1|10|1|1|15", "1|2|20|1|2|25"}
+	unmatchedLeft := []string{"2|1|30|null|null|null", "null|1|40|null|null|null"}
+	unmatchedRight := []string{"null|null|null|1|1|5", "null|null|null|2|2|50", "null|null|null|2|1|10"}
+
+	tests := []struct {
+		typ      JoinOpcode
+		expected []string
+	}{
+		{typ: InnerJoin, expected: matched},
+		{typ: LeftJoin, expected: append(append([]string{}, matched...), unmatchedLeft...)},
+		{typ: RightJoin, expected: append(append([]string{}, matched...), unmatchedRight...)},
+		{typ: FullOuterJoin, expected: append(append(append([]string{}, matched...), unmatchedLeft...), unmatchedRight...)},
+	}
+
+	intType := typeForOffset(0)
+	for _, tc := range tests {
+		expected := sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("a|b|c|x|y|z", "int64|int64|int64|int64|int64|int64"),
+			tc.expected...,
+		)
+		jn := &HashJoin{
+			Opcode:          tc.typ,
+			Cols:            []int{-1, -2, -3, 1, 2, 3},
+			LHSKeys:         []int{0, 1},
+			RHSKeys:         []int{0, 1},
+			ComparisonTypes: []evalengine.Type{intType, intType},
+			Residual:        residual,
+			ResidualCols:    []int{-3, 3},
+			CollationEnv:    collations.MySQL8(),
+		}
+
+		t.Run(tc.typ.String(), func(t *testing.T) {
+			jn.Left, jn.Right = lhs(), rhs()
+			r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+		t.Run("Streaming "+tc.typ.String(), func(t *testing.T) {
+			jn.Left, jn.Right = lhs(), rhs()
+			r, err := wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+		})
+		t.Run("Spilling "+tc.typ.String(), func(t *testing.T) {
+			jn.Left, jn.Right = lhs(), rhs()
+			vc := &loggingVCursor{memoryBudget: NewMemoryBudget(1), spillDir: t.TempDir()}
+			r, err := wrapStreamExecute(jn, vc, map[string]*querypb.BindVariable{}, true)
+			require.NoError(t, err)
+			expectResultAnyOrder(t, r, expected)
+			require.Zero(t, vc.memoryBudget.Used())
+			requireEmptyDir(t, vc.spillDir)
+		})
 	}
 }
 
@@ -31,7 +31,9 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vthash"
 )
 
 var _ Primitive = (*InsertSelect)(nil)
@@ -47,9 +49,27 @@ type (
 		// VindexValueOffset stores the offset for each column in the ColumnVindex
 		// that will appear in the result set of the select query.
 		VindexValueOffset [][]int
+
+		// Replace deletes the rows clashing with the selected rows on their primary or unique keys
+		// before the selected rows are inserted, for a REPLACE statement.
+		Replace Primitive
+
+		// ReplaceKeys stores, for each key used by Replace, the offsets of its columns
+		// in the result set of the select query. The values of the i-th key are passed
+		// to Replace in the ReplaceKeyVals(i) bind variable.
+		ReplaceKeys [][]int
 	}
 )
 
+// ReplaceKeyVals returns the name of the bind variable holding the values of the idx-th key
+// of the rows deleted before a REPLACE statement inserts the selected rows.
+func ReplaceKeyVals(idx int) string {
+	if idx == 0 {
+		return DmlVals
+	}
+	return fmt.Sprintf("%s_%d", DmlVals, idx)
+}
+
 // newInsertSelect creates a new InsertSelect.
 func newInsertSelect(
 	ignore bool,
@@ -83,7 +103,10 @@ func newInsertSelect(
 }
 
 func (ins *InsertSelect) Inputs() ([]Primitive, []map[string]any) {
-	return []Primitive{ins.Input}, nil
+	if ins.Replace == nil {
+		return []Primitive{ins.Input}, nil
+	}
+	return []Primitive{ins.Input, ins.Replace}, []map[string]any{{inputName: "Selection"}, {inputName: "Replace"}}
 }
 
 // RouteType returns a description of the query routing type used by the primitive
@@ -121,8 +144,14 @@ func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor,
 			return nil
 		}
 
-		var qr *sqltypes.Result
+		var deleted uint64
 		var err error
+		irr.rows, deleted, err = ins.deleteReplacedRows(ctx, vcursor, bindVars, irr.rows)
+		if err != nil {
+			return err
+		}
+
+		var qr *sqltypes.Result
 		if sharded {
 			qr, err = ins.insertIntoShardedTable(ctx, vcursor, bindVars, irr)
 		} else {
@@ -132,7 +161,7 @@ func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor,
 			return err
 		}
 
-		output.RowsAffected += qr.RowsAffected
+		output.RowsAffected += deleted + qr.RowsAffected
 		// InsertID needs to be updated to the least insertID value in sqltypes.Result
 		if output.InsertID == 0 || output.InsertID > qr.InsertID {
 			output.InsertID = qr.InsertID
@@ -153,7 +182,100 @@ func (ins *InsertSelect) execInsertUnsharded(ctx context.Context, vcursor VCurso
 	if len(irr.rows) == 0 {
 		return &sqltypes.Result{}, nil
 	}
-	return ins.insertIntoUnshardedTable(ctx, vcursor, bindVars, irr)
+	var deleted uint64
+	irr.rows, deleted, err = ins.deleteReplacedRows(ctx, vcursor, bindVars, irr.rows)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := ins.insertIntoUnshardedTable(ctx, vcursor, bindVars, irr)
+	if err != nil {
+		return nil, err
+	}
+	qr.RowsAffected += deleted
+	return qr, nil
+}
+
+// deleteReplacedRows runs the Replace primitive for the rows about to be inserted.
+// It returns the rows left to insert, and the number of rows deleted by the replace.
+func (ins *InsertSelect) deleteReplacedRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row) ([]sqltypes.Row, uint64, error) {
+	if ins.Replace == nil {
+		return rows, 0, nil
+	}
+	// The delete and the insert have to be committed together.
+	_ = vcursor.AutocommitApproval()
+
+	kept, err := ins.dedupReplacedRows(vcursor, rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	// like MySQL, a row replaced by a later row of the statement counts as inserted and deleted.
+	replaced := 2 * uint64(len(rows)-len(kept))
+	rows = kept
+
+	bvs := sqltypes.CopyBindVariables(bindVars)
+	for idx, offsets := range ins.ReplaceKeys {
+		if len(offsets) == 1 {
+			bvs[ReplaceKeyVals(idx)] = getBVSingle(rows, offsets[0])
+		} else {
+			bvs[ReplaceKeyVals(idx)] = getBVMulti(rows, offsets)
+		}
+	}
+	qr, err := vcursor.ExecutePrimitive(ctx, ins.Replace, bvs, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, replaced + qr.RowsAffected, nil
+}
+
+// dedupReplacedRows drops the rows clashing with a later row of the same batch on any of the ReplaceKeys.
+// MySQL replaces the rows one by one, so only the last of the rows sharing a key value is left.
+// The rows with a NULL in a key don't clash on it, as a unique key can hold several NULLs.
+func (ins *InsertSelect) dedupReplacedRows(vcursor VCursor, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if len(rows) < 2 {
+		return rows, nil
+	}
+	collation := vcursor.ConnCollation()
+	sqlmode := evalengine.ParseSQLMode(vcursor.SQLMode())
+	seen := make([]map[vthash.Hash]struct{}, len(ins.ReplaceKeys))
+	for idx := range seen {
+		seen[idx] = make(map[vthash.Hash]struct{}, len(rows))
+	}
+
+	keep := make([]bool, len(rows))
+	dropped := false
+	for rowIdx := len(rows) - 1; rowIdx >= 0; rowIdx-- {
+		keep[rowIdx] = true
+	keys:
+		for idx, offsets := range ins.ReplaceKeys {
+			hasher := vthash.New()
+			for _, offset := range offsets {
+				val := rows[rowIdx][offset]
+				if val.IsNull() {
+					continue keys
+				}
+				if err := evalengine.NullsafeHashcode128(&hasher, val, collation, val.Type(), sqlmode, nil); err != nil {
+					return nil, err
+				}
+			}
+			code := hasher.Sum128()
+			if _, found := seen[idx][code]; found {
+				keep[rowIdx] = false
+				dropped = true
+			}
+			seen[idx][code] = struct{}{}
+		}
+	}
+	if !dropped {
+		return rows, nil
+	}
+
+	kept := make([]sqltypes.Row, 0, len(rows))
+	for rowIdx, row := range rows {
+		if keep[rowIdx] {
+			kept = append(kept, row)
+		}
+	}
+	return kept, nil
 }
 
 func (ins *InsertSelect) insertIntoUnshardedTable(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, irr insertRowsResult) (*sqltypes.Result, error) {
@@ -314,7 +436,17 @@ func (ins *InsertSelect) execInsertSharded(ctx context.Context, vcursor VCursor,
 		return &sqltypes.Result{}, nil
 	}
 
-	return ins.insertIntoShardedTable(ctx, vcursor, bindVars, result)
+	var deleted uint64
+	result.rows, deleted, err = ins.deleteReplacedRows(ctx, vcursor, bindVars, result.rows)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := ins.insertIntoShardedTable(ctx, vcursor, bindVars, result)
+	if err != nil {
+		return nil, err
+	}
+	qr.RowsAffected += deleted
+	return qr, nil
 }
 
 func (ins *InsertSelect) description() PrimitiveDescription {
@@ -333,6 +465,14 @@ func (ins *InsertSelect) description() PrimitiveDescription {
 		}
 		other["VindexOffsetFromSelect"] = valuesOffsets
 	}
+	if len(ins.ReplaceKeys) > 0 {
+		replaceKeys := make([]string, 0, len(ins.ReplaceKeys))
+		for idx, offsets := range ins.ReplaceKeys {
+			marshal, _ := json.Marshal(offsets)
+			replaceKeys = append(replaceKeys, fmt.Sprintf("%s:%s", ReplaceKeyVals(idx), marshal))
+		}
+		other["ReplaceKeys"] = replaceKeys
+	}
 
 	return PrimitiveDescription{
 		OperatorType:     "Insert",
@@ -1743,6 +1743,150 @@ func TestInsertSelectSimple(t *testing.T) {
 			` {_c1_0: type:VARCHAR value:"a" _c1_1: type:INT64 value:"3"} true false`})
 }
 
+func TestInsertSelectReplace(t *testing.T) {
+	invschema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Name:    "hash",
+							Columns: []string{"id"}}}}}}}}
+
+	vs := vindexes.BuildVSchema(invschema, sqlparser.NewTestParser())
+	ks := vs.Keyspaces["sharded"]
+
+	rb := &Route{
+		Query:      "dummy_select",
+		FieldQuery: "dummy_field_query",
+		RoutingParameters: &RoutingParameters{
+			Opcode:   Scatter,
+			Keyspace: ks.Keyspace}}
+	ins := newInsertSelect(false, ks.Keyspace, ks.Tables["t1"], "prefix ", nil, [][]int{{1}}, rb)
+	// the rows clashing on the id or on the (name, id) key are deleted before the insert.
+	ins.Replace = &Delete{
+		DML: &DML{
+			Query: "dummy_delete",
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Scatter,
+				Keyspace: ks.Keyspace}}}
+	ins.ReplaceKeys = [][]int{{1}, {0, 1}}
+
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20"}
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"name|id",
+				"varchar|int64"),
+			"a|1",
+			"b|2"),
+		{RowsAffected: 1},
+		{RowsAffected: 2}}
+
+	qr, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+
+		// the select query
+		`ExecuteMultiShard sharded.-20: dummy_select {} sharded.20-: dummy_select {} false false`,
+
+		// the delete of the clashing rows, with the values of each key of the selected rows
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ` +
+			`sharded.-20: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} ` +
+			`dml_vals_1: type:TUPLE values:{type:TUPLE value:"\x950\x01a\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x012"}} ` +
+			`sharded.20-: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} ` +
+			`dml_vals_1: type:TUPLE values:{type:TUPLE value:"\x950\x01a\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x012"}} true false`,
+		`ResolveDestinations sharded [value:"0" value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(06e7ea22ce92708f)`,
+
+		// the insert of the selected rows
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix values (:_c0_0, :_c0_1) ` +
+			`{_c0_0: type:VARCHAR value:"a" _c0_1: type:INT64 value:"1"} ` +
+			`sharded.-20: prefix values (:_c1_0, :_c1_1)` +
+			` {_c1_0: type:VARCHAR value:"b" _c1_1: type:INT64 value:"2"} true false`})
+	// the deleted rows are counted as affected, as MySQL does for REPLACE.
+	require.EqualValues(t, 3, qr.RowsAffected)
+}
+
+func TestInsertSelectReplaceDuplicateKeys(t *testing.T) {
+	invschema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Name:    "hash",
+							Columns: []string{"id"}}}}}}}}
+
+	vs := vindexes.BuildVSchema(invschema, sqlparser.NewTestParser())
+	ks := vs.Keyspaces["sharded"]
+
+	rb := &Route{
+		Query:      "dummy_select",
+		FieldQuery: "dummy_field_query",
+		RoutingParameters: &RoutingParameters{
+			Opcode:   Scatter,
+			Keyspace: ks.Keyspace}}
+	ins := newInsertSelect(false, ks.Keyspace, ks.Tables["t1"], "prefix ", nil, [][]int{{1}}, rb)
+	ins.Replace = &Delete{
+		DML: &DML{
+			Query: "dummy_delete",
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Scatter,
+				Keyspace: ks.Keyspace}}}
+	ins.ReplaceKeys = [][]int{{1}, {0, 1}}
+
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20"}
+	vc.results = []*sqltypes.Result{
+		// the selected rows repeat the id 1 and the key (b, 2): only the last row of each key is inserted.
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"name|id",
+				"varchar|int64"),
+			"a|1",
+			"b|2",
+			"c|1",
+			"b|2"),
+		{RowsAffected: 1},
+		{RowsAffected: 2}}
+
+	qr, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+
+		// the select query
+		`ExecuteMultiShard sharded.-20: dummy_select {} sharded.20-: dummy_select {} false false`,
+
+		// the delete of the clashing rows, with the values of the rows left to insert
+		`ResolveDestinations sharded [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ` +
+			`sharded.-20: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} ` +
+			`dml_vals_1: type:TUPLE values:{type:TUPLE value:"\x950\x01c\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x012"}} ` +
+			`sharded.20-: dummy_delete {dml_vals: type:TUPLE values:{type:INT64 value:"1"} values:{type:INT64 value:"2"} ` +
+			`dml_vals_1: type:TUPLE values:{type:TUPLE value:"\x950\x01c\x89\x02\x011"} values:{type:TUPLE value:"\x950\x01b\x89\x02\x012"}} true false`,
+		`ResolveDestinations sharded [value:"0" value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(06e7ea22ce92708f)`,
+
+		// the insert of the rows left
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix values (:_c0_0, :_c0_1) ` +
+			`{_c0_0: type:VARCHAR value:"c" _c0_1: type:INT64 value:"1"} ` +
+			`sharded.-20: prefix values (:_c1_0, :_c1_1)` +
+			` {_c1_0: type:VARCHAR value:"b" _c1_1: type:INT64 value:"2"} true false`})
+	// the two rows replaced within the select are counted as inserted and deleted.
+	require.EqualValues(t, 7, qr.RowsAffected)
+}
+
 func TestInsertSelectOwned(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -232,7 +232,10 @@ func joinRows(lrow, rrow sqltypes.Row, cols []int) sqltypes.Row {
 	row := make([]sqltypes.Value, len(cols))
 	for i, index := range cols {
 		if index < 0 {
-			row[i] = lrow[-index-1]
+			// lrow can be nil on right and full outer hash joins
+			if lrow != nil {
+				row[i] = lrow[-index-1]
+			}
 			continue
 		}
 		// rrow can be nil on left joins
@@ -251,15 +254,33 @@ type JoinOpcode int
 const (
 	InnerJoin = JoinOpcode(iota)
 	LeftJoin
+	// RightJoin and FullOuterJoin are only used by hash joins
+	RightJoin
+	FullOuterJoin
 )
 
 func (code JoinOpcode) String() string {
-	if code == InnerJoin {
+	switch code {
+	case InnerJoin:
 		return "Join"
+	case RightJoin:
+		return "RightJoin"
+	case FullOuterJoin:
+		return "FullOuterJoin"
 	}
 	return "LeftJoin"
 }
 
+// keepsLeftRows returns true when the rows of the LHS without a match are part of the result
+func (code JoinOpcode) keepsLeftRows() bool {
+	return code == LeftJoin || code == FullOuterJoin
+}
+
+// keepsRightRows returns true when the rows of the RHS without a match are part of the result
+func (code JoinOpcode) keepsRightRows() bool {
+	return code == RightJoin || code == FullOuterJoin
+}
+
 // MarshalJSON serializes the JoinOpcode as a JSON string.
 // It's used for testing and diagnostics.
 func (code JoinOpcode) MarshalJSON() ([]byte, error) {
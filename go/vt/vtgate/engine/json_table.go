@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*JSONTable)(nil)
+
+// JSONTable is a primitive that evaluates a JSON_TABLE at the vtgate level.
+// The JSON document can use bind variables, so when it uses the columns of the tables
+// that come before it in the FROM clause, it is evaluated once for every row of them.
+type JSONTable struct {
+	noInputs
+	noTxNeeded
+
+	// Doc is the JSON document that is turned into rows
+	Doc    evalengine.Expr
+	ASTDoc sqlparser.Expr
+
+	Table *evalengine.JSONTable
+
+	// Cols are the offsets of the columns of the JSON_TABLE that are returned
+	Cols []int
+}
+
+// RouteType implements the Primitive interface
+func (jt *JSONTable) RouteType() string {
+	return "JSONTable"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (jt *JSONTable) GetKeyspaceName() string {
+	return ""
+}
+
+// GetTableName implements the Primitive interface
+func (jt *JSONTable) GetTableName() string {
+	return ""
+}
+
+// TryExecute implements the Primitive interface
+func (jt *JSONTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	env := evalengine.NewExpressionEnv(ctx, bindVars, vcursor)
+	rows, err := jt.Table.Rows(env, jt.Doc)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &sqltypes.Result{Fields: jt.fields()}
+	for _, row := range rows {
+		out := make([]sqltypes.Value, 0, len(jt.Cols))
+		for _, col := range jt.Cols {
+			out = append(out, row[col])
+		}
+		res.Rows = append(res.Rows, out)
+	}
+	return res, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (jt *JSONTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	res, err := jt.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(res)
+}
+
+// GetFields implements the Primitive interface
+func (jt *JSONTable) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: jt.fields()}, nil
+}
+
+func (jt *JSONTable) fields() []*querypb.Field {
+	fields := make([]*querypb.Field, 0, len(jt.Cols))
+	for _, offset := range jt.Cols {
+		col := jt.Table.Columns[offset]
+		fields = append(fields, col.Type.ToField(col.Name))
+	}
+	return fields
+}
+
+func (jt *JSONTable) description() PrimitiveDescription {
+	var cols []string
+	for _, offset := range jt.Cols {
+		cols = append(cols, jt.Table.Columns[offset].Name)
+	}
+	other := map[string]any{
+		"Doc":     sqlparser.String(jt.ASTDoc),
+		"Path":    jt.Table.Path.String(),
+		"Columns": cols,
+	}
+	return PrimitiveDescription{
+		OperatorType: "JSONTable",
+		Other:        other,
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func newTestJSONTable(t *testing.T, columns string, cols []int) *JSONTable {
+	venv := vtenv.NewTestEnv()
+	stmt, err := venv.Parser().Parse("select * from json_table(:u_doc, '$[*]' columns(" + columns + ")) as jt")
+	require.NoError(t, err)
+	node := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+
+	cfg := &evalengine.Config{
+		Collation:   collations.CollationUtf8mb4ID,
+		Environment: venv,
+	}
+	table, err := evalengine.TranslateJSONTable(node, cfg)
+	require.NoError(t, err)
+
+	return &JSONTable{
+		Doc:    evalengine.NewBindVar("u_doc", evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID)),
+		ASTDoc: node.Expr,
+		Table:  table,
+		Cols:   cols,
+	}
+}
+
+func TestJSONTableExecute(t *testing.T) {
+	jt := newTestJSONTable(t, "id for ordinality, a int path '$.a', b varchar(10) path '$.b'", []int{2, 0, 1})
+
+	bv := map[string]*querypb.BindVariable{
+		"u_doc": sqltypes.StringBindVariable(`[{"a": 1, "b": "x"}, {"a": 2}, {"b": "z"}]`),
+	}
+	result, err := jt.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+
+	expected := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("b|id|a", "varchar|uint32|int32"),
+		"x|1|1",
+		"null|2|2",
+		"z|3|null",
+	)
+	require.Equal(t, len(expected.Fields), len(result.Fields))
+	for i, field := range result.Fields {
+		require.Equal(t, expected.Fields[i].Name, field.Name)
+		require.Equal(t, expected.Fields[i].Type, field.Type)
+	}
+	utils.MustMatch(t, expected.Rows, result.Rows)
+
+	fields, err := jt.GetFields(context.Background(), &noopVCursor{}, bv)
+	require.NoError(t, err)
+	utils.MustMatch(t, result.Fields, fields.Fields)
+
+	// a NULL document has no rows
+	bv["u_doc"] = sqltypes.NullBindVariable
+	result, err = jt.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	require.Empty(t, result.Rows)
+}
+
+func TestJSONTableStreamExecuteError(t *testing.T) {
+	jt := newTestJSONTable(t, "a int path '$.a' error on empty", []int{0})
+
+	bv := map[string]*querypb.BindVariable{
+		"u_doc": sqltypes.StringBindVariable(`[{"a": 1}, {"b": 2}]`),
+	}
+	err := jt.TryStreamExecute(context.Background(), &noopVCursor{}, bv, true, func(*sqltypes.Result) error {
+		return nil
+	})
+	require.EqualError(t, err, "Missing value for JSON_TABLE column 'a'")
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*Load)(nil)
+
+// loadBatchSize is the number of rows sent in every INSERT by the Load primitive.
+const loadBatchSize = 1000
+
+// LocalInfileReader requests the content of fileName from the client. The
+// callback is called for every chunk of the file that is received.
+type LocalInfileReader func(fileName string, callback func([]byte) error) error
+
+// WithLocalInfileReader returns a context that lets the Load primitive
+// request files from the client through reader.
+func WithLocalInfileReader(ctx context.Context, reader LocalInfileReader) context.Context {
+	return context.WithValue(ctx, localInfileReader, reader)
+}
+
+// Load is the primitive for LOAD DATA LOCAL INFILE. The file is requested
+// from the client and parsed by vtgate, and its rows are inserted using
+// multi-row INSERT statements of loadBatchSize rows. Every batch is planned
+// like any other INSERT, so the rows are routed using the primary vindex,
+// lookup vindexes are maintained and sequences fill missing values.
+//
+// All the batches are part of a single transaction. In autocommit mode the
+// executor begins it before the first batch and only commits it once the
+// whole file is inserted, and inside an explicit transaction a failing batch
+// rolls back to the savepoint taken before the first one. Either way, an
+// error in the middle of the file does not leave a partial import.
+type Load struct {
+	txNeeded
+
+	Keyspace  *vindexes.Keyspace
+	TableName string
+	FileName  string
+
+	// Insert is the INSERT statement the rows are added to,
+	// without its VALUES clause.
+	Insert string
+	// Columns is the number of fields every row must have.
+	Columns int
+
+	Format LoadFormat
+	// IgnoreLines is the number of lines skipped at the start of the file.
+	IgnoreLines int
+}
+
+// LoadFormat describes how fields and lines are written in a LOAD DATA file.
+// FieldsEnclosedBy and FieldsEscapedBy are either empty or a single byte.
+type LoadFormat struct {
+	FieldsTerminatedBy string
+	FieldsEnclosedBy   string
+	FieldsEscapedBy    string
+	LinesStartingBy    string
+	LinesTerminatedBy  string
+}
+
+// RouteType implements the Primitive interface.
+func (l *Load) RouteType() string {
+	return "Load"
+}
+
+// GetKeyspaceName implements the Primitive interface.
+func (l *Load) GetKeyspaceName() string {
+	return l.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface.
+func (l *Load) GetTableName() string {
+	return l.TableName
+}
+
+// TryExecute implements the Primitive interface.
+func (l *Load) TryExecute(ctx context.Context, vcursor VCursor, _ map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	reader, ok := ctx.Value(localInfileReader).(LocalInfileReader)
+	if !ok || reader == nil {
+		return nil, vterrors.VT09025()
+	}
+
+	ld := newLoader(l, vcursor)
+	err := reader(l.FileName, func(data []byte) error {
+		return ld.write(ctx, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ld.close(ctx); err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{RowsAffected: ld.rowsAffected}, nil
+}
+
+// TryStreamExecute implements the Primitive interface.
+func (l *Load) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	res, err := l.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(res)
+}
+
+// GetFields implements the Primitive interface.
+func (l *Load) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.VT13001("unexpected fields call for load query")
+}
+
+// Inputs implements the Primitive interface.
+func (l *Load) Inputs() ([]Primitive, []map[string]any) {
+	return nil, nil
+}
+
+func (l *Load) description() PrimitiveDescription {
+	other := map[string]any{
+		"FileName":           l.FileName,
+		"Insert":             l.Insert,
+		"FieldsTerminatedBy": l.Format.FieldsTerminatedBy,
+		"LinesTerminatedBy":  l.Format.LinesTerminatedBy,
+	}
+	if l.Format.FieldsEnclosedBy != "" {
+		other["FieldsEnclosedBy"] = l.Format.FieldsEnclosedBy
+	}
+	if l.Format.FieldsEscapedBy != "" {
+		other["FieldsEscapedBy"] = l.Format.FieldsEscapedBy
+	}
+	if l.Format.LinesStartingBy != "" {
+		other["LinesStartingBy"] = l.Format.LinesStartingBy
+	}
+	if l.IgnoreLines > 0 {
+		other["IgnoreLines"] = l.IgnoreLines
+	}
+	return PrimitiveDescription{
+		OperatorType: "Load",
+		Keyspace:     l.Keyspace,
+		Other:        other,
+	}
+}
+
+// loader parses the content of a file while it is received,
+// and inserts the rows it contains.
+type loader struct {
+	*Load
+	vcursor VCursor
+
+	fieldTerm, lineTerm, linePrefix []byte
+	enclosure, escape               byte
+
+	// buf holds the part of the file that has not been parsed yet.
+	buf []byte
+	// line is the number of rows read so far.
+	line int
+
+	bindVars     map[string]*querypb.BindVariable
+	rows         int
+	rowsAffected uint64
+}
+
+func newLoader(l *Load, vcursor VCursor) *loader {
+	ld := &loader{
+		Load:       l,
+		vcursor:    vcursor,
+		fieldTerm:  []byte(l.Format.FieldsTerminatedBy),
+		lineTerm:   []byte(l.Format.LinesTerminatedBy),
+		linePrefix: []byte(l.Format.LinesStartingBy),
+		bindVars:   make(map[string]*querypb.BindVariable),
+	}
+	if l.Format.FieldsEnclosedBy != "" {
+		ld.enclosure = l.Format.FieldsEnclosedBy[0]
+	}
+	if l.Format.FieldsEscapedBy != "" {
+		ld.escape = l.Format.FieldsEscapedBy[0]
+	}
+	return ld
+}
+
+// write adds data to the unparsed content and inserts the complete rows.
+func (ld *loader) write(ctx context.Context, data []byte) error {
+	ld.buf = append(ld.buf, data...)
+	return ld.parse(ctx, false)
+}
+
+// close parses what is left of the file and inserts the last batch.
+func (ld *loader) close(ctx context.Context) error {
+	if err := ld.parse(ctx, true); err != nil {
+		return err
+	}
+	return ld.flush(ctx)
+}
+
+func (ld *loader) parse(ctx context.Context, atEOF bool) error {
+	pos := 0
+	for pos < len(ld.buf) {
+		row, n, ok := ld.nextRow(ld.buf[pos:], atEOF)
+		if !ok {
+			break
+		}
+		pos += n
+		if row == nil {
+			// The line did not contain the LINES STARTING BY prefix.
+			continue
+		}
+		ld.line++
+		if ld.line <= ld.IgnoreLines {
+			continue
+		}
+		if err := ld.addRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	ld.buf = append(ld.buf[:0], ld.buf[pos:]...)
+	return nil
+}
+
+func (ld *loader) addRow(ctx context.Context, row []sqltypes.Value) error {
+	switch {
+	case len(row) < ld.Columns:
+		return vterrors.VT03039(ld.line)
+	case len(row) > ld.Columns:
+		return vterrors.VT03040(ld.line)
+	}
+	for i, val := range row {
+		ld.bindVars[loadBindVarName(ld.rows*ld.Columns+i)] = sqltypes.ValueBindVariable(val)
+	}
+	ld.rows++
+	if ld.rows < loadBatchSize {
+		return nil
+	}
+	return ld.flush(ctx)
+}
+
+// flush inserts the rows of the current batch.
+func (ld *loader) flush(ctx context.Context) error {
+	if ld.rows == 0 {
+		return nil
+	}
+
+	var query strings.Builder
+	query.WriteString(ld.Insert)
+	query.WriteString(" values ")
+	for r := 0; r < ld.rows; r++ {
+		if r > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteByte('(')
+		for c := 0; c < ld.Columns; c++ {
+			if c > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteByte(':')
+			query.WriteString(loadBindVarName(r*ld.Columns + c))
+		}
+		query.WriteByte(')')
+	}
+
+	qr, err := ld.vcursor.Execute(ctx, "Load", query.String(), ld.bindVars, true /* rollbackOnError */, vtgatepb.CommitOrder_NORMAL)
+	if err != nil {
+		return err
+	}
+	ld.rowsAffected += qr.RowsAffected
+	ld.rows = 0
+	ld.bindVars = make(map[string]*querypb.BindVariable, len(ld.bindVars))
+	return nil
+}
+
+func loadBindVarName(i int) string {
+	return "ld" + strconv.Itoa(i)
+}
+
+// nextRow parses the row at the start of buf, and returns its values and the
+// number of bytes it used. A nil row is returned for lines that are skipped.
+// When buf does not hold the complete row and more data can still come,
+// ok is false.
+func (ld *loader) nextRow(buf []byte, atEOF bool) (row []sqltypes.Value, n int, ok bool) {
+	if len(ld.linePrefix) > 0 {
+		start := bytes.Index(buf, ld.linePrefix)
+		end := bytes.Index(buf, ld.lineTerm)
+		switch {
+		case end >= 0 && (start < 0 || end < start):
+			return nil, end + len(ld.lineTerm), true
+		case start < 0 && atEOF:
+			return nil, len(buf), true
+		case start < 0:
+			return nil, 0, false
+		}
+		n = start + len(ld.linePrefix)
+	}
+
+	for {
+		val, size, endOfLine, ok := ld.nextField(buf[n:], atEOF)
+		if !ok {
+			return nil, 0, false
+		}
+		row = append(row, val)
+		n += size
+		if endOfLine {
+			return row, n, true
+		}
+	}
+}
+
+// nextField parses the field at the start of buf, and returns its value and
+// the number of bytes it used, including the terminator that follows it.
+func (ld *loader) nextField(buf []byte, atEOF bool) (val sqltypes.Value, n int, endOfLine, ok bool) {
+	enclosed := ld.enclosure != 0 && len(buf) > 0 && buf[0] == ld.enclosure
+	if enclosed {
+		n = 1
+	}
+
+	var field []byte
+	for {
+		rest := buf[n:]
+		if len(rest) == 0 {
+			if !atEOF {
+				return val, 0, false, false
+			}
+			return ld.fieldValue(buf[:n], field, enclosed), n, true, true
+		}
+
+		if ld.escape != 0 && rest[0] == ld.escape {
+			if len(rest) == 1 {
+				if !atEOF {
+					return val, 0, false, false
+				}
+				field = append(field, rest[0])
+				n++
+				continue
+			}
+			field = append(field, unescapeLoadByte(rest[1]))
+			n += 2
+			continue
+		}
+
+		// The terminators are only looked for after the closing
+		// enclosure of enclosed fields.
+		after := rest
+		if enclosed {
+			if rest[0] != ld.enclosure {
+				field = append(field, rest[0])
+				n++
+				continue
+			}
+			after = rest[1:]
+			if len(after) > 0 && after[0] == ld.enclosure {
+				field = append(field, ld.enclosure)
+				n += 2
+				continue
+			}
+			if len(after) == 0 && atEOF {
+				return ld.fieldValue(buf[:n], field, enclosed), n + 1, true, true
+			}
+		}
+
+		skip := len(rest) - len(after)
+		switch {
+		case bytes.HasPrefix(after, ld.fieldTerm):
+			return ld.fieldValue(buf[:n], field, enclosed), n + skip + len(ld.fieldTerm), false, true
+		case bytes.HasPrefix(after, ld.lineTerm):
+			return ld.fieldValue(buf[:n], field, enclosed), n + skip + len(ld.lineTerm), true, true
+		case !atEOF && (isPartialPrefix(after, ld.fieldTerm) || isPartialPrefix(after, ld.lineTerm)):
+			return val, 0, false, false
+		}
+		field = append(field, rest[0])
+		n++
+	}
+}
+
+// fieldValue returns the value of a field, given its raw content and its
+// content once unescaped. Like MySQL, an unenclosed \N is NULL, and so is
+// an unenclosed NULL when FIELDS ENCLOSED BY is set.
+func (ld *loader) fieldValue(raw, field []byte, enclosed bool) sqltypes.Value {
+	if !enclosed {
+		if ld.escape != 0 && len(raw) == 2 && raw[0] == ld.escape && raw[1] == 'N' {
+			return sqltypes.NULL
+		}
+		if ld.enclosure != 0 && string(raw) == "NULL" {
+			return sqltypes.NULL
+		}
+	}
+	return sqltypes.MakeTrusted(sqltypes.VarChar, field)
+}
+
+// isPartialPrefix returns true if buf is the start of term, but not all of it.
+func isPartialPrefix(buf, term []byte) bool {
+	return len(buf) < len(term) && bytes.HasPrefix(term, buf)
+}
+
+// unescapeLoadByte returns the byte the escape sequence ending with b stands for.
+func unescapeLoadByte(b byte) byte {
+	switch b {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	}
+	return b
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// loadVCursor records the inserts executed by the Load primitive,
+// with the values of every row.
+type loadVCursor struct {
+	noopVCursor
+	queries []string
+	rows    [][]string
+}
+
+func (vc *loadVCursor) Execute(_ context.Context, _ string, query string, bindVars map[string]*querypb.BindVariable, _ bool, _ vtgatepb.CommitOrder) (*sqltypes.Result, error) {
+	vc.queries = append(vc.queries, query)
+	cols := strings.Count(query[:strings.Index(query, ")")], ",") + 1
+	for r := 0; r < len(bindVars)/cols; r++ {
+		var row []string
+		for c := 0; c < cols; c++ {
+			val, err := sqltypes.BindVariableToValue(bindVars[loadBindVarName(r*cols+c)])
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, val.String())
+		}
+		vc.rows = append(vc.rows, row)
+	}
+	return &sqltypes.Result{RowsAffected: uint64(len(bindVars) / cols)}, nil
+}
+
+func newTestLoad(columns int, format LoadFormat) *Load {
+	return &Load{
+		Keyspace:  &vindexes.Keyspace{Name: "ks", Sharded: true},
+		TableName: "t",
+		FileName:  "data.txt",
+		Insert:    "insert into t(a, b)",
+		Columns:   columns,
+		Format:    format,
+	}
+}
+
+var defaultLoadFormat = LoadFormat{
+	FieldsTerminatedBy: "\t",
+	FieldsEscapedBy:    "\\",
+	LinesTerminatedBy:  "\n",
+}
+
+var csvLoadFormat = LoadFormat{
+	FieldsTerminatedBy: ",",
+	FieldsEnclosedBy:   `"`,
+	FieldsEscapedBy:    "\\",
+	LinesTerminatedBy:  "\r\n",
+}
+
+// execLoad runs the primitive with a client that sends the file in chunks.
+func execLoad(l *Load, chunks ...string) (*loadVCursor, *sqltypes.Result, error) {
+	vc := &loadVCursor{}
+	ctx := WithLocalInfileReader(context.Background(), func(fileName string, callback func([]byte) error) error {
+		if fileName != l.FileName {
+			return fmt.Errorf("unexpected file %s", fileName)
+		}
+		for _, chunk := range chunks {
+			if err := callback([]byte(chunk)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	res, err := l.TryExecute(ctx, vc, nil, false)
+	return vc, res, err
+}
+
+func TestLoadFormats(t *testing.T) {
+	tcases := []struct {
+		name   string
+		format LoadFormat
+		ignore int
+		file   string
+		rows   [][]string
+	}{{
+		name:   "default format",
+		format: defaultLoadFormat,
+		file:   "1\tfoo\n2\t\\N\n3\tba\\\tr\\n\n",
+		rows:   [][]string{{`VARCHAR("1")`, `VARCHAR("foo")`}, {`VARCHAR("2")`, "NULL"}, {`VARCHAR("3")`, `VARCHAR("ba\tr\n")`}},
+	}, {
+		name:   "no terminator on the last line",
+		format: defaultLoadFormat,
+		file:   "1\tfoo\n2\t",
+		rows:   [][]string{{`VARCHAR("1")`, `VARCHAR("foo")`}, {`VARCHAR("2")`, `VARCHAR("")`}},
+	}, {
+		name:   "csv with a header",
+		format: csvLoadFormat,
+		ignore: 1,
+		file:   "id,name\r\n1,\"a,b\"\r\n2,\"say \"\"hi\"\"\r\n\"\r\n3,NULL\r\n4,\"NULL\"",
+		rows: [][]string{
+			{`VARCHAR("1")`, `VARCHAR("a,b")`},
+			{`VARCHAR("2")`, `VARCHAR("say \"hi\"\r\n")`},
+			{`VARCHAR("3")`, "NULL"},
+			{`VARCHAR("4")`, `VARCHAR("NULL")`},
+		},
+	}, {
+		name: "lines starting by",
+		format: LoadFormat{
+			FieldsTerminatedBy: ",",
+			LinesStartingBy:    ">>",
+			LinesTerminatedBy:  "\n",
+		},
+		file: "xx>>1,a\nno prefix\n>>2,\\N\n",
+		rows: [][]string{{`VARCHAR("1")`, `VARCHAR("a")`}, {`VARCHAR("2")`, `VARCHAR("\\N")`}},
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newTestLoad(2, tc.format)
+			l.IgnoreLines = tc.ignore
+
+			vc, res, err := execLoad(l, tc.file)
+			require.NoError(t, err)
+			assert.Equal(t, tc.rows, vc.rows)
+			assert.EqualValues(t, len(tc.rows), res.RowsAffected)
+
+			// The result does not depend on how the file is split in chunks.
+			chunks := strings.Split(tc.file, "")
+			vc, _, err = execLoad(l, chunks...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.rows, vc.rows)
+		})
+	}
+}
+
+func TestLoadBatches(t *testing.T) {
+	var file strings.Builder
+	for i := 0; i < 2*loadBatchSize+10; i++ {
+		fmt.Fprintf(&file, "%d\tname%d\n", i, i)
+	}
+
+	vc, res, err := execLoad(newTestLoad(2, defaultLoadFormat), file.String())
+	require.NoError(t, err)
+	require.Len(t, vc.queries, 3)
+	assert.True(t, strings.HasPrefix(vc.queries[0], "insert into t(a, b) values (:ld0, :ld1), (:ld2, :ld3), "))
+	assert.True(t, strings.HasSuffix(vc.queries[2], fmt.Sprintf("(:ld%d, :ld%d)", 9*2, 9*2+1)))
+	assert.EqualValues(t, 2*loadBatchSize+10, res.RowsAffected)
+	assert.Equal(t, []string{`VARCHAR("2009")`, `VARCHAR("name2009")`}, vc.rows[2009])
+}
+
+func TestLoadErrors(t *testing.T) {
+	l := newTestLoad(2, defaultLoadFormat)
+
+	_, _, err := execLoad(l, "1\ta\n2\n")
+	require.EqualError(t, err, "VT03039: Row 2 doesn't contain data for all columns")
+
+	_, _, err = execLoad(l, "1\ta\tb\n")
+	require.EqualError(t, err, "VT03040: Row 1 was truncated; it contained more data than there were input columns")
+
+	_, err = l.TryExecute(context.Background(), &loadVCursor{}, nil, false)
+	require.EqualError(t, err, "VT09025: LOAD DATA LOCAL INFILE needs a client connected over the MySQL protocol")
+}
@@ -19,6 +19,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"reflect"
 	"strconv"
@@ -93,6 +94,10 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 		return callback(qr.Truncate(ms.TruncateColumnCount))
 	}
 
+	if mem := newMemoryTracker(vcursor); mem.spilling() {
+		return ms.streamExternalSort(ctx, vcursor, bindVars, wantfields, count, mem, cb)
+	}
+
 	sorter := &evalengine.Sorter{
 		Compare: ms.OrderBy,
 		Limit:   count,
@@ -121,6 +126,156 @@ func (ms *MemorySort) TryStreamExecute(ctx context.Context, vcursor VCursor, bin
 	return cb(&sqltypes.Result{Rows: sorter.Sorted()})
 }
 
+// streamExternalSort sorts the input using the memory budget of the query. The rows that don't fit
+// in the budget are spilled to disk, so the number of rows is not limited by max_memory_rows.
+func (ms *MemorySort) streamExternalSort(
+	ctx context.Context,
+	vcursor VCursor,
+	bindVars map[string]*querypb.BindVariable,
+	wantfields bool,
+	count int,
+	mem *memoryTracker,
+	cb func(*sqltypes.Result) error,
+) error {
+	sorter := &externalSorter{
+		compare: ms.OrderBy,
+		limit:   count,
+		dir:     vcursor.SpillDir(),
+		mem:     mem,
+	}
+	defer sorter.close()
+
+	var mu sync.Mutex
+	err := vcursor.StreamExecutePrimitive(ctx, ms.Input, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(qr.Fields) != 0 {
+			if err := cb(&sqltypes.Result{Fields: qr.Fields}); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if err := sorter.push(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return sorter.sorted(func(rows []sqltypes.Row) error {
+		return cb(&sqltypes.Result{Rows: rows})
+	})
+}
+
+// externalSorter is an external merge sort. When the memory budget is exhausted, the rows in memory
+// are sorted and written to disk as a run. At the end, the runs and the rows still in memory are merged.
+type externalSorter struct {
+	compare evalengine.Comparison
+	limit   int
+	dir     string
+	mem     *memoryTracker
+
+	rows []sqltypes.Row
+	runs []*spillFile
+}
+
+func (es *externalSorter) push(row sqltypes.Row) error {
+	es.rows = append(es.rows, row)
+	if es.mem.add(rowSize(row)) {
+		return nil
+	}
+	return es.spill()
+}
+
+func (es *externalSorter) spill() error {
+	es.compare.Sort(es.rows)
+	run, err := newSpillFile(es.dir)
+	if err != nil {
+		return err
+	}
+	es.runs = append(es.runs, run)
+	// rows after the limit can't be part of the result
+	for _, row := range es.rows[:min(len(es.rows), es.limit)] {
+		if err := run.write(row); err != nil {
+			return err
+		}
+	}
+	es.rows = nil
+	es.mem.releaseAll()
+	return nil
+}
+
+// sorted sends the sorted rows to the callback, in batches when rows have been spilled
+func (es *externalSorter) sorted(callback func([]sqltypes.Row) error) error {
+	es.compare.Sort(es.rows)
+	if len(es.runs) == 0 {
+		return callback(es.rows[:min(len(es.rows), es.limit)])
+	}
+
+	// the rows still in memory are merged as the last source, after the runs
+	inMemory := len(es.runs)
+	merge := &evalengine.Merger{Compare: es.compare}
+	pushNext := func(source int) error {
+		if source == inMemory {
+			if len(es.rows) > 0 {
+				merge.Push(es.rows[0], source)
+				es.rows = es.rows[1:]
+			}
+			return nil
+		}
+		row, err := es.runs[source].next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		merge.Push(row, source)
+		return nil
+	}
+
+	for source, run := range es.runs {
+		if err := run.rewind(); err != nil {
+			return err
+		}
+		if err := pushNext(source); err != nil {
+			return err
+		}
+	}
+	if err := pushNext(inMemory); err != nil {
+		return err
+	}
+	merge.Init()
+
+	batch := make([]sqltypes.Row, 0, spillBatchSize)
+	for sent := 0; merge.Len() > 0 && sent < es.limit; sent++ {
+		row, source := merge.Pop()
+		batch = append(batch, row)
+		if len(batch) == spillBatchSize {
+			if err := callback(batch); err != nil {
+				return err
+			}
+			batch = make([]sqltypes.Row, 0, spillBatchSize)
+		}
+		if err := pushNext(source); err != nil {
+			return err
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return callback(batch)
+}
+
+func (es *externalSorter) close() {
+	for _, run := range es.runs {
+		run.close()
+	}
+	es.mem.releaseAll()
+}
+
 // GetFields satisfies the Primitive interface.
 func (ms *MemorySort) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	return ms.Input.GetFields(ctx, vcursor, bindVars)
@@ -404,6 +404,51 @@ func TestMemorySortStreamExecute(t *testing.T) {
 	utils.MustMatch(t, wantResults, results)
 }
 
+func TestMemorySortStreamExecuteSpilling(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: sqltypes.MakeTestStreamingResults(
+			fields,
+			"a|1",
+			"g|2",
+			"---",
+			"a|1",
+			"x|null",
+			"---",
+			"c|4",
+			"c|3",
+		),
+		allResultsInOneCall: true,
+	}
+
+	ms := &MemorySort{
+		OrderBy: []evalengine.OrderByParams{{
+			WeightStringCol: -1,
+			Col:             1,
+		}},
+		Input: fp,
+	}
+
+	// a budget of 100 bytes keeps a single row in memory, so every other row is spilled to disk
+	vc := &loggingVCursor{memoryBudget: NewMemoryBudget(100), spillDir: t.TempDir()}
+	result, err := wrapStreamExecute(ms, vc, nil, true)
+	require.NoError(t, err)
+	require.NoError(t, sqltypes.RowsEqualsStr(`[[VARBINARY("x") NULL] [VARBINARY("a") DECIMAL(1)] [VARBINARY("a") DECIMAL(1)] [VARBINARY("g") DECIMAL(2)] [VARBINARY("c") DECIMAL(3)] [VARBINARY("c") DECIMAL(4)]]`, result.Rows))
+	require.Zero(t, vc.memoryBudget.Used())
+	requireEmptyDir(t, vc.spillDir)
+
+	fp.rewind()
+	ms.UpperLimit = evalengine.NewBindVar("__upper_limit", evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID))
+	bv := map[string]*querypb.BindVariable{"__upper_limit": sqltypes.Int64BindVariable(3)}
+	result, err = wrapStreamExecute(ms, vc, bv, true)
+	require.NoError(t, err)
+	require.NoError(t, sqltypes.RowsEqualsStr(`[[VARBINARY("x") NULL] [VARBINARY("a") DECIMAL(1)] [VARBINARY("a") DECIMAL(1)]]`, result.Rows))
+	requireEmptyDir(t, vc.spillDir)
+}
+
 func TestMemorySortGetFields(t *testing.T) {
 	result := sqltypes.MakeTestResult(
 		sqltypes.MakeTestFields(
@@ -77,7 +77,18 @@ const (
 	AggregateCountStar
 	AggregateGroupConcat
 	AggregateAvg
-	AggregateUDF  // This is an opcode used to represent UDFs
+	AggregateUDF // This is an opcode used to represent UDFs
+	AggregateGrouping
+	AggregateBitAnd
+	AggregateBitOr
+	AggregateBitXor
+	AggregateVarPop
+	AggregateVarSamp
+	AggregateStddevPop
+	AggregateStddevSamp
+	AggregateJSONArrayAgg
+	AggregateJSONObjectAgg
+	AggregateJSONMerge
 	_NumOfOpCodes // This line must be last of the opcodes!
 )
 
@@ -89,6 +100,19 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"min":   AggregateMin,
 	"max":   AggregateMax,
 	"avg":   AggregateAvg,
+
+	"bit_and":        AggregateBitAnd,
+	"bit_or":         AggregateBitOr,
+	"bit_xor":        AggregateBitXor,
+	"var_pop":        AggregateVarPop,
+	"variance":       AggregateVarPop,
+	"var_samp":       AggregateVarSamp,
+	"stddev_pop":     AggregateStddevPop,
+	"stddev":         AggregateStddevPop,
+	"std":            AggregateStddevPop,
+	"stddev_samp":    AggregateStddevSamp,
+	"json_arrayagg":  AggregateJSONArrayAgg,
+	"json_objectagg": AggregateJSONObjectAgg,
 	// These functions don't exist in mysql, but are used
 	// to display the plan.
 	"count_distinct": AggregateCountDistinct,
@@ -97,6 +121,8 @@ var SupportedAggregates = map[string]AggregateOpcode{
 	"count_star":     AggregateCountStar,
 	"any_value":      AggregateAnyValue,
 	"group_concat":   AggregateGroupConcat,
+	"grouping":       AggregateGrouping,
+	"json_merge":     AggregateJSONMerge,
 }
 
 var AggregateName = map[AggregateOpcode]string{
@@ -111,6 +137,17 @@ var AggregateName = map[AggregateOpcode]string{
 	AggregateGroupConcat:   "group_concat",
 	AggregateAnyValue:      "any_value",
 	AggregateAvg:           "avg",
+	AggregateGrouping:      "grouping",
+	AggregateBitAnd:        "bit_and",
+	AggregateBitOr:         "bit_or",
+	AggregateBitXor:        "bit_xor",
+	AggregateVarPop:        "var_pop",
+	AggregateVarSamp:       "var_samp",
+	AggregateStddevPop:     "stddev_pop",
+	AggregateStddevSamp:    "stddev_samp",
+	AggregateJSONArrayAgg:  "json_arrayagg",
+	AggregateJSONObjectAgg: "json_objectagg",
+	AggregateJSONMerge:     "json_merge",
 }
 
 func (code AggregateOpcode) String() string {
@@ -150,10 +187,23 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 			return sqltypes.Decimal
 		}
 		return sqltypes.Float64
-	case AggregateCount, AggregateCountStar, AggregateCountDistinct:
+	case AggregateCount, AggregateCountStar, AggregateCountDistinct, AggregateGrouping:
 		return sqltypes.Int64
 	case AggregateGtid:
 		return sqltypes.VarChar
+	case AggregateBitAnd, AggregateBitOr, AggregateBitXor:
+		if typ == sqltypes.Unknown {
+			return sqltypes.Unknown
+		}
+		// binary strings are aggregated byte by byte, everything else as an unsigned integer
+		if typ == sqltypes.VarBinary || typ == sqltypes.Binary || typ == sqltypes.Blob {
+			return sqltypes.VarBinary
+		}
+		return sqltypes.Uint64
+	case AggregateVarPop, AggregateVarSamp, AggregateStddevPop, AggregateStddevSamp:
+		return sqltypes.Float64
+	case AggregateJSONArrayAgg, AggregateJSONObjectAgg, AggregateJSONMerge:
+		return sqltypes.TypeJSON
 	case AggregateUDF:
 		return sqltypes.Unknown
 	default:
@@ -163,7 +213,7 @@ func (code AggregateOpcode) SQLType(typ querypb.Type) querypb.Type {
 
 func (code AggregateOpcode) Nullable() bool {
 	switch code {
-	case AggregateCount, AggregateCountStar:
+	case AggregateCount, AggregateCountStar, AggregateGrouping, AggregateBitAnd, AggregateBitOr, AggregateBitXor:
 		return false
 	default:
 		return true
@@ -200,3 +250,63 @@ func (code AggregateOpcode) IsDistinct() bool {
 		return false
 	}
 }
+
+// WindowOpcode is the opcode for the functions evaluated by the Window primitive.
+type WindowOpcode int
+
+// These constants list the possible window function opcodes.
+const (
+	WindowUnassigned = WindowOpcode(iota)
+	WindowRowNumber
+	WindowRank
+	WindowDenseRank
+	WindowPercentRank
+	WindowCumeDist
+	WindowNtile
+	WindowLag
+	WindowLead
+	WindowFirstValue
+	WindowLastValue
+	WindowNthValue
+	WindowAggregate // aggregate functions used with an OVER clause
+)
+
+var WindowName = map[WindowOpcode]string{
+	WindowRowNumber:   "row_number",
+	WindowRank:        "rank",
+	WindowDenseRank:   "dense_rank",
+	WindowPercentRank: "percent_rank",
+	WindowCumeDist:    "cume_dist",
+	WindowNtile:       "ntile",
+	WindowLag:         "lag",
+	WindowLead:        "lead",
+	WindowFirstValue:  "first_value",
+	WindowLastValue:   "last_value",
+	WindowNthValue:    "nth_value",
+	WindowAggregate:   "aggregate",
+}
+
+func (code WindowOpcode) String() string {
+	name := WindowName[code]
+	if name == "" {
+		name = "ERROR"
+	}
+	return name
+}
+
+// MarshalJSON serializes the WindowOpcode as a JSON string.
+// It's used for testing and diagnostics.
+func (code WindowOpcode) MarshalJSON() ([]byte, error) {
+	return ([]byte)(fmt.Sprintf("\"%s\"", code.String())), nil
+}
+
+// UsesFrame returns true if the result of the function depends on the window frame.
+// Ranking functions and LAG/LEAD always work on the whole partition.
+func (code WindowOpcode) UsesFrame() bool {
+	switch code {
+	case WindowFirstValue, WindowLastValue, WindowNthValue, WindowAggregate:
+		return true
+	default:
+		return false
+	}
+}
@@ -52,6 +52,14 @@ func TestType(t *testing.T) {
 		{AggregateCount, sqltypes.Int32, sqltypes.Int64},
 		{AggregateCountStar, sqltypes.Int64, sqltypes.Int64},
 		{AggregateGtid, sqltypes.VarChar, sqltypes.VarChar},
+		{AggregateBitAnd, sqltypes.Int32, sqltypes.Uint64},
+		{AggregateBitOr, sqltypes.VarChar, sqltypes.Uint64},
+		{AggregateBitXor, sqltypes.VarBinary, sqltypes.VarBinary},
+		{AggregateBitXor, sqltypes.Unknown, sqltypes.Unknown},
+		{AggregateVarPop, sqltypes.Int64, sqltypes.Float64},
+		{AggregateStddevSamp, sqltypes.Decimal, sqltypes.Float64},
+		{AggregateJSONArrayAgg, sqltypes.VarChar, sqltypes.TypeJSON},
+		{AggregateJSONMerge, sqltypes.TypeJSON, sqltypes.TypeJSON},
 	}
 
 	for _, tc := range tt {
@@ -130,6 +138,11 @@ func TestAggregateOpcode_MarshalJSON(t *testing.T) {
 		{AggregateGroupConcat, "\"group_concat\""},
 		{AggregateAnyValue, "\"any_value\""},
 		{AggregateAvg, "\"avg\""},
+		{AggregateGrouping, "\"grouping\""},
+		{AggregateBitXor, "\"bit_xor\""},
+		{AggregateStddevPop, "\"stddev_pop\""},
+		{AggregateJSONObjectAgg, "\"json_objectagg\""},
+		{AggregateJSONMerge, "\"json_merge\""},
 		{999, "\"ERROR\""},
 	}
 
@@ -44,6 +44,11 @@ type OrderedAggregate struct {
 	// the aggregation key.
 	GroupByKeys []*GroupByParams
 
+	// WithRollup is set for GROUP BY ... WITH ROLLUP. After each group, the
+	// primitive then also returns the super-aggregate rows of the groups that
+	// are complete, with the rolled up keys set to NULL.
+	WithRollup bool
+
 	// TruncateColumnCount specifies the number of columns to return
 	// in the final result. Rest of the columns are truncated
 	// from the result received. If 0, no truncation happens.
@@ -142,14 +147,18 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	if err != nil {
 		return nil, err
 	}
+	if oa.WithRollup {
+		return oa.executeRollup(vcursor, result)
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeGroupBy(result)
 	}
 
-	agg, fields, err := newAggregation(result.Fields, oa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, oa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
+	defer agg.close()
 
 	out := &sqltypes.Result{
 		Fields: fields,
@@ -166,7 +175,11 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 		}
 
 		if nextGroup {
-			out.Rows = append(out.Rows, agg.finish())
+			row, err := agg.finish()
+			if err != nil {
+				return nil, err
+			}
+			out.Rows = append(out.Rows, row)
 			agg.reset()
 		}
 
@@ -176,7 +189,11 @@ func (oa *OrderedAggregate) execute(ctx context.Context, vcursor VCursor, bindVa
 	}
 
 	if currentKey != nil {
-		out.Rows = append(out.Rows, agg.finish())
+		row, err := agg.finish()
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, row)
 	}
 
 	return out, nil
@@ -235,6 +252,9 @@ func (oa *OrderedAggregate) executeStreamGroupBy(ctx context.Context, vcursor VC
 
 // TryStreamExecute is a Primitive function.
 func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	if oa.WithRollup {
+		return oa.executeStreamRollup(ctx, vcursor, bindVars, callback)
+	}
 	if len(oa.Aggregates) == 0 {
 		return oa.executeStreamGroupBy(ctx, vcursor, bindVars, callback)
 	}
@@ -246,12 +266,15 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	var agg aggregationState
 	var fields []*querypb.Field
 	var currentKey []sqltypes.Value
+	defer func() {
+		agg.close()
+	}()
 
 	visitor := func(qr *sqltypes.Result) error {
 		var err error
 
 		if agg == nil && len(qr.Fields) != 0 {
-			agg, fields, err = newAggregation(qr.Fields, oa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, qr.Fields, oa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -271,7 +294,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 
 			if nextGroup {
 				// this is a new grouping. let's yield the old one, and start a new
-				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+				row, err := agg.finish()
+				if err != nil {
+					return err
+				}
+				if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{row}}); err != nil {
 					return err
 				}
 
@@ -292,7 +319,11 @@ func (oa *OrderedAggregate) TryStreamExecute(ctx context.Context, vcursor VCurso
 	}
 
 	if currentKey != nil {
-		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}}); err != nil {
+		row, err := agg.finish()
+		if err != nil {
+			return err
+		}
+		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{row}}); err != nil {
 			return err
 		}
 	}
@@ -306,7 +337,12 @@ func (oa *OrderedAggregate) GetFields(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, oa.Aggregates)
+	var fields []*querypb.Field
+	if oa.WithRollup {
+		_, fields, err = oa.newRollup(vcursor, qr.Fields)
+	} else {
+		_, fields, err = newAggregation(vcursor, qr.Fields, oa.Aggregates)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -326,34 +362,229 @@ func (oa *OrderedAggregate) NeedsTransaction() bool {
 }
 
 func (oa *OrderedAggregate) nextGroupBy(currentKey, nextRow []sqltypes.Value) (nextKey []sqltypes.Value, nextGroup bool, err error) {
+	nextKey, changed, err := oa.changedKey(currentKey, nextRow)
+	return nextKey, changed < len(oa.GroupByKeys), err
+}
+
+// changedKey returns the index of the first grouping key that differs between the
+// current key and the next row, or len(GroupByKeys) if the row is part of the current group.
+func (oa *OrderedAggregate) changedKey(currentKey, nextRow []sqltypes.Value) (nextKey []sqltypes.Value, changed int, err error) {
 	if currentKey == nil {
-		return nextRow, false, nil
+		return nextRow, len(oa.GroupByKeys), nil
 	}
 
-	for _, gb := range oa.GroupByKeys {
+	for idx, gb := range oa.GroupByKeys {
 		v1 := currentKey[gb.KeyCol]
 		v2 := nextRow[gb.KeyCol]
 		if v1.TinyWeightCmp(v2) != 0 {
-			return nextRow, true, nil
+			return nextRow, idx, nil
 		}
 
 		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 		if err != nil {
 			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
 			if !isCollationErr || gb.WeightStringCol == -1 {
-				return nil, false, err
+				return nil, 0, err
 			}
 			gb.KeyCol = gb.WeightStringCol
 			cmp, err = evalengine.NullsafeCompare(currentKey[gb.WeightStringCol], nextRow[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
 			if err != nil {
-				return nil, false, err
+				return nil, 0, err
 			}
 		}
 		if cmp != 0 {
-			return nextRow, true, nil
+			return nextRow, idx, nil
+		}
+	}
+	return currentKey, len(oa.GroupByKeys), nil
+}
+
+// rollup holds one aggregation state per level of GROUP BY ... WITH ROLLUP.
+// levels[i] aggregates the rows sharing the first i grouping keys, so the last
+// level produces the regular groups and levels[0] the grand total.
+type rollup struct {
+	keys   []*GroupByParams
+	levels []aggregationState
+}
+
+func (oa *OrderedAggregate) newRollup(vcursor VCursor, fields []*querypb.Field) (*rollup, []*querypb.Field, error) {
+	r := &rollup{keys: oa.GroupByKeys}
+	var outFields []*querypb.Field
+	for level := 0; level <= len(oa.GroupByKeys); level++ {
+		agg, aggFields, err := newAggregation(vcursor, fields, oa.Aggregates)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, aggr := range oa.Aggregates {
+			if grouping, ok := agg[aggr.Col].(*aggregatorGrouping); ok {
+				grouping.mask = groupingMask(aggr.GroupingKeys, level)
+			}
+		}
+		r.levels = append(r.levels, agg)
+		outFields = aggFields
+	}
+
+	// the rolled up keys are NULL in the super-aggregate rows
+	for _, gb := range oa.GroupByKeys {
+		outFields[gb.KeyCol].Flags &^= uint32(querypb.MySqlFlag_NOT_NULL_FLAG)
+	}
+	return r, outFields, nil
+}
+
+// groupingMask returns the value of GROUPING() for the rows of the given level:
+// the bit of an argument is set when its grouping key is rolled up.
+func groupingMask(keys []int, level int) int64 {
+	var mask int64
+	for _, key := range keys {
+		mask <<= 1
+		if key >= level {
+			mask |= 1
 		}
 	}
-	return currentKey, false, nil
+	return mask
+}
+
+func (r *rollup) add(row []sqltypes.Value) error {
+	for _, level := range r.levels {
+		if err := level.add(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish returns the rows of all the levels that are complete when the grouping
+// key at index changed differs, starting with the regular group, and resets them.
+func (r *rollup) finish(changed int) ([]sqltypes.Row, error) {
+	var rows []sqltypes.Row
+	for level := len(r.levels) - 1; level > changed; level-- {
+		row, err := r.levels[level].finish()
+		if err != nil {
+			return nil, err
+		}
+		for _, gb := range r.keys[level:] {
+			row[gb.KeyCol] = sqltypes.NULL
+			if gb.WeightStringCol >= 0 {
+				row[gb.WeightStringCol] = sqltypes.NULL
+			}
+		}
+		r.levels[level].reset()
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// close removes the rows spilled to disk by all the levels
+func (r *rollup) close() {
+	if r == nil {
+		return
+	}
+	for _, level := range r.levels {
+		level.close()
+	}
+}
+
+func (oa *OrderedAggregate) executeRollup(vcursor VCursor, result *sqltypes.Result) (*sqltypes.Result, error) {
+	r, fields, err := oa.newRollup(vcursor, result.Fields)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close()
+
+	out := &sqltypes.Result{
+		Fields: fields,
+		Rows:   make([][]sqltypes.Value, 0, len(result.Rows)),
+	}
+
+	var currentKey []sqltypes.Value
+	for _, row := range result.Rows {
+		var changed int
+		currentKey, changed, err = oa.changedKey(currentKey, row)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := r.finish(changed)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+
+		if err := r.add(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if currentKey != nil {
+		rows, err := r.finish(-1)
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+	}
+	return out, nil
+}
+
+func (oa *OrderedAggregate) executeStreamRollup(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
+	cb := func(qr *sqltypes.Result) error {
+		return callback(qr.Truncate(oa.TruncateColumnCount))
+	}
+
+	var r *rollup
+	var currentKey []sqltypes.Value
+	defer func() {
+		r.close()
+	}()
+
+	visitor := func(qr *sqltypes.Result) error {
+		var err error
+		if r == nil && len(qr.Fields) != 0 {
+			var fields []*querypb.Field
+			r, fields, err = oa.newRollup(vcursor, qr.Fields)
+			if err != nil {
+				return err
+			}
+			if err = cb(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range qr.Rows {
+			var changed int
+			currentKey, changed, err = oa.changedKey(currentKey, row)
+			if err != nil {
+				return err
+			}
+			rows, err := r.finish(changed)
+			if err != nil {
+				return err
+			}
+			if len(rows) > 0 {
+				if err := cb(&sqltypes.Result{Rows: rows}); err != nil {
+					return err
+				}
+			}
+
+			if err := r.add(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	err := vcursor.StreamExecutePrimitive(ctx, oa.Input, bindVars, true, visitor)
+	if err != nil {
+		return err
+	}
+
+	if currentKey != nil {
+		rows, err := r.finish(-1)
+		if err != nil {
+			return err
+		}
+		return cb(&sqltypes.Result{Rows: rows})
+	}
+	return nil
 }
 func aggregateParamsToString(in any) string {
 	return in.(*AggregateParams).String()
@@ -373,6 +604,9 @@ func (oa *OrderedAggregate) description() PrimitiveDescription {
 	if oa.TruncateColumnCount > 0 {
 		other["ResultColumns"] = oa.TruncateColumnCount
 	}
+	if oa.WithRollup {
+		other["WithRollup"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType: "Aggregate",
 		Variant:      "Ordered",
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
@@ -875,6 +876,76 @@ func TestMultiDistinct(t *testing.T) {
 	utils.MustMatch(t, want, results)
 }
 
+func TestHashedDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|int64|int64|int64",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			// the input is only sorted on the grouping column
+			"10|2|1|2",
+			"10|1|3|1",
+			"10|2|3|2",
+			"10|1|1|1",
+			"10|2|1|2",
+			"20|null|1|null",
+			"20|1|null|1",
+			"20|1|1|1",
+			"30|3|3|3",
+		)},
+	}
+
+	hashed := func(cols ...int) []CheckCol {
+		return slice.Map(cols, func(col int) CheckCol {
+			return CheckCol{Col: col, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()}
+		})
+	}
+	countC2 := NewAggregateParam(AggregateCountDistinct, 1, "count(distinct c2)", collations.MySQL8())
+	countC2.DistinctCols = hashed(1)
+	sumC3 := NewAggregateParam(AggregateSumDistinct, 2, "sum(distinct c3)", collations.MySQL8())
+	sumC3.DistinctCols = hashed(2)
+	countBoth := NewAggregateParam(AggregateCountDistinct, 3, "count(distinct c2, c3)", collations.MySQL8())
+	countBoth.DistinctCols = hashed(3, 2)
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{countC2, sumC3, countBoth},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"c1|count(distinct c2)|sum(distinct c3)|count(distinct c2, c3)",
+			"int64|int64|decimal|int64",
+		),
+		`10|2|4|4`,
+		`20|1|1|1`,
+		`30|1|3|1`,
+	)
+
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+
+	// the budget only fits the first value seen, the other values of the groups are spilled to disk
+	vc := &loggingVCursor{memoryBudget: NewMemoryBudget(1), spillDir: t.TempDir()}
+	fp.rewind()
+	qr, err = oa.TryExecute(context.Background(), vc, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+	require.Zero(t, vc.memoryBudget.Used())
+	requireEmptyDir(t, vc.spillDir)
+
+	fp.rewind()
+	qr, err = wrapStreamExecute(oa, vc, nil, true)
+	require.NoError(t, err)
+	utils.MustMatch(t, want, qr)
+	require.Zero(t, vc.memoryBudget.Used())
+	requireEmptyDir(t, vc.spillDir)
+}
+
 func TestOrderedAggregateCollate(t *testing.T) {
 	fields := sqltypes.MakeTestFields(
 		"col|count(*)",
@@ -1169,3 +1240,265 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestGroupConcatOnVtgate tests group_concat with several arguments, distinct and order by, evaluated on engine.
+func TestGroupConcatOnVtgate(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|varchar|int64|int64",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"10|a|1|3",
+		"10|b|2|1",
+		"10|a|1|2",
+		"10|c|null|4",
+		"20|x|1|1",
+		"20|y|2|1",
+		"30|null|1|1",
+	)
+
+	agp := NewAggregateParam(AggregateGroupConcat, 1, "group_concat(distinct c2, c3 order by c4 desc separator ';')", collations.MySQL8())
+	agp.Func = &sqlparser.GroupConcatExpr{Separator: ";"}
+	agp.ArgCols = []int{1, 2}
+	agp.DistinctCols = []CheckCol{
+		{Col: 1, Type: evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID), CollationEnv: collations.MySQL8()},
+		{Col: 2, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()},
+	}
+	agp.GroupConcatOrderBy = evalengine.Comparison{{Col: 3, WeightStringCol: -1, Desc: true, Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID), CollationEnv: collations.MySQL8()}}
+	require.Equal(t, "group_concat(distinct 1: utf8mb4_0900_ai_ci, 2 order by 3 DESC) AS group_concat(distinct c2, c3 order by c4 desc separator ';')", agp.String())
+
+	outFields := sqltypes.MakeTestFields(
+		"c1|group_concat(distinct c2, c3 order by c4 desc separator ';')|c3|c4",
+		"int64|text|int64|int64",
+	)
+	tcases := []struct {
+		name   string
+		maxLen string
+		want   *sqltypes.Result
+	}{{
+		name: "default group_concat_max_len",
+		want: sqltypes.MakeTestResult(outFields,
+			`10|a1;b2|1|3`,
+			`20|x1;y2|1|1`,
+			`30|null|1|1`),
+	}, {
+		name:   "result cut by group_concat_max_len",
+		maxLen: "3",
+		want: sqltypes.MakeTestResult(outFields,
+			`10|a1;|1|3`,
+			`20|x1;|1|1`,
+			`30|null|1|1`),
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			oa := &OrderedAggregate{
+				Aggregates:  []*AggregateParams{agp},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+				Input:       &fakePrimitive{results: []*sqltypes.Result{input}},
+			}
+			vc := &loggingVCursor{}
+			if tcase.maxLen != "" {
+				vc.systemVariables = map[string]string{"group_concat_max_len": tcase.maxLen}
+			}
+			qr, err := oa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			utils.MustMatch(t, tcase.want, qr)
+		})
+	}
+}
+
+// TestGroupConcatMaxLen tests that group_concat_max_len doesn't split the characters of text results.
+func TestGroupConcatMaxLen(t *testing.T) {
+	tcases := []struct {
+		typ  string
+		want string
+	}{{
+		typ:  "text",
+		want: "10|é,",
+	}, {
+		typ:  "blob",
+		want: "10|é,\xc3",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.typ, func(t *testing.T) {
+			fields := sqltypes.MakeTestFields("c1|group_concat(c2)", "int64|"+tcase.typ)
+			agp := NewAggregateParam(AggregateGroupConcat, 1, "", collations.MySQL8())
+			agp.Func = &sqlparser.GroupConcatExpr{Separator: ","}
+			oa := &OrderedAggregate{
+				Aggregates:  []*AggregateParams{agp},
+				GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+				Input:       &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "10|é", "10|é", "10|é")}},
+			}
+			vc := &loggingVCursor{systemVariables: map[string]string{"group_concat_max_len": "4"}}
+			qr, err := oa.TryExecute(context.Background(), vc, nil, false)
+			require.NoError(t, err)
+			want := sqltypes.MakeTestResult(fields, tcase.want)
+			assert.Equal(t, want.Rows[0][1].Raw(), qr.Rows[0][1].Raw())
+		})
+	}
+}
+
+func TestOrderedAggregateRollup(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|b|grouping(a, b)|sum(c)",
+		"varbinary|varbinary|int64|decimal",
+	)
+	input := sqltypes.MakeTestResult(
+		fields,
+		"x|1|0|1",
+		"x|1|0|2",
+		"x|2|0|3",
+		"y|1|0|4",
+	)
+
+	grouping := NewAggregateParam(AggregateGrouping, 2, "", collations.MySQL8())
+	grouping.GroupingKeys = []int{0, 1}
+	oa := &OrderedAggregate{
+		Aggregates: []*AggregateParams{
+			grouping,
+			NewAggregateParam(AggregateSum, 3, "", collations.MySQL8()),
+		},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}, {KeyCol: 1, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       &fakePrimitive{results: []*sqltypes.Result{input}},
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+
+	wantRows := []string{
+		"x|1|0|3",
+		"x|2|0|3",
+		"x|null|1|6",
+		"y|1|0|4",
+		"y|null|1|4",
+		"null|null|3|10",
+	}
+	utils.MustMatch(t, sqltypes.MakeTestResult(fields, wantRows...), result)
+
+	oa.Input = &fakePrimitive{results: []*sqltypes.Result{input}}
+	var results []*sqltypes.Result
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	wantResults := sqltypes.MakeTestStreamingResults(
+		fields,
+		"x|1|0|3",
+		"---",
+		"x|2|0|3",
+		"x|null|1|6",
+		"---",
+		"y|1|0|4",
+		"y|null|1|4",
+		"null|null|3|10",
+	)
+	utils.MustMatch(t, wantResults, results)
+}
+
+func TestOrderedAggregateRollupEmpty(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"a|count(*)",
+		"varbinary|int64",
+	)
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateSum, 1, "", collations.MySQL8())},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0, WeightStringCol: -1}},
+		WithRollup:  true,
+		Input:       &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields)}},
+	}
+
+	result, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rows)
+}
+
+func TestGroupingMask(t *testing.T) {
+	// GROUPING(a, c) with GROUP BY a, b, c WITH ROLLUP
+	keys := []int{0, 2}
+	assert.EqualValues(t, 0, groupingMask(keys, 3))
+	assert.EqualValues(t, 1, groupingMask(keys, 2))
+	assert.EqualValues(t, 1, groupingMask(keys, 1))
+	assert.EqualValues(t, 3, groupingMask(keys, 0))
+}
+
+// TestBitwiseAndJSONAggregates tests the bitwise and JSON aggregations, both on the rows of the
+// table and on the partial results of the same aggregations returned by the shards.
+func TestBitwiseAndJSONAggregates(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|c2|c3|c4",
+		"int64|int64|varchar|json",
+	)
+	input := sqltypes.MakeTestResult(fields,
+		"10|6|a|[1]",
+		"10|3|b|null",
+		"20|null|c|{\"x\": 1}",
+		"20|5|null|[2]",
+	)
+
+	bitAnd := NewAggregateParam(AggregateBitAnd, 1, "bit_and(c2)", collations.MySQL8())
+	bitXor := NewAggregateParam(AggregateBitXor, 1, "bit_xor(c2)", collations.MySQL8())
+	arrayAgg := NewAggregateParam(AggregateJSONArrayAgg, 2, "json_arrayagg(c3)", collations.MySQL8())
+	objectAgg := NewAggregateParam(AggregateJSONObjectAgg, 3, "json_objectagg(c3, c4)", collations.MySQL8())
+	objectAgg.ArgCols = []int{2, 3}
+
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{bitAnd, arrayAgg},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       &fakePrimitive{results: []*sqltypes.Result{input}},
+	}
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"c1|bit_and(c2)|json_arrayagg(c3)|c4",
+		"int64|uint64|json|json",
+	),
+		`10|2|["a", "b"]|[1]`,
+		`20|5|["c", null]|{"x": 1}`,
+	), qr)
+
+	oa.Aggregates = []*AggregateParams{bitXor, objectAgg}
+	oa.Input = &fakePrimitive{results: []*sqltypes.Result{input}}
+	_, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.EqualError(t, err, "JSON documents may not contain NULL member names.")
+
+	oa.Input = &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "10|6|a|[1]", "10|3|b|null")}}
+	qr, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+		"c1|bit_xor(c2)|c3|json_objectagg(c3, c4)",
+		"int64|uint64|varchar|json",
+	),
+		`10|5|a|{"a": [1], "b": null}`,
+	), qr)
+
+	// partial results of the aggregations on the shards
+	partialFields := sqltypes.MakeTestFields(
+		"c1|bit_or(c2)|json_arrayagg(c3)|json_objectagg(c3, c4)",
+		"int64|uint64|json|json",
+	)
+	partial := sqltypes.MakeTestResult(partialFields,
+		`10|6|["a"]|{"a": 1}`,
+		`10|3|["b", null]|{"a": 2, "b": 3}`,
+		`20|0|null|null`,
+	)
+	arrayMerge := NewAggregateParam(AggregateJSONMerge, 2, "json_arrayagg(c3)", collations.MySQL8())
+	arrayMerge.OrigOpcode = AggregateJSONArrayAgg
+	objectMerge := NewAggregateParam(AggregateJSONMerge, 3, "json_objectagg(c3, c4)", collations.MySQL8())
+	objectMerge.OrigOpcode = AggregateJSONObjectAgg
+	require.Equal(t, "json_merge_json_objectagg(3) AS json_objectagg(c3, c4)", objectMerge.String())
+
+	oa = &OrderedAggregate{
+		Aggregates:  []*AggregateParams{NewAggregateParam(AggregateBitOr, 1, "bit_or(c2)", collations.MySQL8()), arrayMerge, objectMerge},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       &fakePrimitive{results: []*sqltypes.Result{partial}},
+	}
+	qr, err = oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	utils.MustMatch(t, sqltypes.MakeTestResult(partialFields,
+		`10|7|["a", "b", null]|{"a": 2, "b": 3}`,
+		`20|0|null|null`,
+	), qr)
+}
@@ -32,12 +32,14 @@ import (
 // each node does its part by combining the results of the
 // sub-nodes.
 type Plan struct {
-	Type         sqlparser.StatementType // The type of query we have
-	Original     string                  // Original is the original query.
-	Instructions Primitive               // Instructions contains the instructions needed to fulfil the query.
-	BindVarNeeds *sqlparser.BindVarNeeds // Stores BindVars needed to be provided as part of expression rewriting
-	Warnings     []*query.QueryWarning   // Warnings that need to be yielded every time this query runs
-	TablesUsed   []string                // TablesUsed is the list of tables that this plan will query
+	Type          sqlparser.StatementType // The type of query we have
+	Original      string                  // Original is the original query.
+	Instructions  Primitive               // Instructions contains the instructions needed to fulfil the query.
+	BindVarNeeds  *sqlparser.BindVarNeeds // Stores BindVars needed to be provided as part of expression rewriting
+	Warnings      []*query.QueryWarning   // Warnings that need to be yielded every time this query runs
+	TablesUsed    []string                // TablesUsed is the list of tables that this plan will query
+	ResultCache   bool                    // ResultCache is set if the results of this plan can be served from the vtgate result cache
+	Deterministic bool                    // Deterministic is set if the results of this plan only depend on the rows it reads
 
 	ExecCount    uint64 // Count of times this plan was executed
 	ExecTime     uint64 // Total execution time
@@ -56,6 +56,15 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// CTEMaxRecursionDepth returns the maximum number of iterations a recursive CTE is allowed to run
+		// when the session does not set cte_max_recursion_depth
+		CTEMaxRecursionDepth() int
+
+		// MemoryBudget returns the memory budget of the query, or nil when intermediate results are never spilled to disk
+		MemoryBudget() *MemoryBudget
+		// SpillDir returns the directory where intermediate results are spilled to disk
+		SpillDir() string
+
 		Execute(ctx context.Context, method string, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error)
 		AutocommitApproval() bool
 
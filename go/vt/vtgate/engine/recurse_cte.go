@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*RecurseCTE)(nil)
+
+// RecurseCTE is used to represent recursive CTEs.
+// Seed is the non-recursive part that initializes the result set. Its rows are used to start the recursion
+// on the Term side, and each row produced by the Term is in turn fed back into the Term, until no new rows are produced.
+type RecurseCTE struct {
+	// Seed is the primitive that gives us the initial rows
+	Seed Primitive
+	// Term is the primitive that will be executed once for every row of the previous iteration
+	Term Primitive
+
+	// Vars are the bind variables the Term needs, and the offset of the column in the rows of the
+	// previous iteration that they are fetched from
+	Vars map[string]int
+
+	// CheckCols is set for recursive CTEs using UNION DISTINCT.
+	// Rows that have already been produced are then neither returned nor iterated over again.
+	CheckCols []CheckCol
+}
+
+// RouteType implements the Primitive interface
+func (r *RecurseCTE) RouteType() string {
+	return "RecurseCTE"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (r *RecurseCTE) GetKeyspaceName() string {
+	if r.Seed.GetKeyspaceName() == r.Term.GetKeyspaceName() {
+		return r.Seed.GetKeyspaceName()
+	}
+	return r.Seed.GetKeyspaceName() + "_" + r.Term.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (r *RecurseCTE) GetTableName() string {
+	return r.Seed.GetTableName()
+}
+
+// TryExecute implements the Primitive interface
+func (r *RecurseCTE) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	seedRes, err := vcursor.ExecutePrimitive(ctx, r.Seed, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := r.newSeenRows(vcursor)
+	lastRows, err := seen.filter(seedRes.Rows)
+	if err != nil {
+		return nil, err
+	}
+	res := &sqltypes.Result{Fields: seedRes.Fields}
+	res.Rows = append(res.Rows, lastRows...)
+
+	for iteration := 1; len(lastRows) > 0; iteration++ {
+		if err := checkRecursionDepth(vcursor, iteration); err != nil {
+			return nil, err
+		}
+		var newRows []sqltypes.Row
+		for _, row := range lastRows {
+			termRes, err := vcursor.ExecutePrimitive(ctx, r.Term, r.termVars(bindVars, row), false)
+			if err != nil {
+				return nil, err
+			}
+			rows, err := seen.filter(termRes.Rows)
+			if err != nil {
+				return nil, err
+			}
+			newRows = append(newRows, rows...)
+			res.Rows = append(res.Rows, rows...)
+			if vcursor.ExceedsMaxMemoryRows(len(res.Rows)) {
+				return nil, vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.NetPacketTooLarge, "in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+			}
+		}
+		lastRows = newRows
+	}
+	return res, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *RecurseCTE) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	seen := r.newSeenRows(vcursor)
+
+	// the rows of each iteration are needed to run the next one, so we keep them around,
+	// but we still send them to the client as soon as we get them
+	var lastRows []sqltypes.Row
+	var mu sync.Mutex
+	collect := func(res *sqltypes.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rows, err := seen.filter(res.Rows)
+		if err != nil {
+			return err
+		}
+		lastRows = append(lastRows, rows...)
+		if vcursor.ExceedsMaxMemoryRows(len(lastRows)) {
+			return vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.NetPacketTooLarge, "in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+		}
+		res.Rows = rows
+		return callback(res)
+	}
+
+	err := vcursor.StreamExecutePrimitive(ctx, r.Seed, bindVars, wantfields, collect)
+	if err != nil {
+		return err
+	}
+
+	for iteration := 1; len(lastRows) > 0; iteration++ {
+		if err := checkRecursionDepth(vcursor, iteration); err != nil {
+			return err
+		}
+		rows := lastRows
+		lastRows = nil
+		for _, row := range rows {
+			err := vcursor.StreamExecutePrimitive(ctx, r.Term, r.termVars(bindVars, row), false, collect)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetFields implements the Primitive interface
+func (r *RecurseCTE) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return r.Seed.GetFields(ctx, vcursor, bindVars)
+}
+
+// NeedsTransaction implements the Primitive interface
+func (r *RecurseCTE) NeedsTransaction() bool {
+	return r.Seed.NeedsTransaction() || r.Term.NeedsTransaction()
+}
+
+// Inputs implements the Primitive interface
+func (r *RecurseCTE) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{r.Seed, r.Term}, nil
+}
+
+func (r *RecurseCTE) description() PrimitiveDescription {
+	other := map[string]any{
+		"JoinVars": orderedStringIntMap(r.Vars),
+	}
+	if r.CheckCols != nil {
+		other["Collations"] = r.checkColsDescription()
+	}
+
+	return PrimitiveDescription{
+		OperatorType: "RecurseCTE",
+		Other:        other,
+	}
+}
+
+func (r *RecurseCTE) checkColsDescription() []string {
+	var cols []string
+	for _, col := range r.CheckCols {
+		cols = append(cols, col.String())
+	}
+	return cols
+}
+
+func (r *RecurseCTE) termVars(bindVars map[string]*querypb.BindVariable, row sqltypes.Row) map[string]*querypb.BindVariable {
+	vars := make(map[string]*querypb.BindVariable, len(r.Vars))
+	for name, offset := range r.Vars {
+		vars[name] = sqltypes.ValueBindVariable(row[offset])
+	}
+	return combineVars(bindVars, vars)
+}
+
+func (r *RecurseCTE) newSeenRows(vcursor VCursor) seenRows {
+	if r.CheckCols == nil {
+		return seenRows{}
+	}
+	return seenRows{probeTable: newProbeTable(r.CheckCols, vcursor.Environment().CollationEnv())}
+}
+
+func checkRecursionDepth(vcursor VCursor, iteration int) error {
+	maxDepth := cteMaxRecursionDepth(vcursor)
+	if iteration > maxDepth {
+		return vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.CTEMaxRecursionDepth, "Recursive query aborted after %d iterations. Try increasing @@cte_max_recursion_depth to a larger value.", maxDepth+1)
+	}
+	return nil
+}
+
+// cteMaxRecursionDepth returns the value of cte_max_recursion_depth set in the session,
+// or the default configured on the vtgate
+func cteMaxRecursionDepth(vcursor VCursor) int {
+	maxDepth := vcursor.CTEMaxRecursionDepth()
+	if !vcursor.Session().HasSystemVariables() {
+		return maxDepth
+	}
+	vcursor.Session().GetSystemVariables(func(k string, v string) {
+		if !strings.EqualFold(k, sysvars.CTEMaxRecursionDepth) {
+			return
+		}
+		if n, err := strconv.ParseUint(v, 10, 32); err == nil {
+			maxDepth = int(n)
+		}
+	})
+	return maxDepth
+}
+
+// seenRows keeps track of the rows a recursive CTE using UNION DISTINCT has produced so far.
+// Without a probe table, all rows are let through.
+type seenRows struct {
+	probeTable *probeTable
+}
+
+func (s seenRows) filter(rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	if s.probeTable == nil {
+		return rows, nil
+	}
+	var result []sqltypes.Row
+	for _, row := range rows {
+		row, err := s.probeTable.exists(row)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+func TestRecurseCTEExecute(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|manager", "int64|int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|null"),
+		},
+	}
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2|1", "3|1"),
+			sqltypes.MakeTestResult(fields, "4|2"),
+			sqltypes.MakeTestResult(fields),
+			sqltypes.MakeTestResult(fields),
+		},
+	}
+	bv := map[string]*querypb.BindVariable{
+		"a": sqltypes.Int64BindVariable(10),
+	}
+
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"id": 0},
+	}
+
+	r, err := cte.TryExecute(context.Background(), &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+
+	seed.ExpectLog(t, []string{
+		`Execute a: type:INT64 value:"10" true`,
+	})
+	term.ExpectLog(t, []string{
+		`Execute a: type:INT64 value:"10" id: type:INT64 value:"1" false`,
+		`Execute a: type:INT64 value:"10" id: type:INT64 value:"2" false`,
+		`Execute a: type:INT64 value:"10" id: type:INT64 value:"3" false`,
+		`Execute a: type:INT64 value:"10" id: type:INT64 value:"4" false`,
+	})
+	expectResult(t, r, sqltypes.MakeTestResult(fields, "1|null", "2|1", "3|1", "4|2"))
+
+	// streaming
+	seed.rewind()
+	term.rewind()
+	r, err = wrapStreamExecute(cte, &noopVCursor{}, bv, true)
+	require.NoError(t, err)
+	expectResult(t, r, sqltypes.MakeTestResult(fields, "1|null", "2|1", "3|1", "4|2"))
+}
+
+func TestRecurseCTEDistinct(t *testing.T) {
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1", "1"),
+		},
+	}
+	// the term keeps producing rows that have been seen before, which would recurse forever without the check
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2", "1"),
+			sqltypes.MakeTestResult(fields, "1", "2"),
+		},
+	}
+
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+		CheckCols: []CheckCol{{
+			Col:          0,
+			Type:         evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			CollationEnv: collations.MySQL8(),
+		}},
+	}
+
+	r, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	term.ExpectLog(t, []string{
+		`Execute n: type:INT64 value:"1" false`,
+		`Execute n: type:INT64 value:"2" false`,
+	})
+	expectResult(t, r, sqltypes.MakeTestResult(fields, "1", "2"))
+}
+
+func TestRecurseCTEMaxRecursionDepth(t *testing.T) {
+	defer func(depth int) {
+		testCTEMaxRecursionDepth = depth
+	}(testCTEMaxRecursionDepth)
+	testCTEMaxRecursionDepth = 2
+
+	fields := sqltypes.MakeTestFields("n", "int64")
+	seed := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1"),
+		},
+	}
+	term := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "2"),
+			sqltypes.MakeTestResult(fields, "3"),
+			sqltypes.MakeTestResult(fields, "4"),
+		},
+	}
+
+	cte := &RecurseCTE{
+		Seed: seed,
+		Term: term,
+		Vars: map[string]int{"n": 0},
+	}
+
+	_, err := cte.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "Recursive query aborted after 3 iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+
+	seed.rewind()
+	term.rewind()
+	_, err = wrapStreamExecute(cte, &noopVCursor{}, nil, true)
+	require.EqualError(t, err, "Recursive query aborted after 3 iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+
+	// the session variable takes precedence over the vtgate default
+	seed.rewind()
+	term.rewind()
+	vc := &loggingVCursor{systemVariables: map[string]string{"cte_max_recursion_depth": "1"}}
+	_, err = cte.TryExecute(context.Background(), vc, nil, true)
+	require.EqualError(t, err, "Recursive query aborted after 2 iterations. Try increasing @@cte_max_recursion_depth to a larger value.")
+}
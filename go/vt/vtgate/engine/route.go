@@ -152,6 +152,7 @@ type cxtKey int
 
 const (
 	IgnoreReserveTxn cxtKey = iota
+	localInfileReader
 )
 
 func (route *Route) executeInternal(
@@ -64,7 +64,7 @@ func (sa *ScalarAggregate) GetFields(ctx context.Context, vcursor VCursor, bindV
 		return nil, err
 	}
 
-	_, fields, err := newAggregation(qr.Fields, sa.Aggregates)
+	_, fields, err := newAggregation(vcursor, qr.Fields, sa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
@@ -85,10 +85,11 @@ func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bind
 		return nil, err
 	}
 
-	agg, fields, err := newAggregation(result.Fields, sa.Aggregates)
+	agg, fields, err := newAggregation(vcursor, result.Fields, sa.Aggregates)
 	if err != nil {
 		return nil, err
 	}
+	defer agg.close()
 
 	for _, row := range result.Rows {
 		if err := agg.add(row); err != nil {
@@ -96,9 +97,13 @@ func (sa *ScalarAggregate) TryExecute(ctx context.Context, vcursor VCursor, bind
 		}
 	}
 
+	row, err := agg.finish()
+	if err != nil {
+		return nil, err
+	}
 	out := &sqltypes.Result{
 		Fields: fields,
-		Rows:   [][]sqltypes.Value{agg.finish()},
+		Rows:   [][]sqltypes.Value{row},
 	}
 	return out.Truncate(sa.TruncateColumnCount), nil
 }
@@ -113,6 +118,9 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 	var agg aggregationState
 	var fields []*querypb.Field
 	fieldsSent := !wantfields
+	defer func() {
+		agg.close()
+	}()
 
 	err := vcursor.StreamExecutePrimitive(ctx, sa.Input, bindVars, true, func(result *sqltypes.Result) error {
 		// as the underlying primitive call is not sync
@@ -123,7 +131,7 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 
 		if agg == nil && len(result.Fields) != 0 {
 			var err error
-			agg, fields, err = newAggregation(result.Fields, sa.Aggregates)
+			agg, fields, err = newAggregation(vcursor, result.Fields, sa.Aggregates)
 			if err != nil {
 				return err
 			}
@@ -146,7 +154,11 @@ func (sa *ScalarAggregate) TryStreamExecute(ctx context.Context, vcursor VCursor
 		return err
 	}
 
-	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{agg.finish()}})
+	row, err := agg.finish()
+	if err != nil {
+		return err
+	}
+	return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{row}})
 }
 
 // Inputs implements the Primitive interface
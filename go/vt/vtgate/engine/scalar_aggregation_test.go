@@ -66,6 +66,23 @@ func TestEmptyRows(outer *testing.T) {
 		opcode:      AggregateMin,
 		expectedVal: "null",
 		expectedTyp: "int64",
+	}, {
+		opcode:      AggregateBitAnd,
+		expectedVal: "18446744073709551615",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateBitXor,
+		expectedVal: "0",
+		expectedTyp: "uint64",
+	}, {
+		opcode:      AggregateJSONArrayAgg,
+		expectedVal: "null",
+		expectedTyp: "json",
+	}, {
+		opcode:      AggregateJSONMerge,
+		expectedVal: "null",
+		expectedTyp: "json",
+		origOpcode:  AggregateJSONObjectAgg,
 	}}
 
 	for _, test := range testCases {
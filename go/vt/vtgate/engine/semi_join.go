@@ -35,6 +35,10 @@ type SemiJoin struct {
 	// be built from the LHS result before invoking
 	// the RHS subquery.
 	Vars map[string]int `json:",omitempty"`
+
+	// Anti is set for anti joins, which return the rows of
+	// the LHS that do not have any matching rows on the RHS.
+	Anti bool `json:",omitempty"`
 }
 
 // TryExecute performs a non-streaming exec.
@@ -53,7 +57,7 @@ func (jn *SemiJoin) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 		if err != nil {
 			return nil, err
 		}
-		if len(rresult.Rows) > 0 {
+		if (len(rresult.Rows) > 0) != jn.Anti {
 			result.Rows = append(result.Rows, lrow)
 		}
 	}
@@ -69,17 +73,19 @@ func (jn *SemiJoin) TryStreamExecute(ctx context.Context, vcursor VCursor, bindV
 			for k, col := range jn.Vars {
 				joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
 			}
-			rowAdded := false
+			matched := false
 			err := vcursor.StreamExecutePrimitive(ctx, jn.Right, combineVars(bindVars, joinVars), false, func(rresult *sqltypes.Result) error {
-				if len(rresult.Rows) > 0 && !rowAdded {
-					result.Rows = append(result.Rows, lrow)
-					rowAdded = true
+				if len(rresult.Rows) > 0 {
+					matched = true
 				}
 				return nil
 			})
 			if err != nil {
 				return err
 			}
+			if matched != jn.Anti {
+				result.Rows = append(result.Rows, lrow)
+			}
 		}
 		return callback(result)
 	})
@@ -130,8 +136,12 @@ func (jn *SemiJoin) description() PrimitiveDescription {
 	if len(jn.Vars) > 0 {
 		other["JoinVars"] = orderedStringIntMap(jn.Vars)
 	}
-	return PrimitiveDescription{
+	desc := PrimitiveDescription{
 		OperatorType: "SemiJoin",
 		Other:        other,
 	}
+	if jn.Anti {
+		desc.Variant = "Anti"
+	}
+	return desc
 }
@@ -159,3 +159,46 @@ func TestSemiJoinStreamExecute(t *testing.T) {
 		"4|d|dd",
 	))
 }
+
+func TestAntiJoinExecute(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"col1|col2",
+		"int64|varchar",
+	)
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|a", "2|b", "3|c"),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields("col3", "int64")
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(rightFields, "4"),
+			sqltypes.MakeTestResult(rightFields),
+			sqltypes.MakeTestResult(rightFields, "5"),
+		},
+	}
+
+	jn := &SemiJoin{
+		Left:  leftPrim,
+		Right: rightPrim,
+		Vars: map[string]int{
+			"bv": 1,
+		},
+		Anti: true,
+	}
+	r, err := jn.TryExecute(context.Background(), &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	rightPrim.ExpectLog(t, []string{
+		`Execute bv: type:VARCHAR value:"a" false`,
+		`Execute bv: type:VARCHAR value:"b" false`,
+		`Execute bv: type:VARCHAR value:"c" false`,
+	})
+	expectResult(t, r, sqltypes.MakeTestResult(fields, "2|b"))
+
+	leftPrim.rewind()
+	rightPrim.rewind()
+	r, err = wrapStreamExecute(jn, &noopVCursor{}, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	expectResult(t, r, sqltypes.MakeTestResult(fields, "2|b"))
+}
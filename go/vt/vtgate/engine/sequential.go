@@ -66,6 +66,10 @@ func (s *Sequential) GetTableName() string {
 
 // TryExecute performs a non-streaming exec.
 func (s *Sequential) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantFields bool) (*sqltypes.Result, error) {
+	// The sources run in the same transaction, so none of them may autocommit on its own.
+	// Taking the approval here leaves the commit to the caller once all of them succeeded.
+	_ = vcursor.AutocommitApproval()
+
 	finalRes := &sqltypes.Result{}
 	for _, source := range s.Sources {
 		res, err := vcursor.ExecutePrimitive(ctx, source, bindVars, wantFields)
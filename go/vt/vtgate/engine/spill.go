@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vthash"
+)
+
+// spillBatchSize is the number of rows sent at once when rows that have been spilled are read back
+const spillBatchSize = 1024
+
+// spillPartitions is the number of partitions used by the primitives that partition
+// their rows by hash when they spill them to disk
+const spillPartitions = 16
+
+// MemoryBudget is the number of bytes that the primitives of a query can use to buffer rows.
+// It is shared by all of them, and when the budget is exhausted, the primitive that
+// needs more memory spills its rows to local disk instead of failing the query.
+type MemoryBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// NewMemoryBudget returns a budget of the given number of bytes
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	return &MemoryBudget{limit: limit}
+}
+
+// Used returns the number of bytes currently used by the primitives of the query
+func (mb *MemoryBudget) Used() int64 {
+	return mb.used.Load()
+}
+
+// add adds the given number of bytes to the memory used, and returns false when the budget is exceeded
+func (mb *MemoryBudget) add(n int64) bool {
+	return mb.used.Add(n) <= mb.limit
+}
+
+// memoryTracker keeps track of the memory a primitive uses in the budget of the query.
+// A tracker without a budget never asks to spill.
+type memoryTracker struct {
+	budget *MemoryBudget
+	used   int64
+}
+
+func newMemoryTracker(vcursor VCursor) *memoryTracker {
+	return &memoryTracker{budget: vcursor.MemoryBudget()}
+}
+
+// spilling returns true when the rows can be spilled to disk
+func (mt *memoryTracker) spilling() bool {
+	return mt.budget != nil
+}
+
+// add accounts for the given number of bytes, and returns false when the budget is exhausted
+// and the primitive should spill its rows to disk
+func (mt *memoryTracker) add(n int64) bool {
+	if mt.budget == nil {
+		return true
+	}
+	mt.used += n
+	return mt.budget.add(n)
+}
+
+// releaseAll gives back all the memory used by the primitive, after it has spilled its rows or is done with them
+func (mt *memoryTracker) releaseAll() {
+	if mt.budget == nil {
+		return
+	}
+	mt.budget.used.Add(-mt.used)
+	mt.used = 0
+}
+
+// valueOverhead is the approximate size of a sqltypes.Value without its raw bytes
+const valueOverhead = 32
+
+// rowSize returns the approximate number of bytes used in memory by a row
+func rowSize(row sqltypes.Row) int64 {
+	size := int64(24)
+	for _, v := range row {
+		size += valueOverhead + int64(len(v.Raw()))
+	}
+	return size
+}
+
+// spillFile is a temporary file on local disk where rows are written, to be read back later in the same order.
+// The file is removed when it is closed.
+type spillFile struct {
+	file *os.File
+	w    *bufio.Writer
+	r    *bufio.Reader
+	rows int
+
+	buf []byte
+}
+
+func newSpillFile(dir string) (*spillFile, error) {
+	file, err := os.CreateTemp(dir, "vtgate-spill-*")
+	if err != nil {
+		return nil, vterrors.Wrap(err, "failed to create a file to spill rows to disk")
+	}
+	return &spillFile{
+		file: file,
+		w:    bufio.NewWriter(file),
+	}, nil
+}
+
+// write appends a row to the file. Each row is stored as the number of values, followed by the type,
+// length and raw bytes of each value.
+func (sf *spillFile) write(row sqltypes.Row) error {
+	sf.buf = binary.AppendUvarint(sf.buf[:0], uint64(len(row)))
+	for _, v := range row {
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(v.Type()))
+		if v.IsNull() {
+			continue
+		}
+		sf.buf = binary.AppendUvarint(sf.buf, uint64(len(v.Raw())))
+		sf.buf = append(sf.buf, v.Raw()...)
+	}
+	sf.rows++
+	_, err := sf.w.Write(sf.buf)
+	return err
+}
+
+// rewind flushes the rows written so far, and starts reading the file from its beginning
+func (sf *spillFile) rewind() error {
+	if err := sf.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := sf.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sf.r = bufio.NewReader(sf.file)
+	return nil
+}
+
+// next returns the next row of the file, or io.EOF once all the rows have been read
+func (sf *spillFile) next() (sqltypes.Row, error) {
+	n, err := binary.ReadUvarint(sf.r)
+	if err != nil {
+		return nil, err
+	}
+	row := make(sqltypes.Row, n)
+	for i := range row {
+		typ, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if querypb.Type(typ) == sqltypes.Null {
+			row[i] = sqltypes.NULL
+			continue
+		}
+		size, err := binary.ReadUvarint(sf.r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		raw := make([]byte, size)
+		if _, err := io.ReadFull(sf.r, raw); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		row[i] = sqltypes.MakeTrusted(querypb.Type(typ), raw)
+	}
+	return row, nil
+}
+
+// forEach rewinds the file and calls f for every row in it
+func (sf *spillFile) forEach(f func(row sqltypes.Row) error) error {
+	if err := sf.rewind(); err != nil {
+		return err
+	}
+	for {
+		row, err := sf.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+}
+
+func (sf *spillFile) close() {
+	_ = sf.file.Close()
+	_ = os.Remove(sf.file.Name())
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// spillPartitionSet is a set of spill files, where the rows are distributed using a hash
+type spillPartitionSet struct {
+	files []*spillFile
+}
+
+func newSpillPartitionSet(dir string) (*spillPartitionSet, error) {
+	ps := &spillPartitionSet{}
+	for range spillPartitions {
+		file, err := newSpillFile(dir)
+		if err != nil {
+			ps.close()
+			return nil, err
+		}
+		ps.files = append(ps.files, file)
+	}
+	return ps, nil
+}
+
+func (ps *spillPartitionSet) write(hash vthash.Hash, row sqltypes.Row) error {
+	return ps.files[partitionFor(hash)].write(row)
+}
+
+func (ps *spillPartitionSet) close() {
+	for _, file := range ps.files {
+		file.close()
+	}
+}
+
+// partitionFor returns the partition of a row with the given hash. The rows that are equal have the same hash,
+// so they are always found in the same partition.
+func partitionFor(hash vthash.Hash) int {
+	return int(binary.LittleEndian.Uint64(hash[:8]) % spillPartitions)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestSpillFile(t *testing.T) {
+	dir := t.TempDir()
+	sf, err := newSpillFile(dir)
+	require.NoError(t, err)
+
+	rows := []sqltypes.Row{
+		{sqltypes.NewInt64(1), sqltypes.NewVarChar("a"), sqltypes.NULL},
+		{sqltypes.NULL, sqltypes.NewVarChar(""), sqltypes.NewFloat64(2.5)},
+		{},
+		{sqltypes.NewVarBinary("\x00\xff"), sqltypes.NewDecimal("1.10"), sqltypes.NewInt64(-3)},
+	}
+	for _, row := range rows {
+		require.NoError(t, sf.write(row))
+	}
+
+	// the file can be read more than once
+	for range 2 {
+		var got []sqltypes.Row
+		err = sf.forEach(func(row sqltypes.Row) error {
+			got = append(got, row)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, got, len(rows))
+		for i := range rows {
+			assert.Equal(t, fmt.Sprint(rows[i]), fmt.Sprint(got[i]))
+		}
+	}
+
+	_, err = sf.next()
+	assert.Equal(t, io.EOF, err)
+
+	sf.close()
+	requireEmptyDir(t, dir)
+}
+
+func TestSpillPartitionSet(t *testing.T) {
+	dir := t.TempDir()
+	ps, err := newSpillPartitionSet(dir)
+	require.NoError(t, err)
+	pt := newProbeTable([]CheckCol{{Col: 0}}, nil)
+
+	for i := range 100 {
+		row := sqltypes.Row{sqltypes.NewInt64(int64(i % 10))}
+		hash, err := pt.hashCodeForRow(row)
+		require.NoError(t, err)
+		require.NoError(t, ps.write(hash, row))
+	}
+
+	// all the equal rows are in the same partition
+	seen := map[string]int{}
+	for i, file := range ps.files {
+		err := file.forEach(func(row sqltypes.Row) error {
+			if partition, ok := seen[row[0].String()]; ok {
+				assert.Equal(t, partition, i, row[0].String())
+			}
+			seen[row[0].String()] = i
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Len(t, seen, 10)
+
+	ps.close()
+	requireEmptyDir(t, dir)
+}
+
+func TestMemoryTracker(t *testing.T) {
+	budget := NewMemoryBudget(100)
+	vc := &loggingVCursor{memoryBudget: budget}
+
+	mt1 := newMemoryTracker(vc)
+	mt2 := newMemoryTracker(vc)
+	require.True(t, mt1.spilling())
+	assert.True(t, mt1.add(60))
+	// the budget is shared by all the primitives of the query
+	assert.False(t, mt2.add(60))
+	assert.EqualValues(t, 120, budget.Used())
+
+	mt2.releaseAll()
+	assert.EqualValues(t, 60, budget.Used())
+	assert.True(t, mt2.add(40))
+	mt1.releaseAll()
+	mt2.releaseAll()
+	assert.Zero(t, budget.Used())
+
+	// without a budget, the rows are never spilled
+	mt := newMemoryTracker(&noopVCursor{})
+	assert.False(t, mt.spilling())
+	assert.True(t, mt.add(1<<40))
+}
+
+func requireEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "spill files were not removed")
+}
@@ -19,8 +19,14 @@ package engine
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -49,6 +55,26 @@ type Update struct {
 
 	// ChangedVindexValues contains values for updated Vindexes during an update statement.
 	ChangedVindexValues map[string]*VindexValues
+
+	// Move is set when the statement updates the columns of the primary vindex,
+	// in which case the rows are moved to the shards of their new keyspace ids.
+	Move *RowMove
+}
+
+// RowMove contains the instructions to move the rows whose primary vindex columns are updated.
+// The OwnedVindexQuery of the update selects the new values of the updated columns, followed by
+// the columns of the rows that are not generated. The rows are deleted from their current shards, and inserted with
+// their new values in the shards of their new keyspace ids, all in the same transaction.
+type RowMove struct {
+	// Columns are the updated columns, in the order of their new values in the OwnedVindexQuery
+	Columns []string
+
+	// DeleteQuery deletes the rows that are moved from their current shards
+	DeleteQuery string
+
+	// Insert is the template of the statements inserting the rows in their new shards,
+	// the columns and the rows are added to it at execution time
+	Insert *sqlparser.Insert
 }
 
 // TryExecute performs a non-streaming exec.
@@ -69,6 +95,9 @@ func (upd *Update) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 	case Unsharded:
 		return upd.execUnsharded(ctx, upd, vcursor, bindVars, rss)
 	case Equal, EqualUnique, IN, Scatter, ByDestination, SubShard, MultiEqual:
+		if upd.Move != nil {
+			return upd.moveRows(ctx, vcursor, bindVars, rss)
+		}
 		return upd.execMultiDestination(ctx, upd, vcursor, bindVars, rss, upd.updateVindexEntries, bvs)
 	default:
 		// Unreachable.
@@ -197,6 +226,189 @@ func (upd *Update) updateVindexEntries(ctx context.Context, vcursor VCursor, bin
 	return nil
 }
 
+// moveRows performs an update that changes the primary vindex columns, by deleting the rows from
+// their current shards and inserting them in the shards of their new keyspace ids.
+// The entries of the owned lookup vindexes are moved to the new keyspace ids as well.
+func (upd *Update) moveRows(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, rss []*srvtopo.ResolvedShard) (*sqltypes.Result, error) {
+	if len(rss) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+	queries := make([]*querypb.BoundQuery, len(rss))
+	for i := range rss {
+		queries[i] = &querypb.BoundQuery{Sql: upd.OwnedVindexQuery, BindVariables: bindVars}
+	}
+	selected, errs := vcursor.ExecuteMultiShard(ctx, upd, rss, queries, false /* rollbackOnError */, false /* canAutocommit */)
+	if err := vterrors.Aggregate(errs); err != nil {
+		return nil, err
+	}
+	if len(selected.Rows) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+
+	fields, oldRows, newRows, err := upd.Move.splitRows(selected)
+	if err != nil {
+		return nil, err
+	}
+
+	ksids := make([][]byte, len(newRows))
+	for i := range newRows {
+		if ksids[i], err = upd.moveVindexEntries(ctx, vcursor, fields, oldRows[i], newRows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range rss {
+		queries[i] = &querypb.BoundQuery{Sql: upd.Move.DeleteQuery, BindVariables: bindVars}
+	}
+	// the rows are inserted after the delete, so the delete must not be autocommitted on its own
+	if _, errs := vcursor.ExecuteMultiShard(ctx, upd, rss, queries, true /* rollbackOnError */, false /* canAutocommit */); len(errs) > 0 {
+		return nil, vterrors.Aggregate(errs)
+	}
+	if err := upd.insertMovedRows(ctx, vcursor, fields, newRows, ksids); err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{RowsAffected: uint64(len(newRows))}, nil
+}
+
+// splitRows returns the fields of the table, and the rows selected by the OwnedVindexQuery with their current
+// values and with the new values of the updated columns
+func (m *RowMove) splitRows(selected *sqltypes.Result) (fields []*querypb.Field, oldRows, newRows []sqltypes.Row, err error) {
+	fields = selected.Fields[len(m.Columns):]
+	offsets := make([]int, len(m.Columns))
+	for i, col := range m.Columns {
+		if offsets[i] = fieldIndex(fields, col); offsets[i] < 0 {
+			return nil, nil, nil, vterrors.VT13001(fmt.Sprintf("column %s not found in the rows to move", col))
+		}
+	}
+
+	for _, row := range selected.Rows {
+		oldRow := row[len(m.Columns):]
+		newRow := slices.Clone(oldRow)
+		for i, offset := range offsets {
+			newRow[offset] = row[i]
+		}
+		oldRows = append(oldRows, oldRow)
+		newRows = append(newRows, newRow)
+	}
+	return fields, oldRows, newRows, nil
+}
+
+func fieldIndex(fields []*querypb.Field, col string) int {
+	return slices.IndexFunc(fields, func(f *querypb.Field) bool { return strings.EqualFold(f.Name, col) })
+}
+
+// moveVindexEntries moves the entries of the owned lookup vindexes from the old keyspace id of the row
+// to its new one, and verifies the values of the other vindexes. It returns the new keyspace id.
+func (upd *Update) moveVindexEntries(ctx context.Context, vcursor VCursor, fields []*querypb.Field, oldRow, newRow sqltypes.Row) ([]byte, error) {
+	vindexValues := func(colVindex *vindexes.ColumnVindex, row sqltypes.Row) ([]sqltypes.Value, error) {
+		values := make([]sqltypes.Value, 0, len(colVindex.Columns))
+		for _, col := range colVindex.Columns {
+			idx := fieldIndex(fields, col.String())
+			if idx < 0 {
+				return nil, vterrors.VT13001(fmt.Sprintf("column %s not found in the rows to move", col.String()))
+			}
+			values = append(values, row[idx])
+		}
+		return values, nil
+	}
+
+	primary := upd.Vindexes[0]
+	oldKey, err := vindexValues(primary, oldRow)
+	if err != nil {
+		return nil, err
+	}
+	oldKsid, err := resolveKeyspaceID(ctx, vcursor, upd.KsidVindex, oldKey)
+	if err != nil {
+		return nil, err
+	}
+	newKey, err := vindexValues(primary, newRow)
+	if err != nil {
+		return nil, err
+	}
+	newKsid, err := resolveKeyspaceID(ctx, vcursor, upd.KsidVindex, newKey)
+	if err != nil {
+		return nil, err
+	}
+	if newKsid == nil {
+		return nil, vterrors.VT09024(newKey, "no keyspace id")
+	}
+
+	for _, colVindex := range upd.Vindexes[1:] {
+		oldValues, err := vindexValues(colVindex, oldRow)
+		if err != nil {
+			return nil, err
+		}
+		newValues, err := vindexValues(colVindex, newRow)
+		if err != nil {
+			return nil, err
+		}
+
+		if colVindex.Owned {
+			lookup := colVindex.Vindex.(vindexes.Lookup)
+			if err := lookup.Delete(ctx, vcursor, [][]sqltypes.Value{oldValues}, oldKsid); err != nil {
+				return nil, err
+			}
+			if err := lookup.Create(ctx, vcursor, [][]sqltypes.Value{newValues}, [][]byte{newKsid}, false /* ignoreMode */); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !slices.ContainsFunc(newValues, func(v sqltypes.Value) bool { return !v.IsNull() }) {
+			// All columns for this Vindex are set to null, so we can skip verification
+			continue
+		}
+		verified, err := vindexes.Verify(ctx, colVindex.Vindex, vcursor, [][]sqltypes.Value{newValues}, [][]byte{newKsid})
+		if err != nil {
+			return nil, err
+		}
+		if !verified[0] {
+			return nil, fmt.Errorf("values %v for column %v does not map to keyspace ids", newValues, colVindex.Columns)
+		}
+	}
+	return newKsid, nil
+}
+
+// insertMovedRows inserts the rows in the shards of their new keyspace ids
+func (upd *Update) insertMovedRows(ctx context.Context, vcursor VCursor, fields []*querypb.Field, rows []sqltypes.Row, ksids [][]byte) error {
+	indexes := make([]*querypb.Value, len(rows))
+	destinations := make([]key.Destination, len(rows))
+	for i, ksid := range ksids {
+		indexes[i] = &querypb.Value{Value: strconv.AppendInt(nil, int64(i), 10)}
+		destinations[i] = key.DestinationKeyspaceID(ksid)
+	}
+	rss, indexesPerRss, err := vcursor.ResolveDestinations(ctx, upd.Keyspace.Name, indexes, destinations)
+	if err != nil {
+		return err
+	}
+
+	columns := make(sqlparser.Columns, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, sqlparser.NewIdentifierCI(field.Name))
+	}
+	queries := make([]*querypb.BoundQuery, len(rss))
+	for i := range rss {
+		bindVars := make(map[string]*querypb.BindVariable)
+		var values sqlparser.Values
+		for _, indexValue := range indexesPerRss[i] {
+			rowNum, _ := strconv.Atoi(string(indexValue.Value))
+			tuple := make(sqlparser.ValTuple, 0, len(fields))
+			for colNum, value := range rows[rowNum] {
+				name := fmt.Sprintf("_m%d_%d", rowNum, colNum)
+				bindVars[name] = sqltypes.ValueBindVariable(value)
+				tuple = append(tuple, sqlparser.NewArgument(name))
+			}
+			values = append(values, tuple)
+		}
+		ins := sqlparser.Clone(upd.Move.Insert)
+		ins.Columns = columns
+		ins.Rows = values
+		queries[i] = &querypb.BoundQuery{Sql: sqlparser.String(ins), BindVariables: bindVars}
+	}
+	_, err = execMultiShard(ctx, upd, vcursor, rss, queries, false /* multiShardAutoCommit */)
+	return err
+}
+
 func (upd *Update) description() PrimitiveDescription {
 	other := map[string]any{
 		"Query":                upd.Query,
@@ -217,6 +429,10 @@ func (upd *Update) description() PrimitiveDescription {
 	if len(changedVindexes) > 0 {
 		other["ChangedVindexValues"] = changedVindexes
 	}
+	if upd.Move != nil {
+		other["MovedColumns"] = upd.Move.Columns
+		other["MoveDeleteQuery"] = upd.Move.DeleteQuery
+	}
 
 	return PrimitiveDescription{
 		OperatorType:     "Update",
@@ -588,6 +588,71 @@ func TestUpdateScatterChangedVindex(t *testing.T) {
 
 }
 
+func TestUpdateEqualMoveRows(t *testing.T) {
+	ks := buildTestVSchema().Keyspaces["sharded"]
+	upd := &Update{
+		DML: &DML{
+			RoutingParameters: &RoutingParameters{
+				Opcode:   Equal,
+				Keyspace: ks.Keyspace,
+				Vindex:   ks.Vindexes["hash"],
+				Values:   []evalengine.Expr{evalengine.NewLiteralInt(1)},
+			},
+			Query:            "dummy_update",
+			TableNames:       []string{ks.Tables["t1"].Name.String()},
+			Vindexes:         ks.Tables["t1"].ColumnVindexes,
+			OwnedVindexQuery: "dummy_subquery",
+			KsidVindex:       ks.Vindexes["hash"],
+			KsidLength:       1,
+		},
+		Move: &RowMove{
+			Columns:     []string{"id", "c3"},
+			DeleteQuery: "dummy_delete",
+			Insert: &sqlparser.Insert{
+				Table: sqlparser.NewAliasedTableExpr(sqlparser.NewTableName("t1"), ""),
+			},
+		},
+	}
+
+	results := []*sqltypes.Result{sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"2|10|id|c1|c2|c3",
+			"int64|int64|int64|int64|int64|int64",
+		),
+		"2|10|1|4|5|6",
+	)}
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.results = results
+
+	res, err := upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		// The rows to move are selected with the new values of the updated columns.
+		`ExecuteMultiShard sharded.-20: dummy_subquery {} false false`,
+		// The lookup entries are moved from the old keyspace id to the new one, including the updated column.
+		`Execute delete from lkp2 where from1 = :from1 and from2 = :from2 and toc = :toc from1: type:INT64 value:"4" from2: type:INT64 value:"5" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute insert into lkp2(from1, from2, toc) values(:from1_0, :from2_0, :toc_0) from1_0: type:INT64 value:"4" from2_0: type:INT64 value:"5" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+		`Execute delete from lkp1 where from = :from and toc = :toc from: type:INT64 value:"6" toc: type:VARBINARY value:"\x16k@\xb4J\xbaK\xd6" true`,
+		`Execute insert into lkp1(from, toc) values(:from_0, :toc_0) from_0: type:INT64 value:"10" toc_0: type:VARBINARY value:"\x06\xe7\xea\"Βp\x8f" true`,
+		// The rows are deleted from their current shard, and inserted in the shard of their new keyspace id.
+		`ExecuteMultiShard sharded.-20: dummy_delete {} true false`,
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(06e7ea22ce92708f)`,
+		`ExecuteMultiShard sharded.-20: insert into t1(id, c1, c2, c3) values (:_m0_0, :_m0_1, :_m0_2, :_m0_3) {_m0_0: type:INT64 value:"2" _m0_1: type:INT64 value:"4" _m0_2: type:INT64 value:"5" _m0_3: type:INT64 value:"10"} true true`,
+	})
+
+	// No rows to move.
+	vc = newDMLTestVCursor("-20", "20-")
+	res, err = upd.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, res.RowsAffected)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [type:INT64 value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6)`,
+		`ExecuteMultiShard sharded.-20: dummy_subquery {} false false`,
+	})
+}
+
 func TestUpdateIn(t *testing.T) {
 	ks := buildTestVSchema().Keyspaces["sharded"]
 	upd := &Update{
@@ -0,0 +1,659 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/decimal"
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vterrors"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+)
+
+var _ Primitive = (*Window)(nil)
+
+// Window is a primitive that evaluates window functions over the rows produced by its input.
+// The input must be sorted by the PartitionBy columns, followed by the OrderBy columns.
+// Rows are buffered one partition at a time, so the memory used is bounded by the size
+// of the largest partition and not by the size of the full result.
+type Window struct {
+	// PartitionBy specifies the columns that define the partitions.
+	// An empty list means that all the rows belong to the same partition.
+	PartitionBy []*GroupByParams
+
+	// OrderBy specifies the columns of the window ordering. They are only used
+	// to find peer rows - the input is expected to already be sorted.
+	OrderBy []*GroupByParams
+
+	// Functions are the window functions evaluated by this primitive.
+	Functions []*WindowFunc
+
+	// Cols defines the output columns. A non-negative value is an offset into
+	// the input row, a negative value -(i+1) points to the window function i.
+	Cols []int
+
+	// Input is the primitive that will feed into this Primitive.
+	Input Primitive
+}
+
+// WindowFunc contains everything needed to evaluate a single window function.
+type WindowFunc struct {
+	Opcode WindowOpcode
+
+	// Aggregate is the aggregation used when Opcode is WindowAggregate
+	Aggregate AggregateOpcode
+
+	// Col is the argument column, -1 for functions that don't take one
+	Col int
+
+	// N is the number of buckets for NTILE, the row for NTH_VALUE and the offset for LAG and LEAD
+	N int64
+
+	// DefaultCol is the column holding the default value for LAG and LEAD, -1 if there is none
+	DefaultCol int
+
+	// Frame is the window frame. nil means the default frame
+	Frame *WindowFrame
+
+	Alias string `json:",omitempty"`
+
+	// Type is the type of the argument column
+	Type         evalengine.Type
+	CollationEnv *collations.Environment
+}
+
+// WindowFrame describes the set of rows of a partition that a window function works on.
+type WindowFrame struct {
+	// Rows is true for ROWS frames and false for RANGE frames
+	Rows       bool
+	Start, End FrameBound
+}
+
+// FrameBound is the start or end of a WindowFrame
+type FrameBound struct {
+	Type FrameBoundType
+	// N is the number of rows for Preceding and Following bounds
+	N int64
+}
+
+// FrameBoundType is the kind of frame bound
+type FrameBoundType int
+
+const (
+	UnboundedPreceding = FrameBoundType(iota)
+	Preceding
+	CurrentRow
+	Following
+	UnboundedFollowing
+)
+
+func (fb FrameBound) String() string {
+	switch fb.Type {
+	case UnboundedPreceding:
+		return "unbounded preceding"
+	case Preceding:
+		return fmt.Sprintf("%d preceding", fb.N)
+	case CurrentRow:
+		return "current row"
+	case Following:
+		return fmt.Sprintf("%d following", fb.N)
+	case UnboundedFollowing:
+		return "unbounded following"
+	}
+	return "ERROR"
+}
+
+func (wf *WindowFrame) String() string {
+	unit := "range"
+	if wf.Rows {
+		unit = "rows"
+	}
+	return fmt.Sprintf("%s between %s and %s", unit, wf.Start.String(), wf.End.String())
+}
+
+// String returns a string. Used for plan descriptions
+func (wf *WindowFunc) String() string {
+	name := wf.Opcode.String()
+	if wf.Opcode == WindowAggregate {
+		name = wf.Aggregate.String()
+	}
+	var args []string
+	if wf.Col >= 0 {
+		args = append(args, strconv.Itoa(wf.Col))
+	}
+	switch wf.Opcode {
+	case WindowNtile, WindowNthValue, WindowLag, WindowLead:
+		args = append(args, strconv.FormatInt(wf.N, 10))
+	}
+	if wf.DefaultCol >= 0 {
+		args = append(args, strconv.Itoa(wf.DefaultCol))
+	}
+	out := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if wf.Frame != nil {
+		out += " " + wf.Frame.String()
+	}
+	if wf.Alias != "" {
+		out += " AS " + wf.Alias
+	}
+	return out
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (w *Window) RouteType() string {
+	return w.Input.RouteType()
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (w *Window) GetKeyspaceName() string {
+	return w.Input.GetKeyspaceName()
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (w *Window) GetTableName() string {
+	return w.Input.GetTableName()
+}
+
+// TryExecute is a Primitive function.
+func (w *Window) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
+	/* we need the input fields types to correctly calculate the output types */
+	result, err := vcursor.ExecutePrimitive(ctx, w.Input, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := w.fields(result.Fields)
+	if err != nil {
+		return nil, err
+	}
+	out := &sqltypes.Result{
+		Fields: fields,
+		Rows:   make([]sqltypes.Row, 0, len(result.Rows)),
+	}
+
+	start := 0
+	for i := 1; i <= len(result.Rows); i++ {
+		if i < len(result.Rows) {
+			newPartition, err := w.newPartition(result.Rows[start], result.Rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if !newPartition {
+				continue
+			}
+		}
+		rows, err := w.processPartition(result.Fields, result.Rows[start:i])
+		if err != nil {
+			return nil, err
+		}
+		out.Rows = append(out.Rows, rows...)
+		start = i
+	}
+	return out, nil
+}
+
+// TryStreamExecute is a Primitive function.
+func (w *Window) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool, callback func(*sqltypes.Result) error) error {
+	var inputFields []*querypb.Field
+	var partition []sqltypes.Row
+
+	flush := func() error {
+		if len(partition) == 0 {
+			return nil
+		}
+		rows, err := w.processPartition(inputFields, partition)
+		if err != nil {
+			return err
+		}
+		partition = nil
+		return callback(&sqltypes.Result{Rows: rows})
+	}
+
+	visitor := func(qr *sqltypes.Result) error {
+		if inputFields == nil && len(qr.Fields) > 0 {
+			inputFields = qr.Fields
+			fields, err := w.fields(inputFields)
+			if err != nil {
+				return err
+			}
+			if err := callback(&sqltypes.Result{Fields: fields}); err != nil {
+				return err
+			}
+		}
+		for _, row := range qr.Rows {
+			if len(partition) > 0 {
+				newPartition, err := w.newPartition(partition[0], row)
+				if err != nil {
+					return err
+				}
+				if newPartition {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			}
+			partition = append(partition, row)
+		}
+		return nil
+	}
+
+	/* we need the input fields types to correctly calculate the output types */
+	if err := vcursor.StreamExecutePrimitive(ctx, w.Input, bindVars, true, visitor); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// GetFields is a Primitive function.
+func (w *Window) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := w.Input.GetFields(ctx, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := w.fields(qr.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: fields}, nil
+}
+
+// Inputs returns the Primitive input for this window
+func (w *Window) Inputs() ([]Primitive, []map[string]any) {
+	return []Primitive{w.Input}, nil
+}
+
+// NeedsTransaction implements the Primitive interface
+func (w *Window) NeedsTransaction() bool {
+	return w.Input.NeedsTransaction()
+}
+
+func (w *Window) fields(input []*querypb.Field) ([]*querypb.Field, error) {
+	if input == nil {
+		return nil, nil
+	}
+	out := make([]*querypb.Field, 0, len(w.Cols))
+	for _, col := range w.Cols {
+		if col >= 0 {
+			out = append(out, input[col].CloneVT())
+			continue
+		}
+		fn := w.Functions[-col-1]
+		typ, err := fn.sqlType(input)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, &querypb.Field{Name: fn.Alias, Type: typ})
+	}
+	return out, nil
+}
+
+func (wf *WindowFunc) sqlType(input []*querypb.Field) (querypb.Type, error) {
+	argType := sqltypes.Null
+	if wf.Col >= 0 {
+		argType = input[wf.Col].Type
+	}
+	switch wf.Opcode {
+	case WindowRowNumber, WindowRank, WindowDenseRank, WindowNtile:
+		return sqltypes.Uint64, nil
+	case WindowPercentRank, WindowCumeDist:
+		return sqltypes.Float64, nil
+	case WindowLag, WindowLead, WindowFirstValue, WindowLastValue, WindowNthValue:
+		return argType, nil
+	case WindowAggregate:
+		return wf.Aggregate.SQLType(argType), nil
+	}
+	return sqltypes.Null, vterrors.VT13001(fmt.Sprintf("unexpected window function: %s", wf.Opcode.String()))
+}
+
+// newPartition returns true if the two rows belong to different partitions
+func (w *Window) newPartition(current, next sqltypes.Row) (bool, error) {
+	return rowsDiffer(w.PartitionBy, current, next)
+}
+
+// rowsDiffer compares the two rows using the given columns and returns true if they are not equal
+func rowsDiffer(keys []*GroupByParams, current, next sqltypes.Row) (bool, error) {
+	for _, gb := range keys {
+		v1 := current[gb.KeyCol]
+		v2 := next[gb.KeyCol]
+		if v1.TinyWeightCmp(v2) != 0 {
+			return true, nil
+		}
+
+		cmp, err := evalengine.NullsafeCompare(v1, v2, gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
+		if err != nil {
+			_, isCollationErr := err.(evalengine.UnsupportedCollationError)
+			if !isCollationErr || gb.WeightStringCol == -1 {
+				return false, err
+			}
+			cmp, err = evalengine.NullsafeCompare(current[gb.WeightStringCol], next[gb.WeightStringCol], gb.CollationEnv, gb.Type.Collation(), gb.Type.Values())
+			if err != nil {
+				return false, err
+			}
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// partition holds the rows of a single partition, and the peer groups inside it
+type partition struct {
+	rows []sqltypes.Row
+	// peerStart and peerEnd hold, for every row, the first row of its peer group
+	// and the row after the last row of its peer group
+	peerStart, peerEnd []int
+}
+
+func (w *Window) newPartitionState(rows []sqltypes.Row) (*partition, error) {
+	p := &partition{
+		rows:      rows,
+		peerStart: make([]int, len(rows)),
+		peerEnd:   make([]int, len(rows)),
+	}
+	start := 0
+	for i := 1; i <= len(rows); i++ {
+		if i < len(rows) {
+			differ, err := rowsDiffer(w.OrderBy, rows[start], rows[i])
+			if err != nil {
+				return nil, err
+			}
+			if !differ {
+				continue
+			}
+		}
+		for j := start; j < i; j++ {
+			p.peerStart[j] = start
+			p.peerEnd[j] = i
+		}
+		start = i
+	}
+	return p, nil
+}
+
+func (w *Window) processPartition(fields []*querypb.Field, rows []sqltypes.Row) ([]sqltypes.Row, error) {
+	p, err := w.newPartitionState(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]sqltypes.Value, len(w.Functions))
+	for i, fn := range w.Functions {
+		results[i], err = w.evaluate(fn, fields, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]sqltypes.Row, 0, len(rows))
+	for rowIdx, row := range rows {
+		outRow := make(sqltypes.Row, 0, len(w.Cols))
+		for _, col := range w.Cols {
+			if col >= 0 {
+				outRow = append(outRow, row[col])
+				continue
+			}
+			outRow = append(outRow, results[-col-1][rowIdx])
+		}
+		out = append(out, outRow)
+	}
+	return out, nil
+}
+
+func (w *Window) evaluate(fn *WindowFunc, fields []*querypb.Field, p *partition) ([]sqltypes.Value, error) {
+	n := len(p.rows)
+	res := make([]sqltypes.Value, n)
+	switch fn.Opcode {
+	case WindowRowNumber:
+		for i := range res {
+			res[i] = sqltypes.NewUint64(uint64(i + 1))
+		}
+	case WindowRank:
+		for i := range res {
+			res[i] = sqltypes.NewUint64(uint64(p.peerStart[i] + 1))
+		}
+	case WindowDenseRank:
+		var rank uint64
+		for i := range res {
+			if p.peerStart[i] == i {
+				rank++
+			}
+			res[i] = sqltypes.NewUint64(rank)
+		}
+	case WindowPercentRank:
+		for i := range res {
+			var pr float64
+			if n > 1 {
+				pr = float64(p.peerStart[i]) / float64(n-1)
+			}
+			res[i] = sqltypes.NewFloat64(pr)
+		}
+	case WindowCumeDist:
+		for i := range res {
+			res[i] = sqltypes.NewFloat64(float64(p.peerEnd[i]) / float64(n))
+		}
+	case WindowNtile:
+		if fn.N <= 0 {
+			return nil, vterrors.VT03025("ntile")
+		}
+		// every bucket gets n/N rows, and the first n%N buckets get one extra row
+		size, extra := int64(n)/fn.N, int64(n)%fn.N
+		bucket, inBucket := int64(1), int64(0)
+		for i := range res {
+			limit := size
+			if bucket <= extra {
+				limit++
+			}
+			if inBucket == limit {
+				bucket++
+				inBucket = 0
+			}
+			inBucket++
+			res[i] = sqltypes.NewUint64(uint64(bucket))
+		}
+	case WindowLag, WindowLead:
+		offset := int(fn.N)
+		if fn.Opcode == WindowLag {
+			offset = -offset
+		}
+		for i := range res {
+			j := i + offset
+			switch {
+			case j >= 0 && j < n:
+				res[i] = p.rows[j][fn.Col]
+			case fn.DefaultCol >= 0:
+				res[i] = p.rows[i][fn.DefaultCol]
+			default:
+				res[i] = sqltypes.NULL
+			}
+		}
+	case WindowFirstValue, WindowLastValue, WindowNthValue:
+		for i := range res {
+			start, end := w.frame(fn, p, i)
+			idx := -1
+			switch fn.Opcode {
+			case WindowFirstValue:
+				idx = start
+			case WindowLastValue:
+				idx = end - 1
+			case WindowNthValue:
+				idx = start + int(fn.N) - 1
+			}
+			if idx >= start && idx < end {
+				res[i] = p.rows[idx][fn.Col]
+			} else {
+				res[i] = sqltypes.NULL
+			}
+		}
+	case WindowAggregate:
+		agg, err := newWindowAggregator(fields, fn)
+		if err != nil {
+			return nil, err
+		}
+		incremental := fn.Frame == nil || fn.Frame.Start.Type == UnboundedPreceding
+		added := 0
+		for i := range res {
+			start, end := w.frame(fn, p, i)
+			if !incremental || end < added {
+				agg.reset()
+				added = start
+			}
+			for ; added < end; added++ {
+				if err := agg.add(p.rows[added]); err != nil {
+					return nil, err
+				}
+			}
+			res[i] = agg.finish()
+		}
+	default:
+		return nil, vterrors.VT13001(fmt.Sprintf("unexpected window function: %s", fn.Opcode.String()))
+	}
+	return res, nil
+}
+
+// frame returns the rows [start, end) of the window frame for the given row
+func (w *Window) frame(fn *WindowFunc, p *partition, row int) (start, end int) {
+	n := len(p.rows)
+	if fn.Frame == nil {
+		// without ORDER BY, all rows are peers, so the default frame is the full partition
+		return 0, p.peerEnd[row]
+	}
+
+	bound := func(b FrameBound, isEnd bool) int {
+		var pos int
+		switch b.Type {
+		case UnboundedPreceding:
+			return 0
+		case UnboundedFollowing:
+			return n
+		case CurrentRow:
+			if !fn.Frame.Rows {
+				if isEnd {
+					return p.peerEnd[row]
+				}
+				return p.peerStart[row]
+			}
+			pos = row
+		case Preceding:
+			pos = row - int(b.N)
+		case Following:
+			pos = row + int(b.N)
+		}
+		if isEnd {
+			pos++
+		}
+		return min(max(pos, 0), n)
+	}
+
+	start, end = bound(fn.Frame.Start, false), bound(fn.Frame.End, true)
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+func newWindowAggregator(fields []*querypb.Field, fn *WindowFunc) (aggregator, error) {
+	switch fn.Aggregate {
+	case AggregateCountStar:
+		return &aggregatorCountStar{}, nil
+	case AggregateAvg:
+		return &aggregatorAvg{
+			from: fn.Col,
+			sum:  evalengine.NewAggregationSum(fields[fn.Col].Type),
+		}, nil
+	case AggregateCount, AggregateSum, AggregateMin, AggregateMax:
+		param := NewAggregateParam(fn.Aggregate, fn.Col, fn.Alias, fn.CollationEnv)
+		param.Type = fn.Type
+		agg, _, err := newAggregation(nil, fields, []*AggregateParams{param})
+		if err != nil {
+			return nil, err
+		}
+		return agg[fn.Col], nil
+	}
+	return nil, vterrors.VT12001(fmt.Sprintf("window aggregation: %s", fn.Aggregate.String()))
+}
+
+// aggregatorAvg calculates AVG as SUM divided by COUNT, using the same
+// precision rules as MySQL: four more decimals than the input
+type aggregatorAvg struct {
+	from  int
+	sum   evalengine.Sum
+	count int64
+}
+
+func (a *aggregatorAvg) add(row []sqltypes.Value) error {
+	if row[a.from].IsNull() {
+		return nil
+	}
+	a.count++
+	return a.sum.Add(row[a.from])
+}
+
+func (a *aggregatorAvg) finish() sqltypes.Value {
+	if a.count == 0 {
+		return sqltypes.NULL
+	}
+	sum := a.sum.Result()
+	if sum.Type() == sqltypes.Float64 {
+		f, err := sum.ToFloat64()
+		if err != nil {
+			return sqltypes.NULL
+		}
+		return sqltypes.NewFloat64(f / float64(a.count))
+	}
+	dec, err := decimal.NewFromMySQL(sum.Raw())
+	if err != nil {
+		return sqltypes.NULL
+	}
+	const scaleIncr = 4
+	scale := -dec.Exponent() + scaleIncr
+	avg := dec.Div(decimal.NewFromInt(a.count), scaleIncr)
+	return sqltypes.MakeTrusted(sqltypes.Decimal, avg.FormatMySQL(scale))
+}
+
+func (a *aggregatorAvg) reset() {
+	a.sum.Reset()
+	a.count = 0
+}
+
+func windowFuncToString(i any) string {
+	return i.(*WindowFunc).String()
+}
+
+func (w *Window) description() PrimitiveDescription {
+	other := map[string]any{
+		"Functions": GenericJoin(w.Functions, windowFuncToString),
+		"Columns":   strings.Join(slice.Map(w.Cols, strconv.Itoa), ", "),
+	}
+	if len(w.PartitionBy) > 0 {
+		other["PartitionBy"] = GenericJoin(w.PartitionBy, groupByParamsToString)
+	}
+	if len(w.OrderBy) > 0 {
+		other["OrderBy"] = GenericJoin(w.OrderBy, groupByParamsToString)
+	}
+	return PrimitiveDescription{
+		OperatorType: "Window",
+		Other:        other,
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	. "vitess.io/vitess/go/vt/vtgate/engine/opcode"
+)
+
+func windowInput() *fakePrimitive {
+	return &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"grp|val",
+				"varbinary|int64",
+			),
+			"a|1",
+			"a|2",
+			"a|2",
+			"a|5",
+			"b|3",
+			"c|4",
+			"c|6",
+		)},
+	}
+}
+
+func newWindowFunc(op WindowOpcode, col int) *WindowFunc {
+	return &WindowFunc{
+		Opcode:       op,
+		Col:          col,
+		DefaultCol:   -1,
+		CollationEnv: collations.MySQL8(),
+	}
+}
+
+func windowKey(col int) *GroupByParams {
+	return &GroupByParams{KeyCol: col, WeightStringCol: -1, CollationEnv: collations.MySQL8()}
+}
+
+func TestWindowRanking(t *testing.T) {
+	rowNumber := newWindowFunc(WindowRowNumber, -1)
+	rowNumber.Alias = "rn"
+	rank := newWindowFunc(WindowRank, -1)
+	rank.Alias = "r"
+	denseRank := newWindowFunc(WindowDenseRank, -1)
+	denseRank.Alias = "dr"
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{windowKey(0)},
+		OrderBy:     []*GroupByParams{windowKey(1)},
+		Functions:   []*WindowFunc{rowNumber, rank, denseRank},
+		Cols:        []int{0, 1, -1, -2, -3},
+		Input:       windowInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"grp|val|rn|r|dr",
+			"varbinary|int64|uint64|uint64|uint64",
+		),
+		"a|1|1|1|1",
+		"a|2|2|2|2",
+		"a|2|3|2|2",
+		"a|5|4|4|3",
+		"b|3|1|1|1",
+		"c|4|1|1|1",
+		"c|6|2|2|2",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowLagLead(t *testing.T) {
+	lag := newWindowFunc(WindowLag, 1)
+	lag.N = 1
+	lag.Alias = "prev"
+	lead := newWindowFunc(WindowLead, 1)
+	lead.N = 2
+	lead.DefaultCol = 1
+	lead.Alias = "next"
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{windowKey(0)},
+		OrderBy:     []*GroupByParams{windowKey(1)},
+		Functions:   []*WindowFunc{lag, lead},
+		Cols:        []int{1, -1, -2},
+		Input:       windowInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"val|prev|next",
+			"int64|int64|int64",
+		),
+		"1|null|2",
+		"2|1|5",
+		"2|2|2",
+		"5|2|5",
+		"3|null|3",
+		"4|null|4",
+		"6|4|6",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowAggregateFrames(t *testing.T) {
+	// default frame with ORDER BY: RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW, peers included
+	running := newWindowFunc(WindowAggregate, 1)
+	running.Aggregate = AggregateSum
+	running.Alias = "running"
+
+	// ROWS BETWEEN 1 PRECEDING AND CURRENT ROW
+	moving := newWindowFunc(WindowAggregate, 1)
+	moving.Aggregate = AggregateSum
+	moving.Alias = "moving"
+	moving.Frame = &WindowFrame{
+		Rows:  true,
+		Start: FrameBound{Type: Preceding, N: 1},
+		End:   FrameBound{Type: CurrentRow},
+	}
+
+	// ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING
+	remaining := newWindowFunc(WindowAggregate, -1)
+	remaining.Aggregate = AggregateCountStar
+	remaining.Alias = "remaining"
+	remaining.Frame = &WindowFrame{
+		Rows:  true,
+		Start: FrameBound{Type: CurrentRow},
+		End:   FrameBound{Type: UnboundedFollowing},
+	}
+
+	w := &Window{
+		PartitionBy: []*GroupByParams{windowKey(0)},
+		OrderBy:     []*GroupByParams{windowKey(1)},
+		Functions:   []*WindowFunc{running, moving, remaining},
+		Cols:        []int{1, -1, -2, -3},
+		Input:       windowInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"val|running|moving|remaining",
+			"int64|decimal|decimal|int64",
+		),
+		"1|1|1|4",
+		"2|5|3|3",
+		"2|5|4|2",
+		"5|10|7|1",
+		"3|3|3|1",
+		"4|4|4|2",
+		"6|10|10|1",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowWholePartition(t *testing.T) {
+	avg := newWindowFunc(WindowAggregate, 1)
+	avg.Aggregate = AggregateAvg
+	avg.Alias = "avg"
+	first := newWindowFunc(WindowFirstValue, 1)
+	first.Alias = "first"
+	last := newWindowFunc(WindowLastValue, 1)
+	last.Alias = "last"
+	ntile := newWindowFunc(WindowNtile, -1)
+	ntile.N = 3
+	ntile.Alias = "bucket"
+
+	// no ORDER BY in the window, so every row sees the whole partition
+	w := &Window{
+		PartitionBy: []*GroupByParams{windowKey(0)},
+		Functions:   []*WindowFunc{avg, first, last, ntile},
+		Cols:        []int{0, -1, -2, -3, -4},
+		Input:       windowInput(),
+	}
+
+	result, err := w.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"grp|avg|first|last|bucket",
+			"varbinary|decimal|int64|int64|uint64",
+		),
+		"a|2.5000|1|5|1",
+		"a|2.5000|1|5|1",
+		"a|2.5000|1|5|2",
+		"a|2.5000|1|5|3",
+		"b|3.0000|3|3|1",
+		"c|5.0000|4|6|1",
+		"c|5.0000|4|6|2",
+	)
+	utils.MustMatch(t, want, result)
+}
+
+func TestWindowStreamExecute(t *testing.T) {
+	cumeDist := newWindowFunc(WindowCumeDist, -1)
+	cumeDist.Alias = "cd"
+	percentRank := newWindowFunc(WindowPercentRank, -1)
+	percentRank.Alias = "pr"
+
+	// the fake primitive streams two rows at a time, so partitions span multiple callbacks
+	w := &Window{
+		PartitionBy: []*GroupByParams{windowKey(0)},
+		OrderBy:     []*GroupByParams{windowKey(1)},
+		Functions:   []*WindowFunc{cumeDist, percentRank},
+		Cols:        []int{0, 1, -1, -2},
+		Input:       windowInput(),
+	}
+
+	result, err := wrapStreamExecute(w, &noopVCursor{}, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"grp|val|cd|pr",
+			"varbinary|int64|float64|float64",
+		),
+		"a|1|0.25|0",
+		"a|2|0.75|0.3333333333333333",
+		"a|2|0.75|0.3333333333333333",
+		"a|5|1|1",
+		"b|3|1|0",
+		"c|4|0.5|0",
+		"c|6|1|1",
+	)
+	utils.MustMatch(t, want, result)
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package evalengine
 
 import (
+	"bytes"
+	"math"
 	"strconv"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/decimal"
 	"vitess.io/vitess/go/mysql/fastparse"
 	"vitess.io/vitess/go/mysql/format"
+	"vitess.io/vitess/go/mysql/json"
 	"vitess.io/vitess/go/sqltypes"
 )
 
@@ -499,3 +502,225 @@ func NewAggregationMinMax(typ sqltypes.Type, collationEnv *collations.Environmen
 		return &aggregationMinMax{collation: collation, collationEnv: collationEnv, values: values}
 	}
 }
+
+// Bitwise implements a BIT_AND(), BIT_OR() or BIT_XOR() aggregation.
+// The partial results of the aggregation can be aggregated again with the same operation,
+// so the results of the shards are combined using the same aggregation.
+type Bitwise interface {
+	Add(value sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// aggregationBitwise implements the bitwise aggregations. Like in MySQL, binary strings
+// are aggregated byte by byte and must all have the same length, while any other value is
+// converted to an unsigned 64-bit integer. The result is never NULL: if no values have
+// been aggregated, it is the neutral value of the operation.
+type aggregationBitwise struct {
+	op        opBitBinary
+	neutral   uint64
+	current   uint64
+	binary    []byte
+	collation collations.TypedCollation
+}
+
+func (a *aggregationBitwise) Add(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	e, err := valueToEval(value, a.collation, nil)
+	if err != nil {
+		return err
+	}
+	if b, ok := e.(*evalBytes); ok && b.isBinary() && !b.isHexOrBitLiteral() {
+		switch {
+		case a.binary == nil:
+			if a.current != a.neutral {
+				return errBitwiseOperandsLength
+			}
+			a.binary = bytes.Clone(b.bytes)
+		case len(a.binary) != len(b.bytes):
+			return errBitwiseOperandsLength
+		default:
+			a.binary = a.op.binary(a.binary, b.bytes)
+		}
+		return nil
+	}
+	if a.binary != nil {
+		return errBitwiseOperandsLength
+	}
+	a.current = a.op.numeric(a.current, uint64(evalToInt64(e).i))
+	return nil
+}
+
+func (a *aggregationBitwise) Result() sqltypes.Value {
+	if a.binary != nil {
+		return sqltypes.MakeTrusted(sqltypes.VarBinary, a.binary)
+	}
+	return sqltypes.NewUint64(a.current)
+}
+
+func (a *aggregationBitwise) Reset() {
+	a.current = a.neutral
+	a.binary = nil // not safe to reuse as it's returned as MakeTrusted
+}
+
+func newAggregationBitwise(op opBitBinary, neutral uint64, collation collations.ID) Bitwise {
+	return &aggregationBitwise{
+		op:        op,
+		neutral:   neutral,
+		current:   neutral,
+		collation: typedCoercionCollation(sqltypes.VarChar, collation),
+	}
+}
+
+func NewAggregationBitAnd(collation collations.ID) Bitwise {
+	return newAggregationBitwise(opBitAnd{}, math.MaxUint64, collation)
+}
+
+func NewAggregationBitOr(collation collations.ID) Bitwise {
+	return newAggregationBitwise(opBitOr{}, 0, collation)
+}
+
+func NewAggregationBitXor(collation collations.ID) Bitwise {
+	return newAggregationBitwise(opBitXor{}, 0, collation)
+}
+
+// JSONAggregation implements a JSON_ARRAYAGG() or JSON_OBJECTAGG() aggregation.
+// The rows are added to the aggregation with Add, while Merge adds a JSON document
+// that is the partial result of the same aggregation on a subset of the rows, which
+// is how the results of the shards are combined.
+type JSONAggregation interface {
+	Add(values ...sqltypes.Value) error
+	Merge(value sqltypes.Value) error
+	Result() sqltypes.Value
+	Reset()
+}
+
+// aggregationJSONArray implements JSON_ARRAYAGG. Unlike most aggregations,
+// NULL values are aggregated as JSON nulls.
+type aggregationJSONArray struct {
+	items     []*json.Value
+	init      bool
+	collation collations.TypedCollation
+}
+
+func (a *aggregationJSONArray) Add(values ...sqltypes.Value) error {
+	e, err := valueToEval(values[0], a.collation, nil)
+	if err != nil {
+		return err
+	}
+	item, err := argToJSON(e)
+	if err != nil {
+		return err
+	}
+	a.items = append(a.items, item)
+	a.init = true
+	return nil
+}
+
+func (a *aggregationJSONArray) Merge(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	doc, err := parseJSONAggregation(value)
+	if err != nil {
+		return err
+	}
+	items, ok := doc.Array()
+	if !ok {
+		return errJSONType("JSON_ARRAYAGG")
+	}
+	a.items = append(a.items, items...)
+	a.init = true
+	return nil
+}
+
+func (a *aggregationJSONArray) Result() sqltypes.Value {
+	if !a.init {
+		return sqltypes.NULL
+	}
+	return evalToSQLValue(json.NewArray(a.items))
+}
+
+func (a *aggregationJSONArray) Reset() {
+	a.items = nil
+	a.init = false
+}
+
+// aggregationJSONObject implements JSON_OBJECTAGG. When a key is found
+// more than once, the last value wins.
+type aggregationJSONObject struct {
+	obj       json.Object
+	init      bool
+	collation collations.TypedCollation
+}
+
+func (a *aggregationJSONObject) Add(values ...sqltypes.Value) error {
+	if values[0].IsNull() {
+		return errJSONKeyIsNil
+	}
+	key, err := valueToEval(values[0], a.collation, nil)
+	if err != nil {
+		return err
+	}
+	keyStr, err := evalToVarchar(key, collations.CollationUtf8mb4ID, true)
+	if err != nil {
+		return err
+	}
+	e, err := valueToEval(values[1], a.collation, nil)
+	if err != nil {
+		return err
+	}
+	val, err := argToJSON(e)
+	if err != nil {
+		return err
+	}
+	a.obj.Set(keyStr.string(), val, json.Set)
+	a.init = true
+	return nil
+}
+
+func (a *aggregationJSONObject) Merge(value sqltypes.Value) error {
+	if value.IsNull() {
+		return nil
+	}
+	doc, err := parseJSONAggregation(value)
+	if err != nil {
+		return err
+	}
+	obj, ok := doc.Object()
+	if !ok {
+		return errJSONType("JSON_OBJECTAGG")
+	}
+	obj.Visit(func(key string, val *json.Value) {
+		a.obj.Set(key, val, json.Set)
+	})
+	a.init = true
+	return nil
+}
+
+func (a *aggregationJSONObject) Result() sqltypes.Value {
+	if !a.init {
+		return sqltypes.NULL
+	}
+	return evalToSQLValue(json.NewObject(a.obj))
+}
+
+func (a *aggregationJSONObject) Reset() {
+	a.obj = json.Object{}
+	a.init = false
+}
+
+func parseJSONAggregation(value sqltypes.Value) (*json.Value, error) {
+	var p json.Parser
+	return p.ParseBytes(value.Raw())
+}
+
+func NewAggregationJSONArray(collation collations.ID) JSONAggregation {
+	return &aggregationJSONArray{collation: typedCoercionCollation(sqltypes.VarChar, collation)}
+}
+
+func NewAggregationJSONObject(collation collations.ID) JSONAggregation {
+	return &aggregationJSONObject{collation: typedCoercionCollation(sqltypes.VarChar, collation)}
+}
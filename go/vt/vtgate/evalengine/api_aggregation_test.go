@@ -17,6 +17,7 @@ limitations under the License.
 package evalengine
 
 import (
+	"math"
 	"strconv"
 	"testing"
 
@@ -170,3 +171,110 @@ func TestMinMax(t *testing.T) {
 		})
 	}
 }
+
+func TestBitwise(t *testing.T) {
+	tcases := []struct {
+		values       []sqltypes.Value
+		and, or, xor sqltypes.Value
+		err          string
+	}{
+		{
+			values: []sqltypes.Value{},
+			and:    sqltypes.NewUint64(math.MaxUint64),
+			or:     sqltypes.NewUint64(0),
+			xor:    sqltypes.NewUint64(0),
+		},
+		{
+			values: []sqltypes.Value{NULL, sqltypes.NewInt64(6), sqltypes.NewInt64(3)},
+			and:    sqltypes.NewUint64(2),
+			or:     sqltypes.NewUint64(7),
+			xor:    sqltypes.NewUint64(5),
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewInt64(-1), sqltypes.NewVarChar("12"), sqltypes.NewFloat64(4.6)},
+			and:    sqltypes.NewUint64(4),
+			or:     sqltypes.NewUint64(math.MaxUint64),
+			xor:    sqltypes.NewUint64(math.MaxUint64 ^ 12 ^ 5),
+		},
+		{
+			// partial results of the aggregation on the shards
+			values: []sqltypes.Value{sqltypes.NewUint64(math.MaxUint64), sqltypes.NewUint64(3)},
+			and:    sqltypes.NewUint64(3),
+			or:     sqltypes.NewUint64(math.MaxUint64),
+			xor:    sqltypes.NewUint64(math.MaxUint64 ^ 3),
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewVarBinary("\x0f\xf0"), sqltypes.NewVarBinary("\x3c\x3c")},
+			and:    sqltypes.NewVarBinary("\x0c\x30"),
+			or:     sqltypes.NewVarBinary("\x3f\xfc"),
+			xor:    sqltypes.NewVarBinary("\x33\xcc"),
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewVarBinary("\x0f\xf0"), sqltypes.NewVarBinary("\x3c")},
+			err:    "Binary operands of bitwise operators must be of equal length",
+		},
+		{
+			values: []sqltypes.Value{sqltypes.NewVarBinary("\x0f\xf0"), sqltypes.NewInt64(1)},
+			err:    "Binary operands of bitwise operators must be of equal length",
+		},
+	}
+	for i, tcase := range tcases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			aggs := []struct {
+				agg         Bitwise
+				want, empty sqltypes.Value
+			}{
+				{NewAggregationBitAnd(collations.CollationUtf8mb4ID), tcase.and, tcases[0].and},
+				{NewAggregationBitOr(collations.CollationUtf8mb4ID), tcase.or, tcases[0].or},
+				{NewAggregationBitXor(collations.CollationUtf8mb4ID), tcase.xor, tcases[0].xor},
+			}
+			for _, a := range aggs {
+				var err error
+				for _, v := range tcase.values {
+					if err = a.agg.Add(v); err != nil {
+						break
+					}
+				}
+				if tcase.err != "" {
+					require.EqualError(t, err, tcase.err)
+					continue
+				}
+				require.NoError(t, err)
+				utils.MustMatch(t, a.want, a.agg.Result())
+
+				a.agg.Reset()
+				utils.MustMatch(t, a.empty, a.agg.Result())
+			}
+		})
+	}
+}
+
+func TestJSONAggregation(t *testing.T) {
+	arr := NewAggregationJSONArray(collations.CollationUtf8mb4ID)
+	require.Equal(t, sqltypes.NULL, arr.Result())
+
+	require.NoError(t, arr.Add(sqltypes.NewInt64(1)))
+	require.NoError(t, arr.Add(NULL))
+	require.NoError(t, arr.Add(sqltypes.NewVarChar("a")))
+	require.NoError(t, arr.Add(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"b": 2}`))))
+	require.NoError(t, arr.Merge(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`[3, "c"]`))))
+	require.NoError(t, arr.Merge(NULL))
+	require.Equal(t, `[1, null, "a", {"b": 2}, 3, "c"]`, arr.Result().ToString())
+	require.Error(t, arr.Merge(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"a": 1}`))))
+
+	arr.Reset()
+	require.Equal(t, sqltypes.NULL, arr.Result())
+
+	obj := NewAggregationJSONObject(collations.CollationUtf8mb4ID)
+	require.Equal(t, sqltypes.NULL, obj.Result())
+
+	require.NoError(t, obj.Add(sqltypes.NewVarChar("b"), sqltypes.NewInt64(1)))
+	require.NoError(t, obj.Add(sqltypes.NewInt64(1), NULL))
+	require.NoError(t, obj.Merge(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`{"a": [1], "b": 2}`))))
+	require.Equal(t, `{"1": null, "a": [1], "b": 2}`, obj.Result().ToString())
+	require.EqualError(t, obj.Add(NULL, sqltypes.NewInt64(1)), "JSON documents may not contain NULL member names.")
+	require.Error(t, obj.Merge(sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(`[1]`))))
+
+	obj.Reset()
+	require.Equal(t, sqltypes.NULL, obj.Result())
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"fmt"
+	"strconv"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type (
+	// JSONTable expands a JSON document into rows the same way MySQL's JSON_TABLE does.
+	// Every value matched by Path produces a row, and the columns are extracted from that value.
+	JSONTable struct {
+		Path    *json.Path
+		Columns []JSONTableColumn
+	}
+
+	// JSONTableColumn is a column of a JSON_TABLE
+	JSONTableColumn struct {
+		Name string
+		Kind JSONTableColumnKind
+		Type Type
+
+		// Path is the path of the value of the column, relative to the row
+		Path *json.Path
+
+		// OnEmpty is used when the path doesn't match anything, and OnError when the value can't be stored in the column
+		OnEmpty, OnError JSONTableResponse
+	}
+
+	// JSONTableColumnKind is the kind of column in a JSON_TABLE
+	JSONTableColumnKind int8
+
+	// JSONTableResponse is what a JSON_TABLE column does when its value is missing or invalid
+	JSONTableResponse struct {
+		Kind JSONTableResponseKind
+
+		// Default is the value used with JSONTableDefault, before it is converted to the type of the column.
+		// The DEFAULT of a JSON_TABLE column is JSON text, and Default holds the value it was parsed into.
+		Default sqltypes.Value
+	}
+
+	// JSONTableResponseKind is the kind of JSONTableResponse
+	JSONTableResponseKind int8
+)
+
+const (
+	// JSONTableOrdinality is a FOR ORDINALITY column, counting the rows
+	JSONTableOrdinality JSONTableColumnKind = iota
+	// JSONTablePath is a column with the value found at its path
+	JSONTablePath
+	// JSONTableExists is an EXISTS PATH column, which is 1 if the path matches something and 0 otherwise
+	JSONTableExists
+)
+
+const (
+	// JSONTableNull returns NULL, which is the default in MySQL
+	JSONTableNull JSONTableResponseKind = iota
+	// JSONTableDefault returns the default value of the response
+	JSONTableDefault
+	// JSONTableError fails the query
+	JSONTableError
+)
+
+// TranslateJSONTable translates the definition of the JSON_TABLE so it can be evaluated by the vtgate.
+// The JSON document is not part of the translation, it is passed in when the rows are fetched.
+func TranslateJSONTable(node *sqlparser.JSONTableExpr, cfg *Config) (*JSONTable, error) {
+	path, err := translateJSONTablePath(node.Filter)
+	if err != nil {
+		return nil, err
+	}
+	jt := &JSONTable{Path: path}
+	for _, col := range node.Columns {
+		switch {
+		case col.JtOrdinal != nil:
+			jt.Columns = append(jt.Columns, JSONTableColumn{
+				Name: col.JtOrdinal.Name.String(),
+				Kind: JSONTableOrdinality,
+				Type: NewType(sqltypes.Uint32, collations.CollationBinaryID),
+			})
+		case col.JtPath != nil:
+			column, err := translateJSONTableColumn(col.JtPath, cfg)
+			if err != nil {
+				return nil, err
+			}
+			jt.Columns = append(jt.Columns, column)
+		default:
+			return nil, vterrors.VT12001("NESTED PATH in JSON_TABLE evaluated by the vtgate")
+		}
+	}
+	return jt, nil
+}
+
+func translateJSONTableColumn(col *sqlparser.JtPathColDef, cfg *Config) (JSONTableColumn, error) {
+	path, err := translateJSONTablePath(col.Path)
+	if err != nil {
+		return JSONTableColumn{}, err
+	}
+	ct := col.Type
+	typ := ct.SQLType()
+	values := EnumSetValues(ct.EnumValues)
+	column := JSONTableColumn{
+		Name: col.Name.String(),
+		Kind: JSONTablePath,
+		Path: path,
+		Type: NewTypeEx(typ, collations.CollationForType(typ, cfg.Collation), true, intOrZero(ct.Length), intOrZero(ct.Scale), &values),
+	}
+	if col.JtColExists {
+		column.Kind = JSONTableExists
+		return column, nil
+	}
+	if column.OnEmpty, err = translateJSONTableResponse("JSON_TABLE", col.EmptyOnResponse); err != nil {
+		return JSONTableColumn{}, err
+	}
+	if column.OnError, err = translateJSONTableResponse("JSON_TABLE", col.ErrorOnResponse); err != nil {
+		return JSONTableColumn{}, err
+	}
+	if err = column.parseDefault(&column.OnEmpty); err != nil {
+		return JSONTableColumn{}, err
+	}
+	if err = column.parseDefault(&column.OnError); err != nil {
+		return JSONTableColumn{}, err
+	}
+	return column, nil
+}
+
+// parseDefault parses the DEFAULT of a JSON_TABLE column as JSON, the same way MySQL does.
+// The DEFAULT of JSON_VALUE is not parsed, it is a plain value.
+func (col *JSONTableColumn) parseDefault(r *JSONTableResponse) error {
+	if r.Kind != JSONTableDefault {
+		return nil
+	}
+	var p json.Parser
+	doc, err := p.ParseBytes(r.Default.Raw())
+	if err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid DEFAULT of JSON_TABLE column '%s': %v", col.Name, err)
+	}
+	def, ok := col.scalar(doc)
+	if !ok {
+		return errJSONTableNotScalar(col)
+	}
+	r.Default = def
+	return nil
+}
+
+func translateJSONTableResponse(fn string, r *sqlparser.JtOnResponse) (JSONTableResponse, error) {
+	if r == nil {
+		return JSONTableResponse{Kind: JSONTableNull}, nil
+	}
+	switch r.ResponseType {
+	case sqlparser.ErrorJSONType:
+		return JSONTableResponse{Kind: JSONTableError}, nil
+	case sqlparser.DefaultJSONType:
+		lit, ok := r.Expr.(*sqlparser.Literal)
+		if !ok {
+			return JSONTableResponse{}, vterrors.VT12001(fmt.Sprintf("non-literal DEFAULT in %s evaluated by the vtgate", fn))
+		}
+		def, err := sqlparser.LiteralToValue(lit)
+		if err != nil {
+			return JSONTableResponse{}, err
+		}
+		return JSONTableResponse{Kind: JSONTableDefault, Default: def}, nil
+	default:
+		return JSONTableResponse{Kind: JSONTableNull}, nil
+	}
+}
+
+func translateJSONTablePath(expr sqlparser.Expr) (*json.Path, error) {
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.StrVal {
+		return nil, vterrors.VT12001("non-literal path in JSON_TABLE evaluated by the vtgate")
+	}
+	var p json.PathParser
+	path, err := p.ParseBytes(lit.Bytes())
+	if err != nil {
+		return nil, errJSONPath
+	}
+	return path, nil
+}
+
+func intOrZero(i *int) int32 {
+	if i == nil {
+		return 0
+	}
+	return int32(*i)
+}
+
+// Rows evaluates the JSON document and returns the rows of the JSON_TABLE for it. A NULL document has no rows.
+func (jt *JSONTable) Rows(env *ExpressionEnv, doc Expr) ([][]sqltypes.Value, error) {
+	res, err := env.Evaluate(doc)
+	if err != nil {
+		return nil, err
+	}
+	if res.v == nil {
+		return nil, nil
+	}
+	j, err := intoJSON("json_table", res.v)
+	if err != nil {
+		return nil, err
+	}
+	sqlmode := env.sqlmode
+
+	var rows [][]sqltypes.Value
+	jt.Path.Match(j, true, func(value *json.Value) {
+		if err != nil {
+			return
+		}
+		var row []sqltypes.Value
+		row, err = jt.row(value, len(rows)+1, sqlmode)
+		rows = append(rows, row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (jt *JSONTable) row(value *json.Value, ordinality int, sqlmode SQLMode) ([]sqltypes.Value, error) {
+	row := make([]sqltypes.Value, 0, len(jt.Columns))
+	for _, col := range jt.Columns {
+		var (
+			v   sqltypes.Value
+			err error
+		)
+		switch col.Kind {
+		case JSONTableOrdinality:
+			v = sqltypes.MakeTrusted(sqltypes.Uint32, strconv.AppendInt(nil, int64(ordinality), 10))
+		case JSONTableExists:
+			found := false
+			col.Path.Match(value, true, func(*json.Value) { found = true })
+			v, err = col.cast(sqltypes.NewInt64(boolToInt64(found)), sqlmode)
+		case JSONTablePath:
+			v, err = col.value(value, sqlmode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, v)
+	}
+	return row, nil
+}
+
+func (col *JSONTableColumn) value(row *json.Value, sqlmode SQLMode) (sqltypes.Value, error) {
+	var matches []*json.Value
+	col.Path.Match(row, true, func(value *json.Value) {
+		matches = append(matches, value)
+	})
+
+	switch len(matches) {
+	case 0:
+		return col.OnEmpty.respond(col, sqlmode, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Missing value for JSON_TABLE column '%s'", col.Name))
+	case 1:
+	default:
+		return col.OnError.respond(col, sqlmode, errJSONTableNotScalar(col))
+	}
+
+	v, ok := col.scalar(matches[0])
+	if !ok {
+		return col.OnError.respond(col, sqlmode, errJSONTableNotScalar(col))
+	}
+	if v.IsNull() || col.Type.Type() == sqltypes.TypeJSON {
+		return v, nil
+	}
+
+	res, err := col.cast(v, sqlmode)
+	if err != nil {
+		return col.OnError.respond(col, sqlmode, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid value for JSON_TABLE column '%s': %v", col.Name, err))
+	}
+	return res, nil
+}
+
+func errJSONTableNotScalar(col *JSONTableColumn) error {
+	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't store an array or an object in the scalar column '%s' of JSON_TABLE", col.Name)
+}
+
+// scalar returns the value of the column for the JSON value, before it is converted to the type of the column.
+// It returns false when the value is an array or an object, which can only be stored in a JSON column.
+func (col *JSONTableColumn) scalar(match *json.Value) (sqltypes.Value, bool) {
+	if col.Type.Type() == sqltypes.TypeJSON {
+		return sqltypes.MakeTrusted(sqltypes.TypeJSON, match.ToRawBytes()), true
+	}
+
+	var v sqltypes.Value
+	switch match.Type() {
+	case json.TypeNull:
+		return sqltypes.NULL, true
+	case json.TypeObject, json.TypeArray:
+		return sqltypes.Value{}, false
+	case json.TypeString:
+		str, _ := match.StringBytes()
+		v = sqltypes.MakeTrusted(sqltypes.VarChar, str)
+	case json.TypeNumber:
+		switch match.NumberType() {
+		case json.NumberTypeSigned:
+			v = sqltypes.MakeTrusted(sqltypes.Int64, []byte(match.Raw()))
+		case json.NumberTypeUnsigned:
+			v = sqltypes.MakeTrusted(sqltypes.Uint64, []byte(match.Raw()))
+		case json.NumberTypeDecimal:
+			v = sqltypes.MakeTrusted(sqltypes.Decimal, []byte(match.Raw()))
+		default:
+			v = sqltypes.MakeTrusted(sqltypes.Float64, []byte(match.Raw()))
+		}
+	case json.TypeBoolean:
+		b, _ := match.Bool()
+		if sqltypes.IsText(col.Type.Type()) {
+			v = sqltypes.NewVarChar(strconv.FormatBool(b))
+		} else {
+			v = sqltypes.NewInt64(boolToInt64(b))
+		}
+	case json.TypeDate:
+		v = sqltypes.MakeTrusted(sqltypes.Date, []byte(match.MarshalDate()))
+	case json.TypeDateTime:
+		v = sqltypes.MakeTrusted(sqltypes.Datetime, []byte(match.MarshalDateTime()))
+	case json.TypeTime:
+		v = sqltypes.MakeTrusted(sqltypes.Time, []byte(match.MarshalTime()))
+	default:
+		v = sqltypes.MakeTrusted(sqltypes.VarBinary, match.ToUnencodedBytes())
+	}
+	return v, true
+}
+
+// cast converts the value to the type of the column
+func (col *JSONTableColumn) cast(v sqltypes.Value, sqlmode SQLMode) (sqltypes.Value, error) {
+	e, err := valueToEvalCast(v, col.Type.Type(), col.Type.Collation(), col.Type.values, sqlmode)
+	if err != nil {
+		return sqltypes.Value{}, err
+	}
+	return evalToSQLValueWithType(e, col.Type), nil
+}
+
+func (r *JSONTableResponse) respond(col *JSONTableColumn, sqlmode SQLMode, err error) (sqltypes.Value, error) {
+	switch r.Kind {
+	case JSONTableDefault:
+		if r.Default.IsNull() || col.Type.Type() == sqltypes.TypeJSON {
+			return r.Default, nil
+		}
+		return col.cast(r.Default, sqlmode)
+	case JSONTableError:
+		return sqltypes.Value{}, err
+	default:
+		return sqltypes.NULL, nil
+	}
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+)
+
+func TestJSONTable(t *testing.T) {
+	testCases := []struct {
+		doc     string
+		columns string
+		path    string
+		rows    string
+		err     string
+	}{{
+		doc:     `'[{"a": 1, "b": "x"}, {"a": 2, "b": "y"}]'`,
+		columns: `id for ordinality, a int path '$.a', b varchar(10) path '$.b'`,
+		rows:    `[[UINT32(1) INT32(1) VARCHAR("x")] [UINT32(2) INT32(2) VARCHAR("y")]]`,
+	}, {
+		doc:     `'[{"a": 1}, {"b": 2}, {"a": [1, 2]}]'`,
+		columns: `a int path '$.a', e int exists path '$.b'`,
+		rows:    `[[INT32(1) INT32(0)] [NULL INT32(1)] [NULL INT32(0)]]`,
+	}, {
+		doc:     `'[{"a": 1}, {"b": 2}, {"a": "x"}]'`,
+		columns: `a int path '$.a' default '42' on empty default '-1' on error`,
+		rows:    `[[INT32(1)] [INT32(42)] [INT32(-1)]]`,
+	}, {
+		doc:     `'[{"b": "y"}, {}, {"b": [1]}]'`,
+		columns: `b varchar(10) path '$.b' default '"x"' on empty default '"z"' on error`,
+		rows:    `[[VARCHAR("y")] [VARCHAR("x")] [VARCHAR("z")]]`,
+	}, {
+		doc:     `'[{}]'`,
+		columns: `b json path '$.b' default '{"x": 1}' on empty, c varchar(10) path '$.c' default 'null' on empty`,
+		rows:    `[[JSON("{\"x\": 1}") NULL]]`,
+	}, {
+		doc:     `'[{"a": 1}, {"b": 2}]'`,
+		columns: `a int path '$.a' error on empty`,
+		err:     `Missing value for JSON_TABLE column 'a'`,
+	}, {
+		doc:     `'[{"a": {"x": 1}}]'`,
+		columns: `a varchar(10) path '$.a' error on error`,
+		err:     `Can't store an array or an object in the scalar column 'a' of JSON_TABLE`,
+	}, {
+		doc:     `'[{"a": {"x": 1}}, {"a": true}]'`,
+		columns: `a json path '$.a', b varchar(10) path '$.a', c int path '$.a'`,
+		rows:    `[[JSON("{\"x\": 1}") NULL NULL] [JSON("true") VARCHAR("true") INT32(1)]]`,
+	}, {
+		doc:     `'{"a": 1.5}'`,
+		path:    `'$'`,
+		columns: `a decimal(4,2) path '$.a', b double path '$.a'`,
+		rows:    `[[DECIMAL(1.50) FLOAT64(1.5)]]`,
+	}, {
+		doc:     `null`,
+		columns: `a int path '$'`,
+		rows:    `[]`,
+	}, {
+		doc:     `'not json'`,
+		columns: `a int path '$'`,
+		err:     `cannot parse JSON`,
+	}}
+
+	venv := vtenv.NewTestEnv()
+	for _, tc := range testCases {
+		path := tc.path
+		if path == "" {
+			path = `'$[*]'`
+		}
+		query := fmt.Sprintf("select * from json_table(%s, %s columns(%s)) as jt", tc.doc, path, tc.columns)
+		t.Run(query, func(t *testing.T) {
+			stmt, err := venv.Parser().Parse(query)
+			require.NoError(t, err)
+			node := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+
+			cfg := &Config{
+				Collation:   venv.CollationEnv().DefaultConnectionCharset(),
+				Environment: venv,
+			}
+			jt, err := TranslateJSONTable(node, cfg)
+			require.NoError(t, err)
+
+			doc, err := Translate(node.Expr, cfg)
+			require.NoError(t, err)
+			rows, err := jt.Rows(EmptyExpressionEnv(venv), doc)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.rows, fmt.Sprintf("%v", rows))
+		})
+	}
+}
+
+func TestTranslateJSONTableUnsupported(t *testing.T) {
+	testCases := []struct {
+		query string
+		err   string
+	}{{
+		query: `select * from json_table('[]', '$[*]' columns(nested path '$.a' columns(b int path '$'))) as jt`,
+		err:   "VT12001: unsupported: NESTED PATH in JSON_TABLE evaluated by the vtgate",
+	}, {
+		query: `select * from json_table('[]', '$[' columns(a int path '$')) as jt`,
+		err:   "Invalid JSON path expression.",
+	}, {
+		query: `select * from json_table('[]', '$[*]' columns(a varchar(10) path '$' default 'x' on empty)) as jt`,
+		err:   `Invalid DEFAULT of JSON_TABLE column 'a': cannot parse JSON: invalid number in JSON string: "x"; unparsed tail: "x"`,
+	}}
+
+	venv := vtenv.NewTestEnv()
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := venv.Parser().Parse(tc.query)
+			require.NoError(t, err)
+			node := stmt.(*sqlparser.Select).From[0].(*sqlparser.JSONTableExpr)
+
+			_, err = TranslateJSONTable(node, &Config{Environment: venv})
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
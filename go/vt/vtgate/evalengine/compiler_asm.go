@@ -4626,6 +4626,123 @@ func (asm *assembler) Fn_DATEADD_s(unit datetime.IntervalType, sub bool, col col
 
 }
 
+func (asm *assembler) Fn_TIMESTAMPDIFF(unit datetime.IntervalType) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		dt1, _ := env.vm.stack[env.vm.sp-2].(*evalTemporal)
+		dt2, _ := env.vm.stack[env.vm.sp-1].(*evalTemporal)
+		if dt1 == nil || dt2 == nil || dt1.dt.Date.IsZero() || dt2.dt.Date.IsZero() {
+			env.vm.stack[env.vm.sp-2] = nil
+		} else {
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(datetime.TimestampDiff(dt1.dt, dt2.dt, unit))
+		}
+		env.vm.sp--
+		return 1
+	}, "FN TIMESTAMPDIFF DATETIME(SP-2), DATETIME(SP-1)")
+}
+
+func (asm *assembler) Fn_DATEDIFF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		d1, _ := env.vm.stack[env.vm.sp-2].(*evalTemporal)
+		d2, _ := env.vm.stack[env.vm.sp-1].(*evalTemporal)
+		if d1 == nil || d2 == nil || d1.dt.Date.IsZero() || d2.dt.Date.IsZero() {
+			env.vm.stack[env.vm.sp-2] = nil
+		} else {
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(dateDiff(d1.dt.Date, d2.dt.Date))
+		}
+		env.vm.sp--
+		return 1
+	}, "FN DATEDIFF DATE(SP-2), DATE(SP-1)")
+}
+
+func (asm *assembler) Fn_PERIOD_ADD() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		period := env.vm.stack[env.vm.sp-2].(*evalInt64)
+		months := env.vm.stack[env.vm.sp-1].(*evalInt64)
+
+		var res int64
+		res, env.vm.err = periodAdd(period.i, months.i)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(res)
+		env.vm.sp--
+		return 1
+	}, "FN PERIOD_ADD INT64(SP-2), INT64(SP-1)")
+}
+
+func (asm *assembler) Fn_PERIOD_DIFF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		period1 := env.vm.stack[env.vm.sp-2].(*evalInt64)
+		period2 := env.vm.stack[env.vm.sp-1].(*evalInt64)
+
+		var res int64
+		res, env.vm.err = periodDiff(period1.i, period2.i)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(res)
+		env.vm.sp--
+		return 1
+	}, "FN PERIOD_DIFF INT64(SP-2), INT64(SP-1)")
+}
+
+func (asm *assembler) Fn_ADDTIME(sub bool, col collations.TypedCollation) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		arg := env.vm.stack[env.vm.sp-2]
+		base := evalToAddTimeBase(arg)
+		itv := evalToAddTimeInterval(env.vm.stack[env.vm.sp-1])
+
+		var res *evalTemporal
+		if base != nil && itv != nil {
+			res = addTime(base, itv, sub)
+		}
+
+		switch _, temporal := arg.(*evalTemporal); {
+		case res == nil:
+			env.vm.stack[env.vm.sp-2] = nil
+		case temporal:
+			env.vm.stack[env.vm.sp-2] = res
+		default:
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalText(res.ToRawBytes(), col)
+		}
+		env.vm.sp--
+		return 1
+	}, "FN ADDTIME TEMPORAL(SP-2), TIME(SP-1)")
+}
+
+func (asm *assembler) Fn_STR_TO_DATE(tt sqltypes.Type, prec uint8, mode SQLMode) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		str := env.vm.stack[env.vm.sp-2].(*evalBytes)
+		format := env.vm.stack[env.vm.sp-1].(*evalBytes)
+
+		dt, ok := strToDate(format.string(), str.string(), tt, mode)
+		switch {
+		case !ok:
+			env.vm.stack[env.vm.sp-2] = nil
+		case tt == sqltypes.Date:
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalDate(dt.Date)
+		case tt == sqltypes.Time:
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalTime(dt.Time, int(prec))
+		default:
+			env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalDateTime(dt, int(prec))
+		}
+		env.vm.sp--
+		return 1
+	}, "FN STR_TO_DATE VARBINARY(SP-2), VARBINARY(SP-1)")
+}
+
+func (asm *assembler) Fn_GET_FORMAT(tt sqltypes.Type, col collations.TypedCollation) {
+	asm.emit(func(env *ExpressionEnv) int {
+		arg := env.vm.stack[env.vm.sp-1].(*evalBytes)
+		if f, ok := getFormat(tt, arg.string()); ok {
+			env.vm.stack[env.vm.sp-1] = env.vm.arena.newEvalText([]byte(f), col)
+		} else {
+			env.vm.stack[env.vm.sp-1] = nil
+		}
+		return 1
+	}, "FN GET_FORMAT VARBINARY(SP-1)")
+}
+
 func (asm *assembler) Fn_REGEXP_LIKE(m *icuregex.Matcher, negate bool, c charset.Charset, offset int) {
 	asm.adjustStack(-offset)
 	asm.emit(func(env *ExpressionEnv) int {
@@ -5076,3 +5193,43 @@ func (asm *assembler) Introduce(offset int, t sqltypes.Type, col collations.Type
 		return 1
 	}, "INTRODUCE (SP-1)")
 }
+
+func (asm *assembler) Fn_GIS(call *builtinGeometry) {
+	args := len(call.Arguments)
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		res, err := call.fn.call(call, env.vm.stack[env.vm.sp-args:env.vm.sp])
+		env.vm.stack[env.vm.sp-args] = res
+		env.vm.err = err
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", call.Method, args)
+}
+
+// Fn_UDF calls the Go implementation of a user-defined function with all of its
+// arguments from the stack, NULLs included, and replaces them with its result.
+func (asm *assembler) Fn_UDF(call *builtinUDF) {
+	args := len(call.Arguments)
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		res, err := call.call(env, env.vm.stack[env.vm.sp-args:env.vm.sp])
+		env.vm.stack[env.vm.sp-args] = res
+		env.vm.err = err
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN UDF %s (SP-%d)...(SP-1)", call.Method, args)
+}
+
+// Fn_JSON_CALL evaluates a JSON function that takes all of its arguments from the stack,
+// NULLs included, and replaces them with its result.
+func (asm *assembler) Fn_JSON_CALL(call *CallExpr, fn func(env *ExpressionEnv, args []eval) (eval, error)) {
+	args := len(call.Arguments)
+	asm.adjustStack(-args + 1)
+	asm.emit(func(env *ExpressionEnv) int {
+		res, err := fn(env, env.vm.stack[env.vm.sp-args:env.vm.sp])
+		env.vm.stack[env.vm.sp-args] = res
+		env.vm.err = err
+		env.vm.sp -= args - 1
+		return 1
+	}, "FN %s (SP-%d)...(SP-1)", call.Method, args)
+}
@@ -453,6 +453,43 @@ func TestCompilerSingle(t *testing.T) {
 			expression: `UNIX_TIMESTAMP(time '5 10:34:58')`,
 			result:     `INT64(1698572098)`,
 		},
+		{
+			expression: `DATE_ADD(time '10:00:00', INTERVAL 1 DAY)`,
+			result:     `DATETIME("2023-10-25 10:00:00")`,
+		},
+		{
+			expression: `TIMESTAMPDIFF(MONTH, '2024-01-31', '2024-02-29')`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `DATEDIFF('2010-11-30 23:59:59', column0)`,
+			values:     []sqltypes.Value{sqltypes.NewDate("2010-12-31")},
+			result:     `INT64(-31)`,
+		},
+		{
+			expression: `ADDTIME('2007-12-31 23:59:59.999999', '1 1:1:1.000002')`,
+			result:     `VARCHAR("2008-01-02 01:01:01.000001")`,
+		},
+		{
+			expression: `SUBTIME(time '01:00:00.999999', '02:00:00.999998')`,
+			result:     `TIME("-00:59:59.999999")`,
+		},
+		{
+			expression: `PERIOD_ADD(9912, 1)`,
+			result:     `INT64(200001)`,
+		},
+		{
+			expression: `STR_TO_DATE('May 1, 2013', '%M %d,%Y')`,
+			result:     `DATE("2013-05-01")`,
+		},
+		{
+			expression: `STR_TO_DATE('9', '%m')`,
+			result:     `NULL`,
+		},
+		{
+			expression: `STR_TO_DATE('10.31.2003', GET_FORMAT(DATE, 'USA'))`,
+			result:     `DATETIME("2003-10-31 00:00:00.000000")`,
+		},
 		{
 			expression: `CONV(-1, -1.5e0, 3.141592653589793)`,
 			result:     `VARCHAR("11112220022122120101211020120210210211220")`,
@@ -723,6 +760,130 @@ func TestCompilerSingle(t *testing.T) {
 			expression: `cast(_utf32 0x0000FF as binary)`,
 			result:     `VARBINARY("\x00\x00\x00\xff")`,
 		},
+		{
+			expression: `ST_AsText(column0)`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\x00\x40"))},
+			result:     `TEXT("POINT(1 2)")`,
+		},
+		{
+			expression: `ST_Distance(column0, ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'))`,
+			values:     []sqltypes.Value{sqltypes.MakeTrusted(sqltypes.Geometry, []byte("\x00\x00\x00\x00\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x2a\x40\x00\x00\x00\x00\x00\x00\x2c\x40"))},
+			result:     `FLOAT64(5)`,
+		},
+		{
+			expression: `ST_Contains(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,3 2,3 3,2 2))'), ST_GeomFromText('LINESTRING(1 2.5,5 2.5)'))`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `ST_Contains(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,3 2,3 3,2 2))'), ST_GeomFromText('LINESTRING(1 1,5 1)'))`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Within(POINT(10, 5), ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'))`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `ST_Intersects(ST_GeomFromText('LINESTRING(0 0,10 10)'), ST_GeomFromText('LINESTRING(0 10,10 0)'))`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Area(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,3 2,3 3,2 2))'))`,
+			result:     `FLOAT64(99.5)`,
+		},
+		{
+			expression: `ST_AsText(ST_Centroid(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))')))`,
+			result:     `TEXT("POINT(5 5)")`,
+		},
+		{
+			expression: `ST_AsText(ST_GeomFromText('POINT(10 20)', 4326), 'axis-order=long-lat')`,
+			result:     `TEXT("POINT(20 10)")`,
+		},
+		{
+			expression: `ST_Longitude(ST_GeomFromText('POINT(10 20)', 4326))`,
+			result:     `FLOAT64(20)`,
+		},
+		{
+			expression: `ST_AsGeoJSON(ST_GeomFromText('LINESTRING(0 0,1.23456 2)'), 2)`,
+			result:     `JSON("{\"coordinates\": [[0.0, 0.0], [1.23, 2.0]], \"type\": \"LineString\"}")`,
+		},
+		{
+			expression: `ST_AsText(ST_GeomFromGeoJSON('{"type": "Point", "coordinates": [1, 2]}', 1, 0))`,
+			result:     `TEXT("POINT(1 2)")`,
+		},
+		{
+			expression: `ST_AsText(ST_Envelope(ST_GeomFromText('MULTIPOINT(1 1,3 1)')))`,
+			result:     `TEXT("LINESTRING(1 1,3 1)")`,
+		},
+		{
+			expression: `ST_Distance_Sphere(POINT(-73.9949, 40.7501), POINT(-73.9961, 40.7542))`,
+			result:     `FLOAT64(466.9696023589275)`,
+		},
+		{
+			expression: `ST_Distance(ST_GeomFromText('POINT(0 0)', 4326), ST_GeomFromText('POINT(1 0)', 4326))`,
+			result:     `FLOAT64(110573.13812780967)`,
+		},
+		{
+			expression: `ST_Touches(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))'), ST_GeomFromText('POLYGON((2 2,8 2,8 8,2 8,2 2))'))`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Touches(ST_GeomFromText('LINESTRING(0 0,10 10)'), ST_GeomFromText('LINESTRING(0 10,10 0)'))`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `ST_Overlaps(ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'), ST_GeomFromText('POLYGON((5 0,20 0,20 10,5 10,5 0))'))`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Overlaps(ST_GeomFromText('LINESTRING(0 0,10 10)'), ST_GeomFromText('LINESTRING(0 10,10 0)'))`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `ST_Crosses(ST_GeomFromText('LINESTRING(0 0,10 10)'), ST_GeomFromText('LINESTRING(0 10,10 0)'))`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `ST_Crosses(ST_GeomFromText('LINESTRING(1 5,5 5)'), ST_GeomFromText('POLYGON((0 0,10 0,10 10,0 10,0 0))'))`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `JSON_SET(column0, '$.a', 2, '$.b', column1 > 0, '$.c[3]', 3)`,
+			values:     []sqltypes.Value{sqltypes.NewVarChar(`{"a": 1, "c": [1]}`), sqltypes.NewInt64(1)},
+			result:     `JSON("{\"a\": 2, \"b\": true, \"c\": [1, 3]}")`,
+		},
+		{
+			expression: `JSON_ARRAY_INSERT(JSON_ARRAY_APPEND('[1, 2]', '$[0]', 3), '$[last]', 4)`,
+			result:     `JSON("[[1, 3], 4, 2]")`,
+		},
+		{
+			expression: `JSON_REMOVE(column0, '$.a')`,
+			values:     []sqltypes.Value{sqltypes.NULL},
+			result:     `NULL`,
+		},
+		{
+			expression: `JSON_MERGE_PATCH('{"a": 1, "b": {"c": 2}}', column0)`,
+			values:     []sqltypes.Value{sqltypes.NewVarChar(`{"b": {"c": null, "d": 3}}`)},
+			result:     `JSON("{\"a\": 1, \"b\": {\"d\": 3}}")`,
+		},
+		{
+			expression: `JSON_CONTAINS(column0, '[2]', '$.b')`,
+			values:     []sqltypes.Value{sqltypes.NewVarChar(`{"b": [1, 2]}`)},
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `JSON_SEARCH('["abc", {"x": "abc"}]', 'all', column0)`,
+			values:     []sqltypes.Value{sqltypes.NewVarChar(`a%`)},
+			result:     `JSON("[\"$[0]\", \"$[1].x\"]")`,
+		},
+		{
+			expression: `JSON_VALUE(column0, '$.a' RETURNING DECIMAL(4, 2))`,
+			values:     []sqltypes.Value{sqltypes.NewVarChar(`{"a": 1.567}`)},
+			result:     `DECIMAL(1.57)`,
+		},
+		{
+			expression: `JSON_VALUE('{"a": 1}', '$.b' DEFAULT 'none' ON EMPTY)`,
+			result:     `VARCHAR("none")`,
+		},
 	}
 
 	tz, _ := time.LoadLocation("Europe/Madrid")
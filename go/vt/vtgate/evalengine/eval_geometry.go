@@ -0,0 +1,977 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"vitess.io/vitess/go/mysql/format"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type errGISData string
+
+func (fn errGISData) Error() string {
+	return fmt.Sprintf("Invalid GIS data provided to function %s.", string(fn))
+}
+
+type geometryType uint32
+
+const (
+	geometryPoint geometryType = iota + 1
+	geometryLineString
+	geometryPolygon
+	geometryMultiPoint
+	geometryMultiLineString
+	geometryMultiPolygon
+	geometryCollection
+)
+
+// String returns the name of the type as returned by ST_GeometryType
+func (t geometryType) String() string {
+	switch t {
+	case geometryPoint:
+		return "POINT"
+	case geometryLineString:
+		return "LINESTRING"
+	case geometryPolygon:
+		return "POLYGON"
+	case geometryMultiPoint:
+		return "MULTIPOINT"
+	case geometryMultiLineString:
+		return "MULTILINESTRING"
+	case geometryMultiPolygon:
+		return "MULTIPOLYGON"
+	case geometryCollection:
+		return "GEOMCOLLECTION"
+	default:
+		return "GEOMETRY"
+	}
+}
+
+func (t geometryType) wkt() string {
+	if t == geometryCollection {
+		return "GEOMETRYCOLLECTION"
+	}
+	return t.String()
+}
+
+// member returns the type of the members of a multi-geometry, or 0 if
+// this type can hold any geometry.
+func (t geometryType) member() geometryType {
+	switch t {
+	case geometryMultiPoint:
+		return geometryPoint
+	case geometryMultiLineString:
+		return geometryLineString
+	case geometryMultiPolygon:
+		return geometryPolygon
+	default:
+		return 0
+	}
+}
+
+func (t geometryType) dimension() int {
+	switch t {
+	case geometryPoint, geometryMultiPoint:
+		return 0
+	case geometryLineString, geometryMultiLineString:
+		return 1
+	default:
+		return 2
+	}
+}
+
+type geoPoint struct {
+	x, y float64
+}
+
+// geometry is a decoded spatial value. A point keeps its coordinates in points[0]
+// and a linestring in points; a polygon keeps its rings, the exterior one first;
+// multi-geometries and geometry collections keep their members in geoms.
+type geometry struct {
+	srid   uint32
+	typ    geometryType
+	points []geoPoint
+	rings  [][]geoPoint
+	geoms  []*geometry
+}
+
+func newEvalGeometry(g *geometry) *evalBytes {
+	return newEvalRaw(sqltypes.Geometry, g.encode(), collationBinary)
+}
+
+// geometryArg decodes an argument that must be a geometry in MySQL's internal
+// format: a little-endian SRID followed by the WKB of the value.
+func geometryArg(fn string, e eval) (*geometry, error) {
+	b, ok := e.(*evalBytes)
+	if !ok || len(b.bytes) < 4 {
+		return nil, errGISData(fn)
+	}
+	g, ok := decodeWKB(b.bytes[4:])
+	if !ok {
+		return nil, errGISData(fn)
+	}
+	g.setSRID(binary.LittleEndian.Uint32(b.bytes))
+	return g, nil
+}
+
+func (g *geometry) setSRID(srid uint32) {
+	g.srid = srid
+	for _, m := range g.geoms {
+		m.setSRID(srid)
+	}
+}
+
+func (g *geometry) empty() bool {
+	if g.typ != geometryCollection {
+		return false
+	}
+	for _, m := range g.geoms {
+		if !m.empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// each calls fn for every point, linestring and polygon in this geometry,
+// flattening multi-geometries and collections.
+func (g *geometry) each(fn func(g *geometry)) {
+	if g.typ >= geometryMultiPoint {
+		for _, m := range g.geoms {
+			m.each(fn)
+		}
+		return
+	}
+	fn(g)
+}
+
+// swapped returns a copy of this geometry with its X and Y coordinates swapped.
+func (g *geometry) swapped() *geometry {
+	swap := func(points []geoPoint) []geoPoint {
+		out := make([]geoPoint, len(points))
+		for i, p := range points {
+			out[i] = geoPoint{x: p.y, y: p.x}
+		}
+		return out
+	}
+	out := &geometry{srid: g.srid, typ: g.typ}
+	if g.points != nil {
+		out.points = swap(g.points)
+	}
+	for _, r := range g.rings {
+		out.rings = append(out.rings, swap(r))
+	}
+	for _, m := range g.geoms {
+		out.geoms = append(out.geoms, m.swapped())
+	}
+	return out
+}
+
+func (g *geometry) encode() []byte {
+	buf := binary.LittleEndian.AppendUint32(nil, g.srid)
+	return g.appendWKB(buf)
+}
+
+func appendWKBPoints(buf []byte, points []geoPoint) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(points)))
+	for _, p := range points {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.x))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.y))
+	}
+	return buf
+}
+
+func (g *geometry) appendWKB(buf []byte) []byte {
+	buf = append(buf, 1)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(g.typ))
+	switch g.typ {
+	case geometryPoint:
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(g.points[0].x))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(g.points[0].y))
+	case geometryLineString:
+		buf = appendWKBPoints(buf, g.points)
+	case geometryPolygon:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.rings)))
+		for _, r := range g.rings {
+			buf = appendWKBPoints(buf, r)
+		}
+	default:
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(g.geoms)))
+		for _, m := range g.geoms {
+			buf = m.appendWKB(buf)
+		}
+	}
+	return buf
+}
+
+type wkbDecoder struct {
+	b []byte
+}
+
+func (d *wkbDecoder) uint32(order binary.ByteOrder) (uint32, bool) {
+	if len(d.b) < 4 {
+		return 0, false
+	}
+	v := order.Uint32(d.b)
+	d.b = d.b[4:]
+	return v, true
+}
+
+func (d *wkbDecoder) point(order binary.ByteOrder) (geoPoint, bool) {
+	if len(d.b) < 16 {
+		return geoPoint{}, false
+	}
+	p := geoPoint{
+		x: math.Float64frombits(order.Uint64(d.b)),
+		y: math.Float64frombits(order.Uint64(d.b[8:])),
+	}
+	d.b = d.b[16:]
+	return p, validCoordinate(p)
+}
+
+func (d *wkbDecoder) points(order binary.ByteOrder) ([]geoPoint, bool) {
+	n, ok := d.uint32(order)
+	if !ok || uint64(n)*16 > uint64(len(d.b)) {
+		return nil, false
+	}
+	points := make([]geoPoint, 0, n)
+	for i := uint32(0); i < n; i++ {
+		p, ok := d.point(order)
+		if !ok {
+			return nil, false
+		}
+		points = append(points, p)
+	}
+	return points, true
+}
+
+func (d *wkbDecoder) geometry() (*geometry, bool) {
+	if len(d.b) < 1 {
+		return nil, false
+	}
+	var order binary.ByteOrder
+	switch d.b[0] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return nil, false
+	}
+	d.b = d.b[1:]
+
+	t, ok := d.uint32(order)
+	if !ok {
+		return nil, false
+	}
+
+	g := &geometry{typ: geometryType(t)}
+	switch g.typ {
+	case geometryPoint:
+		var p geoPoint
+		p, ok = d.point(order)
+		g.points = []geoPoint{p}
+	case geometryLineString:
+		g.points, ok = d.points(order)
+	case geometryPolygon:
+		var n uint32
+		if n, ok = d.uint32(order); !ok || uint64(n)*4 > uint64(len(d.b)) {
+			return nil, false
+		}
+		for i := uint32(0); i < n && ok; i++ {
+			var ring []geoPoint
+			ring, ok = d.points(order)
+			g.rings = append(g.rings, ring)
+		}
+	case geometryMultiPoint, geometryMultiLineString, geometryMultiPolygon, geometryCollection:
+		var n uint32
+		if n, ok = d.uint32(order); !ok || uint64(n) > uint64(len(d.b)) {
+			return nil, false
+		}
+		g.geoms = make([]*geometry, 0, n)
+		for i := uint32(0); i < n && ok; i++ {
+			var m *geometry
+			m, ok = d.geometry()
+			if ok && g.typ.member() != 0 && m.typ != g.typ.member() {
+				return nil, false
+			}
+			g.geoms = append(g.geoms, m)
+		}
+	default:
+		return nil, false
+	}
+	return g, ok && g.valid()
+}
+
+func decodeWKB(b []byte) (*geometry, bool) {
+	d := wkbDecoder{b: b}
+	g, ok := d.geometry()
+	return g, ok && len(d.b) == 0
+}
+
+func validCoordinate(p geoPoint) bool {
+	return !math.IsNaN(p.x) && !math.IsInf(p.x, 0) && !math.IsNaN(p.y) && !math.IsInf(p.y, 0)
+}
+
+func validRing(ring []geoPoint) bool {
+	return len(ring) >= 4 && ring[0] == ring[len(ring)-1]
+}
+
+// valid checks the structural constraints MySQL enforces on every geometry
+// it parses: linestrings have at least two points, polygon rings are closed and
+// multi-geometries are not empty.
+func (g *geometry) valid() bool {
+	switch g.typ {
+	case geometryPoint:
+		return len(g.points) == 1
+	case geometryLineString:
+		return len(g.points) >= 2
+	case geometryPolygon:
+		if len(g.rings) == 0 {
+			return false
+		}
+		for _, r := range g.rings {
+			if !validRing(r) {
+				return false
+			}
+		}
+		return true
+	case geometryMultiPoint, geometryMultiLineString, geometryMultiPolygon:
+		return len(g.geoms) > 0
+	case geometryCollection:
+		return true
+	default:
+		return false
+	}
+}
+
+func appendWKTNumber(buf []byte, f float64) []byte {
+	return append(buf, format.FormatFloat(f)...)
+}
+
+func appendWKTPoints(buf []byte, points []geoPoint) []byte {
+	buf = append(buf, '(')
+	for i, p := range points {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendWKTNumber(buf, p.x)
+		buf = append(buf, ' ')
+		buf = appendWKTNumber(buf, p.y)
+	}
+	return append(buf, ')')
+}
+
+func (g *geometry) appendWKTBody(buf []byte) []byte {
+	switch g.typ {
+	case geometryPoint, geometryLineString:
+		buf = appendWKTPoints(buf, g.points)
+	case geometryPolygon:
+		buf = append(buf, '(')
+		for i, r := range g.rings {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendWKTPoints(buf, r)
+		}
+		buf = append(buf, ')')
+	case geometryCollection:
+		if len(g.geoms) == 0 {
+			return append(buf, " EMPTY"...)
+		}
+		buf = append(buf, '(')
+		for i, m := range g.geoms {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = m.appendWKT(buf)
+		}
+		buf = append(buf, ')')
+	default:
+		buf = append(buf, '(')
+		for i, m := range g.geoms {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = m.appendWKTBody(buf)
+		}
+		buf = append(buf, ')')
+	}
+	return buf
+}
+
+func (g *geometry) appendWKT(buf []byte) []byte {
+	buf = append(buf, g.typ.wkt()...)
+	return g.appendWKTBody(buf)
+}
+
+type wktParser struct {
+	s []byte
+}
+
+func (p *wktParser) skipSpace() {
+	for len(p.s) > 0 && (p.s[0] == ' ' || p.s[0] == '\t' || p.s[0] == '\n' || p.s[0] == '\r') {
+		p.s = p.s[1:]
+	}
+}
+
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpace()
+	if len(p.s) > 0 && p.s[0] == c {
+		p.s = p.s[1:]
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) peek(c byte) bool {
+	p.skipSpace()
+	return len(p.s) > 0 && p.s[0] == c
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	i := 0
+	for i < len(p.s) && (p.s[i] >= 'a' && p.s[i] <= 'z' || p.s[i] >= 'A' && p.s[i] <= 'Z') {
+		i++
+	}
+	w := string(bytes.ToUpper(p.s[:i]))
+	p.s = p.s[i:]
+	return w
+}
+
+func (p *wktParser) number() (float64, bool) {
+	p.skipSpace()
+	i := 0
+	for i < len(p.s) && (p.s[i] >= '0' && p.s[i] <= '9' || p.s[i] == '.' || p.s[i] == '-' || p.s[i] == '+' || p.s[i] == 'e' || p.s[i] == 'E') {
+		i++
+	}
+	f, err := strconv.ParseFloat(string(p.s[:i]), 64)
+	p.s = p.s[i:]
+	return f, err == nil && !math.IsInf(f, 0)
+}
+
+func (p *wktParser) point() (geoPoint, bool) {
+	x, ok := p.number()
+	if !ok {
+		return geoPoint{}, false
+	}
+	y, ok := p.number()
+	return geoPoint{x: x, y: y}, ok
+}
+
+func (p *wktParser) points() ([]geoPoint, bool) {
+	if !p.consume('(') {
+		return nil, false
+	}
+	var points []geoPoint
+	for {
+		pt, ok := p.point()
+		if !ok {
+			return nil, false
+		}
+		points = append(points, pt)
+		if !p.consume(',') {
+			break
+		}
+	}
+	return points, p.consume(')')
+}
+
+// body parses the part of a WKT value following the name of its type.
+func (p *wktParser) body(t geometryType) (*geometry, bool) {
+	g := &geometry{typ: t}
+	ok := true
+	switch t {
+	case geometryPoint:
+		if !p.consume('(') {
+			return nil, false
+		}
+		var pt geoPoint
+		if pt, ok = p.point(); !ok || !p.consume(')') {
+			return nil, false
+		}
+		g.points = []geoPoint{pt}
+	case geometryLineString:
+		g.points, ok = p.points()
+	case geometryPolygon:
+		if !p.consume('(') {
+			return nil, false
+		}
+		for {
+			var ring []geoPoint
+			if ring, ok = p.points(); !ok {
+				return nil, false
+			}
+			g.rings = append(g.rings, ring)
+			if !p.consume(',') {
+				break
+			}
+		}
+		ok = p.consume(')')
+	case geometryMultiPoint, geometryMultiLineString, geometryMultiPolygon:
+		if !p.consume('(') {
+			return nil, false
+		}
+		for {
+			var m *geometry
+			if t == geometryMultiPoint && !p.peek('(') {
+				// MULTIPOINT(1 1, 2 2) is accepted as well as MULTIPOINT((1 1), (2 2))
+				var pt geoPoint
+				if pt, ok = p.point(); !ok {
+					return nil, false
+				}
+				m = &geometry{typ: geometryPoint, points: []geoPoint{pt}}
+			} else if m, ok = p.body(t.member()); !ok {
+				return nil, false
+			}
+			g.geoms = append(g.geoms, m)
+			if !p.consume(',') {
+				break
+			}
+		}
+		ok = p.consume(')')
+	case geometryCollection:
+		g.geoms = []*geometry{}
+		if p.word() == "EMPTY" {
+			return g, true
+		}
+		if !p.consume('(') {
+			return nil, false
+		}
+		if p.consume(')') {
+			return g, true
+		}
+		for {
+			var m *geometry
+			if m, ok = p.geometry(); !ok {
+				return nil, false
+			}
+			g.geoms = append(g.geoms, m)
+			if !p.consume(',') {
+				break
+			}
+		}
+		ok = p.consume(')')
+	}
+	return g, ok && g.valid()
+}
+
+func (p *wktParser) geometry() (*geometry, bool) {
+	var t geometryType
+	switch p.word() {
+	case "POINT":
+		t = geometryPoint
+	case "LINESTRING":
+		t = geometryLineString
+	case "POLYGON":
+		t = geometryPolygon
+	case "MULTIPOINT":
+		t = geometryMultiPoint
+	case "MULTILINESTRING":
+		t = geometryMultiLineString
+	case "MULTIPOLYGON":
+		t = geometryMultiPolygon
+	case "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		t = geometryCollection
+	default:
+		return nil, false
+	}
+	return p.body(t)
+}
+
+func parseWKT(wkt []byte) (*geometry, bool) {
+	p := wktParser{s: wkt}
+	g, ok := p.geometry()
+	p.skipSpace()
+	return g, ok && len(p.s) == 0
+}
+
+// spatialReference returns whether the given SRID is a geographic spatial reference
+// system. Only the Cartesian plane (SRID 0), Web Mercator and WGS 84 are known to
+// vtgate; values in any other reference system must be evaluated by MySQL.
+func spatialReference(srid uint32) (geographic bool, err error) {
+	switch srid {
+	case 0, 3857:
+		return false, nil
+	case 4326:
+		return true, nil
+	default:
+		return false, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "spatial reference system %d is not supported in vtgate", srid)
+	}
+}
+
+// checkGeographic validates that all the points in a WGS 84 geometry, stored as
+// longitude and latitude, are within range.
+func checkGeographic(fn string, g *geometry) error {
+	var err error
+	check := func(points []geoPoint) {
+		for _, p := range points {
+			if err != nil {
+				return
+			}
+			if p.x <= -180 || p.x > 180 {
+				err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Longitude %f is out of range in function %s. It must be within (-180.000000, 180.000000].", p.x, fn)
+			} else if p.y < -90 || p.y > 90 {
+				err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Latitude %f is out of range in function %s. It must be within [-90.000000, 90.000000].", p.y, fn)
+			}
+		}
+	}
+	g.each(func(g *geometry) {
+		check(g.points)
+		for _, r := range g.rings {
+			check(r)
+		}
+	})
+	return err
+}
+
+// geoJSONNumber rounds f to the given number of decimal digits and returns it as
+// a JSON double.
+func geoJSONNumber(f float64, digits int64) *json.Value {
+	if digits < 17 {
+		scale := math.Pow10(int(digits))
+		f = math.Round(f*scale) / scale
+	}
+	return evalConvert_fj(newEvalFloat(f))
+}
+
+func geoJSONPoints(points []geoPoint, digits int64) *json.Value {
+	vals := make([]*json.Value, 0, len(points))
+	for _, p := range points {
+		vals = append(vals, json.NewArray([]*json.Value{geoJSONNumber(p.x, digits), geoJSONNumber(p.y, digits)}))
+	}
+	return json.NewArray(vals)
+}
+
+func (g *geometry) geoJSONCoordinates(digits int64) *json.Value {
+	switch g.typ {
+	case geometryPoint:
+		p := g.points[0]
+		return json.NewArray([]*json.Value{geoJSONNumber(p.x, digits), geoJSONNumber(p.y, digits)})
+	case geometryLineString:
+		return geoJSONPoints(g.points, digits)
+	case geometryPolygon:
+		rings := make([]*json.Value, 0, len(g.rings))
+		for _, r := range g.rings {
+			rings = append(rings, geoJSONPoints(r, digits))
+		}
+		return json.NewArray(rings)
+	default:
+		members := make([]*json.Value, 0, len(g.geoms))
+		for _, m := range g.geoms {
+			members = append(members, m.geoJSONCoordinates(digits))
+		}
+		return json.NewArray(members)
+	}
+}
+
+var geoJSONTypes = map[geometryType]string{
+	geometryPoint:           "Point",
+	geometryLineString:      "LineString",
+	geometryPolygon:         "Polygon",
+	geometryMultiPoint:      "MultiPoint",
+	geometryMultiLineString: "MultiLineString",
+	geometryMultiPolygon:    "MultiPolygon",
+	geometryCollection:      "GeometryCollection",
+}
+
+const (
+	geoJSONBoundingBox = 1 << iota
+	geoJSONShortCRS
+	geoJSONLongCRS
+)
+
+// geoJSON returns the GeoJSON representation of this geometry. The options are
+// the bitmask accepted by ST_AsGeoJSON: add a bounding box, and add the CRS of the
+// value as a short or long URN.
+func (g *geometry) geoJSON(digits int64, options int64, root bool) *json.Value {
+	var obj json.Object
+	obj.Add("type", json.NewString(geoJSONTypes[g.typ]))
+	if g.typ == geometryCollection {
+		members := make([]*json.Value, 0, len(g.geoms))
+		for _, m := range g.geoms {
+			members = append(members, m.geoJSON(digits, options&geoJSONBoundingBox, false))
+		}
+		obj.Add("geometries", json.NewArray(members))
+	} else {
+		obj.Add("coordinates", g.geoJSONCoordinates(digits))
+	}
+	if options&geoJSONBoundingBox != 0 {
+		if box, ok := g.envelope(); ok {
+			obj.Add("bbox", json.NewArray([]*json.Value{
+				geoJSONNumber(box.min.x, digits), geoJSONNumber(box.min.y, digits),
+				geoJSONNumber(box.max.x, digits), geoJSONNumber(box.max.y, digits),
+			}))
+		}
+	}
+	if root && g.srid != 0 && options&(geoJSONShortCRS|geoJSONLongCRS) != 0 {
+		name := fmt.Sprintf("EPSG:%d", g.srid)
+		if options&geoJSONLongCRS != 0 {
+			name = fmt.Sprintf("urn:ogc:def:crs:EPSG::%d", g.srid)
+		}
+		var props, crs json.Object
+		props.Add("name", json.NewString(name))
+		crs.Add("type", json.NewString("name"))
+		crs.Add("properties", json.NewObject(props))
+		obj.Add("crs", json.NewObject(crs))
+	}
+	return json.NewObject(obj)
+}
+
+type errGeoJSON string
+
+func (err errGeoJSON) Error() string {
+	return fmt.Sprintf("Invalid GeoJSON data provided to function st_geomfromgeojson: %s", string(err))
+}
+
+// geoJSONParser converts GeoJSON documents into geometries. Coordinates with more
+// than two dimensions are rejected unless stripDimensions is set.
+type geoJSONParser struct {
+	stripDimensions bool
+	srid            uint32
+	hasCRS          bool
+}
+
+func (p *geoJSONParser) member(obj *json.Object, key string, t json.Type) (*json.Value, error) {
+	v := obj.Get(key)
+	if v == nil {
+		return nil, errGeoJSON(fmt.Sprintf("Missing required member '%s'", key))
+	}
+	if v.Type() != t {
+		return nil, errGeoJSON(fmt.Sprintf("Member '%s' must be of type '%s'", key, geoJSONTypeName(t)))
+	}
+	return v, nil
+}
+
+func geoJSONTypeName(t json.Type) string {
+	switch t {
+	case json.TypeObject:
+		return "object"
+	case json.TypeArray:
+		return "array"
+	case json.TypeString:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+func (p *geoJSONParser) position(v *json.Value) (geoPoint, error) {
+	coords, ok := v.Array()
+	if !ok || len(coords) < 2 {
+		return geoPoint{}, errGeoJSON("Each position must be an array of at least two numbers")
+	}
+	if len(coords) > 2 && !p.stripDimensions {
+		return geoPoint{}, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Unsupported number of coordinate dimensions in function st_geomfromgeojson: Found %d in coordinates, but only 2 are supported.", len(coords))
+	}
+	var xy [2]float64
+	for i := range xy {
+		if coords[i].Type() != json.TypeNumber {
+			return geoPoint{}, errGeoJSON("Each position must be an array of at least two numbers")
+		}
+		xy[i], _ = coords[i].Float64()
+	}
+	return geoPoint{x: xy[0], y: xy[1]}, nil
+}
+
+func (p *geoJSONParser) positions(v *json.Value) ([]geoPoint, error) {
+	arr, ok := v.Array()
+	if !ok {
+		return nil, errGeoJSON("Member 'coordinates' must be of type 'array'")
+	}
+	points := make([]geoPoint, 0, len(arr))
+	for _, pos := range arr {
+		pt, err := p.position(pos)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+func (p *geoJSONParser) coordinates(t geometryType, v *json.Value) (*geometry, error) {
+	g := &geometry{typ: t}
+	var err error
+	switch t {
+	case geometryPoint:
+		var pt geoPoint
+		pt, err = p.position(v)
+		g.points = []geoPoint{pt}
+	case geometryLineString:
+		g.points, err = p.positions(v)
+	case geometryPolygon:
+		rings, ok := v.Array()
+		if !ok {
+			return nil, errGeoJSON("Member 'coordinates' must be of type 'array'")
+		}
+		for _, r := range rings {
+			var ring []geoPoint
+			if ring, err = p.positions(r); err != nil {
+				return nil, err
+			}
+			g.rings = append(g.rings, ring)
+		}
+	default:
+		members, ok := v.Array()
+		if !ok {
+			return nil, errGeoJSON("Member 'coordinates' must be of type 'array'")
+		}
+		for _, m := range members {
+			var member *geometry
+			if member, err = p.coordinates(t.member(), m); err != nil {
+				return nil, err
+			}
+			g.geoms = append(g.geoms, member)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !g.valid() {
+		return nil, errGISData("st_geomfromgeojson")
+	}
+	return g, nil
+}
+
+func (p *geoJSONParser) parseCRS(obj *json.Object) error {
+	crs := obj.Get("crs")
+	if crs == nil || crs.Type() == json.TypeNull {
+		return nil
+	}
+	if crs.Type() != json.TypeObject {
+		return errGeoJSON("Member 'crs' must be of type 'object'")
+	}
+	crsObj, _ := crs.Object()
+	props, err := p.member(crsObj, "properties", json.TypeObject)
+	if err != nil {
+		return err
+	}
+	propsObj, _ := props.Object()
+	name, err := p.member(propsObj, "name", json.TypeString)
+	if err != nil {
+		return err
+	}
+	raw, _ := name.StringBytes()
+	switch {
+	case bytes.Equal(raw, []byte("urn:ogc:def:crs:OGC:1.3:CRS84")):
+		p.srid = 4326
+	case bytes.HasPrefix(raw, []byte("EPSG:")):
+		srid, err := strconv.ParseUint(string(raw[len("EPSG:"):]), 10, 32)
+		if err != nil {
+			return errGeoJSON("Unsupported CRS name")
+		}
+		p.srid = uint32(srid)
+	case bytes.HasPrefix(raw, []byte("urn:ogc:def:crs:EPSG::")):
+		srid, err := strconv.ParseUint(string(raw[len("urn:ogc:def:crs:EPSG::"):]), 10, 32)
+		if err != nil {
+			return errGeoJSON("Unsupported CRS name")
+		}
+		p.srid = uint32(srid)
+	default:
+		return errGeoJSON("Unsupported CRS name")
+	}
+	p.hasCRS = true
+	return nil
+}
+
+// parse converts a GeoJSON geometry, Feature or FeatureCollection. It returns a nil
+// geometry for a Feature without a geometry.
+func (p *geoJSONParser) parse(v *json.Value, root bool) (*geometry, error) {
+	obj, ok := v.Object()
+	if !ok {
+		return nil, errGeoJSON("The GeoJSON value must be an object")
+	}
+	typ, err := p.member(obj, "type", json.TypeString)
+	if err != nil {
+		return nil, err
+	}
+	if root {
+		if err := p.parseCRS(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	name, _ := typ.StringBytes()
+	switch string(name) {
+	case "Feature":
+		geom := obj.Get("geometry")
+		if geom == nil {
+			return nil, errGeoJSON("Missing required member 'geometry'")
+		}
+		if geom.Type() == json.TypeNull {
+			return nil, nil
+		}
+		return p.parse(geom, false)
+	case "FeatureCollection":
+		features, err := p.member(obj, "features", json.TypeArray)
+		if err != nil {
+			return nil, err
+		}
+		arr, _ := features.Array()
+		g := &geometry{typ: geometryCollection, geoms: []*geometry{}}
+		for _, f := range arr {
+			m, err := p.parse(f, false)
+			if err != nil {
+				return nil, err
+			}
+			if m != nil {
+				g.geoms = append(g.geoms, m)
+			}
+		}
+		return g, nil
+	case "GeometryCollection":
+		geoms, err := p.member(obj, "geometries", json.TypeArray)
+		if err != nil {
+			return nil, err
+		}
+		arr, _ := geoms.Array()
+		g := &geometry{typ: geometryCollection, geoms: []*geometry{}}
+		for _, m := range arr {
+			member, err := p.parse(m, false)
+			if err != nil {
+				return nil, err
+			}
+			if member == nil {
+				return nil, errGeoJSON("Member 'geometries' must only contain geometries")
+			}
+			g.geoms = append(g.geoms, member)
+		}
+		return g, nil
+	}
+
+	for t, n := range geoJSONTypes {
+		if n == string(name) && t != geometryCollection {
+			coords, err := p.member(obj, "coordinates", json.TypeArray)
+			if err != nil {
+				return nil, err
+			}
+			return p.coordinates(t, coords)
+		}
+	}
+	return nil, errGeoJSON(fmt.Sprintf("Unknown GeoJSON type '%s'", name))
+}
@@ -80,7 +80,11 @@ func (c *ConvertExpr) eval(env *ExpressionEnv) (eval, error) {
 	if e == nil {
 		return nil, nil
 	}
+	return c.convert(env, e)
+}
 
+// convert converts the non-NULL value e to the type of the conversion
+func (c *ConvertExpr) convert(env *ExpressionEnv, e eval) (eval, error) {
 	switch c.Type {
 	case "BINARY":
 		b := evalToBinary(e)
@@ -163,6 +163,7 @@ func NewExpressionEnv(ctx context.Context, bindVars map[string]*querypb.BindVari
 const (
 	sqlModeParsed = 1 << iota
 	sqlModeNoZeroDate
+	sqlModeNoZeroInDate
 )
 
 type SQLMode uint32
@@ -175,11 +176,22 @@ func (mode SQLMode) AllowZeroDate() bool {
 	return (mode & sqlModeNoZeroDate) == 0
 }
 
+func (mode SQLMode) AllowZeroInDate() bool {
+	if mode == 0 {
+		// default: do not allow zero-in-date if the sqlmode is not set
+		return false
+	}
+	return (mode & sqlModeNoZeroInDate) == 0
+}
+
 func ParseSQLMode(sqlmode string) SQLMode {
 	var mode SQLMode
 	if strings.Contains(sqlmode, "NO_ZERO_DATE") {
 		mode |= sqlModeNoZeroDate
 	}
+	if strings.Contains(sqlmode, "NO_ZERO_IN_DATE") {
+		mode |= sqlModeNoZeroInDate
+	}
 	mode |= sqlModeParsed
 	return mode
 }
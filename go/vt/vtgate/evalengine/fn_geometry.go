@@ -0,0 +1,1781 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+type (
+	// builtinGeometry is a spatial function. All spatial functions return NULL
+	// when any of their arguments is NULL, so they share a single implementation
+	// for the AST evaluator and the virtual machine.
+	builtinGeometry struct {
+		CallExpr
+		fn      *gisFunction
+		collate collations.ID
+	}
+
+	gisFunction struct {
+		minArgs, maxArgs int
+		// tt is the type returned by the function; setters return a geometry
+		// instead when they are called with their maximum number of arguments
+		tt     sqltypes.Type
+		setter bool
+		call   func(call *builtinGeometry, args []eval) (eval, error)
+	}
+)
+
+var _ IR = (*builtinGeometry)(nil)
+
+var gisFunctions = map[string]*gisFunction{
+	"point":              {2, 2, sqltypes.Geometry, false, gisPoint},
+	"linestring":         {0, -1, sqltypes.Geometry, false, gisCollect(geometryLineString, geometryPoint)},
+	"polygon":            {0, -1, sqltypes.Geometry, false, gisCollect(geometryPolygon, geometryLineString)},
+	"multipoint":         {0, -1, sqltypes.Geometry, false, gisCollect(geometryMultiPoint, geometryPoint)},
+	"multilinestring":    {0, -1, sqltypes.Geometry, false, gisCollect(geometryMultiLineString, geometryLineString)},
+	"multipolygon":       {0, -1, sqltypes.Geometry, false, gisCollect(geometryMultiPolygon, geometryPolygon)},
+	"geometrycollection": {0, -1, sqltypes.Geometry, false, gisCollect(geometryCollection, 0)},
+	"geomcollection":     {0, -1, sqltypes.Geometry, false, gisCollect(geometryCollection, 0)},
+
+	"st_geometryfromtext":           {1, 3, sqltypes.Geometry, false, gisFromText(0)},
+	"st_geometrycollectionfromtext": {1, 3, sqltypes.Geometry, false, gisFromText(geometryCollection)},
+	"st_pointfromtext":              {1, 3, sqltypes.Geometry, false, gisFromText(geometryPoint)},
+	"st_linestringfromtext":         {1, 3, sqltypes.Geometry, false, gisFromText(geometryLineString)},
+	"st_polygonfromtext":            {1, 3, sqltypes.Geometry, false, gisFromText(geometryPolygon)},
+	"st_multipointfromtext":         {1, 3, sqltypes.Geometry, false, gisFromText(geometryMultiPoint)},
+	"st_multilinestringfromtext":    {1, 3, sqltypes.Geometry, false, gisFromText(geometryMultiLineString)},
+	"st_multipolygonfromtext":       {1, 3, sqltypes.Geometry, false, gisFromText(geometryMultiPolygon)},
+	"st_geometryfromwkb":            {1, 3, sqltypes.Geometry, false, gisFromWKB(0)},
+	"st_geometrycollectionfromwkb":  {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryCollection)},
+	"st_pointfromwkb":               {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryPoint)},
+	"st_linestringfromwkb":          {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryLineString)},
+	"st_polygonfromwkb":             {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryPolygon)},
+	"st_multipointfromwkb":          {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryMultiPoint)},
+	"st_multilinestringfromwkb":     {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryMultiLineString)},
+	"st_multipolygonfromwkb":        {1, 3, sqltypes.Geometry, false, gisFromWKB(geometryMultiPolygon)},
+	"st_astext":                     {1, 2, sqltypes.Text, false, gisAsText},
+	"st_asbinary":                   {1, 2, sqltypes.Blob, false, gisAsBinary},
+	"st_asgeojson":                  {1, 3, sqltypes.TypeJSON, false, gisAsGeoJSON},
+	"st_geomfromgeojson":            {1, 3, sqltypes.Geometry, false, gisFromGeoJSON},
+
+	"st_x":                {1, 2, sqltypes.Float64, true, gisCoordinate(false, false)},
+	"st_y":                {1, 2, sqltypes.Float64, true, gisCoordinate(true, false)},
+	"st_latitude":         {1, 2, sqltypes.Float64, true, gisCoordinate(false, true)},
+	"st_longitude":        {1, 2, sqltypes.Float64, true, gisCoordinate(true, true)},
+	"st_srid":             {1, 2, sqltypes.Int64, true, gisSRID},
+	"st_geometrytype":     {1, 1, sqltypes.VarChar, false, gisGeometryType},
+	"st_dimension":        {1, 1, sqltypes.Int64, false, gisDimension},
+	"st_isempty":          {1, 1, sqltypes.Int64, false, gisIsEmpty},
+	"st_envelope":         {1, 1, sqltypes.Geometry, false, gisEnvelope},
+	"st_numpoints":        {1, 1, sqltypes.Int64, false, gisNumPoints},
+	"st_startpoint":       {1, 1, sqltypes.Geometry, false, gisStartPoint},
+	"st_endpoint":         {1, 1, sqltypes.Geometry, false, gisEndPoint},
+	"st_pointn":           {2, 2, sqltypes.Geometry, false, gisPointN},
+	"st_isclosed":         {1, 1, sqltypes.Int64, false, gisIsClosed},
+	"st_length":           {1, 1, sqltypes.Float64, false, gisLength},
+	"st_area":             {1, 1, sqltypes.Float64, false, gisArea},
+	"st_centroid":         {1, 1, sqltypes.Geometry, false, gisCentroid},
+	"st_exteriorring":     {1, 1, sqltypes.Geometry, false, gisExteriorRing},
+	"st_interiorringn":    {2, 2, sqltypes.Geometry, false, gisInteriorRingN},
+	"st_numinteriorrings": {1, 1, sqltypes.Int64, false, gisNumInteriorRings},
+	"st_numinteriorring":  {1, 1, sqltypes.Int64, false, gisNumInteriorRings},
+	"st_numgeometries":    {1, 1, sqltypes.Int64, false, gisNumGeometries},
+	"st_geometryn":        {2, 2, sqltypes.Geometry, false, gisGeometryN},
+
+	"st_distance":        {2, 2, sqltypes.Float64, false, gisDistance},
+	"st_distance_sphere": {2, 3, sqltypes.Float64, false, gisDistanceSphere},
+	"st_intersects":      {2, 2, sqltypes.Int64, false, gisRelation(gisIntersects)},
+	"st_disjoint":        {2, 2, sqltypes.Int64, false, gisRelation(gisDisjoint)},
+	"st_contains":        {2, 2, sqltypes.Int64, false, gisRelation(gisContains)},
+	"st_within":          {2, 2, sqltypes.Int64, false, gisRelation(gisWithin)},
+	"st_equals":          {2, 2, sqltypes.Int64, false, gisRelation(gisEquals)},
+	"st_touches":         {2, 2, sqltypes.Int64, false, gisRelation(gisTouches)},
+	"st_overlaps":        {2, 2, sqltypes.Int64, false, gisRelation(gisOverlaps)},
+	"st_crosses":         {2, 2, sqltypes.Int64, false, gisRelation(gisCrosses)},
+	"mbrintersects":      {2, 2, sqltypes.Int64, false, gisRelation(mbrIntersects)},
+	"mbrdisjoint":        {2, 2, sqltypes.Int64, false, gisRelation(mbrDisjoint)},
+	"mbrcontains":        {2, 2, sqltypes.Int64, false, gisRelation(mbrContains)},
+	"mbrwithin":          {2, 2, sqltypes.Int64, false, gisRelation(mbrWithin)},
+	"mbrcovers":          {2, 2, sqltypes.Int64, false, gisRelation(mbrCovers)},
+	"mbrcoveredby":       {2, 2, sqltypes.Int64, false, gisRelation(mbrCoveredBy)},
+	"mbrequals":          {2, 2, sqltypes.Int64, false, gisRelation(mbrEquals)},
+}
+
+func newBuiltinGeometry(method string, args []IR, collate collations.ID) (IR, error) {
+	method = strings.ToLower(method)
+	fn, ok := gisFunctions[method]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "spatial function %s is not supported", method)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, argError(method)
+	}
+	return &builtinGeometry{
+		CallExpr: CallExpr{Arguments: args, Method: method},
+		fn:       fn,
+		collate:  collate,
+	}, nil
+}
+
+func (call *builtinGeometry) returnType() sqltypes.Type {
+	if call.fn.setter && len(call.Arguments) == call.fn.maxArgs {
+		return sqltypes.Geometry
+	}
+	return call.fn.tt
+}
+
+func (call *builtinGeometry) returnCollation() collations.TypedCollation {
+	switch tt := call.returnType(); tt {
+	case sqltypes.Geometry, sqltypes.Blob:
+		return collationBinary
+	default:
+		return typedCoercionCollation(tt, call.collate)
+	}
+}
+
+func (call *builtinGeometry) eval(env *ExpressionEnv) (eval, error) {
+	args := make([]eval, 0, len(call.Arguments))
+	for _, arg := range call.Arguments {
+		e, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return nil, nil
+		}
+		args = append(args, e)
+	}
+	return call.fn.call(call, args)
+}
+
+func (call *builtinGeometry) compile(c *compiler) (ctype, error) {
+	skips := make([]*jump, 0, len(call.Arguments))
+	for i, arg := range call.Arguments {
+		a, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		skips = append(skips, c.compileNullCheckArg(a, i))
+	}
+
+	c.asm.Fn_GIS(call)
+	c.asm.jumpDestination(skips...)
+
+	return ctype{Type: call.returnType(), Flag: flagNullable, Col: call.returnCollation()}, nil
+}
+
+func errUnexpectedGeometry(fn string, expected string, g *geometry) error {
+	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%s value is a geometry of unexpected type %s in %s.", expected, g.typ, fn)
+}
+
+func errDifferentSRIDs(fn string, a, b *geometry) error {
+	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Binary geometry function %s given two geometries of different srids: %d and %d, which should have been identical.", fn, a.srid, b.srid)
+}
+
+func errGeographic(fn string, geoms ...*geometry) error {
+	types := make([]string, 0, len(geoms))
+	for _, g := range geoms {
+		types = append(types, g.typ.String())
+	}
+	return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s(%s) has not been implemented for geographic spatial reference systems.", fn, strings.Join(types, ", "))
+}
+
+func errProjected(fn string, a, b *geometry) error {
+	return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s(%s, %s) has not been implemented for projected spatial reference systems.", fn, a.typ, b.typ)
+}
+
+func errUnsupportedGeometries(fn string) error {
+	return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Calling geometry function %s with unsupported types of arguments.", fn)
+}
+
+// cartesianArg decodes a geometry argument for a function that can only be
+// evaluated in a Cartesian spatial reference system.
+func cartesianArg(fn string, e eval) (*geometry, error) {
+	g, err := geometryArg(fn, e)
+	if err != nil {
+		return nil, err
+	}
+	geographic, err := spatialReference(g.srid)
+	if err != nil {
+		return nil, err
+	}
+	if geographic {
+		return nil, errGeographic(fn, g)
+	}
+	return g, nil
+}
+
+func sridArg(fn string, e eval) (uint32, error) {
+	srid := evalToInt64(e).i
+	if srid < 0 || srid > math.MaxUint32 {
+		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "SRID value is out of range in '%s'", fn)
+	}
+	return uint32(srid), nil
+}
+
+// axisOrderArg parses the options string of the WKT and WKB functions, and returns
+// whether the coordinates of a geographic value are in latitude-longitude order.
+func axisOrderArg(fn string, e eval) (latLong bool, err error) {
+	latLong = true
+	if e == nil {
+		return
+	}
+	for _, opt := range strings.Split(evalToBinary(e).string(), ",") {
+		if strings.TrimSpace(opt) == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(opt, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if key != "axis-order" {
+			return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid option key '%s' in function %s.", key, fn)
+		}
+		switch value {
+		case "lat-long", "srid-defined":
+			latLong = true
+		case "long-lat":
+			latLong = false
+		default:
+			return false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid value '%s' for option '%s' in function %s.", value, key, fn)
+		}
+	}
+	return
+}
+
+func optionalArg(args []eval, i int) eval {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+// newGeometryFromInput finishes parsing a WKT or WKB value: it checks its type and
+// sets its spatial reference system, converting the coordinates of geographic
+// values into longitude-latitude order.
+func newGeometryFromInput(fn string, g *geometry, required geometryType, args []eval) (eval, error) {
+	if required != 0 && g.typ != required {
+		return nil, errGISData(fn)
+	}
+	var srid uint32
+	if len(args) > 1 {
+		var err error
+		if srid, err = sridArg(fn, args[1]); err != nil {
+			return nil, err
+		}
+	}
+	geographic, err := spatialReference(srid)
+	if err != nil {
+		return nil, err
+	}
+	latLong, err := axisOrderArg(fn, optionalArg(args, 2))
+	if err != nil {
+		return nil, err
+	}
+	if geographic {
+		if latLong {
+			g = g.swapped()
+		}
+		if err := checkGeographic(fn, g); err != nil {
+			return nil, err
+		}
+	}
+	g.setSRID(srid)
+	return newEvalGeometry(g), nil
+}
+
+func gisFromText(required geometryType) func(call *builtinGeometry, args []eval) (eval, error) {
+	return func(call *builtinGeometry, args []eval) (eval, error) {
+		g, ok := parseWKT(evalToBinary(args[0]).bytes)
+		if !ok {
+			return nil, errGISData(call.Method)
+		}
+		return newGeometryFromInput(call.Method, g, required, args)
+	}
+}
+
+func gisFromWKB(required geometryType) func(call *builtinGeometry, args []eval) (eval, error) {
+	return func(call *builtinGeometry, args []eval) (eval, error) {
+		g, ok := decodeWKB(evalToBinary(args[0]).bytes)
+		if !ok {
+			return nil, errGISData(call.Method)
+		}
+		return newGeometryFromInput(call.Method, g, required, args)
+	}
+}
+
+// outputGeometry returns the geometry that must be formatted by ST_AsText or
+// ST_AsBinary, with the coordinates of geographic values in the requested order.
+func outputGeometry(fn string, args []eval) (*geometry, error) {
+	g, err := geometryArg(fn, args[0])
+	if err != nil {
+		return nil, err
+	}
+	geographic, err := spatialReference(g.srid)
+	if err != nil {
+		return nil, err
+	}
+	latLong, err := axisOrderArg(fn, optionalArg(args, 1))
+	if err != nil {
+		return nil, err
+	}
+	if geographic && latLong {
+		g = g.swapped()
+	}
+	return g, nil
+}
+
+func gisAsText(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := outputGeometry(call.Method, args)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalRaw(sqltypes.Text, g.appendWKT(nil), call.returnCollation()), nil
+}
+
+func gisAsBinary(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := outputGeometry(call.Method, args)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalRaw(sqltypes.Blob, g.appendWKB(nil), collationBinary), nil
+}
+
+func gisAsGeoJSON(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	digits := int64(math.MaxInt32)
+	if len(args) > 1 {
+		digits = evalToInt64(args[1]).i
+		if digits < 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Incorrect max decimal digits value: '%d' for function %s", digits, call.Method)
+		}
+	}
+	var options int64
+	if len(args) > 2 {
+		options = evalToInt64(args[2]).i
+		if options < 0 || options > geoJSONBoundingBox|geoJSONShortCRS|geoJSONLongCRS {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Incorrect options value: '%d' for function %s", options, call.Method)
+		}
+	}
+	return g.geoJSON(digits, options, true), nil
+}
+
+func gisFromGeoJSON(call *builtinGeometry, args []eval) (eval, error) {
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	p := geoJSONParser{srid: 4326}
+	if len(args) > 1 {
+		switch options := evalToInt64(args[1]).i; options {
+		case 1:
+		case 2, 3, 4:
+			p.stripDimensions = true
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Incorrect options value: '%d' for function %s", options, call.Method)
+		}
+	}
+	g, err := p.parse(doc, true)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	if len(args) > 2 {
+		if p.srid, err = sridArg(call.Method, args[2]); err != nil {
+			return nil, err
+		}
+	}
+	geographic, err := spatialReference(p.srid)
+	if err != nil {
+		return nil, err
+	}
+	if geographic {
+		if err := checkGeographic(call.Method, g); err != nil {
+			return nil, err
+		}
+	}
+	g.setSRID(p.srid)
+	return newEvalGeometry(g), nil
+}
+
+func gisPoint(_ *builtinGeometry, args []eval) (eval, error) {
+	x, _ := evalToFloat(args[0])
+	y, _ := evalToFloat(args[1])
+	return newEvalGeometry(&geometry{typ: geometryPoint, points: []geoPoint{{x: x.f, y: y.f}}}), nil
+}
+
+// gisCollect returns the constructor of a geometry built out of other geometries:
+// a linestring out of points, a polygon out of linestrings, or a multi-geometry.
+func gisCollect(t, member geometryType) func(call *builtinGeometry, args []eval) (eval, error) {
+	return func(call *builtinGeometry, args []eval) (eval, error) {
+		g := &geometry{typ: t}
+		for i, arg := range args {
+			m, err := geometryArg(call.Method, arg)
+			if err != nil {
+				return nil, err
+			}
+			if member != 0 && m.typ != member {
+				return nil, errGISData(call.Method)
+			}
+			if i == 0 {
+				g.srid = m.srid
+			} else if m.srid != g.srid {
+				return nil, errDifferentSRIDs(call.Method, g, m)
+			}
+			switch t {
+			case geometryLineString:
+				g.points = append(g.points, m.points[0])
+			case geometryPolygon:
+				g.rings = append(g.rings, m.points)
+			default:
+				g.geoms = append(g.geoms, m)
+			}
+		}
+		if t == geometryCollection && g.geoms == nil {
+			g.geoms = []*geometry{}
+		}
+		if !g.valid() {
+			return nil, errGISData(call.Method)
+		}
+		return newEvalGeometry(g), nil
+	}
+}
+
+// gisCoordinate returns the getter and setter of a point coordinate. ST_X and ST_Y
+// return the first and second coordinates of a point in the axis order of its
+// spatial reference system, which for geographic values is latitude-longitude;
+// ST_Latitude and ST_Longitude are only defined for geographic values.
+func gisCoordinate(second, geographicOnly bool) func(call *builtinGeometry, args []eval) (eval, error) {
+	return func(call *builtinGeometry, args []eval) (eval, error) {
+		g, err := geometryArg(call.Method, args[0])
+		if err != nil {
+			return nil, err
+		}
+		if g.typ != geometryPoint {
+			return nil, errUnexpectedGeometry(call.Method, "POINT", g)
+		}
+		geographic, err := spatialReference(g.srid)
+		if err != nil {
+			return nil, err
+		}
+		if geographicOnly && !geographic {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Function %s is only defined for geographic spatial reference systems, but one of its arguments is in SRID %d, which is not geographic.", call.Method, g.srid)
+		}
+
+		coord := &g.points[0].x
+		if second != geographic {
+			coord = &g.points[0].y
+		}
+		if len(args) == 1 {
+			return newEvalFloat(*coord), nil
+		}
+
+		f, _ := evalToFloat(args[1])
+		*coord = f.f
+		if geographic {
+			if err := checkGeographic(call.Method, g); err != nil {
+				return nil, err
+			}
+		}
+		return newEvalGeometry(g), nil
+	}
+}
+
+func gisSRID(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 1 {
+		return newEvalInt64(int64(g.srid)), nil
+	}
+	srid, err := sridArg(call.Method, args[1])
+	if err != nil {
+		return nil, err
+	}
+	geographic, err := spatialReference(srid)
+	if err != nil {
+		return nil, err
+	}
+	if geographic {
+		if err := checkGeographic(call.Method, g); err != nil {
+			return nil, err
+		}
+	}
+	g.setSRID(srid)
+	return newEvalGeometry(g), nil
+}
+
+func gisGeometryType(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalText([]byte(g.typ.String()), call.returnCollation()), nil
+}
+
+func gisDimension(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	dim := 0
+	g.each(func(m *geometry) {
+		dim = max(dim, m.typ.dimension())
+	})
+	return newEvalInt64(int64(dim)), nil
+}
+
+func gisIsEmpty(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(g.empty()), nil
+}
+
+func gisEnvelope(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := cartesianArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	box, ok := g.envelope()
+	if !ok {
+		return newEvalGeometry(&geometry{srid: g.srid, typ: geometryCollection}), nil
+	}
+	env := box.geometry()
+	env.srid = g.srid
+	return newEvalGeometry(env), nil
+}
+
+func lineStringArg(fn string, e eval) (*geometry, error) {
+	g, err := geometryArg(fn, e)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != geometryLineString {
+		return nil, errUnexpectedGeometry(fn, "LINESTRING", g)
+	}
+	return g, nil
+}
+
+func polygonArg(fn string, e eval) (*geometry, error) {
+	g, err := geometryArg(fn, e)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != geometryPolygon {
+		return nil, errUnexpectedGeometry(fn, "POLYGON", g)
+	}
+	return g, nil
+}
+
+func newEvalPoint(srid uint32, p geoPoint) *evalBytes {
+	return newEvalGeometry(&geometry{srid: srid, typ: geometryPoint, points: []geoPoint{p}})
+}
+
+func gisNumPoints(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := lineStringArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(int64(len(g.points))), nil
+}
+
+func gisStartPoint(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := lineStringArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalPoint(g.srid, g.points[0]), nil
+}
+
+func gisEndPoint(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := lineStringArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalPoint(g.srid, g.points[len(g.points)-1]), nil
+}
+
+func gisPointN(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := lineStringArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	n := evalToInt64(args[1]).i
+	if n < 1 || n > int64(len(g.points)) {
+		return nil, nil
+	}
+	return newEvalPoint(g.srid, g.points[n-1]), nil
+}
+
+func gisIsClosed(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := geometryArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != geometryLineString && g.typ != geometryMultiLineString {
+		return nil, errUnexpectedGeometry(call.Method, "LINESTRING/MULTILINESTRING", g)
+	}
+	closed := true
+	g.each(func(m *geometry) {
+		closed = closed && m.points[0] == m.points[len(m.points)-1]
+	})
+	return newEvalBool(closed), nil
+}
+
+func lineLength(points []geoPoint) (length float64) {
+	for i := 1; i < len(points); i++ {
+		length += distance(points[i-1], points[i])
+	}
+	return
+}
+
+func gisLength(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := cartesianArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != geometryLineString && g.typ != geometryMultiLineString {
+		return nil, errUnexpectedGeometry(call.Method, "LINESTRING/MULTILINESTRING", g)
+	}
+	var length float64
+	g.each(func(m *geometry) {
+		length += lineLength(m.points)
+	})
+	return newEvalFloat(length), nil
+}
+
+// ringArea returns the signed area of a ring: positive when its points are in
+// counter-clockwise order.
+func ringArea(ring []geoPoint) (area float64) {
+	for i := 1; i < len(ring); i++ {
+		area += ring[i-1].x*ring[i].y - ring[i].x*ring[i-1].y
+	}
+	return area / 2
+}
+
+func polygonArea(poly *geometry) float64 {
+	area := math.Abs(ringArea(poly.rings[0]))
+	for _, hole := range poly.rings[1:] {
+		area -= math.Abs(ringArea(hole))
+	}
+	return area
+}
+
+func gisArea(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := cartesianArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if g.typ != geometryPolygon && g.typ != geometryMultiPolygon {
+		return nil, errUnexpectedGeometry(call.Method, "POLYGON/MULTIPOLYGON", g)
+	}
+	var area float64
+	g.each(func(m *geometry) {
+		area += polygonArea(m)
+	})
+	return newEvalFloat(area), nil
+}
+
+// gisCentroid computes the centroid of the members of a geometry with the highest
+// dimension: the area-weighted centroid of its polygons, the length-weighted
+// centroid of its linestrings, or the mean of its points.
+func gisCentroid(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := cartesianArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	if g.empty() {
+		return nil, nil
+	}
+
+	dim := 0
+	g.each(func(m *geometry) {
+		dim = max(dim, m.typ.dimension())
+	})
+
+	// polygons are split in triangles, whose centroids are accumulated without
+	// dividing them by three to limit rounding errors
+	scale := 1.0
+	if dim == 2 {
+		scale = 3
+	}
+	var cx, cy, weight float64
+	add := func(x, y, w float64) {
+		cx += x * w
+		cy += y * w
+		weight += w
+	}
+	g.each(func(m *geometry) {
+		if m.typ.dimension() != dim {
+			return
+		}
+		switch m.typ {
+		case geometryPoint:
+			add(m.points[0].x, m.points[0].y, 1)
+		case geometryLineString:
+			for i := 1; i < len(m.points); i++ {
+				a, b := m.points[i-1], m.points[i]
+				add((a.x+b.x)/2, (a.y+b.y)/2, distance(a, b))
+			}
+		case geometryPolygon:
+			for i, ring := range m.rings {
+				sign := 1.0
+				if (ringArea(ring) < 0) != (i > 0) {
+					sign = -1
+				}
+				o := ring[0]
+				for j := 2; j < len(ring); j++ {
+					a, b := ring[j-1], ring[j]
+					cross := ((a.x-o.x)*(b.y-o.y) - (b.x-o.x)*(a.y-o.y)) * sign
+					add(o.x+a.x+b.x, o.y+a.y+b.y, cross/2)
+				}
+			}
+		}
+	})
+	if weight == 0 {
+		// a degenerate geometry, e.g. a linestring whose points are all equal
+		var p geoPoint
+		g.each(func(m *geometry) {
+			if m.points != nil {
+				p = m.points[0]
+			} else {
+				p = m.rings[0][0]
+			}
+		})
+		return newEvalPoint(g.srid, p), nil
+	}
+	return newEvalPoint(g.srid, geoPoint{x: cx / (weight * scale), y: cy / (weight * scale)}), nil
+}
+
+func gisExteriorRing(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := polygonArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalGeometry(&geometry{srid: g.srid, typ: geometryLineString, points: g.rings[0]}), nil
+}
+
+func gisInteriorRingN(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := polygonArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	n := evalToInt64(args[1]).i
+	if n < 1 || n >= int64(len(g.rings)) {
+		return nil, nil
+	}
+	return newEvalGeometry(&geometry{srid: g.srid, typ: geometryLineString, points: g.rings[n]}), nil
+}
+
+func gisNumInteriorRings(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := polygonArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(int64(len(g.rings) - 1)), nil
+}
+
+func collectionArg(fn string, e eval) (*geometry, error) {
+	g, err := geometryArg(fn, e)
+	if err != nil {
+		return nil, err
+	}
+	if g.typ < geometryMultiPoint {
+		return nil, errUnexpectedGeometry(fn, "GEOMCOLLECTION", g)
+	}
+	return g, nil
+}
+
+func gisNumGeometries(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := collectionArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(int64(len(g.geoms))), nil
+}
+
+func gisGeometryN(call *builtinGeometry, args []eval) (eval, error) {
+	g, err := collectionArg(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	n := evalToInt64(args[1]).i
+	if n < 1 || n > int64(len(g.geoms)) {
+		return nil, nil
+	}
+	return newEvalGeometry(g.geoms[n-1]), nil
+}
+
+// geometryPairArgs decodes the arguments of a function that relates two
+// geometries, which must be in the same spatial reference system.
+func geometryPairArgs(fn string, args []eval) (a, b *geometry, geographic bool, err error) {
+	a, err = geometryArg(fn, args[0])
+	if err != nil {
+		return nil, nil, false, err
+	}
+	b, err = geometryArg(fn, args[1])
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if a.srid != b.srid {
+		return nil, nil, false, errDifferentSRIDs(fn, a, b)
+	}
+	geographic, err = spatialReference(a.srid)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return a, b, geographic, nil
+}
+
+// binaryGeometryArgs decodes the arguments of a function that relates two
+// geometries, which must be in the same Cartesian spatial reference system.
+// It returns nil geometries if any of them is empty.
+func binaryGeometryArgs(fn string, args []eval) (*geometry, *geometry, error) {
+	a, b, geographic, err := geometryPairArgs(fn, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if geographic {
+		return nil, nil, errGeographic(fn, a, b)
+	}
+	if a.empty() || b.empty() {
+		return nil, nil, nil
+	}
+	return a, b, nil
+}
+
+func gisDistance(call *builtinGeometry, args []eval) (eval, error) {
+	a, b, geographic, err := geometryPairArgs(call.Method, args)
+	if err != nil {
+		return nil, err
+	}
+	if geographic {
+		// only the geodesic distance between points is known to vtgate
+		pa, okA := pointsOf(a)
+		pb, okB := pointsOf(b)
+		if !okA || !okB {
+			return nil, errGeographic(call.Method, a, b)
+		}
+		return newEvalFloat(minDistance(pa, pb, andoyerDistance)), nil
+	}
+	if a.empty() || b.empty() {
+		return nil, nil
+	}
+	if intersects(a, b) {
+		return newEvalFloat(0), nil
+	}
+	d := math.Inf(1)
+	for _, s1 := range segments(a) {
+		for _, s2 := range segments(b) {
+			d = min(d, segmentDistance(s1, s2))
+		}
+	}
+	return newEvalFloat(d), nil
+}
+
+// sphereRadius is the default radius of the sphere used by ST_Distance_Sphere, in meters.
+const sphereRadius = 6370986
+
+// gisDistanceSphere returns the distance between two points or multipoints on a
+// sphere. Cartesian coordinates are taken as a longitude and a latitude in degrees.
+func gisDistanceSphere(call *builtinGeometry, args []eval) (eval, error) {
+	a, b, geographic, err := geometryPairArgs(call.Method, args)
+	if err != nil {
+		return nil, err
+	}
+	if !geographic && a.srid != 0 {
+		return nil, errProjected(call.Method, a, b)
+	}
+	pa, okA := pointsOf(a)
+	pb, okB := pointsOf(b)
+	if !okA || !okB {
+		return nil, errUnsupportedGeometries(call.Method)
+	}
+	if !geographic {
+		if err := checkGeographic(call.Method, a); err != nil {
+			return nil, err
+		}
+		if err := checkGeographic(call.Method, b); err != nil {
+			return nil, err
+		}
+	}
+	radius := float64(sphereRadius)
+	if len(args) > 2 {
+		f, _ := evalToFloat(args[2])
+		if radius = f.f; radius <= 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid radius provided to function %s: Radius must be greater than zero.", call.Method)
+		}
+	}
+	return newEvalFloat(radius * minDistance(pa, pb, haversine)), nil
+}
+
+// pointsOf returns the points of a point or a multipoint, or false for any
+// other geometry.
+func pointsOf(g *geometry) ([]geoPoint, bool) {
+	switch g.typ {
+	case geometryPoint:
+		return g.points, true
+	case geometryMultiPoint:
+		points := make([]geoPoint, 0, len(g.geoms))
+		for _, m := range g.geoms {
+			points = append(points, m.points[0])
+		}
+		return points, len(points) > 0
+	default:
+		return nil, false
+	}
+}
+
+func minDistance(a, b []geoPoint, distance func(p, q geoPoint) float64) float64 {
+	d := math.Inf(1)
+	for _, p := range a {
+		for _, q := range b {
+			d = min(d, distance(p, q))
+		}
+	}
+	return d
+}
+
+func radians(p geoPoint) (lon, lat float64) {
+	return p.x * math.Pi / 180, p.y * math.Pi / 180
+}
+
+// haversine returns the central angle between two points of a sphere, given as
+// a longitude and a latitude in degrees.
+func haversine(p, q geoPoint) float64 {
+	hav := func(x float64) float64 {
+		s := math.Sin(x / 2)
+		return s * s
+	}
+	lon1, lat1 := radians(p)
+	lon2, lat2 := radians(q)
+	h := hav(lat2-lat1) + math.Cos(lat1)*math.Cos(lat2)*hav(lon2-lon1)
+	return 2 * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// The WGS 84 ellipsoid.
+const (
+	wgs84SemiMajorAxis = 6378137
+	wgs84Flattening    = 1 / 298.257223563
+)
+
+// andoyerDistance returns the distance in meters between two points of the WGS 84
+// ellipsoid, given as a longitude and a latitude in degrees, using Andoyer's first
+// order approximation of the geodesic.
+func andoyerDistance(p, q geoPoint) float64 {
+	if p == q {
+		return 0
+	}
+	lon1, lat1 := radians(p)
+	lon2, lat2 := radians(q)
+	sinLat1, cosLat1 := math.Sincos(lat1)
+	sinLat2, cosLat2 := math.Sincos(lat2)
+
+	cosD := max(-1, min(1, sinLat1*sinLat2+cosLat1*cosLat2*math.Cos(lon2-lon1)))
+	d := math.Acos(cosD)
+	sinD := math.Sin(d)
+
+	// the corrections vanish for points which are very close or antipodal
+	var h, g float64
+	if cosD < 1 {
+		h = (d + 3*sinD) / (1 - cosD)
+	}
+	if cosD > -1 {
+		g = (d - 3*sinD) / (1 + cosD)
+	}
+	k := (sinLat1 - sinLat2) * (sinLat1 - sinLat2)
+	l := (sinLat1 + sinLat2) * (sinLat1 + sinLat2)
+	return wgs84SemiMajorAxis * (d - wgs84Flattening/4*(h*k+g*l))
+}
+
+type gisPredicate func(fn string, a, b *geometry) (bool, error)
+
+func gisRelation(pred gisPredicate) func(call *builtinGeometry, args []eval) (eval, error) {
+	return func(call *builtinGeometry, args []eval) (eval, error) {
+		a, b, err := binaryGeometryArgs(call.Method, args)
+		if err != nil || a == nil {
+			return nil, err
+		}
+		ok, err := pred(call.Method, a, b)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalBool(ok), nil
+	}
+}
+
+func gisIntersects(_ string, a, b *geometry) (bool, error) {
+	return intersects(a, b), nil
+}
+
+func gisDisjoint(_ string, a, b *geometry) (bool, error) {
+	return !intersects(a, b), nil
+}
+
+func gisContains(fn string, a, b *geometry) (bool, error) {
+	return contains(fn, a, b)
+}
+
+func gisWithin(fn string, a, b *geometry) (bool, error) {
+	return contains(fn, b, a)
+}
+
+func gisEquals(fn string, a, b *geometry) (bool, error) {
+	ok, err := contains(fn, a, b)
+	if !ok || err != nil {
+		return false, err
+	}
+	return contains(fn, b, a)
+}
+
+func gisTouches(fn string, a, b *geometry) (bool, error) {
+	ra, rb, err := relateOperands(fn, a, b)
+	if err != nil {
+		return false, err
+	}
+	return interiorsMeet(ra, rb) < 0 && intersects(a, b), nil
+}
+
+// gisOverlaps returns whether two geometries of the same dimension share part of
+// their interiors with that dimension, while each of them has a part outside of
+// the other one.
+func gisOverlaps(fn string, a, b *geometry) (bool, error) {
+	ra, rb, err := relateOperands(fn, a, b)
+	if err != nil {
+		return false, err
+	}
+	if ra.dim != rb.dim {
+		return false, nil
+	}
+	return interiorsMeet(ra, rb) == ra.dim && interiorMeetsExterior(ra, rb) && interiorMeetsExterior(rb, ra), nil
+}
+
+// gisCrosses returns whether two linestrings cross each other at some points, or
+// whether a geometry runs both inside and outside of a geometry with a higher
+// dimension. Any other pair of geometries never crosses.
+func gisCrosses(fn string, a, b *geometry) (bool, error) {
+	ra, rb, err := relateOperands(fn, a, b)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case ra.dim == 1 && rb.dim == 1:
+		return interiorsMeet(ra, rb) == 0, nil
+	case ra.dim < rb.dim:
+		return interiorsMeet(ra, rb) >= 0 && interiorMeetsExterior(ra, rb), nil
+	default:
+		return false, nil
+	}
+}
+
+func mbrIntersects(_ string, a, b *geometry) (bool, error) {
+	ba, _ := a.envelope()
+	bb, _ := b.envelope()
+	return ba.min.x <= bb.max.x && bb.min.x <= ba.max.x && ba.min.y <= bb.max.y && bb.min.y <= ba.max.y, nil
+}
+
+func mbrDisjoint(fn string, a, b *geometry) (bool, error) {
+	ok, _ := mbrIntersects(fn, a, b)
+	return !ok, nil
+}
+
+func mbrCovers(_ string, a, b *geometry) (bool, error) {
+	ba, _ := a.envelope()
+	bb, _ := b.envelope()
+	return ba.min.x <= bb.min.x && bb.max.x <= ba.max.x && ba.min.y <= bb.min.y && bb.max.y <= ba.max.y, nil
+}
+
+func mbrCoveredBy(fn string, a, b *geometry) (bool, error) {
+	return mbrCovers(fn, b, a)
+}
+
+// axisInteriorsMeet returns whether the interiors of two envelopes meet along
+// one axis. The interior of a degenerate envelope along an axis is its only
+// coordinate, while it is an open interval otherwise.
+func axisInteriorsMeet(amin, amax, bmin, bmax float64) bool {
+	switch {
+	case amin == amax && bmin == bmax:
+		return amin == bmin
+	case amin == amax:
+		return bmin < amin && amin < bmax
+	case bmin == bmax:
+		return amin < bmin && bmin < amax
+	default:
+		return amin < bmax && bmin < amax
+	}
+}
+
+func mbrContains(fn string, a, b *geometry) (bool, error) {
+	if ok, _ := mbrCovers(fn, a, b); !ok {
+		return false, nil
+	}
+	ba, _ := a.envelope()
+	bb, _ := b.envelope()
+	return axisInteriorsMeet(ba.min.x, ba.max.x, bb.min.x, bb.max.x) && axisInteriorsMeet(ba.min.y, ba.max.y, bb.min.y, bb.max.y), nil
+}
+
+func mbrWithin(fn string, a, b *geometry) (bool, error) {
+	return mbrContains(fn, b, a)
+}
+
+func mbrEquals(_ string, a, b *geometry) (bool, error) {
+	ba, _ := a.envelope()
+	bb, _ := b.envelope()
+	return ba == bb, nil
+}
+
+type geoBox struct {
+	min, max geoPoint
+}
+
+// envelope returns the minimum bounding rectangle of this geometry, or false if
+// the geometry is empty.
+func (g *geometry) envelope() (box geoBox, ok bool) {
+	box = geoBox{
+		min: geoPoint{x: math.Inf(1), y: math.Inf(1)},
+		max: geoPoint{x: math.Inf(-1), y: math.Inf(-1)},
+	}
+	add := func(points []geoPoint) {
+		for _, p := range points {
+			box.min.x = min(box.min.x, p.x)
+			box.min.y = min(box.min.y, p.y)
+			box.max.x = max(box.max.x, p.x)
+			box.max.y = max(box.max.y, p.y)
+			ok = true
+		}
+	}
+	g.each(func(m *geometry) {
+		add(m.points)
+		if len(m.rings) > 0 {
+			add(m.rings[0])
+		}
+	})
+	return
+}
+
+// geometry returns the envelope as a polygon, or as a point or a linestring when
+// the envelope is degenerate.
+func (box geoBox) geometry() *geometry {
+	switch {
+	case box.min == box.max:
+		return &geometry{typ: geometryPoint, points: []geoPoint{box.min}}
+	case box.min.x == box.max.x || box.min.y == box.max.y:
+		return &geometry{typ: geometryLineString, points: []geoPoint{box.min, box.max}}
+	default:
+		return &geometry{typ: geometryPolygon, rings: [][]geoPoint{{
+			box.min,
+			{x: box.max.x, y: box.min.y},
+			box.max,
+			{x: box.min.x, y: box.max.y},
+			box.min,
+		}}}
+	}
+}
+
+// geoEpsilon is the relative tolerance used to decide whether three points are
+// collinear, so that points computed along a segment are considered to lie on it.
+const geoEpsilon = 1e-12
+
+func distance(a, b geoPoint) float64 {
+	return math.Hypot(b.x-a.x, b.y-a.y)
+}
+
+// orientation returns 1 if c is to the left of the line going from a to b,
+// -1 if it is to the right and 0 if the three points are collinear.
+func orientation(a, b, c geoPoint) int {
+	cross := (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+	if math.Abs(cross) <= geoEpsilon*distance(a, b)*distance(a, c) {
+		return 0
+	}
+	if cross > 0 {
+		return 1
+	}
+	return -1
+}
+
+type geoSegment [2]geoPoint
+
+func (s geoSegment) inBox(p geoPoint) bool {
+	return min(s[0].x, s[1].x) <= p.x && p.x <= max(s[0].x, s[1].x) &&
+		min(s[0].y, s[1].y) <= p.y && p.y <= max(s[0].y, s[1].y)
+}
+
+func (s geoSegment) contains(p geoPoint) bool {
+	return orientation(s[0], s[1], p) == 0 && s.inBox(p)
+}
+
+func (s geoSegment) intersects(o geoSegment) bool {
+	o1 := orientation(s[0], s[1], o[0])
+	o2 := orientation(s[0], s[1], o[1])
+	o3 := orientation(o[0], o[1], s[0])
+	o4 := orientation(o[0], o[1], s[1])
+	if o1 != o2 && o3 != o4 && o1*o2 <= 0 && o3*o4 <= 0 {
+		return true
+	}
+	return s.contains(o[0]) || s.contains(o[1]) || o.contains(s[0]) || o.contains(s[1])
+}
+
+func (s geoSegment) distance(p geoPoint) float64 {
+	dx, dy := s[1].x-s[0].x, s[1].y-s[0].y
+	if dx == 0 && dy == 0 {
+		return distance(s[0], p)
+	}
+	t := ((p.x-s[0].x)*dx + (p.y-s[0].y)*dy) / (dx*dx + dy*dy)
+	t = max(0, min(1, t))
+	return distance(geoPoint{x: s[0].x + t*dx, y: s[0].y + t*dy}, p)
+}
+
+// crossing returns the point where two segments cross each other, if they do at
+// a single point which is not an endpoint of either of them.
+func (s geoSegment) crossing(o geoSegment) (geoPoint, bool) {
+	if orientation(s[0], s[1], o[0])*orientation(s[0], s[1], o[1]) >= 0 ||
+		orientation(o[0], o[1], s[0])*orientation(o[0], o[1], s[1]) >= 0 {
+		return geoPoint{}, false
+	}
+	dx, dy := s[1].x-s[0].x, s[1].y-s[0].y
+	ex, ey := o[1].x-o[0].x, o[1].y-o[0].y
+	t := ((o[0].x-s[0].x)*ey - (o[0].y-s[0].y)*ex) / (dx*ey - dy*ex)
+	return geoPoint{x: s[0].x + t*dx, y: s[0].y + t*dy}, true
+}
+
+func segmentDistance(a, b geoSegment) float64 {
+	if a.intersects(b) {
+		return 0
+	}
+	return min(a.distance(b[0]), a.distance(b[1]), b.distance(a[0]), b.distance(a[1]))
+}
+
+func pointSegments(points []geoPoint) []geoSegment {
+	if len(points) == 1 {
+		return []geoSegment{{points[0], points[0]}}
+	}
+	segs := make([]geoSegment, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		segs = append(segs, geoSegment{points[i-1], points[i]})
+	}
+	return segs
+}
+
+// segments returns the segments that make up a geometry: a point is a degenerate
+// segment, and polygons are made up of the segments of their rings.
+func segments(g *geometry) (segs []geoSegment) {
+	g.each(func(m *geometry) {
+		if m.points != nil {
+			segs = append(segs, pointSegments(m.points)...)
+		}
+		for _, r := range m.rings {
+			segs = append(segs, pointSegments(r)...)
+		}
+	})
+	return
+}
+
+const (
+	locExterior = iota
+	locBoundary
+	locInterior
+)
+
+func locateInRing(p geoPoint, ring []geoPoint) int {
+	inside := false
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if (geoSegment{a, b}).contains(p) {
+			return locBoundary
+		}
+		if (a.y > p.y) != (b.y > p.y) && p.x < (b.x-a.x)*(p.y-a.y)/(b.y-a.y)+a.x {
+			inside = !inside
+		}
+	}
+	if inside {
+		return locInterior
+	}
+	return locExterior
+}
+
+func locateInPolygon(p geoPoint, poly *geometry) int {
+	loc := locateInRing(p, poly.rings[0])
+	if loc != locInterior {
+		return loc
+	}
+	for _, hole := range poly.rings[1:] {
+		switch locateInRing(p, hole) {
+		case locBoundary:
+			return locBoundary
+		case locInterior:
+			return locExterior
+		}
+	}
+	return locInterior
+}
+
+func locate(p geoPoint, polys []*geometry) int {
+	loc := locExterior
+	for _, poly := range polys {
+		loc = max(loc, locateInPolygon(p, poly))
+	}
+	return loc
+}
+
+// components returns the points, linestrings and polygons of a geometry.
+func components(g *geometry) (points, lines, polys []*geometry) {
+	g.each(func(m *geometry) {
+		switch m.typ {
+		case geometryPoint:
+			points = append(points, m)
+		case geometryLineString:
+			lines = append(lines, m)
+		case geometryPolygon:
+			polys = append(polys, m)
+		}
+	})
+	return
+}
+
+func intersects(a, b *geometry) bool {
+	aPoints, aLines, aPolys := components(a)
+	bPoints, bLines, bPolys := components(b)
+
+	// any vertex of one of the geometries inside the polygons of the other
+	for _, p := range aPoints {
+		if locate(p.points[0], bPolys) != locExterior {
+			return true
+		}
+	}
+	for _, l := range aLines {
+		if locate(l.points[0], bPolys) != locExterior {
+			return true
+		}
+	}
+	for _, poly := range aPolys {
+		if locate(poly.rings[0][0], bPolys) != locExterior {
+			return true
+		}
+	}
+	for _, p := range bPoints {
+		if locate(p.points[0], aPolys) != locExterior {
+			return true
+		}
+	}
+	for _, l := range bLines {
+		if locate(l.points[0], aPolys) != locExterior {
+			return true
+		}
+	}
+	for _, poly := range bPolys {
+		if locate(poly.rings[0][0], aPolys) != locExterior {
+			return true
+		}
+	}
+
+	// otherwise, the geometries intersect if any of their segments do
+	for _, s1 := range segments(a) {
+		for _, s2 := range segments(b) {
+			if s1.intersects(s2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitSegment splits a segment at all the points where it meets the given
+// segments, and returns the points in between them, where the location of
+// the segment relative to a geometry does not change.
+func splitSegment(s geoSegment, by []geoSegment) []geoPoint {
+	ts := []float64{0, 1}
+	dx, dy := s[1].x-s[0].x, s[1].y-s[0].y
+	length := dx*dx + dy*dy
+	project := func(p geoPoint) {
+		if length > 0 && s.contains(p) {
+			ts = append(ts, ((p.x-s[0].x)*dx+(p.y-s[0].y)*dy)/length)
+		}
+	}
+	for _, o := range by {
+		ex, ey := o[1].x-o[0].x, o[1].y-o[0].y
+		denom := dx*ey - dy*ex
+		if denom != 0 {
+			t := ((o[0].x-s[0].x)*ey - (o[0].y-s[0].y)*ex) / denom
+			u := ((o[0].x-s[0].x)*dy - (o[0].y-s[0].y)*dx) / denom
+			if t > 0 && t < 1 && u >= 0 && u <= 1 {
+				ts = append(ts, t)
+			}
+		}
+		project(o[0])
+		project(o[1])
+	}
+	sort.Float64s(ts)
+
+	mids := make([]geoPoint, 0, len(ts))
+	for i := 1; i < len(ts); i++ {
+		if ts[i] == ts[i-1] {
+			continue
+		}
+		t := (ts[i-1] + ts[i]) / 2
+		mids = append(mids, geoPoint{x: s[0].x + t*dx, y: s[0].y + t*dy})
+	}
+	return mids
+}
+
+// linearBoundary returns the boundary of a set of linestrings: following the
+// mod-2 rule, the endpoints shared by an odd number of open linestrings.
+func linearBoundary(lines []*geometry) []geoPoint {
+	var ends []geoPoint
+	for _, l := range lines {
+		first, last := l.points[0], l.points[len(l.points)-1]
+		if first != last {
+			ends = append(ends, first, last)
+		}
+	}
+	var boundary []geoPoint
+	for _, p := range ends {
+		count := 0
+		for _, q := range ends {
+			if p == q {
+				count++
+			}
+		}
+		if count%2 == 1 {
+			boundary = append(boundary, p)
+		}
+	}
+	return boundary
+}
+
+// contains returns whether a contains b: no point of b lies in the exterior of a,
+// and at least one point of the interior of b lies in the interior of a.
+// Containment is supported when a is made up only of polygons or only of points,
+// and when a is made up of linestrings and b has no linestrings.
+func contains(fn string, a, b *geometry) (bool, error) {
+	aPoints, aLines, aPolys := components(a)
+	bPoints, bLines, bPolys := components(b)
+
+	switch {
+	case len(aPolys) > 0 && len(aPoints) == 0 && len(aLines) == 0:
+		return polygonsContain(aPolys, bPoints, bLines, bPolys), nil
+
+	case len(aLines) > 0 && len(aPoints) == 0 && len(aPolys) == 0 && len(bLines) == 0:
+		if len(bPolys) > 0 {
+			return false, nil
+		}
+		boundary := linearBoundary(aLines)
+		hit := false
+		for _, p := range bPoints {
+			on := false
+			for _, l := range aLines {
+				for _, s := range pointSegments(l.points) {
+					on = on || s.contains(p.points[0])
+				}
+			}
+			if !on {
+				return false, nil
+			}
+			isBoundary := false
+			for _, q := range boundary {
+				isBoundary = isBoundary || q == p.points[0]
+			}
+			hit = hit || !isBoundary
+		}
+		return hit, nil
+
+	case len(aPoints) > 0 && len(aLines) == 0 && len(aPolys) == 0:
+		if len(bLines) > 0 || len(bPolys) > 0 {
+			return false, nil
+		}
+		for _, p := range bPoints {
+			found := false
+			for _, q := range aPoints {
+				found = found || p.points[0] == q.points[0]
+			}
+			if !found {
+				return false, nil
+			}
+		}
+		return true, nil
+
+	default:
+		return false, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s(%s, %s) is not supported in vtgate", fn, a.typ, b.typ)
+	}
+}
+
+func polygonsContain(polys []*geometry, bPoints, bLines, bPolys []*geometry) bool {
+	boundary := segments(&geometry{typ: geometryMultiPolygon, geoms: polys})
+	hit := false
+
+	// covered returns whether a chain of points lies in the closure of the
+	// polygons, and records whether any of it lies in their interior.
+	covered := func(points []geoPoint) bool {
+		for _, p := range points {
+			switch locate(p, polys) {
+			case locExterior:
+				return false
+			case locInterior:
+				hit = true
+			}
+		}
+		for _, s := range pointSegments(points) {
+			for _, mid := range splitSegment(s, boundary) {
+				switch locate(mid, polys) {
+				case locExterior:
+					return false
+				case locInterior:
+					hit = true
+				}
+			}
+		}
+		return true
+	}
+
+	for _, p := range bPoints {
+		if !covered(p.points) {
+			return false
+		}
+	}
+	for _, l := range bLines {
+		if !covered(l.points) {
+			return false
+		}
+	}
+	for _, poly := range bPolys {
+		for _, r := range poly.rings {
+			if !covered(r) {
+				return false
+			}
+		}
+		// the boundary of the polygon is covered, but the container could still
+		// have a hole inside of it
+		for _, s := range boundary {
+			if locateInPolygon(s[0], poly) == locInterior || locateInPolygon(midpoint(s), poly) == locInterior {
+				return false
+			}
+		}
+		hit = hit || polygonArea(poly) > 0
+	}
+	return hit
+}
+
+func midpoint(s geoSegment) geoPoint {
+	return geoPoint{x: (s[0].x + s[1].x) / 2, y: (s[0].y + s[1].y) / 2}
+}
+
+// relateOperand is a geometry made up of members of a single dimension, whose
+// interior, boundary and exterior are known.
+type relateOperand struct {
+	dim   int
+	parts []*geometry
+	segs  []geoSegment
+	// ends is the boundary of a set of linestrings
+	ends []geoPoint
+}
+
+// relateOperands decodes the operands of a predicate on the DE-9IM model of two
+// geometries, which is supported when each of them is made up only of points,
+// only of linestrings or only of polygons.
+func relateOperands(fn string, a, b *geometry) (*relateOperand, *relateOperand, error) {
+	newOperand := func(g *geometry) *relateOperand {
+		points, lines, polys := components(g)
+		switch {
+		case len(lines) == 0 && len(polys) == 0:
+			return &relateOperand{dim: 0, parts: points, segs: segments(g)}
+		case len(points) == 0 && len(polys) == 0:
+			return &relateOperand{dim: 1, parts: lines, segs: segments(g), ends: linearBoundary(lines)}
+		case len(points) == 0 && len(lines) == 0:
+			return &relateOperand{dim: 2, parts: polys, segs: segments(g)}
+		default:
+			return nil
+		}
+	}
+	ra, rb := newOperand(a), newOperand(b)
+	if ra == nil || rb == nil {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "%s(%s, %s) is not supported in vtgate", fn, a.typ, b.typ)
+	}
+	return ra, rb, nil
+}
+
+func (o *relateOperand) locate(p geoPoint) int {
+	switch o.dim {
+	case 0:
+		for _, q := range o.parts {
+			if q.points[0] == p {
+				return locInterior
+			}
+		}
+		return locExterior
+	case 1:
+		for _, q := range o.ends {
+			if q == p {
+				return locBoundary
+			}
+		}
+		for _, s := range o.segs {
+			if s.contains(p) {
+				return locInterior
+			}
+		}
+		return locExterior
+	default:
+		return locate(p, o.parts)
+	}
+}
+
+// interiorSamples returns points of the interior of a set of points or linestrings
+// from which its relation with other can be told: the midpoints of the pieces
+// of the linestrings split wherever they meet other, where their location does
+// not change, and the points where they meet other and their vertices.
+func (o *relateOperand) interiorSamples(other *relateOperand) (mids, points []geoPoint) {
+	if o.dim == 0 {
+		for _, p := range o.parts {
+			points = append(points, p.points[0])
+		}
+		return nil, points
+	}
+	var candidates []geoPoint
+	for _, s := range o.segs {
+		mids = append(mids, splitSegment(s, other.segs)...)
+		candidates = append(candidates, s[0], s[1])
+		for _, t := range other.segs {
+			if p, ok := s.crossing(t); ok {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	for _, t := range other.segs {
+		candidates = append(candidates, t[0], t[1])
+	}
+	for _, p := range candidates {
+		if o.locate(p) == locInterior {
+			points = append(points, p)
+		}
+	}
+	return mids, points
+}
+
+// interiorsMeet returns the dimension of the intersection of the interiors of
+// two geometries, or -1 if their interiors do not meet.
+func interiorsMeet(a, b *relateOperand) int {
+	if a.dim > b.dim {
+		a, b = b, a
+	}
+	if a.dim == 2 {
+		if boundarySides(a, b, func(la, ra, lb, rb bool) bool { return la && lb || ra && rb }) {
+			return 2
+		}
+		return -1
+	}
+	mids, points := a.interiorSamples(b)
+	for _, p := range mids {
+		if b.locate(p) == locInterior {
+			return 1
+		}
+	}
+	for _, p := range points {
+		if b.locate(p) == locInterior {
+			return 0
+		}
+	}
+	return -1
+}
+
+// interiorMeetsExterior returns whether the interior of a meets the exterior of b.
+func interiorMeetsExterior(a, b *relateOperand) bool {
+	switch {
+	case a.dim < 2:
+		mids, points := a.interiorSamples(b)
+		for _, p := range append(mids, points...) {
+			if b.locate(p) == locExterior {
+				return true
+			}
+		}
+		return false
+	case b.dim < 2:
+		// a set of points or linestrings cannot cover the interior of a polygon
+		return true
+	default:
+		return boundarySides(a, b, func(la, ra, lb, rb bool) bool { return la && !lb || ra && !rb })
+	}
+}
+
+// boundarySides walks the pieces of the boundaries of two sets of polygons, split
+// wherever they meet, and returns whether pred holds for any of them. pred is
+// given whether the points just to the left and just to the right of the piece
+// are in the interior of a and of b.
+//
+// The intersection of the interior of a with the interior or the exterior of b
+// is an open region that is bounded by pieces of their boundaries, so the region
+// exists if and only if there is a piece with the region on one of its sides.
+func boundarySides(a, b *relateOperand, pred func(la, ra, lb, rb bool) bool) bool {
+	walk := func(segs, by []geoSegment) bool {
+		for _, s := range segs {
+			if s[0] == s[1] {
+				continue
+			}
+			for _, m := range splitSegment(s, by) {
+				la, ra := polygonSides(s, m, a.parts)
+				lb, rb := polygonSides(s, m, b.parts)
+				if pred(la, ra, lb, rb) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(a.segs, b.segs) || walk(b.segs, a.segs)
+}
+
+// polygonSides returns whether the points just to the left and just to the right
+// of a point m along the direction of segment s are in the interior of a set of
+// polygons. When m is on their boundary, s must run along it.
+func polygonSides(s geoSegment, m geoPoint, polys []*geometry) (left, right bool) {
+	switch locate(m, polys) {
+	case locInterior:
+		return true, true
+	case locExterior:
+		return false, false
+	}
+	dx, dy := s[1].x-s[0].x, s[1].y-s[0].y
+	for _, poly := range polys {
+		for i, ring := range poly.rings {
+			// the interior is to the left of a counter-clockwise exterior ring, and
+			// to the right of a counter-clockwise hole
+			interiorLeft := (ringArea(ring) > 0) == (i == 0)
+			for _, e := range pointSegments(ring) {
+				if !e.contains(m) {
+					continue
+				}
+				sameDirection := (e[1].x-e[0].x)*dx+(e[1].y-e[0].y)*dy > 0
+				if interiorLeft == sameDirection {
+					left = true
+				} else {
+					right = true
+				}
+			}
+		}
+	}
+	return
+}
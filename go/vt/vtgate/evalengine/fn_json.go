@@ -17,8 +17,13 @@ limitations under the License.
 package evalengine
 
 import (
+	"bytes"
+	"strconv"
+
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/colldata"
 	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/sqltypes"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -57,6 +62,25 @@ type (
 	builtinJSONKeys struct {
 		CallExpr
 	}
+
+	builtinJSONContains struct {
+		CallExpr
+	}
+
+	builtinJSONOverlaps struct {
+		CallExpr
+	}
+
+	builtinJSONSearch struct {
+		CallExpr
+	}
+
+	builtinJSONValue struct {
+		CallExpr
+		// returning is the conversion of the value to the RETURNING type; its Inner expression is not used
+		returning        *ConvertExpr
+		onEmpty, onError JSONTableResponse
+	}
 )
 
 var _ IR = (*builtinJSONExtract)(nil)
@@ -67,6 +91,10 @@ var _ IR = (*builtinJSONDepth)(nil)
 var _ IR = (*builtinJSONLength)(nil)
 var _ IR = (*builtinJSONContainsPath)(nil)
 var _ IR = (*builtinJSONKeys)(nil)
+var _ IR = (*builtinJSONContains)(nil)
+var _ IR = (*builtinJSONOverlaps)(nil)
+var _ IR = (*builtinJSONSearch)(nil)
+var _ IR = (*builtinJSONValue)(nil)
 
 var errInvalidPathForTransform = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "In this situation, path expressions may not contain the * and ** tokens or an array range.")
 
@@ -511,3 +539,440 @@ func (call *builtinJSONKeys) compile(c *compiler) (ctype, error) {
 	c.asm.Fn_JSON_KEYS(jp)
 	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
 }
+
+func (call *builtinJSONContains) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.contains(env, args)
+}
+
+func (call *builtinJSONContains) contains(_ *ExpressionEnv, args []eval) (eval, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	target, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := intoJSON(call.Method, args[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 3 {
+		jp, err := intoJSONPath(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if jp.ContainsWildcards() {
+			return nil, errInvalidPathForTransform
+		}
+		var match *json.Value
+		jp.Match(target, true, func(value *json.Value) { match = value })
+		if match == nil {
+			return nil, nil
+		}
+		target = match
+	}
+	ok, err := jsonContains(target, candidate)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(ok), nil
+}
+
+// jsonContains returns whether the candidate is contained in the target document, with the same
+// rules as MySQL: scalars must be equal, objects must contain all the keys of the candidate with
+// values that contain the candidate's values, and arrays must contain the candidate or all of its items.
+func jsonContains(target, candidate *json.Value) (bool, error) {
+	switch target.Type() {
+	case json.TypeArray:
+		items, _ := target.Array()
+		want, ok := candidate.Array()
+		if !ok {
+			for _, item := range items {
+				if ok, err := jsonContains(item, candidate); ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		for _, w := range want {
+			found := false
+			for _, item := range items {
+				var err error
+				switch w.Type() {
+				case json.TypeArray, json.TypeObject:
+					found, err = jsonContains(item, w)
+				default:
+					found, err = jsonEqual(item, w)
+				}
+				if err != nil {
+					return false, err
+				}
+				if found {
+					break
+				}
+			}
+			if !found {
+				return false, nil
+			}
+		}
+		return true, nil
+
+	case json.TypeObject:
+		want, ok := candidate.Object()
+		if !ok {
+			return false, nil
+		}
+		obj, _ := target.Object()
+		for _, key := range want.Keys() {
+			value := obj.Get(key)
+			if value == nil {
+				return false, nil
+			}
+			if ok, err := jsonContains(value, want.Get(key)); !ok || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	default:
+		return jsonEqual(target, candidate)
+	}
+}
+
+func jsonEqual(a, b *json.Value) (bool, error) {
+	if a.Type() != b.Type() {
+		return false, nil
+	}
+	cmp, err := compareJSONValue(a, b)
+	return cmp == 0, err
+}
+
+func (call *builtinJSONContains) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.contains)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+func (call *builtinJSONOverlaps) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.overlaps(env, args)
+}
+
+func (call *builtinJSONOverlaps) overlaps(_ *ExpressionEnv, args []eval) (eval, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	a, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := intoJSON(call.Method, args[1])
+	if err != nil {
+		return nil, err
+	}
+	ok, err := jsonOverlaps(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalBool(ok), nil
+}
+
+// jsonOverlaps returns whether two documents have any value in common: two arrays overlap
+// when they share an item, two objects when they share a key with the same value, and an
+// array overlaps with any other value which is one of its items.
+func jsonOverlaps(a, b *json.Value) (bool, error) {
+	if a.Type() != json.TypeArray && b.Type() == json.TypeArray {
+		a, b = b, a
+	}
+	if a.Type() != json.TypeArray {
+		ao, ok1 := a.Object()
+		bo, ok2 := b.Object()
+		if !ok1 || !ok2 {
+			return jsonEqual(a, b)
+		}
+		for _, key := range ao.Keys() {
+			if value := bo.Get(key); value != nil {
+				if ok, err := jsonEqual(ao.Get(key), value); ok || err != nil {
+					return ok, err
+				}
+			}
+		}
+		return false, nil
+	}
+
+	items, _ := a.Array()
+	others, ok := b.Array()
+	if !ok {
+		others = []*json.Value{b}
+	}
+	for _, item := range items {
+		for _, other := range others {
+			if ok, err := jsonEqual(item, other); ok || err != nil {
+				return ok, err
+			}
+		}
+	}
+	return false, nil
+}
+
+func (call *builtinJSONOverlaps) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.overlaps)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: flagIsBoolean | flagNullable}, nil
+}
+
+var errJSONSearchEscape = vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "Incorrect arguments to ESCAPE")
+
+func (call *builtinJSONSearch) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.search(env, args)
+}
+
+func (call *builtinJSONSearch) search(_ *ExpressionEnv, args []eval) (eval, error) {
+	for i, arg := range args {
+		// a NULL escape character is the default escape character
+		if arg == nil && i != 3 {
+			return nil, nil
+		}
+	}
+
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	match, err := intoOneOrAll(call.Method, evalToBinary(args[1]).string())
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := evalToVarchar(args[2], collationJSON.Collation, true)
+	if err != nil {
+		return nil, err
+	}
+	escape := '\\'
+	if len(args) > 3 && args[3] != nil {
+		esc, err := evalToVarchar(args[3], collationJSON.Collation, true)
+		if err != nil {
+			return nil, err
+		}
+		switch r := []rune(esc.string()); len(r) {
+		case 0:
+		case 1:
+			escape = r[0]
+		default:
+			return nil, errJSONSearchEscape
+		}
+	}
+
+	// Only the values found at the given paths are searched, but the results are the full
+	// paths to the matching strings.
+	var roots map[*json.Value]struct{}
+	if len(args) > 4 {
+		roots = make(map[*json.Value]struct{})
+		for _, arg := range args[4:] {
+			jp, err := intoJSONPath(arg)
+			if err != nil {
+				return nil, err
+			}
+			jp.Match(doc, true, func(value *json.Value) { roots[value] = struct{}{} })
+		}
+	}
+
+	wc := colldata.Lookup(collationJSON.Collation).Wildcard(pattern.bytes, 0, 0, escape)
+	var (
+		found  []*json.Value
+		inside []byte
+	)
+	doc.Walk(func(path []byte, value *json.Value) {
+		if match == jsonMatchOne && len(found) > 0 {
+			return
+		}
+		if roots != nil {
+			if inside != nil && !jsonPathIsInside(path, inside) {
+				inside = nil
+			}
+			if _, ok := roots[value]; ok && inside == nil {
+				inside = append([]byte(nil), path...)
+			}
+			if inside == nil {
+				return
+			}
+		}
+		if str, ok := value.StringBytes(); ok && wc.Match(str) {
+			found = append(found, json.NewString(string(path)))
+		}
+	})
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		return json.NewArray(found), nil
+	}
+}
+
+// jsonPathIsInside returns whether the path leads to the value at parent or to a value nested inside of it.
+func jsonPathIsInside(path, parent []byte) bool {
+	if !bytes.HasPrefix(path, parent) {
+		return false
+	}
+	return len(path) == len(parent) || path[len(parent)] == '.' || path[len(parent)] == '['
+}
+
+func (call *builtinJSONSearch) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.search)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+func (call *builtinJSONValue) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.value(env, args)
+}
+
+func (call *builtinJSONValue) value(env *ExpressionEnv, args []eval) (eval, error) {
+	if args[0] == nil || args[1] == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+	jp, err := intoJSONPath(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if jp.ContainsWildcards() {
+		return nil, errInvalidPathForTransform
+	}
+
+	var match *json.Value
+	jp.Match(doc, true, func(value *json.Value) { match = value })
+	if match == nil {
+		return call.respond(env, call.onEmpty, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "No value was found by 'json_value' on the specified path."))
+	}
+	if call.returning.Type == "JSON" {
+		return match, nil
+	}
+
+	var e eval
+	switch match.Type() {
+	case json.TypeNull:
+		return nil, nil
+	case json.TypeObject, json.TypeArray:
+		return call.respond(env, call.onError, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Can't convert an array or an object to a scalar in 'json_value'."))
+	case json.TypeString:
+		str, _ := match.StringBytes()
+		e = newEvalText(str, collationJSON)
+	case json.TypeNumber:
+		switch match.NumberType() {
+		case json.NumberTypeSigned:
+			i, _ := match.Int64()
+			e = newEvalInt64(i)
+		case json.NumberTypeUnsigned:
+			u, _ := match.Uint64()
+			e = newEvalUint64(u)
+		default:
+			f, _ := match.Float64()
+			e = newEvalFloat(f)
+		}
+	case json.TypeBoolean:
+		b, _ := match.Bool()
+		if call.returning.Type == "CHAR" || call.returning.Type == "NCHAR" {
+			e = newEvalText([]byte(strconv.FormatBool(b)), collationJSON)
+		} else {
+			e = newEvalBool(b)
+		}
+	case json.TypeDate:
+		e = newEvalText([]byte(match.MarshalDate()), collationJSON)
+	case json.TypeDateTime:
+		e = newEvalText([]byte(match.MarshalDateTime()), collationJSON)
+	case json.TypeTime:
+		e = newEvalText([]byte(match.MarshalTime()), collationJSON)
+	default:
+		e = newEvalBinary(match.ToUnencodedBytes())
+	}
+
+	res, err := call.returning.convert(env, e)
+	if err == nil && res == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Invalid value for the RETURNING type of 'json_value'.")
+	}
+	if err != nil {
+		return call.respond(env, call.onError, err)
+	}
+	return res, nil
+}
+
+func (call *builtinJSONValue) respond(env *ExpressionEnv, r JSONTableResponse, err error) (eval, error) {
+	switch r.Kind {
+	case JSONTableDefault:
+		def, err := valueToEval(r.Default, collationJSON, nil)
+		if err != nil || def == nil {
+			return nil, err
+		}
+		return call.returning.convert(env, def)
+	case JSONTableError:
+		return nil, err
+	default:
+		return nil, nil
+	}
+}
+
+func (call *builtinJSONValue) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.value)
+
+	conv := call.returning
+	var ct ctype
+	switch conv.Type {
+	case "BINARY":
+		ct = ctype{Type: conv.convertToBinaryType(sqltypes.VarChar), Col: collationBinary}
+	case "CHAR", "NCHAR":
+		ct = ctype{Type: conv.convertToCharType(sqltypes.VarChar), Col: collations.TypedCollation{Collation: conv.Collation}}
+	case "DECIMAL":
+		m, d := conv.decimalPrecision()
+		ct = ctype{Type: sqltypes.Decimal, Col: collationNumeric, Size: m, Scale: d}
+	case "DOUBLE", "REAL":
+		ct = ctype{Type: sqltypes.Float64, Col: collationNumeric}
+	case "SIGNED", "SIGNED INTEGER":
+		ct = ctype{Type: sqltypes.Int64, Col: collationNumeric}
+	case "UNSIGNED", "UNSIGNED INTEGER":
+		ct = ctype{Type: sqltypes.Uint64, Col: collationNumeric}
+	case "JSON":
+		ct = ctype{Type: sqltypes.TypeJSON, Col: collationJSON}
+	case "DATE":
+		ct = ctype{Type: sqltypes.Date, Col: collationBinary}
+	case "DATETIME":
+		ct = ctype{Type: sqltypes.Datetime, Col: collationBinary, Size: int32(ptr.Unwrap(conv.Length, 0))}
+	case "TIME":
+		ct = ctype{Type: sqltypes.Time, Col: collationBinary, Size: int32(ptr.Unwrap(conv.Length, 0))}
+	default:
+		return ctype{}, c.unsupported(call)
+	}
+	ct.Flag = flagNullable
+	return ct, nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+type (
+	// builtinJSONModify implements JSON_SET, JSON_INSERT, JSON_REPLACE, JSON_ARRAY_APPEND
+	// and JSON_ARRAY_INSERT, which take a document followed by pairs of paths and values.
+	builtinJSONModify struct {
+		CallExpr
+		t json.Transformation
+	}
+
+	builtinJSONRemove struct {
+		CallExpr
+	}
+
+	builtinJSONMergePatch struct {
+		CallExpr
+	}
+
+	// builtinJSONMergePreserve implements JSON_MERGE_PRESERVE and its deprecated alias JSON_MERGE
+	builtinJSONMergePreserve struct {
+		CallExpr
+	}
+)
+
+var _ IR = (*builtinJSONModify)(nil)
+var _ IR = (*builtinJSONRemove)(nil)
+var _ IR = (*builtinJSONMergePatch)(nil)
+var _ IR = (*builtinJSONMergePreserve)(nil)
+
+func (call *builtinJSONModify) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.modify(env, args)
+}
+
+func (call *builtinJSONModify) modify(_ *ExpressionEnv, args []eval) (eval, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*json.Path, 0, len(args)/2)
+	values := make([]*json.Value, 0, len(args)/2)
+	for i := 1; i < len(args); i += 2 {
+		if args[i] == nil {
+			return nil, nil
+		}
+		path, err := intoJSONPath(args[i])
+		if err != nil {
+			return nil, err
+		}
+		value, err := argToJSON(args[i+1])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		values = append(values, value)
+	}
+	return jsonTransform(call.t, doc, paths, values)
+}
+
+func (call *builtinJSONModify) compile(c *compiler) (ctype, error) {
+	for i, arg := range call.Arguments {
+		ct, err := arg.compile(c)
+		if err != nil {
+			return ctype{}, err
+		}
+		if i > 0 && i%2 == 0 {
+			c.compileJSONBoolean(ct)
+		}
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.modify)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+// compileJSONBoolean converts a boolean that is going to be added to a JSON document into a JSON
+// boolean. Booleans can't be told apart from integers once they're on the stack, so this has to
+// be done while the type of the argument is known.
+func (c *compiler) compileJSONBoolean(ct ctype) {
+	if ct.Type != sqltypes.Int64 || ct.Flag&flagIsBoolean == 0 {
+		return
+	}
+	skip := c.compileNullCheck1(ct)
+	c.asm.Convert_ij(1, true)
+	c.asm.jumpDestination(skip)
+}
+
+// jsonTransform applies the transformation to a copy of doc, so the arguments of the
+// function are never modified.
+func jsonTransform(t json.Transformation, doc *json.Value, paths []*json.Path, values []*json.Value) (eval, error) {
+	res, err := json.ApplyTransform(t, doc.Clone(), paths, values)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (call *builtinJSONRemove) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.remove(env, args)
+}
+
+func (call *builtinJSONRemove) remove(_ *ExpressionEnv, args []eval) (eval, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	doc, err := intoJSON(call.Method, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*json.Path, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		path, err := intoJSONPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return jsonTransform(json.Remove, doc, paths, nil)
+}
+
+func (call *builtinJSONRemove) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.remove)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+// compileJSONArgs pushes all the arguments of a JSON function which is then evaluated
+// by a single instruction that also takes care of the NULL arguments.
+func (c *compiler) compileJSONArgs(args []IR) error {
+	for _, arg := range args {
+		if _, err := arg.compile(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (call *builtinJSONMergePatch) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.merge(env, args)
+}
+
+func (call *builtinJSONMergePatch) merge(_ *ExpressionEnv, args []eval) (eval, error) {
+	// The result of merging a patch that is not an object is the patch itself, so a NULL document
+	// or patch only makes the result NULL if it's not followed by such a patch.
+	var doc *json.Value
+	for i, arg := range args {
+		if arg == nil {
+			doc = nil
+			continue
+		}
+		patch, err := intoJSON(call.Method, arg)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case i == 0 || patch.Type() != json.TypeObject:
+			doc = patch.Clone()
+		case doc != nil:
+			doc = jsonMergePatch(doc, patch.Clone())
+		}
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// jsonMergePatch merges patch into doc following RFC 7396. Both documents can be modified.
+func jsonMergePatch(doc, patch *json.Value) *json.Value {
+	po, ok := patch.Object()
+	if !ok {
+		return patch
+	}
+	obj, ok := doc.Object()
+	if !ok {
+		doc = json.NewObject(json.Object{})
+		obj, _ = doc.Object()
+	}
+	po.Visit(func(key string, value *json.Value) {
+		if value.Type() == json.TypeNull {
+			obj.Del(key)
+			return
+		}
+		target := obj.Get(key)
+		if target == nil {
+			target = json.ValueNull
+		}
+		obj.Set(key, jsonMergePatch(target, value), json.Set)
+	})
+	return doc
+}
+
+func (call *builtinJSONMergePatch) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.merge)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
+
+func (call *builtinJSONMergePreserve) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.merge(env, args)
+}
+
+func (call *builtinJSONMergePreserve) merge(_ *ExpressionEnv, args []eval) (eval, error) {
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+	var doc *json.Value
+	for _, arg := range args {
+		j, err := intoJSON(call.Method, arg)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			doc = j.Clone()
+		} else {
+			doc = jsonMergePreserve(doc, j.Clone())
+		}
+	}
+	return doc, nil
+}
+
+// jsonMergePreserve merges two documents keeping all of their values: arrays are concatenated,
+// objects are merged recursively and any other value is merged as an array with a single item.
+// Both documents can be modified.
+func jsonMergePreserve(doc, other *json.Value) *json.Value {
+	if obj, ok := doc.Object(); ok {
+		if oo, ok := other.Object(); ok {
+			oo.Visit(func(key string, value *json.Value) {
+				if existing := obj.Get(key); existing != nil {
+					value = jsonMergePreserve(existing, value)
+				}
+				obj.Set(key, value, json.Set)
+			})
+			return doc
+		}
+	}
+
+	var items []*json.Value
+	for _, v := range []*json.Value{doc, other} {
+		if ary, ok := v.Array(); ok {
+			items = append(items, ary...)
+		} else {
+			items = append(items, v)
+		}
+	}
+	return json.NewArray(items)
+}
+
+func (call *builtinJSONMergePreserve) compile(c *compiler) (ctype, error) {
+	if err := c.compileJSONArgs(call.Arguments); err != nil {
+		return ctype{}, err
+	}
+	c.asm.Fn_JSON_CALL(&call.CallExpr, call.merge)
+	return ctype{Type: sqltypes.TypeJSON, Flag: flagNullable, Col: collationJSON}, nil
+}
@@ -25,6 +25,8 @@ import (
 	"vitess.io/vitess/go/mysql/datetime"
 	"vitess.io/vitess/go/mysql/decimal"
 	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 var SystemTime = time.Now
@@ -179,6 +181,42 @@ type (
 		unit    datetime.IntervalType
 		collate collations.ID
 	}
+
+	builtinTimestampDiff struct {
+		CallExpr
+		unit datetime.IntervalType
+	}
+
+	builtinDateDiff struct {
+		CallExpr
+	}
+
+	builtinPeriodAdd struct {
+		CallExpr
+	}
+
+	builtinPeriodDiff struct {
+		CallExpr
+	}
+
+	// builtinAddTime implements ADDTIME and SUBTIME
+	builtinAddTime struct {
+		CallExpr
+		sub     bool
+		collate collations.ID
+	}
+
+	builtinStrToDate struct {
+		CallExpr
+		tt   sqltypes.Type
+		prec uint8
+	}
+
+	builtinGetFormat struct {
+		CallExpr
+		tt      sqltypes.Type
+		collate collations.ID
+	}
 )
 
 var _ IR = (*builtinNow)(nil)
@@ -213,6 +251,13 @@ var _ IR = (*builtinWeekDay)(nil)
 var _ IR = (*builtinWeekOfYear)(nil)
 var _ IR = (*builtinYear)(nil)
 var _ IR = (*builtinYearWeek)(nil)
+var _ IR = (*builtinTimestampDiff)(nil)
+var _ IR = (*builtinDateDiff)(nil)
+var _ IR = (*builtinPeriodAdd)(nil)
+var _ IR = (*builtinPeriodDiff)(nil)
+var _ IR = (*builtinAddTime)(nil)
+var _ IR = (*builtinStrToDate)(nil)
+var _ IR = (*builtinGetFormat)(nil)
 
 func (call *builtinNow) eval(env *ExpressionEnv) (eval, error) {
 	now := env.time(call.utc)
@@ -2028,3 +2073,455 @@ func (call *builtinDateMath) compile(c *compiler) (ctype, error) {
 	}
 	return ret, nil
 }
+
+func (call *builtinTimestampDiff) eval(env *ExpressionEnv) (eval, error) {
+	arg1, arg2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg1 == nil || arg2 == nil {
+		return nil, nil
+	}
+
+	dt1 := evalToDateTime(arg1, -1, env.now, false)
+	dt2 := evalToDateTime(arg2, -1, env.now, false)
+	if dt1 == nil || dt2 == nil || dt1.dt.Date.IsZero() || dt2.dt.Date.IsZero() {
+		return nil, nil
+	}
+	return newEvalInt64(datetime.TimestampDiff(dt1.dt, dt2.dt, call.unit)), nil
+}
+
+func (call *builtinTimestampDiff) compile(c *compiler) (ctype, error) {
+	arg1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip1 := c.compileNullCheck1(arg1)
+
+	switch arg1.Type {
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+	default:
+		c.asm.Convert_xDT(1, -1, false)
+	}
+
+	arg2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip2 := c.compileNullCheck1r(arg2)
+
+	switch arg2.Type {
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+	default:
+		c.asm.Convert_xDT(1, -1, false)
+	}
+
+	c.asm.Fn_TIMESTAMPDIFF(call.unit)
+	c.asm.jumpDestination(skip1, skip2)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: arg1.Flag | arg2.Flag | flagNullable}, nil
+}
+
+// dateDiff returns the number of days from d2 to d1, which is how MySQL implements DATEDIFF.
+func dateDiff(d1, d2 datetime.Date) int64 {
+	return int64(datetime.MysqlDayNumber(d1.Year(), d1.Month(), d1.Day()) - datetime.MysqlDayNumber(d2.Year(), d2.Month(), d2.Day()))
+}
+
+func (call *builtinDateDiff) eval(env *ExpressionEnv) (eval, error) {
+	arg1, arg2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg1 == nil || arg2 == nil {
+		return nil, nil
+	}
+
+	d1 := evalToDate(arg1, env.now, false)
+	d2 := evalToDate(arg2, env.now, false)
+	if d1 == nil || d2 == nil || d1.dt.Date.IsZero() || d2.dt.Date.IsZero() {
+		return nil, nil
+	}
+	return newEvalInt64(dateDiff(d1.dt.Date, d2.dt.Date)), nil
+}
+
+func (call *builtinDateDiff) compile(c *compiler) (ctype, error) {
+	arg1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip1 := c.compileNullCheck1(arg1)
+
+	switch arg1.Type {
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+	default:
+		c.asm.Convert_xD(1, false)
+	}
+
+	arg2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip2 := c.compileNullCheck1r(arg2)
+
+	switch arg2.Type {
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+	default:
+		c.asm.Convert_xD(1, false)
+	}
+
+	c.asm.Fn_DATEDIFF()
+	c.asm.jumpDestination(skip1, skip2)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: arg1.Flag | arg2.Flag | flagNullable}, nil
+}
+
+func errIncorrectPeriod(fn string) error {
+	return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongArguments, "Incorrect arguments to %s", fn)
+}
+
+func periodAdd(period, months int64) (int64, error) {
+	// MySQL returns 0 for a zero period instead of failing.
+	if period == 0 {
+		return 0, nil
+	}
+	if !datetime.ValidPeriod(period) {
+		return 0, errIncorrectPeriod("period_add")
+	}
+	// The arithmetic is performed on 32-bit integers, like MySQL does.
+	total := uint32(int32(datetime.PeriodToMonth(period)) + int32(months))
+	return datetime.MonthToPeriod(int64(total)), nil
+}
+
+func periodDiff(period1, period2 int64) (int64, error) {
+	if !datetime.ValidPeriod(period1) || !datetime.ValidPeriod(period2) {
+		return 0, errIncorrectPeriod("period_diff")
+	}
+	return datetime.PeriodToMonth(period1) - datetime.PeriodToMonth(period2), nil
+}
+
+func (call *builtinPeriodAdd) eval(env *ExpressionEnv) (eval, error) {
+	period, months, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if period == nil || months == nil {
+		return nil, nil
+	}
+
+	res, err := periodAdd(evalToInt64(period).i, evalToInt64(months).i)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(res), nil
+}
+
+func (call *builtinPeriodAdd) compile(c *compiler) (ctype, error) {
+	return c.compilePeriod(call.Arguments, c.asm.Fn_PERIOD_ADD)
+}
+
+func (call *builtinPeriodDiff) eval(env *ExpressionEnv) (eval, error) {
+	period1, period2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if period1 == nil || period2 == nil {
+		return nil, nil
+	}
+
+	res, err := periodDiff(evalToInt64(period1).i, evalToInt64(period2).i)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalInt64(res), nil
+}
+
+func (call *builtinPeriodDiff) compile(c *compiler) (ctype, error) {
+	return c.compilePeriod(call.Arguments, c.asm.Fn_PERIOD_DIFF)
+}
+
+// compilePeriod compiles PERIOD_ADD and PERIOD_DIFF, which convert both of their arguments
+// into integers before calling the given instruction.
+func (c *compiler) compilePeriod(args []IR, fn func()) (ctype, error) {
+	arg1, err := args[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip1 := c.compileNullCheck1(arg1)
+
+	switch arg1.Type {
+	case sqltypes.Int64:
+	default:
+		c.asm.Convert_xi(1)
+	}
+
+	arg2, err := args[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip2 := c.compileNullCheck1r(arg2)
+
+	switch arg2.Type {
+	case sqltypes.Int64:
+	default:
+		c.asm.Convert_xi(1)
+	}
+
+	fn()
+	c.asm.jumpDestination(skip1, skip2)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: arg1.Flag | arg2.Flag}, nil
+}
+
+// evalToAddTimeBase converts the first argument of ADDTIME and SUBTIME into a temporal value.
+// Strings can hold either a datetime or a time, while numbers are interpreted as a time first,
+// and only as a datetime when they are too large to be a time.
+func evalToAddTimeBase(e eval) *evalTemporal {
+	switch e := e.(type) {
+	case *evalTemporal:
+		return e
+	case *evalInt64:
+		if t, ok := datetime.ParseTimeInt64(e.i); ok {
+			return newEvalTime(t, 0)
+		}
+	case *evalUint64:
+		if t, ok := datetime.ParseTimeInt64(int64(e.u)); ok {
+			return newEvalTime(t, 0)
+		}
+	case *evalFloat:
+		if t, l, ok := datetime.ParseTimeFloat(e.f, -1); ok {
+			return newEvalTime(t, l)
+		}
+	case *evalDecimal:
+		if t, l, ok := datetime.ParseTimeDecimal(e.dec, e.length, -1); ok {
+			return newEvalTime(t, l)
+		}
+	}
+	return evalToTemporal(e, true)
+}
+
+// evalToAddTimeInterval converts the second argument of ADDTIME and SUBTIME into a time.
+// Unlike in other conversions to TIME, strings that hold a full datetime are not accepted.
+func evalToAddTimeInterval(e eval) *evalTemporal {
+	if b, ok := e.(*evalBytes); ok {
+		if _, _, ok := datetime.ParseDateTime(b.string(), -1); ok {
+			return nil
+		}
+	}
+	return evalToTime(e, -1)
+}
+
+// addTime adds or subtracts itv to base. The result is a TIME if base is a TIME, and a DATETIME
+// otherwise, with the largest precision of the two arguments.
+func addTime(base, itv *evalTemporal, sub bool) *evalTemporal {
+	prec := int(max(base.prec, itv.prec))
+	if base.SQLType() == sqltypes.Time {
+		return newEvalTime(base.dt.Time.AddTime(itv.dt.Time, sub), prec)
+	}
+	dt, ok := base.dt.AddTime(itv.dt.Time, sub)
+	if !ok {
+		return nil
+	}
+	return newEvalDateTime(dt, prec, true)
+}
+
+func (call *builtinAddTime) eval(env *ExpressionEnv) (eval, error) {
+	arg1, arg2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg1 == nil || arg2 == nil {
+		return nil, nil
+	}
+
+	base := evalToAddTimeBase(arg1)
+	itv := evalToAddTimeInterval(arg2)
+	if base == nil || itv == nil {
+		return nil, nil
+	}
+
+	res := addTime(base, itv, call.sub)
+	if res == nil {
+		return nil, nil
+	}
+	// The result is only temporal when the first argument is; otherwise it's a string.
+	if _, ok := arg1.(*evalTemporal); ok {
+		return res, nil
+	}
+	return newEvalText(res.ToRawBytes(), typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinAddTime) compile(c *compiler) (ctype, error) {
+	arg1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip1 := c.compileNullCheck1(arg1)
+
+	arg2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip2 := c.compileNullCheck1r(arg2)
+
+	ret := ctype{Flag: arg1.Flag | arg2.Flag | flagNullable, Col: collationBinary}
+	switch arg1.Type {
+	case sqltypes.Time:
+		ret.Type = sqltypes.Time
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+		ret.Type = sqltypes.Datetime
+	default:
+		ret.Type = sqltypes.VarChar
+		ret.Col = typedCoercionCollation(sqltypes.VarChar, c.collation)
+	}
+
+	c.asm.Fn_ADDTIME(call.sub, ret.Col)
+	c.asm.jumpDestination(skip1, skip2)
+	return ret, nil
+}
+
+// strToDateType returns the type and precision of the result of STR_TO_DATE with the given format
+// argument. Like in MySQL, it depends on the specifiers of the format if it's a literal, and it's a
+// DATETIME(6) otherwise. The normalizer keeps a literal format out of the bind variables for that reason.
+func strToDateType(format IR) (sqltypes.Type, uint8) {
+	lit, ok := format.(*Literal)
+	if !ok || lit.inner == nil {
+		return sqltypes.Datetime, datetime.DefaultPrecision
+	}
+
+	hasDate, hasTime, hasFrac := datetime.FormatParts(evalToBinary(lit.inner).string())
+	var prec uint8
+	if hasFrac {
+		prec = datetime.DefaultPrecision
+	}
+	switch {
+	case hasDate && hasTime:
+		return sqltypes.Datetime, prec
+	case hasTime:
+		return sqltypes.Time, prec
+	default:
+		return sqltypes.Date, 0
+	}
+}
+
+// strToDate parses s with the given format into a value of type tt. Dates that are zero, have
+// zero parts or don't exist are rejected depending on the SQL mode.
+func strToDate(format, s string, tt sqltypes.Type, mode SQLMode) (datetime.DateTime, bool) {
+	if tt == sqltypes.Time {
+		t, ok := datetime.StrToTime(format, s)
+		return datetime.DateTime{Time: t}, ok
+	}
+	dt, ok := datetime.StrToDate(format, s)
+	if !ok || !dt.Date.Valid(mode.AllowZeroDate(), mode.AllowZeroInDate()) {
+		return datetime.DateTime{}, false
+	}
+	return dt, true
+}
+
+func (call *builtinStrToDate) eval(env *ExpressionEnv) (eval, error) {
+	str, format, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if str == nil || format == nil {
+		return nil, nil
+	}
+
+	dt, ok := strToDate(evalToBinary(format).string(), evalToBinary(str).string(), call.tt, env.sqlmode)
+	if !ok {
+		return nil, nil
+	}
+	switch call.tt {
+	case sqltypes.Date:
+		return newEvalDate(dt.Date, true), nil
+	case sqltypes.Time:
+		return newEvalTime(dt.Time, int(call.prec)), nil
+	default:
+		return newEvalDateTime(dt, int(call.prec), true), nil
+	}
+}
+
+func (call *builtinStrToDate) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip1 := c.compileNullCheck1(str)
+
+	switch str.Type {
+	case sqltypes.VarChar, sqltypes.VarBinary:
+	default:
+		c.asm.Convert_xb(1, sqltypes.VarBinary, nil)
+	}
+
+	format, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip2 := c.compileNullCheck1r(format)
+
+	switch format.Type {
+	case sqltypes.VarChar, sqltypes.VarBinary:
+	default:
+		c.asm.Convert_xb(1, sqltypes.VarBinary, nil)
+	}
+
+	c.asm.Fn_STR_TO_DATE(call.tt, call.prec, c.sqlmode)
+	c.asm.jumpDestination(skip1, skip2)
+	return ctype{Type: call.tt, Col: collationBinary, Flag: str.Flag | format.Flag | flagNullable, Size: int32(call.prec)}, nil
+}
+
+// getFormat returns the format of the given type for a standard, as returned by GET_FORMAT.
+func getFormat(tt sqltypes.Type, name string) (string, bool) {
+	date, dt, t, ok := datetime.KnownFormat(name)
+	switch tt {
+	case sqltypes.Date:
+		return date, ok
+	case sqltypes.Time:
+		return t, ok
+	default:
+		return dt, ok
+	}
+}
+
+func (call *builtinGetFormat) eval(env *ExpressionEnv) (eval, error) {
+	arg, err := call.arg1(env)
+	if err != nil {
+		return nil, err
+	}
+	if arg == nil {
+		return nil, nil
+	}
+
+	f, ok := getFormat(call.tt, evalToBinary(arg).string())
+	if !ok {
+		return nil, nil
+	}
+	return newEvalText([]byte(f), typedCoercionCollation(sqltypes.VarChar, call.collate)), nil
+}
+
+func (call *builtinGetFormat) compile(c *compiler) (ctype, error) {
+	arg, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck1(arg)
+
+	switch arg.Type {
+	case sqltypes.VarChar, sqltypes.VarBinary:
+	default:
+		c.asm.Convert_xb(1, sqltypes.VarBinary, nil)
+	}
+
+	col := typedCoercionCollation(sqltypes.VarChar, c.collation)
+	c.asm.Fn_GET_FORMAT(call.tt, col)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.VarChar, Col: col, Flag: arg.Flag | flagNullable}, nil
+}
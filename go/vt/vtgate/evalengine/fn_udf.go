@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type (
+	// UserDefinedFunction is a Go implementation of a stored or loadable function.
+	// Registering one lets vtgate evaluate calls to the function itself instead of
+	// always pushing them down to MySQL.
+	UserDefinedFunction struct {
+		// Type is the SQL type of the values returned by Call.
+		Type sqltypes.Type
+		// MinArgs and MaxArgs bound the number of arguments of the function.
+		// A negative MaxArgs means that the function is variadic.
+		MinArgs, MaxArgs int
+		// Call evaluates the function. NULL arguments are passed as sqltypes.NULL,
+		// and the returned value is cast to Type.
+		Call func(args []sqltypes.Value) (sqltypes.Value, error)
+	}
+
+	// FunctionResolver returns the Go implementation of a function call that is not
+	// one of the builtins, and whether the function is deterministic. A nil function
+	// means that the call cannot be evaluated by vtgate.
+	FunctionResolver func(fn *sqlparser.FuncExpr) (udf *UserDefinedFunction, deterministic bool, err error)
+
+	builtinUDF struct {
+		CallExpr
+		udf           *UserDefinedFunction
+		deterministic bool
+		collate       collations.ID
+	}
+)
+
+var _ IR = (*builtinUDF)(nil)
+
+var udfRegistry = make(map[string]*UserDefinedFunction)
+
+// RegisterFunction registers the Go implementation of a user-defined function under
+// the specified plugin name, which VSchemas use to refer to it. A duplicate name will
+// generate a panic. Functions must be registered before any VSchema is loaded, usually
+// in an init function.
+func RegisterFunction(plugin string, udf *UserDefinedFunction) {
+	plugin = strings.ToLower(plugin)
+	if _, ok := udfRegistry[plugin]; ok {
+		panic(fmt.Sprintf("%s is already registered", plugin))
+	}
+	udfRegistry[plugin] = udf
+}
+
+// LookupFunction returns the user-defined function registered under the specified
+// plugin name.
+func LookupFunction(plugin string) (*UserDefinedFunction, bool) {
+	udf, ok := udfRegistry[strings.ToLower(plugin)]
+	return udf, ok
+}
+
+func (ast *astCompiler) translateUDF(fn *sqlparser.FuncExpr, args []IR) (IR, error) {
+	if ast.cfg.ResolveFunction == nil {
+		return nil, translateExprNotSupported(fn)
+	}
+	udf, deterministic, err := ast.cfg.ResolveFunction(fn)
+	if err != nil {
+		return nil, err
+	}
+	if udf == nil {
+		return nil, translateExprNotSupported(fn)
+	}
+
+	method := fn.Name.String()
+	if len(args) < udf.MinArgs || (udf.MaxArgs >= 0 && len(args) > udf.MaxArgs) {
+		return nil, argError(method)
+	}
+	return &builtinUDF{
+		CallExpr:      CallExpr{Arguments: args, Method: method},
+		udf:           udf,
+		deterministic: deterministic,
+		collate:       ast.cfg.Collation,
+	}, nil
+}
+
+func (call *builtinUDF) call(env *ExpressionEnv, args []eval) (eval, error) {
+	values := make([]sqltypes.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, evalToSQLValue(arg))
+	}
+	res, err := call.udf.Call(values)
+	if err != nil || res.IsNull() {
+		return nil, err
+	}
+	return valueToEvalCast(res, call.udf.Type, call.collate, nil, env.sqlmode)
+}
+
+func (call *builtinUDF) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.call(env, args)
+}
+
+func (call *builtinUDF) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+
+	c.asm.Fn_UDF(call)
+	return ctype{Type: call.udf.Type, Flag: flagNullable, Col: typedCoercionCollation(call.udf.Type, call.collate)}, nil
+}
+
+func (call *builtinUDF) constant() bool {
+	return call.deterministic && call.CallExpr.constant()
+}
@@ -278,6 +278,31 @@ func (c *builtinWeightString) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (call *builtinTimestampDiff) format(buf *sqlparser.TrackedBuffer) {
+	buf.WriteLiteral("timestampdiff(")
+	buf.WriteLiteral(call.unit.ToString())
+	buf.WriteString(", ")
+	formatExpr(buf, call, call.Arguments[0], true)
+	buf.WriteString(", ")
+	formatExpr(buf, call, call.Arguments[1], true)
+	buf.WriteByte(')')
+}
+
+func (call *builtinGetFormat) format(buf *sqlparser.TrackedBuffer) {
+	buf.WriteLiteral("get_format(")
+	switch call.tt {
+	case sqltypes.Date:
+		buf.WriteLiteral("date")
+	case sqltypes.Time:
+		buf.WriteLiteral("time")
+	default:
+		buf.WriteLiteral("datetime")
+	}
+	buf.WriteString(", ")
+	formatExpr(buf, call, call.Arguments[0], true)
+	buf.WriteByte(')')
+}
+
 func (n *NegateExpr) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte('-')
 	formatExpr(buf, n, n.Inner, true)
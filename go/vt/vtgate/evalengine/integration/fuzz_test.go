@@ -101,6 +101,12 @@ var (
 		regexp.MustCompile(`Illegal argument to a regular expression`),
 		regexp.MustCompile(`Incorrect arguments to regexp_substr`),
 		regexp.MustCompile(`Incorrect arguments to regexp_replace`),
+		regexp.MustCompile(`Invalid GIS data provided to function (\w+)`),
+		regexp.MustCompile(`Invalid GeoJSON data provided to function (\w+)`),
+		regexp.MustCompile(`value is a geometry of unexpected type (\w+) in (\w+)`),
+		regexp.MustCompile(`(Latitude|Longitude) (.*?) is out of range in function (\w+)`),
+		regexp.MustCompile(`Invalid radius provided to function (\w+)`),
+		regexp.MustCompile(`Calling geometry function (\w+) with unsupported types of arguments`),
 	}
 )
 
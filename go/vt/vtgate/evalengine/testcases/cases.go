@@ -33,6 +33,10 @@ var Cases = []TestCase{
 	{Run: JSONPathOperations},
 	{Run: JSONArray},
 	{Run: JSONObject},
+	{Run: JSONModification},
+	{Run: JSONMerge},
+	{Run: JSONSearch},
+	{Run: JSONValue},
 	{Run: CharsetConversionOperators},
 	{Run: CaseExprWithPredicate},
 	{Run: CaseExprWithValue},
@@ -155,6 +159,13 @@ var Cases = []TestCase{
 	{Run: FnWeekOfYear},
 	{Run: FnYear},
 	{Run: FnYearWeek},
+	{Run: FnTimestampDiff},
+	{Run: FnDateDiff},
+	{Run: FnPeriodAdd},
+	{Run: FnPeriodDiff},
+	{Run: FnAddTime},
+	{Run: FnStrToDate},
+	{Run: FnGetFormat},
 	{Run: FnInetAton},
 	{Run: FnInetNtoa},
 	{Run: FnInet6Aton},
@@ -172,6 +183,10 @@ var Cases = []TestCase{
 	{Run: RegexpInstr},
 	{Run: RegexpSubstr},
 	{Run: RegexpReplace},
+	{Run: FnSpatialFormat},
+	{Run: FnSpatialProperties},
+	{Run: FnSpatialRelations},
+	{Run: FnSpatialDistance},
 }
 
 func JSONPathOperations(yield Query) {
@@ -212,6 +227,84 @@ func JSONObject(yield Query) {
 	yield("JSON_OBJECT()", nil)
 }
 
+func JSONModification(yield Query) {
+	var functions = []string{"JSON_SET", "JSON_INSERT", "JSON_REPLACE", "JSON_ARRAY_APPEND", "JSON_ARRAY_INSERT"}
+	var values = []string{`1`, `'foo'`, `true`, `NULL`, `JSON_ARRAY(1, 2)`}
+
+	for _, obj := range inputJSONObjects {
+		for _, path1 := range inputJSONPaths {
+			yield(fmt.Sprintf("JSON_REMOVE('%s', '%s')", obj, path1), nil)
+
+			for _, fn := range functions {
+				for _, value := range values {
+					yield(fmt.Sprintf("%s('%s', '%s', %s)", fn, obj, path1, value), nil)
+				}
+			}
+		}
+
+		for _, fn := range functions {
+			yield(fmt.Sprintf("%s('%s', '$[0]', 1, '$[0][1]', 2)", fn, obj), nil)
+			yield(fmt.Sprintf("%s('%s', '$.a', 1 = 1, '$.z', 'bar')", fn, obj), nil)
+			yield(fmt.Sprintf("%s('%s', NULL, 1)", fn, obj), nil)
+		}
+		yield(fmt.Sprintf("JSON_REMOVE('%s', '$[1]', '$[1]')", obj), nil)
+		yield(fmt.Sprintf("JSON_REMOVE('%s', '$.a', NULL)", obj), nil)
+	}
+}
+
+func JSONMerge(yield Query) {
+	var docs = append([]string{`1`, `"foo"`, `null`, `{"a": null, "d": [1]}`, `[1, 2]`}, inputJSONObjects...)
+
+	for _, a := range docs {
+		for _, b := range docs {
+			yield(fmt.Sprintf("JSON_MERGE_PATCH('%s', '%s')", a, b), nil)
+			yield(fmt.Sprintf("JSON_MERGE_PRESERVE('%s', '%s')", a, b), nil)
+			yield(fmt.Sprintf("JSON_OVERLAPS('%s', '%s')", a, b), nil)
+			yield(fmt.Sprintf("JSON_CONTAINS('%s', '%s')", a, b), nil)
+			yield(fmt.Sprintf("JSON_CONTAINS('%s', '%s', '$[1]')", a, b), nil)
+		}
+		yield(fmt.Sprintf("JSON_MERGE_PATCH('%s', NULL, '[1]')", a), nil)
+		yield(fmt.Sprintf("JSON_MERGE_PATCH(NULL, '%s')", a), nil)
+		yield(fmt.Sprintf("JSON_MERGE_PRESERVE('%s', '%s', '%s')", a, a, a), nil)
+		yield(fmt.Sprintf("JSON_MERGE_PRESERVE('%s', NULL)", a), nil)
+	}
+}
+
+func JSONSearch(yield Query) {
+	var patterns = []string{`'foo'`, `'%o%'`, `'1%'`, `'a'`, `'_'`, `'%'`, `NULL`}
+
+	for _, obj := range inputJSONObjects {
+		for _, pattern := range patterns {
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'one', %s)", obj, pattern), nil)
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'all', %s)", obj, pattern), nil)
+			yield(fmt.Sprintf("JSON_SEARCH('%s', 'all', %s, NULL, '$[1]', '$.b')", obj, pattern), nil)
+
+			for _, path1 := range inputJSONPaths {
+				yield(fmt.Sprintf("JSON_SEARCH('%s', 'all', %s, '|', '%s')", obj, pattern, path1), nil)
+			}
+		}
+	}
+}
+
+func JSONValue(yield Query) {
+	var returning = []string{"", " RETURNING SIGNED", " RETURNING CHAR(2)", " RETURNING DECIMAL(10, 2)", " RETURNING JSON", " RETURNING DATE"}
+
+	for _, obj := range inputJSONObjects {
+		for _, path1 := range inputJSONPaths {
+			for _, r := range returning {
+				yield(fmt.Sprintf("JSON_VALUE('%s', '%s'%s)", obj, path1, r), nil)
+			}
+			yield(fmt.Sprintf("JSON_VALUE('%s', '%s' DEFAULT 'missing' ON EMPTY)", obj, path1), nil)
+		}
+	}
+	yield(`JSON_VALUE('{"a": "2024-01-02"}', '$.a' RETURNING DATE)`, nil)
+	yield(`JSON_VALUE('{"a": 1.5}', '$.a' RETURNING DECIMAL(4, 2))`, nil)
+	yield(`JSON_VALUE('{"a": true}', '$.a')`, nil)
+	yield(`JSON_VALUE('{"a": true}', '$.a' RETURNING UNSIGNED)`, nil)
+	yield(`JSON_VALUE('{"a": null}', '$.a')`, nil)
+	yield(`JSON_VALUE(NULL, '$.a')`, nil)
+}
+
 func CharsetConversionOperators(yield Query) {
 	var introducers = []string{
 		"", "_latin1", "_utf8mb4", "_utf8", "_binary",
@@ -2220,6 +2313,177 @@ func FnYearWeek(yield Query) {
 	}
 }
 
+func FnTimestampDiff(yield Query) {
+	units := []string{"MICROSECOND", "SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR"}
+	dates := []string{
+		`DATE'2024-01-31'`,
+		`TIMESTAMP'2024-02-29 10:00:00'`,
+		`'2024-02-29 09:59:59.999999'`,
+		`'2024-01-15 10:00:00'`,
+		`'2024-03-15 09:00:00'`,
+		`'2023-02-28'`,
+		`'1999-12-31 23:59:59'`,
+		`20240101`,
+		`'0000-00-00'`,
+		`'2024-13-01'`,
+		`NULL`,
+	}
+
+	for _, u := range units {
+		for _, d1 := range dates {
+			for _, d2 := range dates {
+				yield(fmt.Sprintf("TIMESTAMPDIFF(%s, %s, %s)", u, d1, d2), nil)
+			}
+		}
+	}
+	for _, d := range inputConversions {
+		yield(fmt.Sprintf("TIMESTAMPDIFF(DAY, %s, '2024-01-01')", d), nil)
+		yield(fmt.Sprintf("TIMESTAMPDIFF(MONTH, '2024-01-01', %s)", d), nil)
+	}
+}
+
+func FnDateDiff(yield Query) {
+	dates := []string{
+		`DATE'2024-01-31'`,
+		`TIMESTAMP'2024-02-29 10:00:00'`,
+		`'2007-12-31 23:59:59'`,
+		`'2007-12-30'`,
+		`'2010-11-30 23:59:59'`,
+		`'2010-12-31'`,
+		`20240101`,
+		`'0000-00-00'`,
+		`'foobar'`,
+		`NULL`,
+	}
+
+	for _, d1 := range dates {
+		for _, d2 := range dates {
+			yield(fmt.Sprintf("DATEDIFF(%s, %s)", d1, d2), nil)
+		}
+	}
+	for _, d := range inputConversions {
+		yield(fmt.Sprintf("DATEDIFF(%s, '2024-01-01')", d), nil)
+		yield(fmt.Sprintf("DATEDIFF('2024-01-01', %s)", d), nil)
+	}
+}
+
+var periods = []string{
+	"0", "1", "12", "13", "100", "101", "112", "113", "6901", "7001", "9912", "200801", "200812", "200813",
+	"'200801'", "199912.9", "-200801", "NULL",
+}
+
+func FnPeriodAdd(yield Query) {
+	months := []string{"0", "1", "2", "11", "12", "13", "-1", "-12", "-24000", "2.5", "'3'", "NULL"}
+	for _, p := range periods {
+		for _, m := range months {
+			yield(fmt.Sprintf("PERIOD_ADD(%s, %s)", p, m), nil)
+		}
+	}
+}
+
+func FnPeriodDiff(yield Query) {
+	for _, p1 := range periods {
+		for _, p2 := range periods {
+			yield(fmt.Sprintf("PERIOD_DIFF(%s, %s)", p1, p2), nil)
+		}
+	}
+}
+
+func FnAddTime(yield Query) {
+	bases := []string{
+		`TIME'10:00:00'`,
+		`TIME'-10:00:00.5'`,
+		`TIME'838:59:59'`,
+		`DATE'2024-02-28'`,
+		`TIMESTAMP'2007-12-31 23:59:59.999999'`,
+		`TIMESTAMP'9999-12-31 23:00:00'`,
+		`TIMESTAMP'0001-01-01 00:00:00'`,
+		`'2007-12-31 23:59:59.999999'`,
+		`'01:00:00.999999'`,
+		`'2024-02-28'`,
+		`'0000-00-00 00:00:00'`,
+		`103458`,
+		`20240101103458`,
+		`'foobar'`,
+		`NULL`,
+	}
+	intervals := []string{
+		`TIME'01:00:00'`,
+		`TIME'-25:00:00.25'`,
+		`'1 1:1:1.000002'`,
+		`'02:00:00.999998'`,
+		`'-838:59:59'`,
+		`'2007-12-31 23:59:59'`,
+		`'2007-12-31'`,
+		`TIMESTAMP'2007-12-31 01:02:03'`,
+		`10`,
+		`1.5`,
+		`'foobar'`,
+		`NULL`,
+	}
+
+	for _, b := range bases {
+		for _, i := range intervals {
+			yield(fmt.Sprintf("ADDTIME(%s, %s)", b, i), nil)
+			yield(fmt.Sprintf("SUBTIME(%s, %s)", b, i), nil)
+		}
+	}
+}
+
+func FnStrToDate(yield Query) {
+	mysqlDocSamples := []string{
+		`STR_TO_DATE('01,5,2013','%d,%m,%Y')`,
+		`STR_TO_DATE('May 1, 2013','%M %d,%Y')`,
+		`STR_TO_DATE('a09:30:17','a%h:%i:%s')`,
+		`STR_TO_DATE('a09:30:17','%h:%i:%s')`,
+		`STR_TO_DATE('09:30:17a','%h:%i:%s')`,
+		`STR_TO_DATE('abc','abc')`,
+		`STR_TO_DATE('9','%m')`,
+		`STR_TO_DATE('9','%s')`,
+		`STR_TO_DATE('00/00/0000', '%m/%d/%Y')`,
+		`STR_TO_DATE('04/31/2004', '%m/%d/%Y')`,
+		`STR_TO_DATE('200442 Monday', '%X%V %W')`,
+		`STR_TO_DATE('Tuesday 52 2001', '%W %V %X')`,
+		`STR_TO_DATE('15-01-2001 12:59:58', '%d-%m-%Y %H:%i:%s')`,
+		`STR_TO_DATE('15-01-2001 12:59:59', GET_FORMAT(DATE,'USA'))`,
+		`STR_TO_DATE('10.31.2003', GET_FORMAT(DATE,'USA'))`,
+		`STR_TO_DATE('2003-10-31 11.59.59', GET_FORMAT(DATETIME,'EUR'))`,
+		`STR_TO_DATE('11:59:59 PM', GET_FORMAT(TIME,'USA'))`,
+		`STR_TO_DATE('2020-01-01 10:20:30.5', '%Y-%m-%d %H:%i:%s.%f')`,
+		`STR_TO_DATE('10:20:30.123', '%H:%i:%s.%f')`,
+		`STR_TO_DATE('10:20:30 PM', '%r')`,
+		`STR_TO_DATE('2 10:30', '%d %H:%i')`,
+		`STR_TO_DATE('2021-032', '%Y-%j')`,
+		`STR_TO_DATE('feb 29 2021', '%b %d %Y')`,
+		`STR_TO_DATE('20240101', '%Y%m%d')`,
+		`STR_TO_DATE(20240101, '%Y%m%d')`,
+		`STR_TO_DATE(NULL, '%Y')`,
+		`STR_TO_DATE('2024', NULL)`,
+		`STR_TO_DATE('2024-01-01', CONCAT('%Y-', '%m-%d'))`,
+	}
+
+	for _, q := range mysqlDocSamples {
+		yield(q, nil)
+	}
+
+	formats := []string{`'%Y-%m-%d'`, `'%H:%i:%s'`, `'%Y%m%d%H%i%s'`, `'%Y-%m-%d %H:%i:%s.%f'`}
+	for _, f := range formats {
+		for _, d := range inputConversions {
+			yield(fmt.Sprintf("STR_TO_DATE(%s, %s)", d, f), nil)
+		}
+	}
+}
+
+func FnGetFormat(yield Query) {
+	types := []string{"DATE", "TIME", "DATETIME", "TIMESTAMP"}
+	formats := []string{`'USA'`, `'jis'`, `'Iso'`, `'EUR'`, `'INTERNAL'`, `'internal '`, `'foobar'`, `1`, `NULL`}
+	for _, t := range types {
+		for _, f := range formats {
+			yield(fmt.Sprintf("GET_FORMAT(%s, %s)", t, f), nil)
+		}
+	}
+}
+
 func FnInetAton(yield Query) {
 	for _, d := range ipInputs {
 		yield(fmt.Sprintf("INET_ATON(%s)", d), nil)
@@ -2344,6 +2608,9 @@ func DateMath(yield Query) {
 		`20250101`,
 		`'pokemon trainers'`,
 		`'20250101'`,
+		`TIME'10:00:00'`,
+		`TIME'-10:00:00.5'`,
+		`TIME'838:59:59'`,
 	}
 	intervalValues := []string{
 		`1`, `'1:1'`, `'1 1:1:1'`, `'-1 10'`, `'1 10'`, `31`, `30`, `'1.999999'`, `1.999`, `'1.999'`,
@@ -2666,3 +2933,133 @@ func RegexpReplace(yield Query) {
 		yield(q, nil)
 	}
 }
+
+func FnSpatialFormat(yield Query) {
+	for _, wkt := range inputGeometries {
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromText(%s))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsBinary(ST_GeomFromText(%s))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromWKB(ST_AsBinary(ST_GeomFromText(%s))))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsGeoJSON(ST_GeomFromText(%s))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsGeoJSON(ST_GeomFromText(%s), 1, 1)", wkt), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromGeoJSON(ST_AsGeoJSON(ST_GeomFromText(%s)), 1, 0))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromText(%s, 4326))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromText(%s, 4326, 'axis-order=long-lat'))", wkt), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromText(%s, 4326), 'axis-order=long-lat')", wkt), nil)
+		yield(fmt.Sprintf("ST_AsGeoJSON(ST_GeomFromText(%s, 4326), 3, 2)", wkt), nil)
+	}
+
+	var constructors = []string{
+		`POINT(1, 2)`, `POINT('1.5', 2e0)`, `POINT(NULL, 1)`,
+		`LINESTRING(POINT(0, 0), POINT(1, 1))`,
+		`LINESTRING(POINT(0, 0))`,
+		`POLYGON(LINESTRING(POINT(0, 0), POINT(1, 0), POINT(1, 1), POINT(0, 0)))`,
+		`POLYGON(LINESTRING(POINT(0, 0), POINT(1, 0), POINT(1, 1)))`,
+		`MULTIPOINT(POINT(0, 0), POINT(1, 1))`,
+		`MULTILINESTRING(LINESTRING(POINT(0, 0), POINT(1, 1)))`,
+		`MULTIPOLYGON(POLYGON(LINESTRING(POINT(0, 0), POINT(1, 0), POINT(1, 1), POINT(0, 0))))`,
+		`GEOMETRYCOLLECTION(POINT(0, 0), LINESTRING(POINT(0, 0), POINT(1, 1)))`,
+		`GEOMETRYCOLLECTION()`,
+	}
+	for _, c := range constructors {
+		yield(fmt.Sprintf("ST_AsText(%s)", c), nil)
+	}
+
+	var geojson = []string{
+		`'{"type": "Point", "coordinates": [1, 2]}'`,
+		`'{"type": "Point", "coordinates": [1, 2, 3]}'`,
+		`'{"type": "LineString", "coordinates": [[1, 2], [3, 4]]}'`,
+		`'{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}'`,
+		`'{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}, "properties": {}}'`,
+		`'{"type": "Feature", "geometry": null, "properties": {}}'`,
+		`'{"type": "GeometryCollection", "geometries": []}'`,
+		`'{"type": "Point", "coordinates": [200, 2]}'`,
+		`'{"type": "Point"}'`,
+	}
+	for _, doc := range geojson {
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromGeoJSON(%s))", doc), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeomFromGeoJSON(%s, 2, 0))", doc), nil)
+		yield(fmt.Sprintf("ST_SRID(ST_GeomFromGeoJSON(%s))", doc), nil)
+	}
+}
+
+func FnSpatialProperties(yield Query) {
+	for _, wkt := range inputGeometries {
+		g := fmt.Sprintf("ST_GeomFromText(%s)", wkt)
+		yield(fmt.Sprintf("ST_X(%s)", g), nil)
+		yield(fmt.Sprintf("ST_Y(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_X(%s, 7))", g), nil)
+		yield(fmt.Sprintf("ST_X(ST_GeomFromText(%s, 4326))", wkt), nil)
+		yield(fmt.Sprintf("ST_Latitude(ST_GeomFromText(%s, 4326))", wkt), nil)
+		yield(fmt.Sprintf("ST_Longitude(ST_GeomFromText(%s, 4326))", wkt), nil)
+		yield(fmt.Sprintf("ST_Latitude(%s)", g), nil)
+		yield(fmt.Sprintf("ST_SRID(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_SRID(%s, 4326))", g), nil)
+		yield(fmt.Sprintf("ST_GeometryType(%s)", g), nil)
+		yield(fmt.Sprintf("ST_Dimension(%s)", g), nil)
+		yield(fmt.Sprintf("ST_IsEmpty(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_Envelope(%s))", g), nil)
+		yield(fmt.Sprintf("ST_NumPoints(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_StartPoint(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_EndPoint(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_PointN(%s, 2))", g), nil)
+		yield(fmt.Sprintf("ST_IsClosed(%s)", g), nil)
+		yield(fmt.Sprintf("ST_Length(%s)", g), nil)
+		yield(fmt.Sprintf("ST_Area(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_Centroid(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_ExteriorRing(%s))", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_InteriorRingN(%s, 1))", g), nil)
+		yield(fmt.Sprintf("ST_NumInteriorRings(%s)", g), nil)
+		yield(fmt.Sprintf("ST_NumGeometries(%s)", g), nil)
+		yield(fmt.Sprintf("ST_AsText(ST_GeometryN(%s, 1))", g), nil)
+	}
+}
+
+func FnSpatialRelations(yield Query) {
+	var relations = []string{
+		"ST_Distance", "ST_Intersects", "ST_Disjoint", "ST_Touches", "ST_Overlaps", "ST_Crosses",
+		"MBRIntersects", "MBRDisjoint", "MBRContains", "MBRWithin", "MBRCovers", "MBRCoveredBy", "MBREquals",
+	}
+	for _, fn := range relations {
+		for _, wkt1 := range inputGeometries {
+			for _, wkt2 := range inputGeometries {
+				yield(fmt.Sprintf("%s(ST_GeomFromText(%s), ST_GeomFromText(%s))", fn, wkt1, wkt2), nil)
+			}
+		}
+		yield(fmt.Sprintf("%s(POINT(1, 1), ST_GeomFromText('POINT(1 1)', 4326))", fn), nil)
+		yield(fmt.Sprintf("%s(POINT(1, 1), NULL)", fn), nil)
+	}
+
+	// containment is only evaluated when the container is made up of points or polygons
+	for _, container := range inputContainers {
+		for _, wkt := range inputGeometries {
+			yield(fmt.Sprintf("ST_Contains(ST_GeomFromText(%s), ST_GeomFromText(%s))", container, wkt), nil)
+			yield(fmt.Sprintf("ST_Within(ST_GeomFromText(%s), ST_GeomFromText(%s))", wkt, container), nil)
+		}
+		for _, other := range inputContainers {
+			yield(fmt.Sprintf("ST_Equals(ST_GeomFromText(%s), ST_GeomFromText(%s))", container, other), nil)
+		}
+	}
+}
+
+func FnSpatialDistance(yield Query) {
+	var points = []string{
+		`'POINT(0 0)'`, `'POINT(40.7501 -73.9949)'`, `'POINT(-90 180)'`, `'POINT(90 0)'`,
+		`'MULTIPOINT((40.7542 -73.9961),(0 0))'`, `'LINESTRING(0 0,1 1)'`,
+	}
+	for _, wkt1 := range points {
+		for _, wkt2 := range points {
+			yield(fmt.Sprintf("ST_Distance(ST_GeomFromText(%s, 4326), ST_GeomFromText(%s, 4326))", wkt1, wkt2), nil)
+			yield(fmt.Sprintf("ST_Distance_Sphere(ST_GeomFromText(%s, 4326), ST_GeomFromText(%s, 4326))", wkt1, wkt2), nil)
+			yield(fmt.Sprintf("ST_Distance_Sphere(ST_GeomFromText(%s), ST_GeomFromText(%s))", wkt1, wkt2), nil)
+		}
+	}
+
+	var radiuses = []string{"1", "6371000", "'10.5'", "0", "-1", "NULL"}
+	for _, r := range radiuses {
+		yield(fmt.Sprintf("ST_Distance_Sphere(POINT(-73.9949, 40.7501), POINT(-73.9961, 40.7542), %s)", r), nil)
+	}
+	yield("ST_Distance_Sphere(POINT(0, 0), POINT(181, 0))", nil)
+	yield("ST_Distance_Sphere(POINT(0, 0), POINT(0, 91))", nil)
+	yield("ST_Distance_Sphere(POINT(0, 0), ST_GeomFromText('POINT(0 0)', 4326))", nil)
+	yield("ST_Distance_Sphere(ST_GeomFromText('POINT(0 0)', 3857), ST_GeomFromText('POINT(0 0)', 3857))", nil)
+}
@@ -411,3 +411,24 @@ var inputIntervals = []string{"day",
 	"second_microsecond",
 	"year_month",
 }
+
+var inputGeometries = []string{
+	`'POINT(1 2)'`, `'POINT(-1.5 0.25)'`, `'POINT(5 5)'`, `'POINT(10 5)'`,
+	`'LINESTRING(0 0,3 4,3 5)'`, `'LINESTRING(0 0,10 10)'`, `'LINESTRING(0 10,10 0)'`, `'LINESTRING(0 0,0 10,10 10,0 0)'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0))'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,3 2,3 3,2 2))'`,
+	`'POLYGON((1 1,5 1,5 5,1 5,1 1))'`,
+	`'MULTIPOINT(1 1,2 2)'`, `'MULTIPOINT((5 5),(20 20))'`,
+	`'MULTILINESTRING((0 0,1 1),(5 5,6 6,7 5))'`,
+	`'MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((20 20,30 20,30 30,20 20)))'`,
+	`'GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(2 2,3 3))'`,
+	`'GEOMETRYCOLLECTION EMPTY'`,
+	`'POINT(1)'`, `'LINESTRING(1 1)'`, `'POLYGON((0 0,1 0,1 1))'`, `'foobar'`,
+}
+
+var inputContainers = []string{
+	`'POINT(5 5)'`, `'MULTIPOINT(1 1,2 2)'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0))'`,
+	`'POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,3 2,3 3,2 2))'`,
+	`'MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((20 20,30 20,30 30,20 20)))'`,
+}
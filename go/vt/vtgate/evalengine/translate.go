@@ -566,8 +566,9 @@ type ColumnResolver func(name *sqlparser.ColName) (int, error)
 type TypeResolver func(expr sqlparser.Expr) (Type, bool)
 
 type Config struct {
-	ResolveColumn ColumnResolver
-	ResolveType   TypeResolver
+	ResolveColumn   ColumnResolver
+	ResolveType     TypeResolver
+	ResolveFunction FunctionResolver
 
 	Collation         collations.ID
 	NoConstantFolding bool
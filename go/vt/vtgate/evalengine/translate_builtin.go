@@ -21,6 +21,9 @@ import (
 	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/json"
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/sqltypes"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -462,6 +465,32 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinFromDays{CallExpr: call}, nil
+	case "datediff":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinDateDiff{CallExpr: call}, nil
+	case "period_add":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinPeriodAdd{CallExpr: call}, nil
+	case "period_diff":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinPeriodDiff{CallExpr: call}, nil
+	case "addtime", "subtime":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinAddTime{CallExpr: call, sub: method == "subtime", collate: ast.cfg.Collation}, nil
+	case "str_to_date":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		tt, prec := strToDateType(args[1])
+		return &builtinStrToDate{CallExpr: call, tt: tt, prec: prec}, nil
 	case "sec_to_time":
 		if len(args) != 1 {
 			return nil, argError(method)
@@ -649,7 +678,10 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 		}
 		return &builtinReplace{CallExpr: call, collate: ast.cfg.Collation}, nil
 	default:
-		return nil, translateExprNotSupported(fn)
+		if _, ok := gisFunctions[method]; ok {
+			return newBuiltinGeometry(method, args, ast.cfg.Collation)
+		}
+		return ast.translateUDF(fn, args)
 	}
 }
 
@@ -768,6 +800,95 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			Method:    "JSON_KEYS",
 		}}, nil
 
+	case *sqlparser.JSONValueModifierExpr:
+		exprs := []sqlparser.Expr{call.JSONDoc}
+		for _, param := range call.Params {
+			exprs = append(exprs, param.Key, param.Value)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		var t json.Transformation
+		switch call.Type {
+		case sqlparser.JSONArrayAppendType:
+			t = json.ArrayAppend
+		case sqlparser.JSONArrayInsertType:
+			t = json.ArrayInsert
+		case sqlparser.JSONInsertType:
+			t = json.Insert
+		case sqlparser.JSONReplaceType:
+			t = json.Replace
+		default:
+			t = json.Set
+		}
+		return &builtinJSONModify{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    strings.ToUpper(call.Type.ToString()),
+		}, t: t}, nil
+
+	case *sqlparser.JSONValueMergeExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.JSONDocList...))
+		if err != nil {
+			return nil, err
+		}
+		cexpr := CallExpr{Arguments: args, Method: strings.ToUpper(call.Type.ToString())}
+		if call.Type == sqlparser.JSONMergePatchType {
+			return &builtinJSONMergePatch{CallExpr: cexpr}, nil
+		}
+		return &builtinJSONMergePreserve{CallExpr: cexpr}, nil
+
+	case *sqlparser.JSONRemoveExpr:
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.JSONDoc}, call.PathList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONRemove{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_REMOVE",
+		}}, nil
+
+	case *sqlparser.JSONContainsExpr:
+		if len(call.PathList) > 1 {
+			return nil, argError("JSON_CONTAINS")
+		}
+		args, err := ast.translateFuncArgs(append([]sqlparser.Expr{call.Target, call.Candidate}, call.PathList...))
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONContains{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_CONTAINS",
+		}}, nil
+
+	case *sqlparser.JSONOverlapsExpr:
+		args, err := ast.translateFuncArgs([]sqlparser.Expr{call.JSONDoc1, call.JSONDoc2})
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONOverlaps{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_OVERLAPS",
+		}}, nil
+
+	case *sqlparser.JSONSearchExpr:
+		exprs := []sqlparser.Expr{call.JSONDoc, call.OneOrAll, call.SearchStr}
+		if call.EscapeChar != nil {
+			exprs = append(exprs, call.EscapeChar)
+			exprs = append(exprs, call.PathList...)
+		}
+		args, err := ast.translateFuncArgs(exprs)
+		if err != nil {
+			return nil, err
+		}
+		return &builtinJSONSearch{CallExpr: CallExpr{
+			Arguments: args,
+			Method:    "JSON_SEARCH",
+		}}, nil
+
+	case *sqlparser.JSONValueExpr:
+		return ast.translateJSONValue(call)
+
 	case *sqlparser.CurTimeFuncExpr:
 		if call.Fsp > 6 {
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Too-big precision %d specified for '%s'. Maximum is 6.", call.Fsp, call.Name.String())
@@ -889,6 +1010,42 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			collate:  ast.cfg.Collation,
 		}, nil
 
+	case *sqlparser.TimestampDiffExpr:
+		expr1, err := ast.translateExpr(call.Expr1)
+		if err != nil {
+			return nil, err
+		}
+		expr2, err := ast.translateExpr(call.Expr2)
+		if err != nil {
+			return nil, err
+		}
+
+		return &builtinTimestampDiff{
+			CallExpr: CallExpr{Arguments: []IR{expr1, expr2}, Method: "TIMESTAMPDIFF"},
+			unit:     call.Unit,
+		}, nil
+
+	case *sqlparser.GetFormatExpr:
+		format, err := ast.translateExpr(call.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		var tt sqltypes.Type
+		switch call.Type {
+		case sqlparser.GetFormatDate:
+			tt = sqltypes.Date
+		case sqlparser.GetFormatTime:
+			tt = sqltypes.Time
+		default:
+			tt = sqltypes.Datetime
+		}
+		return &builtinGetFormat{
+			CallExpr: CallExpr{Arguments: []IR{format}, Method: "GET_FORMAT"},
+			tt:       tt,
+			collate:  ast.cfg.Collation,
+		}, nil
+
 	case *sqlparser.RegexpLikeExpr:
 		input, err := ast.translateExpr(call.Expr)
 		if err != nil {
@@ -1105,11 +1262,64 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			CallExpr: cexpr,
 			collate:  coll,
 		}, nil
+	case *sqlparser.PointExpr:
+		return ast.translateGeometryFunc("point", call.XCordinate, call.YCordinate)
+	case *sqlparser.LineStringExpr:
+		return ast.translateGeometryFunc("linestring", call.PointParams...)
+	case *sqlparser.PolygonExpr:
+		return ast.translateGeometryFunc("polygon", call.LinestringParams...)
+	case *sqlparser.MultiPointExpr:
+		return ast.translateGeometryFunc("multipoint", call.PointParams...)
+	case *sqlparser.MultiLinestringExpr:
+		return ast.translateGeometryFunc("multilinestring", call.LinestringParams...)
+	case *sqlparser.MultiPolygonExpr:
+		return ast.translateGeometryFunc("multipolygon", call.PolygonParams...)
+	case *sqlparser.GeomFromTextExpr:
+		return ast.translateGeometryFunc(call.Type.ToString(), call.WktText, call.Srid, call.AxisOrderOpt)
+	case *sqlparser.GeomFromWKBExpr:
+		return ast.translateGeometryFunc(call.Type.ToString(), call.WkbBlob, call.Srid, call.AxisOrderOpt)
+	case *sqlparser.GeomFormatExpr:
+		return ast.translateGeometryFunc(call.FormatType.ToString(), call.Geom, call.AxisOrderOpt)
+	case *sqlparser.GeomPropertyFuncExpr:
+		if call.Property == sqlparser.IsSimple {
+			return nil, translateExprNotSupported(call)
+		}
+		return ast.translateGeometryFunc(call.Property.ToString(), call.Geom)
+	case *sqlparser.PointPropertyFuncExpr:
+		return ast.translateGeometryFunc(call.Property.ToString(), call.Point, call.ValueToSet)
+	case *sqlparser.LinestrPropertyFuncExpr:
+		return ast.translateGeometryFunc(call.Property.ToString(), call.Linestring, call.PropertyDefArg)
+	case *sqlparser.PolygonPropertyFuncExpr:
+		return ast.translateGeometryFunc(call.Property.ToString(), call.Polygon, call.PropertyDefArg)
+	case *sqlparser.GeomCollPropertyFuncExpr:
+		return ast.translateGeometryFunc(call.Property.ToString(), call.GeomColl, call.PropertyDefArg)
+	case *sqlparser.GeoJSONFromGeomExpr:
+		return ast.translateGeometryFunc("st_asgeojson", call.Geom, call.MaxDecimalDigits, call.Bitmask)
+	case *sqlparser.GeomFromGeoJSONExpr:
+		return ast.translateGeometryFunc("st_geomfromgeojson", call.GeoJSON, call.HigherDimHandlerOpt, call.Srid)
 	default:
 		return nil, translateExprNotSupported(call)
 	}
 }
 
+// translateGeometryFunc translates a spatial function from one of the dedicated
+// AST nodes the parser uses for them. Optional arguments that were not given are
+// nil, and always trail the given ones.
+func (ast *astCompiler) translateGeometryFunc(method string, exprs ...sqlparser.Expr) (IR, error) {
+	args := make([]IR, 0, len(exprs))
+	for _, expr := range exprs {
+		if expr == nil {
+			break
+		}
+		arg, err := ast.translateExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return newBuiltinGeometry(method, args, ast.cfg.Collation)
+}
+
 func builtinJSONExtractUnquoteRewrite(left IR, right IR) (IR, error) {
 	extract, err := builtinJSONExtractRewrite(left, right)
 	if err != nil {
@@ -1202,3 +1412,35 @@ func builtinIfRewrite(args []IR) (IR, error) {
 		Else: args[2],
 	}, nil
 }
+
+func (ast *astCompiler) translateJSONValue(call *sqlparser.JSONValueExpr) (IR, error) {
+	args, err := ast.translateFuncArgs([]sqlparser.Expr{call.JSONDoc, call.Path})
+	if err != nil {
+		return nil, err
+	}
+	jv := &builtinJSONValue{CallExpr: CallExpr{
+		Arguments: args,
+		Method:    "JSON_VALUE",
+	}}
+	if call.ReturningType != nil {
+		jv.returning, err = ast.translateConvertType(call, call.ReturningType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Without a RETURNING clause, the value is returned as a VARCHAR(512) with a binary utf8mb4 collation
+		jv.returning = &ConvertExpr{
+			Type:         "CHAR",
+			Length:       ptr.Of(512),
+			Collation:    collationJSON.Collation,
+			CollationEnv: ast.cfg.Environment.CollationEnv(),
+		}
+	}
+	if jv.onEmpty, err = translateJSONTableResponse("JSON_VALUE", call.EmptyOnResponse); err != nil {
+		return nil, err
+	}
+	if jv.onError, err = translateJSONTableResponse("JSON_VALUE", call.ErrorOnResponse); err != nil {
+		return nil, err
+	}
+	return jv, nil
+}
@@ -61,17 +61,27 @@ func (ast *astCompiler) translateConvertCharset(charset string, binary bool) (co
 }
 
 func (ast *astCompiler) translateConvertExpr(expr sqlparser.Expr, convertType *sqlparser.ConvertType) (IR, error) {
+	inner, err := ast.translateExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	convert, err := ast.translateConvertType(expr, convertType)
+	if err != nil {
+		return nil, err
+	}
+	convert.Inner = inner
+	return convert, nil
+}
+
+// translateConvertType translates the target type of a conversion of expr. The Inner
+// expression of the returned ConvertExpr is left empty.
+func (ast *astCompiler) translateConvertType(expr sqlparser.Expr, convertType *sqlparser.ConvertType) (*ConvertExpr, error) {
 	var (
 		convert ConvertExpr
 		err     error
 	)
 
 	convert.CollationEnv = ast.cfg.Environment.CollationEnv()
-	convert.Inner, err = ast.translateExpr(expr)
-	if err != nil {
-		return nil, err
-	}
-
 	convert.Length = convertType.Length
 	convert.Scale = convertType.Scale
 	convert.Type = strings.ToUpper(convertType.Type)
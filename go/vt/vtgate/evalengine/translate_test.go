@@ -452,3 +452,107 @@ func TestCardinalityWithBindVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateUserDefinedFunction(t *testing.T) {
+	RegisterFunction("translate_test_join", &UserDefinedFunction{
+		Type:    sqltypes.VarChar,
+		MinArgs: 1,
+		MaxArgs: -1,
+		Call: func(args []sqltypes.Value) (sqltypes.Value, error) {
+			var parts []string
+			for _, arg := range args {
+				if arg.IsNull() {
+					return sqltypes.NULL, nil
+				}
+				parts = append(parts, arg.ToString())
+			}
+			return sqltypes.NewVarChar(strings.Join(parts, "-")), nil
+		},
+	})
+	udf, ok := LookupFunction("TRANSLATE_TEST_JOIN")
+	require.True(t, ok)
+
+	resolve := func(fn *sqlparser.FuncExpr) (*UserDefinedFunction, bool, error) {
+		switch fn.Name.Lowered() {
+		case "join_fn":
+			return udf, true, nil
+		case "random_join_fn":
+			return udf, false, nil
+		}
+		return nil, false, nil
+	}
+
+	testCases := []struct {
+		expression string
+		expected   sqltypes.Value
+		err        string
+	}{{
+		expression: "join_fn('a', 'b', 1)",
+		expected:   sqltypes.NewVarChar("a-b-1"),
+	}, {
+		expression: "join_fn(1 + 1)",
+		expected:   sqltypes.NewVarChar("2"),
+	}, {
+		expression: "join_fn('a', null)",
+		expected:   sqltypes.NULL,
+	}, {
+		expression: "concat(join_fn('a'), 'b')",
+		expected:   sqltypes.NewVarChar("ab"),
+	}, {
+		expression: "join_fn()",
+		err:        "Incorrect parameter count in the call to native function 'join_fn'",
+	}, {
+		expression: "other_fn(1)",
+		err:        "expr cannot be translated, not supported: other_fn(1)",
+	}}
+
+	venv := vtenv.NewTestEnv()
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := venv.Parser().ParseExpr(tc.expression)
+			require.NoError(t, err)
+
+			cfg := &Config{
+				Collation:         venv.CollationEnv().DefaultConnectionCharset(),
+				Environment:       venv,
+				NoConstantFolding: true,
+				ResolveFunction:   resolve,
+			}
+			converted, err := Translate(expr, cfg)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			env := EmptyExpressionEnv(venv)
+			res, err := env.EvaluateAST(converted)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res.Value(collations.MySQL8().DefaultConnectionCharset()))
+
+			res, err = env.Evaluate(converted)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res.Value(collations.MySQL8().DefaultConnectionCharset()))
+		})
+	}
+
+	t.Run("constant folding", func(t *testing.T) {
+		cfg := &Config{
+			Collation:       venv.CollationEnv().DefaultConnectionCharset(),
+			Environment:     venv,
+			ResolveFunction: resolve,
+		}
+
+		expr, err := venv.Parser().ParseExpr("join_fn('a', 'b')")
+		require.NoError(t, err)
+		converted, err := Translate(expr, cfg)
+		require.NoError(t, err)
+		assert.IsType(t, &Literal{}, converted)
+
+		expr, err = venv.Parser().ParseExpr("random_join_fn('a', 'b')")
+		require.NoError(t, err)
+		converted, err = Translate(expr, cfg)
+		require.NoError(t, err)
+		assert.IsType(t, &CompiledExpr{}, converted)
+	})
+}
@@ -77,6 +77,10 @@ var (
 	queriesProcessedByTable = stats.NewCountersWithMultiLabels("QueriesProcessedByTable", "Queries processed at vtgate by plan type, keyspace and table", []string{"Plan", "Keyspace", "Table"})
 	queriesRoutedByTable    = stats.NewCountersWithMultiLabels("QueriesRoutedByTable", "Queries routed from vtgate to vttablet by plan type, keyspace and table", []string{"Plan", "Keyspace", "Table"})
 
+	planBaselinesApplied = stats.NewCountersWithSingleLabel("PlanBaselinesApplied", "Queries planned with a plan baseline at vtgate by keyspace", "Keyspace")
+
+	consolidatorWaits = stats.NewTimings("ConsolidatorWaits", "Time spent by the queries waiting for the result of an identical query by keyspace", "Keyspace")
+
 	exceedMemoryRowsLogger = logutil.NewThrottledLogger("ExceedMemoryRows", 1*time.Minute)
 
 	errorTransform errorTransformer = nullErrorTransformer{}
@@ -116,6 +120,13 @@ type Executor struct {
 	plans *PlanCache
 	epoch atomic.Uint32
 
+	// resultCache is set if the results of the cacheable plans can be served from vtgate.
+	resultCache *resultCache
+	// consolidator is set if the identical read-only queries running at the same time are merged.
+	consolidator *consolidator
+	// planWarmup is set if the most frequently executed queries are planned ahead of their execution.
+	planWarmup *planWarmup
+
 	normalize       bool
 	warnShardedOnly bool
 
@@ -137,6 +148,7 @@ var executorOnce sync.Once
 const pathQueryPlans = "/debug/query_plans"
 const pathScatterStats = "/debug/scatter_stats"
 const pathVSchema = "/debug/vschema"
+const pathConsolidations = "/debug/query_consolidations"
 
 type PlanCacheKey = theine.HashKey256
 type PlanCache = theine.Store[PlanCacheKey, *engine.Plan]
@@ -213,6 +225,7 @@ func NewExecutor(
 		servenv.HTTPHandle(pathQueryPlans, e)
 		servenv.HTTPHandle(pathScatterStats, e)
 		servenv.HTTPHandle(pathVSchema, e)
+		servenv.HTTPHandle(pathConsolidations, e)
 	})
 	return e
 }
@@ -1056,7 +1069,13 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 		e.vschema = vschema
 	}
 	e.vschemaStats = stats
+	if e.planWarmup != nil && vschema != nil {
+		e.warmPlans()
+	}
 	e.ClearPlans()
+	if e.resultCache != nil && vschema != nil {
+		e.resultCache.setVSchema(vschema)
+	}
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1105,15 +1124,9 @@ func (e *Executor) getPlan(
 		return nil, vterrors.VT13001("vschema not initialized")
 	}
 
-	vcursor.SetIgnoreMaxMemoryRows(sqlparser.IgnoreMaxMaxMemoryRowsDirective(stmt))
-	vcursor.SetConsolidator(sqlparser.Consolidator(stmt))
-	vcursor.SetWorkloadName(sqlparser.GetWorkloadNameFromStatement(stmt))
-	vcursor.UpdateForeignKeyChecksState(sqlparser.ForeignKeyChecksState(stmt))
-	priority, err := sqlparser.GetPriorityFromStatement(stmt)
-	if err != nil {
+	if err := setDirectives(vcursor, stmt); err != nil {
 		return nil, err
 	}
-	vcursor.SetPriority(priority)
 
 	setVarComment, err := prepareSetVarComment(vcursor, stmt)
 	if err != nil {
@@ -1145,12 +1158,33 @@ func (e *Executor) getPlan(
 		query = sqlparser.String(stmt)
 	}
 
+	if baseline := vcursor.vschema.PlanBaselines[query]; baseline != nil {
+		query, err = applyPlanBaseline(vcursor, query, stmt, baseline)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	logStats.SQL = comments.Leading + query + comments.Trailing
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVars)
 
 	return e.cacheAndBuildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, logStats)
 }
 
+// setDirectives sets the execution options of the vcursor given by the comment directives of the statement.
+func setDirectives(vcursor *vcursorImpl, stmt sqlparser.Statement) error {
+	vcursor.SetIgnoreMaxMemoryRows(sqlparser.IgnoreMaxMaxMemoryRowsDirective(stmt))
+	vcursor.SetConsolidator(sqlparser.Consolidator(stmt))
+	vcursor.SetWorkloadName(sqlparser.GetWorkloadNameFromStatement(stmt))
+	vcursor.UpdateForeignKeyChecksState(sqlparser.ForeignKeyChecksState(stmt))
+	priority, err := sqlparser.GetPriorityFromStatement(stmt)
+	if err != nil {
+		return err
+	}
+	vcursor.SetPriority(priority)
+	return nil
+}
+
 func (e *Executor) hashPlan(ctx context.Context, vcursor *vcursorImpl, query string) PlanCacheKey {
 	hasher := vthash.New256()
 	vcursor.keyForPlan(ctx, query, hasher)
@@ -1175,6 +1209,8 @@ func (e *Executor) buildStatement(
 
 	plan.Warnings = vcursor.warnings
 	vcursor.warnings = nil
+	plan.Deterministic = isDeterministic(stmt)
+	plan.ResultCache = isResultCacheable(stmt, plan, vcursor.vschema)
 
 	err = e.checkThatPlanIsValid(stmt, plan)
 	return plan, err
@@ -1196,7 +1232,11 @@ func (e *Executor) cacheAndBuildStatement(
 		var plan *engine.Plan
 		var err error
 		plan, logStats.CachedPlan, err = e.plans.GetOrLoad(planKey, e.epoch.Load(), func() (*engine.Plan, error) {
-			return e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds)
+			plan, err := e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds)
+			if err == nil && e.planWarmup != nil {
+				e.planWarmup.record(planKey, vcursor, plan)
+			}
+			return plan, err
 		})
 		return plan, err
 	}
@@ -1258,6 +1298,8 @@ func (e *Executor) ServeHTTP(response http.ResponseWriter, request *http.Request
 		returnAsJSON(response, e.VSchema())
 	case pathScatterStats:
 		e.WriteScatterStats(response)
+	case pathConsolidations:
+		e.writeConsolidations(response)
 	default:
 		response.WriteHeader(http.StatusNotFound)
 	}
@@ -1612,7 +1654,13 @@ func (e *Executor) Close() {
 		panic(err)
 	}
 	topo.Close()
+	if e.planWarmup != nil {
+		e.closePlanWarmup()
+	}
 	e.plans.Close()
+	if e.resultCache != nil {
+		e.resultCache.close()
+	}
 }
 
 func (e *Executor) environment() *vtenv.Environment {
@@ -33,6 +33,7 @@ import (
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	_ "vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
@@ -3140,3 +3141,139 @@ func TestDeleteMultiTable(t *testing.T) {
 	// delete from `user` where (`user`.id) in ::dml_vals - 1 shard
 	testQueryLog(t, executor, logChan, "TestExecute", "DELETE", "delete `user` from `user` join music on `user`.col = music.col where music.user_id = 1", 18)
 }
+
+func TestLoadDataLocalInfile(t *testing.T) {
+	mysqlServerLocalInfile = true
+	defer func() { mysqlServerLocalInfile = false }()
+	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
+
+	// The second row gets its id from the sequence.
+	sbclookup.SetResults([]*sqltypes.Result{{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(4),
+		}},
+		RowsAffected: 1,
+		InsertID:     4,
+	}})
+	ctx = engine.WithLocalInfileReader(ctx, func(fileName string, callback func([]byte) error) error {
+		assert.Equal(t, "users.txt", fileName)
+		return callback([]byte("1\tfoo\n\\N\tbar\n"))
+	})
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+		Autocommit:   true,
+	}
+	qr, err := executorExec(ctx, executor, session, "load data local infile 'users.txt' into table user (id, name)", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, qr.RowsAffected)
+	assert.False(t, session.InTransaction)
+
+	assertQueries(t, sbc1, []*querypb.BoundQuery{{
+		Sql: "insert ignore into `user`(id, `name`) values (:_Id_0, :_name_0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"_Id_0":   sqltypes.StringBindVariable("1"),
+			"_name_0": sqltypes.StringBindVariable("foo"),
+		},
+	}})
+	assertQueries(t, sbc2, []*querypb.BoundQuery{{
+		Sql: "insert ignore into `user`(id, `name`) values (:_Id_1, :_name_1)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"_Id_1":   sqltypes.Int64BindVariable(4),
+			"_name_1": sqltypes.StringBindVariable("bar"),
+		},
+	}})
+	assertQueries(t, sbclookup, []*querypb.BoundQuery{{
+		Sql:           "select next :n /* INT64 */ values from user_seq",
+		BindVariables: map[string]*querypb.BindVariable{"n": sqltypes.Int64BindVariable(1)},
+	}, {
+		Sql: "insert ignore into name_user_map(`name`, user_id) values (:name_0, :user_id_0), (:name_1, :user_id_1)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"name_0":    sqltypes.StringBindVariable("bar"),
+			"user_id_0": sqltypes.Uint64BindVariable(4),
+			"name_1":    sqltypes.StringBindVariable("foo"),
+			"user_id_1": sqltypes.Uint64BindVariable(1),
+		},
+	}, {
+		Sql: "select `name` from name_user_map where `name` = :name and user_id = :user_id",
+		BindVariables: map[string]*querypb.BindVariable{
+			"name":    sqltypes.StringBindVariable("foo"),
+			"user_id": sqltypes.Uint64BindVariable(1),
+		},
+	}, {
+		Sql: "select `name` from name_user_map where `name` = :name and user_id = :user_id",
+		BindVariables: map[string]*querypb.BindVariable{
+			"name":    sqltypes.StringBindVariable("bar"),
+			"user_id": sqltypes.Uint64BindVariable(4),
+		},
+	}})
+}
+
+func TestLoadDataLocalInfileBatches(t *testing.T) {
+	mysqlServerLocalInfile = true
+	defer func() { mysqlServerLocalInfile = false }()
+	// The rows all have the same user_id, so the second batch of 1000 rows
+	// only holds a duplicate of the rows of the first one.
+	var file strings.Builder
+	for i := 0; i <= 1000; i++ {
+		fmt.Fprintf(&file, "1\t%d\n", i)
+	}
+	load := func(ctx context.Context, content string) context.Context {
+		return engine.WithLocalInfileReader(ctx, func(_ string, callback func([]byte) error) error {
+			return callback([]byte(content))
+		})
+	}
+
+	query := "load data local infile 'extra.txt' into table user_extra (user_id, col)"
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		executor, sbc1, _, _, ctx := createExecutorEnv(t)
+		session := &vtgatepb.Session{
+			TargetString: "@primary",
+			Autocommit:   true,
+		}
+		_, err := executorExec(load(ctx, file.String()), executor, session, query, nil)
+		require.NoError(t, err)
+		assert.False(t, session.InTransaction)
+
+		// Duplicate keys are ignored, and both batches are committed together.
+		require.Len(t, sbc1.Queries, 2)
+		for _, query := range sbc1.Queries {
+			assert.True(t, strings.HasPrefix(query.Sql, "insert ignore into user_extra(user_id, col) values "), query.Sql)
+		}
+		assert.EqualValues(t, 1, sbc1.CommitCount.Load())
+		assert.EqualValues(t, 0, sbc1.RollbackCount.Load())
+	})
+
+	t.Run("error after the first batch", func(t *testing.T) {
+		executor, sbc1, _, _, ctx := createExecutorEnv(t)
+		session := &vtgatepb.Session{
+			TargetString: "@primary",
+			Autocommit:   true,
+		}
+		_, err := executorExec(load(ctx, file.String()+"1\n"), executor, session, query, nil)
+		require.ErrorContains(t, err, "VT03039: Row 1002 doesn't contain data for all columns")
+		assert.False(t, session.InTransaction)
+
+		// The first batch is rolled back.
+		assert.Len(t, sbc1.Queries, 1)
+		assert.EqualValues(t, 0, sbc1.CommitCount.Load())
+		assert.EqualValues(t, 1, sbc1.RollbackCount.Load())
+	})
+}
+
+func TestLoadDataLocalInfileDisabled(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+
+	ctx = engine.WithLocalInfileReader(ctx, func(string, func([]byte) error) error {
+		t.Fatal("the file must not be requested from the client")
+		return nil
+	})
+	session := &vtgatepb.Session{
+		TargetString: KsTestSharded + "@primary",
+		Autocommit:   true,
+	}
+	_, err := executorExec(ctx, executor, session, "load data local infile 'users.txt' into table user (id, name)", nil)
+	require.ErrorContains(t, err, "LOAD is not supported on sharded keyspace")
+	assert.Empty(t, sbc1.Queries)
+	assert.Empty(t, sbc2.Queries)
+}
@@ -755,6 +755,19 @@ func TestSelectLastInsertId(t *testing.T) {
 	utils.MustMatch(t, wantResult, result, "Mismatch")
 }
 
+// TestSelectStrToDate tests that the type of STR_TO_DATE is resolved from its format when the query is normalized.
+func TestSelectStrToDate(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	session := &vtgatepb.Session{TargetString: "@primary"}
+	executor.normalize = true
+
+	result, err := executorExec(ctx, executor, session, "select str_to_date('10:30', '%H:%i')", map[string]*querypb.BindVariable{})
+	require.NoError(t, err)
+	require.Len(t, result.Fields, 1)
+	assert.Equal(t, sqltypes.Time, result.Fields[0].Type)
+	assert.Equal(t, `[[TIME("10:30:00")]]`, fmt.Sprintf("%v", result.Rows))
+}
+
 func TestSelectSystemVariables(t *testing.T) {
 	executor, _, _, _, ctx := createExecutorEnv(t)
 
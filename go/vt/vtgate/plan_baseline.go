@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// applyPlanBaseline plans the statement with the comment directives and the target pinned for its
+// normalized query by a plan baseline of the vschema. It returns the query to plan.
+func applyPlanBaseline(vcursor *vcursorImpl, query string, stmt sqlparser.Statement, baseline *vschemapb.PlanBaseline) (string, error) {
+	if baseline.Target != "" {
+		keyspace, tabletType, destination, err := parseDestinationTarget(baseline.Target, vcursor.vschema)
+		if err != nil {
+			return "", vterrors.Wrapf(err, "invalid target of the plan baseline for query: %s", baseline.Query)
+		}
+		vcursor.keyspace = keyspace
+		vcursor.destination = destination
+		// the tablet type of the session is kept unless the target overrides it.
+		if strings.Contains(baseline.Target, "@") {
+			vcursor.tabletType = tabletType
+		}
+	}
+
+	if commented, ok := stmt.(sqlparser.Commented); ok && baseline.Comments != "" {
+		// the directives of the baseline come last, so that they take precedence over the ones of the query.
+		commented.SetComments(commented.GetParsedComments().Append(baseline.Comments))
+		if err := setDirectives(vcursor, stmt); err != nil {
+			return "", err
+		}
+		query = sqlparser.String(stmt)
+	}
+
+	planBaselinesApplied.Add(vcursor.keyspace, 1)
+	return query, nil
+}
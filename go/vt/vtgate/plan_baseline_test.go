@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestPlanBaselineComments(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+	executor.vschema.PlanBaselines = map[string]*vschemapb.PlanBaseline{
+		"select /*vt+ PRIORITY=10 */ * from music_user_map where id = :id /* INT64 */": {
+			Query:    "select /*vt+ PRIORITY=10 */ * from music_user_map where id = :id /* INT64 */",
+			Comments: "/*vt+ PRIORITY=33 */",
+		},
+	}
+
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Options: &querypb.ExecuteOptions{}})
+	vcursor, err := newVCursorImpl(session, makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, nil, false, pv)
+	require.NoError(t, err)
+
+	query := "select /*vt+ PRIORITY=10 */ * from music_user_map where id = 1"
+	stmt, reservedVars, err := parseAndValidateQuery(query, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	plan, err := executor.getPlan(ctx, vcursor, query, stmt, makeComments(""), map[string]*querypb.BindVariable{}, reservedVars, executor.normalize, logstats.NewLogStats(ctx, "Test", "", "", nil))
+	require.NoError(t, err)
+
+	// the directives of the baseline take precedence over the ones of the query.
+	assert.Equal(t, "select /*vt+ PRIORITY=10 */ /*vt+ PRIORITY=33 */ * from music_user_map where id = :id /* INT64 */", plan.Original)
+	assert.Equal(t, "33", session.Options.Priority)
+}
+
+func TestPlanBaselineTarget(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+	executor.vschema.PlanBaselines = map[string]*vschemapb.PlanBaseline{
+		"select id from `user`": {
+			Query:  "select id from `user`",
+			Target: KsTestSharded + ":-20",
+		},
+	}
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	_, err := executorExec(ctx, executor, session, "select id from user", nil)
+	require.NoError(t, err)
+	assert.Len(t, sbc1.Queries, 1)
+	assert.Empty(t, sbc2.Queries)
+
+	// a baseline for a keyspace that does not exist fails the query.
+	executor.vschema.PlanBaselines["select id from `user`"].Target = "unknown:-20"
+	_, err = executorExec(ctx, executor, session, "select id from user", nil)
+	assert.Error(t, err)
+}
@@ -165,6 +165,11 @@ func (e *Executor) newExecute(
 			err = execPlan(ctx, plan, vcursor, bindVars, execStart)
 		}
 
+		if err == nil && e.resultCache != nil && plan.Type != sqlparser.StmtSelect && !safeSession.InTransaction() {
+			// the changes of the statement are committed, the results read before them are stale
+			e.resultCache.written(plan.TablesUsed)
+		}
+
 		if err == nil || safeSession.InTransaction() {
 			return err
 		}
@@ -317,8 +322,22 @@ func (e *Executor) executePlan(
 	execStart time.Time,
 ) (*sqltypes.Result, error) {
 
+	cacheKey, cacheable := e.resultCacheKey(ctx, safeSession, plan, vcursor, bindVars)
+	if cacheable {
+		if qr, ok := e.resultCache.get(cacheKey); ok {
+			e.setLogStats(logStats, plan, vcursor, execStart, nil, qr)
+			return qr.ShallowCopy(), nil
+		}
+	}
+
 	// 4: Execute!
-	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	var qr *sqltypes.Result
+	var err error
+	if key, consolidate := e.consolidationKey(ctx, safeSession, plan, vcursor, bindVars); consolidate {
+		qr, err = e.executeConsolidated(ctx, plan, vcursor, bindVars, key)
+	} else {
+		qr, err = vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	}
 
 	// 5: Log and add statistics
 	e.setLogStats(logStats, plan, vcursor, execStart, err, qr)
@@ -327,6 +346,9 @@ func (e *Executor) executePlan(
 	if err != nil {
 		return nil, e.rollbackExecIfNeeded(ctx, safeSession, bindVars, logStats, err)
 	}
+	if cacheable {
+		e.resultCache.set(ctx, vcursor, plan, bindVars, cacheKey, qr.Copy())
+	}
 	return qr, nil
 }
 
@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// planWarmup keeps track of the most frequently executed queries of the plan cache and persists them
+// to a local file, so that their plans can be built in the background when vtgate starts and when
+// the VSchema changes, before the queries are received.
+type planWarmup struct {
+	path string
+	size int
+
+	mu sync.Mutex
+	// queries contains the query and target of the cached plans that can be warmed up.
+	queries map[PlanCacheKey]*warmupQuery
+	// pending contains the queries loaded from the file, until they are planned for the first VSchema.
+	pending []*warmupQuery
+	// warmed is closed once the queries loaded from the file have been planned.
+	warmed chan struct{}
+	// cancel stops the warm-up in progress.
+	cancel context.CancelFunc
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// warmupQuery is a normalized query that can be planned ahead of its execution.
+type warmupQuery struct {
+	Query  string `json:"query"`
+	Target string `json:"target"`
+}
+
+func newPlanWarmup(path string, size int) (*planWarmup, error) {
+	pw := &planWarmup{
+		path:    path,
+		size:    size,
+		queries: make(map[PlanCacheKey]*warmupQuery),
+		warmed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &pw.pending); err != nil {
+			return nil, vterrors.Wrapf(err, "bad plan warm-up file %s", path)
+		}
+	}
+	if len(pw.pending) == 0 {
+		close(pw.warmed)
+	}
+	return pw, nil
+}
+
+// isWarmed returns true once the queries loaded from the file have been planned.
+func (pw *planWarmup) isWarmed() bool {
+	select {
+	case <-pw.warmed:
+		return true
+	default:
+		return false
+	}
+}
+
+// record remembers the query and the target the plan was built for, unless it depends on the
+// session in a way that a plan built ahead of the query would not.
+func (pw *planWarmup) record(key PlanCacheKey, vcursor *vcursorImpl, plan *engine.Plan) {
+	if vcursor.destination != nil || hasBindVarNeeds(plan.BindVarNeeds) {
+		return
+	}
+	wq := &warmupQuery{
+		Query:  plan.Original,
+		Target: vcursor.keyspace + "@" + topoproto.TabletTypeLString(vcursor.tabletType),
+	}
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.queries[key] = wq
+}
+
+func hasBindVarNeeds(bvn *sqlparser.BindVarNeeds) bool {
+	return bvn != nil && (len(bvn.NeedFunctionResult) > 0 || len(bvn.NeedSystemVariable) > 0 || len(bvn.NeedUserDefinedVariables) > 0)
+}
+
+// topQueries returns the most frequently executed queries of the plan cache that can be warmed up.
+func (e *Executor) topQueries() []*warmupQuery {
+	pw := e.planWarmup
+	type countedQuery struct {
+		*warmupQuery
+		count uint64
+	}
+	var counted []countedQuery
+	cached := make(map[PlanCacheKey]bool)
+
+	pw.mu.Lock()
+	e.plans.Range(e.epoch.Load(), func(key PlanCacheKey, plan *engine.Plan) bool {
+		cached[key] = true
+		if wq, ok := pw.queries[key]; ok {
+			count, _, _, _, _, _ := plan.Stats()
+			counted = append(counted, countedQuery{warmupQuery: wq, count: count})
+		}
+		return true
+	})
+	for key := range pw.queries {
+		if !cached[key] {
+			delete(pw.queries, key)
+		}
+	}
+	pw.mu.Unlock()
+
+	sort.SliceStable(counted, func(i, j int) bool {
+		return counted[i].count > counted[j].count
+	})
+	queries := make([]*warmupQuery, 0, min(len(counted), pw.size))
+	for _, cq := range counted {
+		if len(queries) == pw.size {
+			break
+		}
+		queries = append(queries, cq.warmupQuery)
+	}
+	return queries
+}
+
+// persistTopQueries writes the most frequently executed queries to the file.
+func (e *Executor) persistTopQueries() error {
+	queries := e.topQueries()
+	if len(queries) == 0 {
+		// an empty plan cache, e.g. right after a VSchema change, must not erase the queries persisted before.
+		return nil
+	}
+	data, err := json.MarshalIndent(queries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(e.planWarmup.path), filepath.Base(e.planWarmup.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), e.planWarmup.path)
+}
+
+// warmPlans builds the plans of the queries in the background, after the VSchema changed. The queries
+// loaded from the file are planned for the first VSchema, the most frequently executed ones afterwards.
+// It must be called with e.mu held, before the plans of the previous VSchema are cleared.
+func (e *Executor) warmPlans() {
+	pw := e.planWarmup
+	pw.mu.Lock()
+	queries := pw.pending
+	pw.pending = nil
+	warmed := pw.warmed
+	if pw.cancel != nil {
+		pw.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	pw.cancel = cancel
+	pw.mu.Unlock()
+
+	startup := queries != nil
+	if !startup {
+		queries = e.topQueries()
+	}
+	if len(queries) == 0 {
+		return
+	}
+
+	pw.wg.Add(1)
+	go func() {
+		defer pw.wg.Done()
+		if startup {
+			defer close(warmed)
+		}
+		start := time.Now()
+		var planned int
+		for _, wq := range queries {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := e.warmPlan(ctx, wq); err != nil {
+				log.Warningf("Failed to warm up the plan of query %q for target %s: %v", wq.Query, wq.Target, err)
+				continue
+			}
+			planned++
+		}
+		log.Infof("Warmed up the plans of %d queries out of %d in %v", planned, len(queries), time.Since(start))
+	}()
+}
+
+// warmPlan builds and caches the plan of the normalized query, under the same key as the queries it
+// was normalized from.
+func (e *Executor) warmPlan(ctx context.Context, wq *warmupQuery) error {
+	safeSession := NewSafeSession(&vtgatepb.Session{TargetString: wq.Target, Autocommit: true})
+	vcursor, err := newVCursorImpl(safeSession, sqlparser.MarginComments{}, e, nil, e.vm, e.VSchema(), e.resolver.resolver, e.serv, e.warnShardedOnly, e.pv)
+	if err != nil {
+		return err
+	}
+	stmt, reservedVars, err := parseAndValidateQuery(wq.Query, e.env.Parser())
+	if err != nil {
+		return err
+	}
+	if !sqlparser.CachePlan(stmt) {
+		return nil
+	}
+	epoch := e.epoch.Load()
+	planKey := e.hashPlan(ctx, vcursor, wq.Query)
+	if _, ok := e.plans.Get(planKey, epoch); ok {
+		return nil
+	}
+	// the query was rewritten and normalized when it was first planned.
+	plan, err := e.buildStatement(ctx, vcursor, wq.Query, stmt, reservedVars, &sqlparser.BindVarNeeds{})
+	if err != nil {
+		return err
+	}
+	// the doorkeeper of the cache only admits the keys it has seen before.
+	if !e.plans.Set(planKey, plan, 0, epoch) {
+		e.plans.Set(planKey, plan, 0, epoch)
+	}
+	e.planWarmup.record(planKey, vcursor, plan)
+	return nil
+}
+
+// enablePlanWarmup persists the most frequently executed queries to the file every interval, and plans
+// them in the background when vtgate starts and when the VSchema changes.
+func (e *Executor) enablePlanWarmup(path string, size int, interval time.Duration) error {
+	pw, err := newPlanWarmup(path, size)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.planWarmup = pw
+	if e.vschema != nil {
+		e.warmPlans()
+	}
+
+	pw.wg.Add(1)
+	go func() {
+		defer pw.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pw.done:
+				return
+			case <-ticker.C:
+				if err := e.persistTopQueries(); err != nil {
+					log.Warningf("Failed to persist the queries to warm up in %s: %v", path, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// closePlanWarmup stops the warm-up and persists the most frequently executed queries a last time.
+func (e *Executor) closePlanWarmup() {
+	pw := e.planWarmup
+	close(pw.done)
+	pw.mu.Lock()
+	if pw.cancel != nil {
+		pw.cancel()
+	}
+	pw.mu.Unlock()
+	pw.wg.Wait()
+	if err := e.persistTopQueries(); err != nil {
+		log.Warningf("Failed to persist the queries to warm up in %s: %v", pw.path, err)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestPlanWarmup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+	require.NoError(t, executor.enablePlanWarmup(path, 2, time.Hour))
+	assert.True(t, executor.planWarmup.isWarmed())
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	for i := 0; i < 3; i++ {
+		_, err := executorExec(ctx, executor, session, "select id from music_user_map where id = 1", nil)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := executorExec(ctx, executor, session, "select id from `user` where id = 1", nil)
+		require.NoError(t, err)
+	}
+	// the least executed query is not persisted, nor the one that depends on the session.
+	_, err := executorExec(ctx, executor, session, "select id from main1", nil)
+	require.NoError(t, err)
+	_, err = executorExec(ctx, executor, session, "select database() from main1", nil)
+	require.NoError(t, err)
+
+	want := []*warmupQuery{
+		{Query: "select id from music_user_map where id = :id /* INT64 */", Target: "@primary"},
+		{Query: "select id from `user` where id = :id /* INT64 */", Target: "@primary"},
+	}
+	require.NoError(t, executor.persistTopQueries())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got []*warmupQuery
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestPlanWarmupStartup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+	want := []*warmupQuery{
+		{Query: "select id from music_user_map where id = :id /* INT64 */", Target: "@primary"},
+		{Query: "select id from `user` where id = :id /* INT64 */", Target: "@primary"},
+	}
+	data, err := json.Marshal(want)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	// the persisted queries are planned before they are received.
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+	require.NoError(t, executor.enablePlanWarmup(path, 2, time.Hour))
+	assert.Eventually(t, executor.planWarmup.isWarmed, 5*time.Second, 10*time.Millisecond)
+	assertCachedQueries(t, executor, want)
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	_, err = executorExec(ctx, executor, session, "select id from music_user_map where id = 2", nil)
+	require.NoError(t, err)
+	var planned *engine.Plan
+	executor.ForEachPlan(func(plan *engine.Plan) bool {
+		if plan.Original == want[0].Query {
+			planned = plan
+		}
+		return true
+	})
+	require.NotNil(t, planned)
+	assert.EqualValues(t, 1, planned.ExecCount)
+
+	// the plans are warmed up again when the VSchema changes.
+	executor.SaveVSchema(executor.VSchema(), executor.VSchemaStats())
+	assert.Eventually(t, func() bool {
+		return len(executor.debugCacheEntries()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assertCachedQueries(t, executor, want)
+}
+
+func assertCachedQueries(t *testing.T, executor *Executor, want []*warmupQuery) {
+	t.Helper()
+	for _, wq := range want {
+		vcursor, err := newVCursorImpl(NewSafeSession(&vtgatepb.Session{TargetString: wq.Target}), makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, nil, false, executor.pv)
+		require.NoError(t, err)
+		_, ok := executor.plans.Get(executor.hashPlan(context.Background(), vcursor, wq.Query), executor.epoch.Load())
+		assert.Truef(t, ok, "plan not cached for query: %s", wq.Query)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/vschemawrapper"
@@ -194,7 +195,7 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	case *sqlparser.Set:
 		return buildSetPlan(stmt, vschema)
 	case *sqlparser.Load:
-		return buildLoadPlan(query, vschema)
+		return buildLoadPlan(stmt, query, vschema)
 	case sqlparser.DBDDLStatement:
 		return buildRoutePlan(stmt, reservedVars, vschema, buildDBDDLPlan)
 	case *sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback,
@@ -306,13 +307,17 @@ func buildDBDDLPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema
 	return nil, vterrors.VT13001(fmt.Sprintf("database DDL not recognized: %s", sqlparser.String(dbDDLstmt)))
 }
 
-func buildLoadPlan(query string, vschema plancontext.VSchema) (*planResult, error) {
+func buildLoadPlan(stmt *sqlparser.Load, query string, vschema plancontext.VSchema) (*planResult, error) {
+	destination := vschema.Destination()
+	if stmt.Local && destination == nil && vschema.IsLocalInfileEnabled() {
+		return buildLoadLocalPlan(stmt, vschema)
+	}
+
 	keyspace, err := vschema.DefaultKeyspace()
 	if err != nil {
 		return nil, err
 	}
 
-	destination := vschema.Destination()
 	if destination == nil {
 		if err := vschema.ErrorIfShardedF(keyspace, "LOAD", "LOAD is not supported on sharded keyspace"); err != nil {
 			return nil, err
@@ -329,6 +334,85 @@ func buildLoadPlan(query string, vschema plancontext.VSchema) (*planResult, erro
 	}), nil
 }
 
+// buildLoadLocalPlan plans LOAD DATA LOCAL INFILE. The file is parsed by vtgate
+// and the rows are inserted in batches, which works for any keyspace.
+// It is only used when vtgate is allowed to read local files from the client.
+func buildLoadLocalPlan(stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	vTbl, _, tabletType, _, err := vschema.FindTable(stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+	if tabletType != topodatapb.TabletType_PRIMARY {
+		return nil, vterrors.VT09002("LOAD DATA")
+	}
+
+	columns := stmt.Columns
+	if len(columns) == 0 {
+		if !vTbl.ColumnListAuthoritative {
+			return nil, vterrors.VT09004()
+		}
+		for _, col := range vTbl.Columns {
+			columns = append(columns, col.Name)
+		}
+	}
+
+	format := engine.LoadFormat{
+		FieldsTerminatedBy: "\t",
+		FieldsEscapedBy:    "\\",
+		LinesTerminatedBy:  "\n",
+	}
+	if fields := stmt.Fields; fields != nil {
+		setLoadOption(&format.FieldsTerminatedBy, fields.TerminatedBy)
+		setLoadOption(&format.FieldsEnclosedBy, fields.EnclosedBy)
+		setLoadOption(&format.FieldsEscapedBy, fields.EscapedBy)
+	}
+	if lines := stmt.Lines; lines != nil {
+		setLoadOption(&format.LinesStartingBy, lines.StartingBy)
+		setLoadOption(&format.LinesTerminatedBy, lines.TerminatedBy)
+	}
+	if len(format.FieldsEnclosedBy) > 1 || len(format.FieldsEscapedBy) > 1 {
+		return nil, vterrors.VT03041()
+	}
+	if format.FieldsTerminatedBy == "" || format.LinesTerminatedBy == "" {
+		return nil, vterrors.VT12001("LOAD DATA with an empty FIELDS TERMINATED BY or LINES TERMINATED BY")
+	}
+
+	var ignoreLines int
+	if stmt.IgnoreLines != nil {
+		ignoreLines, err = strconv.Atoi(stmt.IgnoreLines.Val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The server can't stop the client from sending the rest of a LOCAL
+	// file, so like MySQL, duplicate keys are handled as with IGNORE unless
+	// REPLACE is given.
+	action := sqlparser.InsertStr + " " + sqlparser.IgnoreStr
+	if stmt.Action == sqlparser.ReplaceAct {
+		action = sqlparser.ReplaceStr + " "
+	}
+	tblName := sqlparser.TableName{Name: vTbl.Name, Qualifier: sqlparser.NewIdentifierCS(vTbl.Keyspace.Name)}
+	insert := fmt.Sprintf("%sinto %s%s", action, sqlparser.String(tblName), sqlparser.String(columns))
+
+	return newPlanResult(&engine.Load{
+		Keyspace:    vTbl.Keyspace,
+		TableName:   vTbl.Name.String(),
+		FileName:    stmt.FileName.Val,
+		Insert:      insert,
+		Columns:     len(columns),
+		Format:      format,
+		IgnoreLines: ignoreLines,
+	}, singleTable(vTbl.Keyspace.Name, vTbl.Name.String())), nil
+}
+
+// setLoadOption overrides a default LOAD DATA option if it is given.
+func setLoadOption(option *string, lit *sqlparser.Literal) {
+	if lit != nil {
+		*option = lit.Val
+	}
+}
+
 func buildVSchemaDDLPlan(stmt *sqlparser.AlterVschema, vschema plancontext.VSchema) (*planResult, error) {
 	_, keyspace, _, err := vschema.TargetDestination(stmt.Table.Qualifier.String())
 	if err != nil {
@@ -18,6 +18,7 @@ package planbuilder
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -63,6 +64,8 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformAggregator(ctx, op)
 	case *operators.Distinct:
 		return transformDistinct(ctx, op)
+	case *operators.Window:
+		return transformWindow(ctx, op)
 	case *operators.FkCascade:
 		return transformFkCascade(ctx, op)
 	case *operators.FkVerify:
@@ -77,6 +80,10 @@ func transformToPrimitive(ctx *plancontext.PlanningContext, op operators.Operato
 		return transformSequential(ctx, op)
 	case *operators.DMLWithInput:
 		return transformDMLWithInput(ctx, op)
+	case *operators.RecurseCTE:
+		return transformRecurseCTE(ctx, op)
+	case *operators.JSONTable:
+		return transformJSONTable(ctx, op)
 	}
 
 	return nil, vterrors.VT13001(fmt.Sprintf("unknown type encountered: %T (transformToPrimitive)", op))
@@ -184,6 +191,14 @@ func transformInsertionSelection(ctx *plancontext.PlanningContext, op *operators
 	}
 
 	eins.Input = selectionPlan
+
+	if op.Replace != nil {
+		eins.Replace, err = transformToPrimitive(ctx, op.Replace)
+		if err != nil {
+			return nil, err
+		}
+		eins.ReplaceKeys = op.ReplaceKeys
+	}
 	return eins, nil
 }
 
@@ -257,6 +272,7 @@ func transformSubQuery(ctx *plancontext.PlanningContext, op *operators.SubQuery)
 		Left:  outer,
 		Right: inner,
 		Vars:  op.Vars,
+		Anti:  op.FilterType == opcode.PulloutNotExists,
 	}, nil
 }
 
@@ -292,9 +308,6 @@ func transformFkVerify(ctx *plancontext.PlanningContext, fkv *operators.FkVerify
 }
 
 func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggregator) (engine.Primitive, error) {
-	if op.WithRollup {
-		return nil, vterrors.VT12001("GROUP BY WITH ROLLUP not supported for sharded queries")
-	}
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
 		return nil, err
@@ -311,6 +324,10 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 			message := fmt.Sprintf("Aggregate UDF '%s' must be pushed down to MySQL", sqlparser.String(aggr.Original.Expr))
 			return nil, vterrors.VT12001(message)
 		}
+		if op.WithRollup && aggr.OpCode.IsDistinct() {
+			// the distinct aggregators expect their input sorted within the group, which is not true for the super-aggregate rows
+			return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s' with GROUP BY WITH ROLLUP", sqlparser.String(aggr.Original)))
+		}
 
 		aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
 		aggrParam.Func = aggr.Func
@@ -321,6 +338,22 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		if aggr.HashDistinct {
+			aggrParam.DistinctCols = distinctCols(ctx, aggr)
+		}
+		switch {
+		case aggr.OpCode == opcode.AggregateGroupConcat && len(aggr.ArgOffsets) > 0:
+			aggrParam.ArgCols = aggr.ArgOffsets
+			aggrParam.GroupConcatOrderBy = groupConcatOrderBy(ctx, aggr)
+		case aggr.OpCode == opcode.AggregateJSONObjectAgg:
+			aggrParam.ArgCols = aggr.ArgOffsets
+		}
+		if aggr.OpCode == opcode.AggregateGrouping {
+			aggrParam.GroupingKeys, err = groupingKeys(ctx, op, aggr)
+			if err != nil {
+				return nil, err
+			}
+		}
 		aggregates = append(aggregates, aggrParam)
 	}
 
@@ -346,11 +379,66 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 	return &engine.OrderedAggregate{
 		Aggregates:          aggregates,
 		GroupByKeys:         groupByKeys,
+		WithRollup:          op.WithRollup,
 		TruncateColumnCount: op.ResultColumns,
 		Input:               src,
 	}, nil
 }
 
+// groupingKeys returns the index in the grouping of every argument of a GROUPING() function
+// distinctCols returns the columns used to check if the arguments of a hashed distinct aggregation have already been seen
+func distinctCols(ctx *plancontext.PlanningContext, aggr operators.Aggr) []engine.CheckCol {
+	var cols []engine.CheckCol
+	for idx, arg := range aggr.Func.GetArgs() {
+		var wsCol *int
+		if wsOffset := aggr.ArgWSOffsets[idx]; wsOffset != -1 {
+			wsCol = &wsOffset
+		}
+		typ, _ := ctx.TypeForExpr(arg)
+		cols = append(cols, engine.CheckCol{
+			Col:          aggr.ArgOffsets[idx],
+			WsCol:        wsCol,
+			Type:         typ,
+			CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return cols
+}
+
+// groupConcatOrderBy returns the ordering used to sort the rows of a GROUP_CONCAT evaluated at the vtgate level
+func groupConcatOrderBy(ctx *plancontext.PlanningContext, aggr operators.Aggr) evalengine.Comparison {
+	var cmp evalengine.Comparison
+	for idx, order := range aggr.GroupConcatOrderBy() {
+		typ, _ := ctx.TypeForExpr(order.Expr)
+		cmp = append(cmp, evalengine.OrderByParams{
+			Col:             aggr.OrderOffsets[idx],
+			WeightStringCol: aggr.OrderWSOffsets[idx],
+			Desc:            order.Direction == sqlparser.DescOrder,
+			Type:            typ,
+			CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return cmp
+}
+
+func groupingKeys(ctx *plancontext.PlanningContext, op *operators.Aggregator, aggr operators.Aggr) ([]int, error) {
+	grouping, ok := aggr.Original.Expr.(*sqlparser.GroupingFunc)
+	if !ok {
+		return nil, vterrors.VT13001(fmt.Sprintf("expected GROUPING function, got: %s", sqlparser.String(aggr.Original.Expr)))
+	}
+	keys := make([]int, 0, len(grouping.Exprs))
+	for idx, arg := range grouping.Exprs {
+		offset := slices.IndexFunc(op.Grouping, func(gb operators.GroupBy) bool {
+			return ctx.SemTable.EqualsExprWithDeps(gb.Inner, arg)
+		})
+		if offset < 0 {
+			return nil, vterrors.VT03042(idx + 1)
+		}
+		keys = append(keys, offset)
+	}
+	return keys, nil
+}
+
 func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct) (engine.Primitive, error) {
 	src, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -364,6 +452,212 @@ func transformDistinct(ctx *plancontext.PlanningContext, op *operators.Distinct)
 	}, nil
 }
 
+func transformRecurseCTE(ctx *plancontext.PlanningContext, op *operators.RecurseCTE) (engine.Primitive, error) {
+	seed, err := transformToPrimitive(ctx, op.Seed)
+	if err != nil {
+		return nil, err
+	}
+	term, err := transformToPrimitive(ctx, op.Term)
+	if err != nil {
+		return nil, err
+	}
+	return &engine.RecurseCTE{
+		Seed:      seed,
+		Term:      term,
+		Vars:      op.Vars,
+		CheckCols: op.CheckCols,
+	}, nil
+}
+
+func transformWindow(ctx *plancontext.PlanningContext, op *operators.Window) (engine.Primitive, error) {
+	src, err := transformToPrimitive(ctx, op.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	frame, err := createWindowFrame(op.Spec.FrameClause)
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []*engine.WindowFunc
+	for _, fn := range op.Functions {
+		wf, err := createWindowFunc(ctx, fn, frame)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, wf)
+	}
+
+	keys := func(in []operators.GroupBy) []*engine.GroupByParams {
+		return slice.Map(in, func(gb operators.GroupBy) *engine.GroupByParams {
+			typ, _ := ctx.TypeForExpr(gb.Inner)
+			return &engine.GroupByParams{
+				KeyCol:          gb.ColOffset,
+				WeightStringCol: gb.WSOffset,
+				Expr:            gb.Inner,
+				Type:            typ,
+				CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
+			}
+		})
+	}
+
+	return &engine.Window{
+		PartitionBy: keys(op.Partition),
+		OrderBy:     keys(op.Order),
+		Functions:   functions,
+		Cols:        op.Offsets,
+		Input:       src,
+	}, nil
+}
+
+func createWindowFunc(ctx *plancontext.PlanningContext, fn operators.WindowFunc, frame *engine.WindowFrame) (*engine.WindowFunc, error) {
+	wf := &engine.WindowFunc{
+		Col:          fn.ArgOffset,
+		DefaultCol:   fn.DefaultOffset,
+		Alias:        fn.Original.ColumnName(),
+		CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+	}
+
+	unsupported := func() (*engine.WindowFunc, error) {
+		return nil, vterrors.VT12001(fmt.Sprintf("window function '%s' in a sharded query", sqlparser.String(fn.Func)))
+	}
+
+	var err error
+	var arg sqlparser.Expr
+	switch f := fn.Func.(type) {
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch f.Type {
+		case sqlparser.RowNumberExprType:
+			wf.Opcode = opcode.WindowRowNumber
+		case sqlparser.RankExprType:
+			wf.Opcode = opcode.WindowRank
+		case sqlparser.DenseRankExprType:
+			wf.Opcode = opcode.WindowDenseRank
+		case sqlparser.PercentRankExprType:
+			wf.Opcode = opcode.WindowPercentRank
+		case sqlparser.CumeDistExprType:
+			wf.Opcode = opcode.WindowCumeDist
+		}
+		return wf, nil
+	case *sqlparser.NtileExpr:
+		wf.Opcode = opcode.WindowNtile
+		wf.N, err = windowFuncLiteral(f.N, 0, true)
+	case *sqlparser.LagLeadExpr:
+		wf.Opcode = opcode.WindowLag
+		if f.Type == sqlparser.LeadExprType {
+			wf.Opcode = opcode.WindowLead
+		}
+		wf.N, err = windowFuncLiteral(f.N, 1, false)
+		arg = f.Expr
+	case *sqlparser.FirstOrLastValueExpr:
+		wf.Opcode = opcode.WindowFirstValue
+		if f.Type == sqlparser.LastValueExprType {
+			wf.Opcode = opcode.WindowLastValue
+		}
+		wf.Frame = frame
+		arg = f.Expr
+	case *sqlparser.NTHValueExpr:
+		if f.FromFirstLastClause != nil && f.FromFirstLastClause.Type == sqlparser.FromLastType {
+			return unsupported()
+		}
+		wf.Opcode = opcode.WindowNthValue
+		wf.N, err = windowFuncLiteral(f.N, 0, true)
+		wf.Frame = frame
+		arg = f.Expr
+	case *sqlparser.CountStar:
+		wf.Opcode = opcode.WindowAggregate
+		wf.Aggregate = opcode.AggregateCountStar
+		wf.Frame = frame
+		return wf, nil
+	case sqlparser.AggrFunc:
+		if distinct, ok := f.(sqlparser.DistinctableAggr); ok && distinct.IsDistinct() {
+			return unsupported()
+		}
+		code := opcode.SupportedAggregates[f.AggrName()]
+		switch code {
+		case opcode.AggregateCount, opcode.AggregateSum, opcode.AggregateMin, opcode.AggregateMax, opcode.AggregateAvg:
+		default:
+			return unsupported()
+		}
+		wf.Opcode = opcode.WindowAggregate
+		wf.Aggregate = code
+		wf.Frame = frame
+		arg = f.GetArg()
+	default:
+		return unsupported()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if arg != nil {
+		wf.Type, _ = ctx.TypeForExpr(arg)
+	}
+	return wf, nil
+}
+
+// windowFuncLiteral returns the value of an integer literal argument to a window function,
+// or the default value if the argument is missing. Zero is only accepted if positive is false.
+func windowFuncLiteral(expr sqlparser.Expr, def int64, positive bool) (int64, error) {
+	if expr == nil {
+		return def, nil
+	}
+	lit, ok := expr.(*sqlparser.Literal)
+	if !ok || lit.Type != sqlparser.IntVal {
+		return 0, vterrors.VT12001(fmt.Sprintf("non-literal window function argument '%s' in a sharded query", sqlparser.String(expr)))
+	}
+	n, err := strconv.ParseInt(lit.Val, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || (n == 0 && positive) {
+		return 0, vterrors.VT03025(sqlparser.String(expr))
+	}
+	return n, nil
+}
+
+func createWindowFrame(frame *sqlparser.FrameClause) (*engine.WindowFrame, error) {
+	if frame == nil {
+		return nil, nil
+	}
+	rows := frame.Unit == sqlparser.FrameRowsType
+	bound := func(fp *sqlparser.FramePoint) (engine.FrameBound, error) {
+		if fp == nil {
+			return engine.FrameBound{Type: engine.CurrentRow}, nil
+		}
+		switch fp.Type {
+		case sqlparser.CurrentRowType:
+			return engine.FrameBound{Type: engine.CurrentRow}, nil
+		case sqlparser.UnboundedPrecedingType:
+			return engine.FrameBound{Type: engine.UnboundedPreceding}, nil
+		case sqlparser.UnboundedFollowingType:
+			return engine.FrameBound{Type: engine.UnboundedFollowing}, nil
+		}
+		if !rows {
+			return engine.FrameBound{}, vterrors.VT12001(fmt.Sprintf("RANGE frame with an offset in a sharded query: %s", sqlparser.String(frame)))
+		}
+		n, err := windowFuncLiteral(fp.Expr, 0, false)
+		if err != nil {
+			return engine.FrameBound{}, err
+		}
+		if fp.Type == sqlparser.ExprPrecedingType {
+			return engine.FrameBound{Type: engine.Preceding, N: n}, nil
+		}
+		return engine.FrameBound{Type: engine.Following, N: n}, nil
+	}
+
+	start, err := bound(frame.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(frame.End)
+	if err != nil {
+		return nil, err
+	}
+	return &engine.WindowFrame{Rows: rows, Start: start, End: end}, nil
+}
+
 func transformOrdering(ctx *plancontext.PlanningContext, op *operators.Ordering) (engine.Primitive, error) {
 	plan, err := transformToPrimitive(ctx, op.Source)
 	if err != nil {
@@ -571,6 +865,10 @@ func transformRoutePlan(ctx *plancontext.PlanningContext, op *operators.Route) (
 		stmtWithComments.SetComments(comments)
 	}
 
+	if err := checkFunctionsInKeyspace(ctx, stmt, op.Routing.Keyspace()); err != nil {
+		return nil, err
+	}
+
 	hints := getHints(op.Comments)
 	switch stmt := stmt.(type) {
 	case sqlparser.SelectStatement:
@@ -597,6 +895,16 @@ func buildRoutePrimitive(ctx *plancontext.PlanningContext, op *operators.Route,
 		return nil, err
 	}
 
+	if _, isDual := op.Routing.(*operators.DualRouting); isDual {
+		// queries that don't read any table are sent to the keyspace declaring the functions they call
+		if fn := ctx.FunctionOutsideKeyspace(stmt, eroute.Keyspace); fn != nil {
+			eroute.Keyspace = fn.Keyspace
+		}
+		if err := checkFunctionsInKeyspace(ctx, stmt, eroute.Keyspace); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, order := range op.Ordering {
 		typ, _ := ctx.TypeForExpr(order.AST)
 		eroute.OrderBy = append(eroute.OrderBy, evalengine.OrderByParams{
@@ -618,6 +926,16 @@ func buildRoutePrimitive(ctx *plancontext.PlanningContext, op *operators.Route,
 	return res, nil
 }
 
+// checkFunctionsInKeyspace fails if the statement calls a function declared in the VSchema
+// that does not exist in the keyspace it is sent to.
+func checkFunctionsInKeyspace(ctx *plancontext.PlanningContext, stmt sqlparser.Statement, keyspace *vindexes.Keyspace) error {
+	fn := ctx.FunctionOutsideKeyspace(stmt, keyspace)
+	if fn == nil {
+		return nil
+	}
+	return vterrors.VT12001(fmt.Sprintf("calling function %s of keyspace %s in a query sent to keyspace %s", fn.Name.String(), fn.Keyspace.Name, keyspace.Name))
+}
+
 func buildInsertPrimitive(
 	rb *operators.Route, op operators.Operator, stmt *sqlparser.Insert,
 	hints *queryHints,
@@ -735,15 +1053,57 @@ func buildUpdatePrimitive(
 	if upd.VerifyAll {
 		stmt.SetComments(stmt.GetParsedComments().SetMySQLSetVarValue(sysvars.ForeignKeyChecks, "OFF"))
 	}
+	var move *engine.RowMove
+	if upd.MoveRows {
+		upd.OwnedVindexQuery.From = stmt.GetFrom()
+		upd.OwnedVindexQuery.Where = stmt.Where
+		vQuery = sqlparser.String(upd.OwnedVindexQuery)
+		vindexes = upd.Target.VTable.ColumnVindexes
+		if upd.OwnedVindexQuery.Limit != nil && len(upd.OwnedVindexQuery.OrderBy) == 0 {
+			return nil, vterrors.VT12001("Vindex update should have ORDER BY clause when using LIMIT")
+		}
+		move = buildRowMove(upd, stmt)
+	}
 	_ = updateSelectedVindexPredicate(rb.Routing)
 	edml := createDMLPrimitive(ctx, rb, hints, upd.Target.VTable, generateQuery(stmt), vindexes, vQuery)
 
 	return &engine.Update{
 		DML:                 edml,
 		ChangedVindexValues: upd.ChangedVindexValues,
+		Move:                move,
 	}, nil
 }
 
+// buildRowMove creates the statements that move the rows whose primary vindex columns are updated:
+// a delete of the rows selected by the update, and the template of the inserts in their new shards
+func buildRowMove(upd *operators.Update, stmt *sqlparser.Update) *engine.RowMove {
+	del := &sqlparser.Delete{
+		Comments:   stmt.Comments,
+		TableExprs: stmt.TableExprs,
+		Where:      stmt.Where,
+		OrderBy:    stmt.OrderBy,
+		Limit:      stmt.Limit,
+	}
+	if len(stmt.TableExprs) > 1 {
+		del.Targets = sqlparser.TableNames{upd.Target.Name}
+	} else if _, isAliased := stmt.TableExprs[0].(*sqlparser.AliasedTableExpr); !isAliased {
+		del.Targets = sqlparser.TableNames{upd.Target.Name}
+	}
+
+	columns := make([]string, 0, len(upd.Assignments))
+	for _, assignment := range upd.Assignments {
+		columns = append(columns, assignment.Name.Name.String())
+	}
+	return &engine.RowMove{
+		Columns:     columns,
+		DeleteQuery: generateQuery(del),
+		Insert: &sqlparser.Insert{
+			Comments: stmt.Comments,
+			Table:    sqlparser.NewAliasedTableExpr(sqlparser.NewTableName(upd.Target.VTable.Name.String()), ""),
+		},
+	}
+}
+
 func buildDeletePrimitive(ctx *plancontext.PlanningContext, rb *operators.Route, dmlOp operators.Operator, stmt *sqlparser.Delete, hints *queryHints) (engine.Primitive, error) {
 	del := dmlOp.(*operators.Delete)
 
@@ -904,24 +1264,40 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 		return nil, err
 	}
 
-	if len(op.LHSKeys) != 1 {
-		return nil, vterrors.VT12001("hash joins must have exactly one join predicate")
+	if len(op.LHSKeys) == 0 {
+		return nil, vterrors.VT12001("hash joins must have at least one equality join predicate")
 	}
 
 	joinOp := engine.InnerJoin
-	if op.LeftJoin {
+	switch {
+	case op.LeftJoin && op.RightJoin:
+		joinOp = engine.FullOuterJoin
+	case op.LeftJoin:
 		joinOp = engine.LeftJoin
+	case op.RightJoin:
+		joinOp = engine.RightJoin
 	}
 
 	var missingTypes []string
+	var comparisonTypes []evalengine.Type
+	for _, cmp := range op.JoinComparisons {
+		ltyp, lFound := ctx.TypeForExpr(cmp.LHS)
+		if !lFound {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.LHS))
+		}
+		rtyp, rFound := ctx.TypeForExpr(cmp.RHS)
+		if !rFound {
+			missingTypes = append(missingTypes, sqlparser.String(cmp.RHS))
+		}
+		if !lFound || !rFound {
+			continue
+		}
 
-	ltyp, found := ctx.TypeForExpr(op.JoinComparisons[0].LHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].LHS))
-	}
-	rtyp, found := ctx.TypeForExpr(op.JoinComparisons[0].RHS)
-	if !found {
-		missingTypes = append(missingTypes, sqlparser.String(op.JoinComparisons[0].RHS))
+		comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
+		if err != nil {
+			return nil, err
+		}
+		comparisonTypes = append(comparisonTypes, comparisonType)
 	}
 
 	if len(missingTypes) > 0 {
@@ -929,23 +1305,18 @@ func transformHashJoin(ctx *plancontext.PlanningContext, op *operators.HashJoin)
 			fmt.Sprintf("missing type information for [%s]", strings.Join(missingTypes, ", ")))
 	}
 
-	comparisonType, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
-	if err != nil {
-		return nil, err
-	}
-
 	return &engine.HashJoin{
-		Left:           lhs,
-		Right:          rhs,
-		Opcode:         joinOp,
-		Cols:           op.ColumnOffsets,
-		LHSKey:         op.LHSKeys[0],
-		RHSKey:         op.RHSKeys[0],
-		ASTPred:        op.JoinPredicate(),
-		Collation:      comparisonType.Collation(),
-		ComparisonType: comparisonType.Type(),
-		CollationEnv:   ctx.VSchema.Environment().CollationEnv(),
-		Values:         comparisonType.Values(),
+		Left:            lhs,
+		Right:           rhs,
+		Opcode:          joinOp,
+		Cols:            op.ColumnOffsets,
+		LHSKeys:         op.LHSKeys,
+		RHSKeys:         op.RHSKeys,
+		ASTPred:         op.JoinPredicate(),
+		ComparisonTypes: comparisonTypes,
+		Residual:        op.Residual,
+		ResidualCols:    op.ResidualCols,
+		CollationEnv:    ctx.VSchema.Environment().CollationEnv(),
 	}, nil
 }
 
@@ -956,9 +1327,10 @@ func transformVindexPlan(ctx *plancontext.PlanningContext, op *operators.Vindex)
 	}
 
 	expr, err := evalengine.Translate(op.Value, &evalengine.Config{
-		Collation:   ctx.SemTable.Collation,
-		ResolveType: ctx.TypeForExpr,
-		Environment: ctx.VSchema.Environment(),
+		Collation:       ctx.SemTable.Collation,
+		ResolveType:     ctx.TypeForExpr,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveDeterministicFunction,
 	})
 	if err != nil {
 		return nil, err
@@ -981,6 +1353,39 @@ func transformVindexPlan(ctx *plancontext.PlanningContext, op *operators.Vindex)
 	return prim, nil
 }
 
+func transformJSONTable(ctx *plancontext.PlanningContext, op *operators.JSONTable) (engine.Primitive, error) {
+	cfg := &evalengine.Config{
+		Collation:       ctx.SemTable.Collation,
+		ResolveType:     ctx.TypeForExpr,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
+	}
+	doc, err := evalengine.Translate(op.Expr.Expr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	table, err := evalengine.TranslateJSONTable(op.Expr, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	prim := &engine.JSONTable{
+		Doc:    doc,
+		ASTDoc: op.Expr.Expr,
+		Table:  table,
+	}
+	for _, col := range op.Columns {
+		offset := slices.IndexFunc(table.Columns, func(column evalengine.JSONTableColumn) bool {
+			return col.Name.EqualString(column.Name)
+		})
+		if offset < 0 {
+			return nil, vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the JSON_TABLE", sqlparser.String(col)))
+		}
+		prim.Cols = append(prim.Cols, offset)
+	}
+	return prim, nil
+}
+
 func generateQuery(statement sqlparser.Statement) string {
 	buf := sqlparser.NewTrackedBuffer(dmlFormatter)
 	statement.Format(buf)
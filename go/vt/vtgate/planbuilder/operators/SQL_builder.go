@@ -218,7 +218,7 @@ var _ FromStatement = (*sqlparser.Select)(nil)
 var _ FromStatement = (*sqlparser.Update)(nil)
 var _ FromStatement = (*sqlparser.Delete)(nil)
 
-func (qb *queryBuilder) joinWith(other *queryBuilder, onCondition sqlparser.Expr, joinType sqlparser.JoinType) {
+func (qb *queryBuilder) joinWith(other *queryBuilder, onCondition sqlparser.Expr, joinType sqlparser.JoinType, lateral bool) {
 	stmt := qb.stmt.(FromStatement)
 	otherStmt := other.stmt.(FromStatement)
 
@@ -230,8 +230,9 @@ func (qb *queryBuilder) joinWith(other *queryBuilder, onCondition sqlparser.Expr
 	qb.mergeWhereClauses(stmt, otherStmt)
 
 	var newFromClause []sqlparser.TableExpr
-	switch joinType {
-	case sqlparser.NormalJoinType:
+	switch {
+	case joinType == sqlparser.NormalJoinType && !lateral:
+		// a lateral derived table has to come after the tables it uses, so it is kept in an explicit join
 		newFromClause = append(stmt.GetFrom(), otherStmt.GetFrom()...)
 		qb.addPredicate(onCondition)
 	default:
@@ -370,6 +371,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 	switch op := op.(type) {
 	case *Table:
 		buildTable(op, qb)
+	case *JSONTable:
+		buildJSONTable(op, qb)
 	case *Projection:
 		buildProjection(op, qb)
 	case *ApplyJoin:
@@ -496,6 +499,17 @@ func buildTable(op *Table, qb *queryBuilder) {
 	}
 }
 
+func buildJSONTable(op *JSONTable, qb *queryBuilder) {
+	if qb.stmt == nil {
+		qb.stmt = &sqlparser.Select{}
+	}
+	stmt := qb.stmt.(FromStatement)
+	stmt.SetFrom(append(stmt.GetFrom(), sqlparser.CloneRefOfJSONTableExpr(op.Expr)))
+	for _, name := range op.Columns {
+		qb.addProjection(&sqlparser.AliasedExpr{Expr: name})
+	}
+}
+
 func buildProjection(op *Projection, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 
@@ -541,7 +555,38 @@ func buildApplyJoin(op *ApplyJoin, qb *queryBuilder) {
 
 	qbR := &queryBuilder{ctx: qb.ctx}
 	buildQuery(op.RHS, qbR)
-	qb.joinWith(qbR, pred, op.JoinType)
+	if op.lateral {
+		qbR.makeLateral(op.ExtraLHSVars)
+	}
+	qb.joinWith(qbR, pred, op.JoinType, op.lateral)
+}
+
+// makeLateral marks the derived table as lateral, and puts back the columns
+// of the outer query that were replaced with arguments
+func (qb *queryBuilder) makeLateral(vars []BindVarExpr) {
+	for _, tbl := range qb.stmt.(FromStatement).GetFrom() {
+		aliased, ok := tbl.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			continue
+		}
+		if dt, ok := aliased.Expr.(*sqlparser.DerivedTable); ok {
+			dt.Lateral = true
+		}
+	}
+
+	_ = sqlparser.Rewrite(qb.stmt, func(cursor *sqlparser.Cursor) bool {
+		arg, ok := cursor.Node().(*sqlparser.Argument)
+		if !ok {
+			return true
+		}
+		idx := slices.IndexFunc(vars, func(bve BindVarExpr) bool {
+			return bve.Name == arg.Name
+		})
+		if idx >= 0 {
+			cursor.Replace(sqlparser.CloneExpr(vars[idx].Expr))
+		}
+		return true
+	}, nil)
 }
 
 func buildUnion(op *Union, qb *queryBuilder) {
@@ -21,26 +21,22 @@ import (
 	"fmt"
 	"slices"
 
+	"vitess.io/vitess/go/ptr"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 )
 
-func errDistinctAggrWithMultiExpr(f sqlparser.AggrFunc) {
-	if f == nil {
-		panic(vterrors.VT12001("distinct aggregation function with multiple expressions"))
-	}
-	panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation function with multiple expressions '%s'", sqlparser.String(f))))
-}
-
 func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator) (output Operator, applyResult *ApplyResult) {
 	if aggregator.Pushed {
 		return aggregator, NoRewrite
 	}
 
-	// this rewrite is always valid, and we should do it whenever possible
-	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || overlappingUniqueVindex(ctx, aggregator.Grouping)) {
+	// this rewrite is always valid, and we should do it whenever possible.
+	// the super-aggregate rows of a rollup span all groups, so they can only be computed by a single shard
+	if route, ok := aggregator.Source.(*Route); ok && (route.IsSingleShard() || (!aggregator.WithRollup && overlappingUniqueVindex(ctx, aggregator.Grouping))) {
 		return Swap(aggregator, route, "push down aggregation under route - remove original")
 	}
 
@@ -50,7 +46,7 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 	}
 
 	// if we have not yet been able to push this aggregation down,
-	// we need to turn AVG into SUM/COUNT to support this over a sharded keyspace
+	// we need to turn AVG and the statistical aggregations into SUM/COUNT to support this over a sharded keyspace
 	if needAvgBreaking(aggregator.Aggregations) {
 		return splitAvgAggregations(ctx, aggregator)
 	}
@@ -141,6 +137,10 @@ func aggregateTheAggregate(a *Aggregator, i int) {
 		// Think of it as we are SUMming together a bunch of distributed COUNTs.
 		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateSum
 		a.Aggregations[i] = aggr
+	case opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg:
+		// The shards return JSON arrays and objects, which are merged together above the Route.
+		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateJSONMerge
+		a.Aggregations[i] = aggr
 	}
 }
 
@@ -149,6 +149,11 @@ func pushAggregationThroughRoute(
 	aggregator *Aggregator,
 	route *Route,
 ) (Operator, *ApplyResult) {
+	if slices.ContainsFunc(aggregator.Aggregations, Aggr.needsAllRows) {
+		// the aggregation is done by the vtgate on all the rows returned by the route
+		return nil, NoRewrite
+	}
+
 	// Create a new aggregator to be placed below the route.
 	aggrBelowRoute := aggregator.SplitAggregatorBelowOperators(ctx, route.Inputs())
 	aggrBelowRoute.Aggregations = nil
@@ -169,11 +174,14 @@ func pushAggregationThroughRoute(
 
 // pushAggregations splits aggregations between the original aggregator and the one we are pushing down
 func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator, aggrBelowRoute *Aggregator) {
-	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
-
-	distinctAggrGroupByAdded := false
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
 	for i, aggr := range aggregator.Aggregations {
+		if aggr.OpCode == opcode.AggregateGrouping {
+			// the shards don't roll up, so GROUPING() is evaluated at the vtgate level
+			aggrBelowRoute.Columns[aggr.ColOffset] = aeWrap(aggr.getPushColumn())
+			continue
+		}
 		if !aggr.Distinct || canPushDistinctAggr {
 			aggrBelowRoute.Aggregations = append(aggrBelowRoute.Aggregations, aggr)
 			aggregateTheAggregate(aggregator, i)
@@ -181,35 +189,55 @@ func pushAggregations(ctx *plancontext.PlanningContext, aggregator *Aggregator,
 			continue
 		}
 
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(aggr.Func)
-		}
-
 		// We handle a distinct aggregation by turning it into a group by and
 		// doing the aggregating on the vtgate level instead
-		aeDistinctExpr := aeWrap(distinctExprs[0])
-		aggrBelowRoute.Columns[aggr.ColOffset] = aeDistinctExpr
+		args := aggr.Func.GetArgs()
+		aggrBelowRoute.Columns[aggr.ColOffset] = aeWrap(args[0])
+		for _, order := range aggr.GroupConcatOrderBy() {
+			// the rows of a GROUP_CONCAT are sorted by the vtgate, so the shards need to keep the ordering values
+			args = append(args, order.Expr)
+		}
 
-		// We handle a distinct aggregation by turning it into a group by and
-		// doing the aggregating on the vtgate level instead
 		// Adding to group by can be done only once even though there are multiple distinct aggregation with same expression.
-		if !distinctAggrGroupByAdded {
-			groupBy := NewGroupBy(distinctExprs[0])
-			groupBy.ColOffset = aggr.ColOffset
+		for idx, arg := range args {
+			if slices.ContainsFunc(aggrBelowRoute.Grouping, func(gb GroupBy) bool {
+				return ctx.SemTable.EqualsExprWithDeps(gb.Inner, arg)
+			}) {
+				continue
+			}
+			groupBy := NewGroupBy(arg)
+			if idx == 0 {
+				groupBy.ColOffset = aggr.ColOffset
+			}
 			aggrBelowRoute.Grouping = append(aggrBelowRoute.Grouping, groupBy)
-			distinctAggrGroupByAdded = true
+		}
+
+		if distinctExpr == nil && usesHashedDistinct(aggr) {
+			// the input can't be sorted for all the distinct aggregations,
+			// so the values already seen are kept in a hash set at the vtgate level
+			aggregator.Aggregations[i].HashDistinct = true
 		}
 	}
 
 	if !canPushDistinctAggr {
-		aggregator.DistinctExpr = distinctExprs[0]
+		aggregator.DistinctExpr = distinctExpr
 	}
 }
 
-func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Exprs) {
+// usesHashedDistinct returns true for the distinct aggregations that have to keep their values in a hash set when the input is not
+// sorted on them. MIN and MAX don't need to, and GROUP_CONCAT always does, as it does not compare its values to the previous row.
+func usesHashedDistinct(aggr Aggr) bool {
+	return aggr.OpCode.IsDistinct() || aggr.OpCode == opcode.AggregateGroupConcat
+}
+
+// checkIfWeCanPush checks if the distinct aggregations can be pushed down to the shards,
+// which is the case when they have a unique vindex in their arguments.
+// When they can't be pushed, and all of them use the same single expression, it is returned, so the input
+// can be ordered on it. Otherwise, the expression is nil, and the distinct values are kept in hash sets.
+func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator) (bool, sqlparser.Expr) {
 	canPush := true
 	var distinctExprs sqlparser.Exprs
-	var differentExpr *sqlparser.AliasedExpr
+	sameExprs := true
 
 	for _, aggr := range aggregator.Aggregations {
 		if !aggr.Distinct {
@@ -227,22 +255,22 @@ func checkIfWeCanPush(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 		if !hasUniqVindex {
 			canPush = false
 		}
-		if len(distinctExprs) == 0 {
+		if aggr.OpCode == opcode.AggregateGroupConcat {
+			sameExprs = false
+		}
+		if distinctExprs == nil {
 			distinctExprs = args
+			continue
 		}
-		for idx, expr := range distinctExprs {
-			if !ctx.SemTable.EqualsExpr(expr, args[idx]) {
-				differentExpr = aggr.Original
-				break
-			}
+		if !slices.EqualFunc(distinctExprs, args, ctx.SemTable.EqualsExpr) {
+			sameExprs = false
 		}
 	}
 
-	if !canPush && differentExpr != nil {
-		panic(vterrors.VT12001(fmt.Sprintf("only one DISTINCT aggregation is allowed in a SELECT: %s", sqlparser.String(differentExpr))))
+	if canPush || !sameExprs || len(distinctExprs) != 1 {
+		return canPush, nil
 	}
-
-	return canPush, distinctExprs
+	return canPush, distinctExprs[0]
 }
 
 func pushAggregationThroughFilter(
@@ -400,6 +428,12 @@ func pushAggregationThroughApplyJoin(ctx *plancontext.PlanningContext, rootAggr
 
 // pushAggregationThroughHashJoin pushes aggregation through a hash-join in a similar way to pushAggregationThroughApplyJoin
 func pushAggregationThroughHashJoin(ctx *plancontext.PlanningContext, rootAggr *Aggregator, join *HashJoin) (Operator, *ApplyResult) {
+	if join.RightJoin || len(join.ResidualPredicates) > 0 {
+		// the rows can only be aggregated before the join when they are matched using the
+		// hash comparisons alone, and the LHS is the only side that can be kept without a match
+		return nil, nil
+	}
+
 	lhs := createJoinPusher(rootAggr, join.LHS)
 	rhs := createJoinPusher(rootAggr, join.RHS)
 
@@ -542,15 +576,20 @@ func splitAggrColumnsToLeftAndRight(
 		outerJoin:   leftJoin,
 	}
 
-	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
+	canPushDistinctAggr, distinctExpr := checkIfWeCanPush(ctx, aggregator)
 
 	// Distinct aggregation cannot be pushed down in the join.
 	// We keep node of the distinct aggregation expression to be used later for ordering.
+	// When there is no single expression to order on, the distinct values are kept in hash sets instead.
 	if !canPushDistinctAggr {
-		if len(distinctExprs) != 1 {
-			errDistinctAggrWithMultiExpr(nil)
+		aggregator.DistinctExpr = distinctExpr
+		if distinctExpr == nil {
+			for i, aggr := range aggregator.Aggregations {
+				if aggr.Distinct && usesHashedDistinct(aggr) {
+					aggregator.Aggregations[i].HashDistinct = true
+				}
+			}
 		}
-		aggregator.DistinctExpr = distinctExprs[0]
 		return nil, errAbortAggrPushing
 	}
 
@@ -595,14 +634,15 @@ func extractExpr(expr *sqlparser.AliasedExpr) sqlparser.Expr { return expr.Expr
 
 func needAvgBreaking(aggrs []Aggr) bool {
 	for _, aggr := range aggrs {
-		if aggr.OpCode == opcode.AggregateAvg {
+		switch aggr.OpCode {
+		case opcode.AggregateAvg, opcode.AggregateVarPop, opcode.AggregateVarSamp, opcode.AggregateStddevPop, opcode.AggregateStddevSamp:
 			return true
 		}
 	}
 	return false
 }
 
-// splitAvgAggregations takes an aggregator that has AVG aggregations in it and splits
+// splitAvgAggregations takes an aggregator that has AVG or statistical aggregations in it and splits
 // these into sum/count expressions that can be spread out to shards
 func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (Operator, *ApplyResult) {
 	proj := newAliasedProjection(aggr)
@@ -611,40 +651,59 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (O
 	var aggregations []Aggr
 
 	for offset, col := range aggr.Columns {
-		avg, ok := col.Expr.(*sqlparser.Avg)
+		fnc, ok := col.Expr.(sqlparser.AggrFunc)
 		if !ok {
 			proj.addColumnWithoutPushing(ctx, col, false /* addToGroupBy */)
 			continue
 		}
 
-		if avg.Distinct {
-			panic(vterrors.VT12001("AVG(distinct <>)"))
-		}
+		var sumExpr *sqlparser.Sum
+		var calcExpr sqlparser.Expr
+		var extra []Aggr
 
-		// We have an AVG that we need to split
-		sumExpr := &sqlparser.Sum{Arg: avg.Arg}
-		countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
-		calcExpr := &sqlparser.BinaryExpr{
-			Operator: sqlparser.DivOp,
-			Left:     sumExpr,
-			Right:    countExpr,
+		switch code := opcode.SupportedAggregates[fnc.AggrName()]; code {
+		case opcode.AggregateAvg:
+			avg := fnc.(*sqlparser.Avg)
+			if avg.Distinct {
+				panic(vterrors.VT12001("AVG(distinct <>)"))
+			}
+
+			// We have an AVG that we need to split
+			sumExpr = &sqlparser.Sum{Arg: avg.Arg}
+			countExpr := &sqlparser.Count{Args: []sqlparser.Expr{avg.Arg}}
+			calcExpr = &sqlparser.BinaryExpr{
+				Operator: sqlparser.DivOp,
+				Left:     sumExpr,
+				Right:    countExpr,
+			}
+			extra = append(extra, NewAggr(opcode.AggregateCount, countExpr, aeWrap(countExpr), sqlparser.String(countExpr)))
+		case opcode.AggregateVarPop, opcode.AggregateVarSamp, opcode.AggregateStddevPop, opcode.AggregateStddevSamp:
+			var sumSquaresExpr *sqlparser.Sum
+			var countExpr *sqlparser.Count
+			sumExpr, sumSquaresExpr, countExpr, calcExpr = splitStatisticalAggregation(ctx, code, fnc.GetArg())
+			extra = append(extra,
+				NewAggr(opcode.AggregateSum, sumSquaresExpr, aeWrap(sumSquaresExpr), sqlparser.String(sumSquaresExpr)),
+				NewAggr(opcode.AggregateCount, countExpr, aeWrap(countExpr), sqlparser.String(countExpr)),
+			)
+		default:
+			proj.addColumnWithoutPushing(ctx, col, false /* addToGroupBy */)
+			continue
 		}
 
-		outputColumn := aeWrap(col.Expr)
-		outputColumn.As = sqlparser.NewIdentifierCI(col.ColumnName())
 		proj.addUnexploredExpr(sqlparser.Clone(col), calcExpr)
 		col.Expr = sumExpr
 		found := false
 		for aggrOffset, aggregation := range aggr.Aggregations {
 			if offset == aggregation.ColOffset {
-				// We have found the AVG column. We'll change it to SUM, and then we add a COUNT as well
+				// We have found the column. We'll change it to SUM, and then we add the other aggregations as well
 				aggr.Aggregations[aggrOffset].OpCode = opcode.AggregateSum
+				aggr.Aggregations[aggrOffset].Func = sumExpr
 
-				countExprAlias := aeWrap(countExpr)
-				countAggr := NewAggr(opcode.AggregateCount, countExpr, countExprAlias, sqlparser.String(countExpr))
-				countAggr.ColOffset = len(aggr.Columns) + len(columns)
-				aggregations = append(aggregations, countAggr)
-				columns = append(columns, countExprAlias)
+				for _, extraAggr := range extra {
+					extraAggr.ColOffset = len(aggr.Columns) + len(columns)
+					aggregations = append(aggregations, extraAggr)
+					columns = append(columns, extraAggr.Original)
+				}
 				found = true
 				break // no need to search the remaining aggregations
 			}
@@ -660,3 +719,82 @@ func splitAvgAggregations(ctx *plancontext.PlanningContext, aggr *Aggregator) (O
 
 	return proj, Rewrote("split avg aggregation")
 }
+
+// splitStatisticalAggregation splits the population or sample variance or standard deviation of an expression
+// into the sum of the expression, the sum of its squares and its count, and returns the expression that
+// computes the aggregation from them.
+//
+//	VAR_POP(x)  = (SUM(x * x) * COUNT(x) - SUM(x) * SUM(x)) / (COUNT(x) * COUNT(x))
+//	VAR_SAMP(x) = (SUM(x * x) * COUNT(x) - SUM(x) * SUM(x)) / (COUNT(x) * (COUNT(x) - 1))
+//
+// The subtraction cancels most digits of the sums when the values are large compared to their spread, so
+// the sums have to be exact. Integer and decimal values are squared as DECIMAL, which makes the numerator
+// exact, and it is only converted to a DOUBLE for the division. Other values are squared using POW, which
+// returns a DOUBLE, so their variance can lose precision; a numerator that rounds below zero is clamped to
+// zero, so that the standard deviation is not NULL.
+//
+// Like in MySQL, the result is NULL if there are no values, or only one value for VAR_SAMP, as a division
+// by zero evaluates to NULL. The standard deviations are the square roots of the variances.
+func splitStatisticalAggregation(ctx *plancontext.PlanningContext, code opcode.AggregateOpcode, arg sqlparser.Expr) (*sqlparser.Sum, *sqlparser.Sum, *sqlparser.Count, sqlparser.Expr) {
+	sumExpr := &sqlparser.Sum{Arg: arg}
+	sumSquaresExpr := &sqlparser.Sum{Arg: squareExpr(ctx, arg)}
+	countExpr := &sqlparser.Count{Args: sqlparser.Exprs{arg}}
+
+	mul := func(left, right sqlparser.Expr) sqlparser.Expr {
+		return &sqlparser.BinaryExpr{Operator: sqlparser.MultOp, Left: left, Right: right}
+	}
+
+	var count sqlparser.Expr = sqlparser.Clone(countExpr)
+	if code == opcode.AggregateVarSamp || code == opcode.AggregateStddevSamp {
+		count = &sqlparser.BinaryExpr{Operator: sqlparser.MinusOp, Left: count, Right: sqlparser.NewIntLiteral("1")}
+	}
+
+	numerator := &sqlparser.BinaryExpr{
+		Operator: sqlparser.MinusOp,
+		Left:     mul(sumSquaresExpr, countExpr),
+		Right:    mul(sumExpr, sqlparser.Clone(sumExpr)),
+	}
+	var calcExpr sqlparser.Expr = &sqlparser.BinaryExpr{
+		Operator: sqlparser.DivOp,
+		Left: &sqlparser.FuncExpr{
+			Name: sqlparser.NewIdentifierCI("greatest"),
+			Exprs: sqlparser.Exprs{
+				&sqlparser.CastExpr{Expr: numerator, Type: &sqlparser.ConvertType{Type: "double"}},
+				sqlparser.NewIntLiteral("0"),
+			},
+		},
+		Right: mul(sqlparser.Clone(countExpr), count),
+	}
+	if code == opcode.AggregateStddevPop || code == opcode.AggregateStddevSamp {
+		calcExpr = &sqlparser.FuncExpr{
+			Name:  sqlparser.NewIdentifierCI("sqrt"),
+			Exprs: sqlparser.Exprs{calcExpr},
+		}
+	}
+	return sumExpr, sumSquaresExpr, countExpr, calcExpr
+}
+
+// squareExpr returns the square of an expression, computed as a DECIMAL for integer and decimal values,
+// and using POW for the others.
+func squareExpr(ctx *plancontext.PlanningContext, arg sqlparser.Expr) sqlparser.Expr {
+	typ, found := ctx.TypeForExpr(arg)
+	switch {
+	case found && sqltypes.IsIntegral(typ.Type()):
+		// the square of a BIGINT can overflow, so one of the factors is converted to a DECIMAL large enough
+		// for any integer, and the product is a DECIMAL as well
+		return &sqlparser.BinaryExpr{
+			Operator: sqlparser.MultOp,
+			Left: &sqlparser.CastExpr{
+				Expr: sqlparser.Clone(arg),
+				Type: &sqlparser.ConvertType{Type: "decimal", Length: ptr.Of(20), Scale: ptr.Of(0)},
+			},
+			Right: sqlparser.Clone(arg),
+		}
+	case found && sqltypes.IsDecimal(typ.Type()):
+		return &sqlparser.BinaryExpr{Operator: sqlparser.MultOp, Left: sqlparser.Clone(arg), Right: sqlparser.Clone(arg)}
+	}
+	return &sqlparser.FuncExpr{
+		Name:  sqlparser.NewIdentifierCI("pow"),
+		Exprs: sqlparser.Exprs{sqlparser.Clone(arg), sqlparser.NewIntLiteral("2")},
+	}
+}
@@ -130,13 +130,16 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 		return nil
 	case opcode.AggregateCount, opcode.AggregateSum:
 		return ab.handleAggrWithCountStarMultiplier(ctx, aggr)
-	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
+	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue, opcode.AggregateBitAnd, opcode.AggregateBitOr:
 		return ab.handlePushThroughAggregation(ctx, aggr)
+	case opcode.AggregateBitXor, opcode.AggregateJSONArrayAgg, opcode.AggregateJSONObjectAgg:
+		// the rows of one side are repeated for each matching row of the other side,
+		// which changes the result of these aggregations, so the columns are pushed instead
+		return errAbortAggrPushing
+	case opcode.AggregateGrouping:
+		// GROUPING() has to stay on the aggregator doing the rollup
+		return errAbortAggrPushing
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 {
-			panic(vterrors.VT12001("cannot evaluate group concat with distinct or order by"))
-		}
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
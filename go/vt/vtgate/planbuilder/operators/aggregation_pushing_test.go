@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// TestSplitStatisticalAggregation evaluates the expressions computing the statistical aggregations
+// from the sums and counts returned by the shards.
+func TestSplitStatisticalAggregation(t *testing.T) {
+	tests := []struct {
+		name               string
+		typ                sqltypes.Type
+		code               opcode.AggregateOpcode
+		sum, sumSquares    string
+		count              int
+		squareExpr, result string
+	}{{
+		// 1000 times each of 999999999, 1000000000 and 1000000001
+		name:       "large integers",
+		typ:        sqltypes.Int64,
+		code:       opcode.AggregateVarPop,
+		sum:        "3000000000000",
+		sumSquares: "3000000000000000002000",
+		count:      3000,
+		squareExpr: "cast(x as decimal(20, 0)) * x",
+		result:     "FLOAT64(0.6666666666666666)",
+	}, {
+		name:       "decimals",
+		typ:        sqltypes.Decimal,
+		code:       opcode.AggregateStddevSamp,
+		sum:        "6.0",
+		sumSquares: "14.00",
+		count:      3,
+		squareExpr: "x * x",
+		result:     "FLOAT64(1)",
+	}, {
+		// the sum of the squares was rounded below the square of the sum divided by the count
+		name:       "doubles",
+		typ:        sqltypes.Float64,
+		code:       opcode.AggregateStddevPop,
+		sum:        "3e9",
+		sumSquares: "2.9999999999999995e18",
+		count:      3,
+		squareExpr: "pow(x, 2)",
+		result:     "FLOAT64(0)",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			arg := sqlparser.NewColName("x")
+			ctx := &plancontext.PlanningContext{SemTable: semantics.EmptySemTable()}
+			ctx.SemTable.ExprTypes[arg] = evalengine.NewType(test.typ, collations.CollationBinaryID)
+
+			sumExpr, sumSquaresExpr, countExpr, calcExpr := splitStatisticalAggregation(ctx, test.code, arg)
+			assert.Equal(t, test.squareExpr, sqlparser.String(sumSquaresExpr.Arg))
+
+			// replace the aggregations by the values returned by the shards
+			newLiteral := sqlparser.NewDecimalLiteral
+			if sqltypes.IsFloat(test.typ) {
+				newLiteral = sqlparser.NewFloatLiteral
+			}
+			calcExpr = sqlparser.Rewrite(calcExpr, nil, func(cursor *sqlparser.Cursor) bool {
+				switch node := cursor.Node().(type) {
+				case *sqlparser.Sum:
+					if sqlparser.Equals.RefOfSum(node, sumSquaresExpr) {
+						cursor.Replace(newLiteral(test.sumSquares))
+					} else if sqlparser.Equals.RefOfSum(node, sumExpr) {
+						cursor.Replace(newLiteral(test.sum))
+					}
+				case *sqlparser.Count:
+					if sqlparser.Equals.RefOfCount(node, countExpr) {
+						cursor.Replace(sqlparser.NewIntLiteral(fmt.Sprint(test.count)))
+					}
+				}
+				return true
+			}).(sqlparser.Expr)
+
+			venv := vtenv.NewTestEnv()
+			expr, err := evalengine.Translate(calcExpr, &evalengine.Config{
+				Environment: venv,
+				Collation:   collations.MySQL8().DefaultConnectionCharset(),
+			})
+			require.NoError(t, err)
+			res, err := evalengine.EmptyExpressionEnv(venv).Evaluate(expr)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, res.Value(collations.MySQL8().DefaultConnectionCharset()).String())
+		})
+	}
+}
@@ -361,7 +361,14 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		a.offsetPlanned = true
 	}()
 	if !a.Pushed {
+		for idx, aggr := range a.Aggregations {
+			if aggr.OpCode == opcode.AggregateGroupConcat && aggr.Distinct {
+				// the vtgate sees all the values, and GROUP_CONCAT does not expect them to be sorted
+				a.Aggregations[idx].HashDistinct = true
+			}
+		}
 		a.planOffsetsNotPushed(ctx)
+		a.planArgOffsets(ctx)
 		return nil
 	}
 
@@ -395,9 +402,43 @@ func (a *Aggregator) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		}
 		a.Aggregations[idx].WSOffset = offset
 	}
+	a.planArgOffsets(ctx)
 	return nil
 }
 
+// planArgOffsets finds the offsets of all the arguments of the aggregations that need them, with the weight strings
+// needed to keep the distinct values in hash sets, and the offsets of the ORDER BY expressions of GROUP_CONCAT
+func (a *Aggregator) planArgOffsets(ctx *plancontext.PlanningContext) {
+	for idx, aggr := range a.Aggregations {
+		if !aggr.needsArgOffsets() {
+			continue
+		}
+		var offsets, wsOffsets []int
+		for _, arg := range aggr.Func.GetArgs() {
+			offsets = append(offsets, a.internalAddColumn(ctx, aeWrap(arg), a.Pushed))
+			wsOffset := -1
+			if aggr.HashDistinct && ctx.NeedsWeightString(arg) {
+				wsOffset = a.internalAddColumn(ctx, aeWrap(weightStringFor(arg)), a.Pushed)
+			}
+			wsOffsets = append(wsOffsets, wsOffset)
+		}
+		a.Aggregations[idx].ArgOffsets = offsets
+		a.Aggregations[idx].ArgWSOffsets = wsOffsets
+
+		offsets, wsOffsets = nil, nil
+		for _, order := range aggr.GroupConcatOrderBy() {
+			offsets = append(offsets, a.internalAddColumn(ctx, aeWrap(order.Expr), a.Pushed))
+			wsOffset := -1
+			if ctx.NeedsWeightString(order.Expr) {
+				wsOffset = a.internalAddColumn(ctx, aeWrap(weightStringFor(order.Expr)), a.Pushed)
+			}
+			wsOffsets = append(wsOffsets, wsOffset)
+		}
+		a.Aggregations[idx].OrderOffsets = offsets
+		a.Aggregations[idx].OrderWSOffsets = wsOffsets
+	}
+}
+
 func (aggr Aggr) setPushColumn(exprs sqlparser.Exprs) {
 	if aggr.Func == nil {
 		if len(exprs) > 1 {
@@ -419,16 +460,18 @@ func (aggr Aggr) getPushColumn() sqlparser.Expr {
 		return aggr.Original.Expr
 	case opcode.AggregateCountStar:
 		return sqlparser.NewIntLiteral("1")
-	case opcode.AggregateGroupConcat:
-		if len(aggr.Func.GetArgs()) > 1 {
-			panic(vterrors.VT12001("group_concat with more than 1 column"))
-		}
-		return aggr.Func.GetArg()
+	case opcode.AggregateGrouping:
+		// the value is computed by vtgate while rolling up, nothing is needed from the input
+		return sqlparser.NewIntLiteral("0")
+	case opcode.AggregateGroupConcat, opcode.AggregateJSONObjectAgg:
+		// the other arguments are found using ArgOffsets
+		return aggr.Func.GetArgs()[0]
 	default:
-		if len(aggr.Func.GetArgs()) > 1 {
+		if len(aggr.Func.GetArgs()) > 1 && !aggr.HashDistinct {
 			panic(vterrors.VT03001(sqlparser.String(aggr.Func)))
 		}
-		return aggr.Func.GetArg()
+		// hashed distinct aggregations find all their arguments using ArgOffsets
+		return aggr.Func.GetArgs()[0]
 	}
 }
 
@@ -438,6 +481,8 @@ func (aggr Aggr) getPushColumnExprs() sqlparser.Exprs {
 		return sqlparser.Exprs{aggr.Original.Expr}
 	case opcode.AggregateCountStar:
 		return sqlparser.Exprs{sqlparser.NewIntLiteral("1")}
+	case opcode.AggregateGrouping:
+		return sqlparser.Exprs{sqlparser.NewIntLiteral("0")}
 	default:
 		return aggr.Func.GetArgs()
 	}
@@ -557,6 +602,8 @@ func (a *Aggregator) SplitAggregatorBelowOperators(ctx *plancontext.PlanningCont
 	newOp.Pushed = false
 	newOp.Original = false
 	newOp.DT = nil
+	// the super-aggregate rows are only produced by the original aggregator
+	newOp.WithRollup = false
 
 	// We need to make sure that the columns are cloned so that the original operator is not affected
 	// by the changes we make to the new operator
@@ -50,6 +50,14 @@ type (
 		// these are needed by other operators further down the right hand side of the join
 		ExtraLHSVars []BindVarExpr
 
+		// scalarSubquery is set when the RHS is a correlated scalar subquery that is evaluated for every row of the LHS.
+		// Aggregations on the RHS then have to keep producing a row even when they have no input rows.
+		scalarSubquery bool
+
+		// lateral is set when the RHS is a lateral derived table. The columns of the LHS it uses are passed
+		// in through ExtraLHSVars. When the join has been merged into a route, they are put back in the query.
+		lateral bool
+
 		// After offset planning
 
 		// Columns stores the column indexes of the columns coming from the left and right side
@@ -18,6 +18,9 @@ package operators
 
 import (
 	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/slice"
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -144,6 +147,9 @@ func (jpc *joinPredicateCollector) inspectPredicate(
 }
 
 func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.Union) Operator {
+	if cte := ctx.SemTable.RecursiveCTE(node); cte != nil {
+		return newHorizon(createRecurseCTE(ctx, node, cte), node)
+	}
 	_, isRHSUnion := node.Right.(*sqlparser.Union)
 	if isRHSUnion {
 		panic(vterrors.VT12001("nesting of UNIONs on the right-hand side"))
@@ -158,6 +164,42 @@ func createOperatorFromUnion(ctx *plancontext.PlanningContext, node *sqlparser.U
 	return newHorizon(union, node)
 }
 
+// createRecurseCTE creates the operator for a recursive CTE. The recursive part is planned as a
+// query that takes the columns of the CTE as bind variables, and is run once for every row of the previous iteration
+func createRecurseCTE(ctx *plancontext.PlanningContext, node *sqlparser.Union, cte *semantics.CTETable) Operator {
+	cteID := ctx.SemTable.TableSetFor(cte.ASTNode)
+	vars := map[string]int{}
+	columns := cte.ColumnNames()
+	term := sqlparser.Rewrite(node.Right, nil, func(cursor *sqlparser.Cursor) bool {
+		col, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok || ctx.SemTable.DirectDeps(col) != cteID {
+			return true
+		}
+		offset := slices.IndexFunc(columns, func(name string) bool {
+			return col.Name.EqualString(name)
+		})
+		if offset < 0 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(col))))
+		}
+		typ, _ := ctx.SemTable.TypeForExpr(col)
+		bvName := ctx.GetReservedArgumentFor(col)
+		vars[bvName] = offset
+		cursor.Replace(sqlparser.NewTypedArgument(bvName, typ.Type()))
+		return true
+	}).(sqlparser.SelectStatement)
+
+	seed := translateQueryToOp(ctx, node.Left)
+	termOp := translateQueryToOp(ctx, term)
+	cols := slice.Map(ctx.SemTable.SelectExprs(node), func(from sqlparser.SelectExpr) *sqlparser.AliasedExpr {
+		ae, ok := from.(*sqlparser.AliasedExpr)
+		if !ok {
+			panic(vterrors.VT09015())
+		}
+		return ae
+	})
+	return newRecurse(cte, seed, termOp, vars, node.Distinct, cols)
+}
+
 // createOpFromStmt creates an operator from the given statement. It takes in two additional arguments—
 //  1. verifyAllFKs: For this given statement, do we need to verify validity of all the foreign keys on the vtgate level.
 //  2. fkToIgnore: The foreign key constraint to specifically ignore while planning the statement. This field is used in UPDATE CASCADE planning, wherein while planning the child update
@@ -177,6 +219,10 @@ func createOpFromStmt(inCtx *plancontext.PlanningContext, stmt sqlparser.Stateme
 		ctx.VerifyAllFKs = verifyAllFKs
 	}
 
+	if upd, isUpdate := stmt.(*sqlparser.Update); isUpdate && UpdateRequiresFKChecksOff(ctx, upd) {
+		ctx.VerifyAllFKs = true
+	}
+
 	// From all the parent foreign keys involved, we should remove the one that we need to ignore.
 	err = ctx.SemTable.RemoveParentForeignKey(fkToIgnore)
 	if err != nil {
@@ -216,6 +262,8 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 		return getOperatorFromJoinTableExpr(ctx, tableExpr)
 	case *sqlparser.ParenTableExpr:
 		return crossJoin(ctx, tableExpr.Exprs)
+	case *sqlparser.JSONTableExpr:
+		return newJSONTableRoute(ctx, tableExpr)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T table type", tableExpr)))
 	}
@@ -223,6 +271,9 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 
 func getOperatorFromJoinTableExpr(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr) Operator {
 	lhs := getOperatorFromTableExpr(ctx, tableExpr.LeftExpr, false)
+	if lateralTbl := getLateralTable(tableExpr.RightExpr); lateralTbl != nil {
+		return createLateralJoinFromJoinTableExpr(ctx, tableExpr, lhs, lateralTbl)
+	}
 	rhs := getOperatorFromTableExpr(ctx, tableExpr.RightExpr, false)
 
 	switch tableExpr.Join {
@@ -246,6 +297,15 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 			panic(err)
 		}
 
+		if _, isCTE := tableInfo.(*semantics.CTETable); isCTE {
+			// the rows of the previous iteration are passed in as bind variables,
+			// so the recursive part reads from dual instead of from the CTE
+			dual := sqlparser.NewTableName("dual")
+			qg := newQueryGraph()
+			qg.Tables = append(qg.Tables, &QueryTable{Alias: sqlparser.NewAliasedTableExpr(dual, ""), Table: dual, ID: tableID})
+			return qg
+		}
+
 		if vt, isVindex := tableInfo.(*semantics.VindexTable); isVindex {
 			solves := tableID
 			return &Vindex{
@@ -287,6 +347,10 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Operator {
 	var output Operator
 	for _, tableExpr := range exprs {
+		if lateralTbl := getLateralTable(tableExpr); lateralTbl != nil && output != nil {
+			output = createLateralJoin(ctx, output, lateralTbl, sqlparser.NormalJoinType)
+			continue
+		}
 		op := getOperatorFromTableExpr(ctx, tableExpr, len(exprs) == 1)
 		if output == nil {
 			output = op
@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// The cost model estimates the number of rows produced by an operator tree, and the work needed to produce them,
+// using the table statistics collected by the schema tracker. It is used to pick the join order and the join
+// algorithm of query graphs that have statistics for at least one of their tables. Without statistics,
+// the planner compares plans using the routing cost of their routes, like it always did.
+
+const (
+	// defaultTableRows is the number of rows assumed for the tables that have no statistics
+	defaultTableRows = 1000
+
+	// routeQueryCost is the cost of sending a query to a shard, measured in rows
+	routeQueryCost = 10
+
+	// hashJoinBuildCost is the cost of adding a row of the LHS to the probe table of a hash join.
+	// Building the probe table costs more than probing it, so the smaller side is used as the LHS.
+	hashJoinBuildCost = 2
+
+	// the selectivity of predicates, when the cardinality of the columns they compare is not known
+	equalitySelectivity = 0.1
+	rangeSelectivity    = 1.0 / 3
+	defaultSelectivity  = 0.5
+)
+
+type planCost struct {
+	// rows is the estimated number of rows produced by the operator
+	rows float64
+	// cost is the estimated work needed to produce these rows, measured in rows read from the tablets or
+	// processed at the vtgate
+	cost float64
+}
+
+// hasStatistics returns true if any table of the query graph has statistics
+func hasStatistics(ctx *plancontext.PlanningContext, qg *QueryGraph) bool {
+	for _, table := range qg.Tables {
+		if tableHasStatistics(ctx, table.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStatisticsFor returns true if any table used by the operators has statistics
+func hasStatisticsFor(ctx *plancontext.PlanningContext, ops ...Operator) bool {
+	for _, op := range ops {
+		for _, id := range TableID(op).Constituents() {
+			if tableHasStatistics(ctx, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func tableHasStatistics(ctx *plancontext.PlanningContext, id semantics.TableSet) bool {
+	ti, err := ctx.SemTable.TableInfoFor(id)
+	if err != nil {
+		return false
+	}
+	vtbl := ti.GetVindexTable()
+	return vtbl != nil && vtbl.Statistics != nil
+}
+
+// cheaper returns true if a is cheaper to run than b. With costBased, the plans are compared using
+// the cost model, and the routing cost of their routes is only used to break ties.
+func cheaper(ctx *plancontext.PlanningContext, costBased bool, a, b Operator) bool {
+	if costBased {
+		aCost, bCost := estimateCost(ctx, a).cost, estimateCost(ctx, b).cost
+		if aCost != bCost {
+			return aCost < bCost
+		}
+	}
+	return CostOf(a) < CostOf(b)
+}
+
+func estimateCost(ctx *plancontext.PlanningContext, op Operator) planCost {
+	switch op := op.(type) {
+	case *Route:
+		src := estimateCost(ctx, op.Source)
+		return planCost{rows: src.rows, cost: src.rows + routeQueryCost*float64(op.Cost())}
+	case *Table:
+		rows := tableRows(op.VTable)
+		if op.QTable != nil {
+			for _, pred := range op.QTable.Predicates {
+				rows *= selectivity(ctx, pred)
+			}
+		}
+		return planCost{rows: rows}
+	case *Filter:
+		src := estimateCost(ctx, op.Source)
+		for _, pred := range op.Predicates {
+			src.rows *= selectivity(ctx, pred)
+		}
+		return src
+	case *Join:
+		lhs, rhs := estimateCost(ctx, op.LHS), estimateCost(ctx, op.RHS)
+		rows := lhs.rows * rhs.rows
+		if op.Predicate != nil {
+			rows *= selectivity(ctx, op.Predicate)
+		}
+		if !op.JoinType.IsInner() {
+			rows = max(rows, lhs.rows)
+		}
+		return planCost{rows: rows, cost: lhs.cost + rhs.cost}
+	case *ApplyJoin:
+		// the RHS is run once for every row of the LHS, with the join predicates already applied to it
+		lhs, rhs := estimateCost(ctx, op.LHS), estimateCost(ctx, op.RHS)
+		rows := lhs.rows * rhs.rows
+		if op.LeftJoin {
+			rows = max(rows, lhs.rows)
+		}
+		return planCost{rows: rows, cost: lhs.cost + lhs.rows*rhs.cost}
+	case *HashJoin:
+		// both sides are run once, and all their rows are processed by the vtgate
+		lhs, rhs := estimateCost(ctx, op.LHS), estimateCost(ctx, op.RHS)
+		rows := lhs.rows * rhs.rows
+		for _, cmp := range op.JoinComparisons {
+			rows *= comparisonSelectivity(ctx, cmp.LHS, cmp.RHS)
+		}
+		for _, pred := range op.ResidualPredicates {
+			rows *= selectivity(ctx, pred)
+		}
+		if op.LeftJoin {
+			rows = max(rows, lhs.rows)
+		}
+		if op.RightJoin {
+			rows = max(rows, rhs.rows)
+		}
+		return planCost{rows: rows, cost: lhs.cost + rhs.cost + hashJoinBuildCost*lhs.rows + rhs.rows}
+	}
+
+	inputs := op.Inputs()
+	if len(inputs) == 1 {
+		return estimateCost(ctx, inputs[0])
+	}
+	result := planCost{rows: 1}
+	if len(inputs) > 0 {
+		result.rows = 0
+	}
+	for _, input := range inputs {
+		cost := estimateCost(ctx, input)
+		result.rows += cost.rows
+		result.cost += cost.cost
+	}
+	return result
+}
+
+func tableRows(vtbl *vindexes.Table) float64 {
+	if vtbl == nil || vtbl.Statistics == nil {
+		return defaultTableRows
+	}
+	return float64(vtbl.Statistics.Rows)
+}
+
+// selectivity estimates the fraction of the rows that the predicate keeps
+func selectivity(ctx *plancontext.PlanningContext, expr sqlparser.Expr) float64 {
+	switch expr := expr.(type) {
+	case *sqlparser.AndExpr:
+		return selectivity(ctx, expr.Left) * selectivity(ctx, expr.Right)
+	case *sqlparser.OrExpr:
+		return min(1, selectivity(ctx, expr.Left)+selectivity(ctx, expr.Right))
+	case *sqlparser.NotExpr:
+		return 1 - selectivity(ctx, expr.Expr)
+	case *sqlparser.BetweenExpr:
+		return rangeSelectivity
+	case *sqlparser.ComparisonExpr:
+		switch expr.Operator {
+		case sqlparser.EqualOp, sqlparser.NullSafeEqualOp:
+			return comparisonSelectivity(ctx, expr.Left, expr.Right)
+		case sqlparser.NotEqualOp:
+			return 1 - comparisonSelectivity(ctx, expr.Left, expr.Right)
+		case sqlparser.InOp:
+			values := defaultTableRows * equalitySelectivity
+			if tuple, ok := expr.Right.(sqlparser.ValTuple); ok {
+				values = float64(len(tuple))
+			}
+			return min(1, values*comparisonSelectivity(ctx, expr.Left, nil))
+		case sqlparser.LessThanOp, sqlparser.GreaterThanOp, sqlparser.LessEqualOp, sqlparser.GreaterEqualOp, sqlparser.LikeOp:
+			return rangeSelectivity
+		}
+	}
+	return defaultSelectivity
+}
+
+// comparisonSelectivity estimates the fraction of the rows for which the two expressions are equal.
+// When the expressions are columns with statistics, each value is assumed to be repeated as many times.
+func comparisonSelectivity(ctx *plancontext.PlanningContext, left, right sqlparser.Expr) float64 {
+	cardinality := max(columnCardinality(ctx, left), columnCardinality(ctx, right))
+	if cardinality == 0 {
+		return equalitySelectivity
+	}
+	return 1 / float64(cardinality)
+}
+
+// columnCardinality returns the number of distinct values of a column, or 0 when it is not known
+func columnCardinality(ctx *plancontext.PlanningContext, expr sqlparser.Expr) uint64 {
+	col, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return 0
+	}
+	ti, err := ctx.SemTable.TableInfoForExpr(col)
+	if err != nil {
+		return 0
+	}
+	vtbl := ti.GetVindexTable()
+	if vtbl == nil {
+		return 0
+	}
+	return vtbl.Statistics.ColumnCardinality(col.Name)
+}
+
+// useHashJoinIfCheaper replaces an ApplyJoin by a HashJoin when the cost model estimates that running both sides
+// once and joining their rows at the vtgate is cheaper than running the RHS for every row of the LHS.
+// For outer joins, the probe table can be built on either side, so both variants are considered.
+func useHashJoinIfCheaper(ctx *plancontext.PlanningContext, join, lhs, rhs Operator, joinPredicates []sqlparser.Expr, joinType sqlparser.JoinType) Operator {
+	if _, isApplyJoin := join.(*ApplyJoin); !isApplyJoin || !canUseHashJoin(ctx, lhs, rhs, joinPredicates) {
+		return join
+	}
+
+	candidates := []*HashJoin{newHashJoinWith(ctx, lhs, rhs, joinPredicates, !joinType.IsInner())}
+	if !joinType.IsInner() {
+		swapped := newHashJoinWith(ctx, rhs, lhs, joinPredicates, false)
+		swapped.RightJoin = true
+		candidates = append(candidates, swapped)
+	}
+
+	best, bestCost := join, estimateCost(ctx, join).cost
+	for _, hj := range candidates {
+		if cost := estimateCost(ctx, hj).cost; cost < bestCost {
+			best, bestCost = hj, cost
+		}
+	}
+	return best
+}
+
+func newHashJoinWith(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredicates []sqlparser.Expr, leftJoin bool) *HashJoin {
+	hj := NewHashJoin(Clone(lhs), Clone(rhs), leftJoin)
+	for _, pred := range joinPredicates {
+		hj.AddJoinPredicate(ctx, pred)
+	}
+	return hj
+}
+
+// canUseHashJoin returns true when the join predicates can be evaluated by a hash join. At least one of them
+// has to be an equality between an expression of each side, where the type of both expressions is known.
+// The other predicates are evaluated on the rows that match, so they have to be supported by the evalengine.
+func canUseHashJoin(ctx *plancontext.PlanningContext, lhs, rhs Operator, joinPredicates []sqlparser.Expr) bool {
+	if _, isSelect := ctx.Statement.(sqlparser.SelectStatement); !isSelect {
+		return false
+	}
+
+	hashable := false
+	for _, pred := range joinPredicates {
+		if isHashableComparison(ctx, lhs, rhs, pred) {
+			hashable = true
+			continue
+		}
+		if !canEvaluateAtVTGate(ctx, pred) {
+			return false
+		}
+	}
+	return hashable
+}
+
+func isHashableComparison(ctx *plancontext.PlanningContext, lhs, rhs Operator, pred sqlparser.Expr) bool {
+	cmp, ok := pred.(*sqlparser.ComparisonExpr)
+	if !ok || !canBeSolvedWithHashJoin(cmp.Operator) {
+		return false
+	}
+
+	lID, rID := TableID(lhs), TableID(rhs)
+	lDeps, rDeps := ctx.SemTable.RecursiveDeps(cmp.Left), ctx.SemTable.RecursiveDeps(cmp.Right)
+	switch {
+	case lDeps.IsSolvedBy(lID) && rDeps.IsSolvedBy(rID):
+	case lDeps.IsSolvedBy(rID) && rDeps.IsSolvedBy(lID):
+	default:
+		return false
+	}
+
+	ltyp, lFound := ctx.TypeForExpr(cmp.Left)
+	rtyp, rFound := ctx.TypeForExpr(cmp.Right)
+	if !lFound || !rFound || ltyp.Type() == sqltypes.Unknown || rtyp.Type() == sqltypes.Unknown {
+		return false
+	}
+	_, err := evalengine.CoerceTypes(ltyp, rtyp, ctx.VSchema.Environment().CollationEnv())
+	return err == nil
+}
+
+// canEvaluateAtVTGate returns true if the evalengine supports the expression
+func canEvaluateAtVTGate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) bool {
+	_, err := evalengine.Translate(expr, &evalengine.Config{
+		ResolveColumn:   func(*sqlparser.ColName) (int, error) { return 0, nil },
+		ResolveType:     ctx.TypeForExpr,
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
+	})
+	return err == nil
+}
@@ -117,7 +117,7 @@ func deleteWithInputPlanningRequired(childFks []vindexes.ChildFKInfo, deleteStmt
 }
 
 func createDeleteWithInputOp(ctx *plancontext.PlanningContext, del *sqlparser.Delete) (op Operator) {
-	delClone := ctx.SemTable.Clone(del).(*sqlparser.Delete)
+	delClone := ctx.SemTable.CloneDML(del).(*sqlparser.Delete)
 	del.Limit = nil
 	del.OrderBy = nil
 
@@ -229,6 +229,10 @@ func createDeleteOperator(ctx *plancontext.PlanningContext, del *sqlparser.Delet
 	if err != nil {
 		panic(err)
 	}
+	if _, isATable := tblInfo.(*semantics.RealTable); !isATable {
+		// the target is a derived table, e.g. a common table expression
+		panic(vterrors.VT03004(del.Targets[0].Name.String()))
+	}
 
 	vTbl := tblInfo.GetVindexTable()
 	// Reference table should delete from the source table.
@@ -127,9 +127,10 @@ func (f *Filter) Compact(*plancontext.PlanningContext) (Operator, *ApplyResult)
 
 func (f *Filter) planOffsets(ctx *plancontext.PlanningContext) Operator {
 	cfg := &evalengine.Config{
-		ResolveType: ctx.TypeForExpr,
-		Collation:   ctx.SemTable.Collation,
-		Environment: ctx.VSchema.Environment(),
+		ResolveType:     ctx.TypeForExpr,
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
 	}
 
 	predicate := sqlparser.AndExpressions(f.Predicates...)
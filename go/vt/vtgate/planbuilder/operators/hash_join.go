@@ -36,9 +36,17 @@ type (
 		// LeftJoin will be true in the case of an outer join
 		LeftJoin bool
 
+		// RightJoin will be true when the rows of the RHS that have no match are kept.
+		// The planner uses it to build the probe table on the smaller side of an outer join.
+		RightJoin bool
+
 		// Before offset planning
 		JoinComparisons []Comparison
 
+		// ResidualPredicates are the join predicates that can't be used to hash the rows,
+		// they are evaluated on the rows that have matching hash keys
+		ResidualPredicates []sqlparser.Expr
+
 		// These columns are the output columns of the hash join. While in operator mode we keep track of complex expression,
 		// but once we move to the engine primitives, the hash join only passes through column from either left or right.
 		// anything more complex will be solved by a projection on top of the hash join
@@ -53,6 +61,11 @@ type (
 		// These are the values that will be hashed together
 		LHSKeys, RHSKeys []int
 
+		// Residual is the evaluated form of the residual predicates, using the columns in ResidualCols.
+		// ResidualCols uses the same encoding as ColumnOffsets.
+		Residual     evalengine.Expr
+		ResidualCols []int
+
 		offset bool
 	}
 
@@ -94,6 +107,8 @@ func (hj *HashJoin) Clone(inputs []Operator) Operator {
 	kopy.LHSKeys = slices.Clone(hj.LHSKeys)
 	kopy.RHSKeys = slices.Clone(hj.RHSKeys)
 	kopy.JoinComparisons = slices.Clone(hj.JoinComparisons)
+	kopy.ResidualPredicates = slices.Clone(hj.ResidualPredicates)
+	kopy.ResidualCols = slices.Clone(hj.ResidualCols)
 	return &kopy
 }
 
@@ -106,6 +121,20 @@ func (hj *HashJoin) SetInputs(operators []Operator) {
 }
 
 func (hj *HashJoin) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	switch {
+	case hj.LeftJoin && hj.RightJoin:
+		// both sides are kept by a full outer join, so the predicates have to be evaluated after it
+		return newFilterSinglePredicate(hj, expr)
+	case hj.RightJoin && canConvertToInner(ctx, expr, TableID(hj.LHS)):
+		hj.MakeInner()
+	case hj.RightJoin:
+		// the RHS is the side that is kept, so only the predicates on the RHS can be pushed down
+		if ctx.SemTable.DirectDeps(expr).IsSolvedBy(TableID(hj.RHS)) {
+			hj.RHS = hj.RHS.AddPredicate(ctx, expr)
+			return hj
+		}
+		return newFilterSinglePredicate(hj, expr)
+	}
 	return AddPredicate(ctx, hj, expr, false, newFilterSinglePredicate)
 }
 
@@ -158,6 +187,9 @@ func (hj *HashJoin) planOffsets(ctx *plancontext.PlanningContext) Operator {
 		rOffset := hj.RHS.AddColumn(ctx, true, false, aeWrap(cmp.RHS))
 		hj.RHSKeys = append(hj.RHSKeys, rOffset)
 	}
+	if len(hj.ResidualPredicates) > 0 {
+		hj.Residual = hj.planResidual(ctx)
+	}
 
 	needsProj := false
 	lID := TableID(hj.LHS)
@@ -213,6 +245,9 @@ func (hj *HashJoin) ShortDescription() string {
 	comparisons := slice.Map(hj.JoinComparisons, func(from Comparison) string {
 		return from.String()
 	})
+	for _, pred := range hj.ResidualPredicates {
+		comparisons = append(comparisons, sqlparser.String(pred))
+	}
 	cmp := strings.Join(comparisons, " AND ")
 
 	if len(hj.columns.columns) > 0 {
@@ -256,16 +291,30 @@ func (hj *HashJoin) SetRHS(op Operator) {
 
 func (hj *HashJoin) MakeInner() {
 	hj.LeftJoin = false
+	hj.RightJoin = false
 }
 
 func (hj *HashJoin) IsInner() bool {
-	return !hj.LeftJoin
+	return !hj.LeftJoin && !hj.RightJoin
 }
 
+// AddJoinPredicate adds a predicate to the join condition. Equality comparisons between the two sides
+// are used to hash the rows, all the other predicates are evaluated on the rows that match by hash.
 func (hj *HashJoin) AddJoinPredicate(ctx *plancontext.PlanningContext, expr sqlparser.Expr) {
+	cmp, ok := hj.hashComparison(ctx, expr)
+	if !ok {
+		hj.ResidualPredicates = append(hj.ResidualPredicates, expr)
+		return
+	}
+	hj.JoinComparisons = append(hj.JoinComparisons, cmp)
+}
+
+// hashComparison returns the comparison to hash the rows with, if the predicate is an equality
+// between an expression of each side of the join
+func (hj *HashJoin) hashComparison(ctx *plancontext.PlanningContext, expr sqlparser.Expr) (Comparison, bool) {
 	cmp, ok := expr.(*sqlparser.ComparisonExpr)
 	if !ok || !canBeSolvedWithHashJoin(cmp.Operator) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+		return Comparison{}, false
 	}
 	lExpr := cmp.Left
 	lDeps := ctx.SemTable.RecursiveDeps(lExpr)
@@ -280,13 +329,13 @@ func (hj *HashJoin) AddJoinPredicate(ctx *plancontext.PlanningContext, expr sqlp
 	}
 
 	if !lDeps.IsSolvedBy(lID) || !rDeps.IsSolvedBy(rID) {
-		panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+		return Comparison{}, false
 	}
 
-	hj.JoinComparisons = append(hj.JoinComparisons, Comparison{
+	return Comparison{
 		LHS: lExpr,
 		RHS: rExpr,
-	})
+	}, true
 }
 
 func canBeSolvedWithHashJoin(op sqlparser.ComparisonExprOperator) bool {
@@ -358,9 +407,10 @@ func (hj *HashJoin) addColumn(ctx *plancontext.PlanningContext, in sqlparser.Exp
 
 	rewrittenExpr := sqlparser.CopyOnRewrite(in, pre, r.post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
 	cfg := &evalengine.Config{
-		ResolveType: ctx.TypeForExpr,
-		Collation:   ctx.SemTable.Collation,
-		Environment: ctx.VSchema.Environment(),
+		ResolveType:     ctx.TypeForExpr,
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
 	}
 	eexpr, err := evalengine.Translate(rewrittenExpr, cfg)
 	if err != nil {
@@ -377,6 +427,50 @@ func (hj *HashJoin) addColumn(ctx *plancontext.PlanningContext, in sqlparser.Exp
 	}, isPureOffset
 }
 
+// planResidual rewrites the residual predicates to use the columns fetched from the inputs,
+// and adds these columns to ResidualCols
+func (hj *HashJoin) planResidual(ctx *plancontext.PlanningContext) evalengine.Expr {
+	lID, rID := TableID(hj.LHS), TableID(hj.RHS)
+	r := new(replacer)
+	pre := func(node, parent sqlparser.SQLNode) bool {
+		expr, ok := node.(sqlparser.Expr)
+		if !ok || !mustFetchFromInput(ctx, expr) {
+			return true
+		}
+		deps := ctx.SemTable.RecursiveDeps(expr)
+		var offset int
+		switch {
+		case deps.IsSolvedBy(lID):
+			offset = lhsOffset(hj.LHS.AddColumn(ctx, true, false, aeWrap(expr)))
+		case deps.IsSolvedBy(rID):
+			offset = rhsOffset(hj.RHS.AddColumn(ctx, true, false, aeWrap(expr)))
+		default:
+			panic(vterrors.VT12001(fmt.Sprintf("can't use [%s] with hash joins", sqlparser.String(expr))))
+		}
+		idx := slices.Index(hj.ResidualCols, offset)
+		if idx < 0 {
+			idx = len(hj.ResidualCols)
+			hj.ResidualCols = append(hj.ResidualCols, offset)
+		}
+		r.replaceExpr = sqlparser.NewOffset(idx, expr)
+		return false
+	}
+
+	residual := sqlparser.AndExpressions(hj.ResidualPredicates...)
+	rewrittenExpr := sqlparser.CopyOnRewrite(residual, pre, r.post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
+	cfg := &evalengine.Config{
+		ResolveType:     ctx.TypeForExpr,
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
+	}
+	eexpr, err := evalengine.Translate(rewrittenExpr, cfg)
+	if err != nil {
+		panic(err)
+	}
+	return eexpr
+}
+
 // JoinPredicate produces an AST representation of the join condition this join has
 func (hj *HashJoin) JoinPredicate() sqlparser.Expr {
 	exprs := slice.Map(hj.JoinComparisons, func(from Comparison) sqlparser.Expr {
@@ -385,7 +479,7 @@ func (hj *HashJoin) JoinPredicate() sqlparser.Expr {
 			Right: from.RHS,
 		}
 	})
-	return sqlparser.AndExpressions(exprs...)
+	return sqlparser.AndExpressions(append(exprs, hj.ResidualPredicates...)...)
 }
 
 type replacer struct {
@@ -458,9 +552,10 @@ func (hj *HashJoin) addSingleSidedColumn(
 
 	rewrittenExpr := sqlparser.CopyOnRewrite(in, pre, r.post, ctx.SemTable.CopySemanticInfo).(sqlparser.Expr)
 	cfg := &evalengine.Config{
-		ResolveType: ctx.TypeForExpr,
-		Collation:   ctx.SemTable.Collation,
-		Environment: ctx.VSchema.Environment(),
+		ResolveType:     ctx.TypeForExpr,
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
 	}
 	eexpr, err := evalengine.Translate(rewrittenExpr, cfg)
 	if err != nil {
@@ -91,6 +91,14 @@ func (h *Horizon) AddPredicate(ctx *plancontext.PlanningContext, expr sqlparser.
 		h.Source = h.Source.AddPredicate(ctx, expr)
 		return h
 	}
+	if _, isRecursive := h.Source.(*RecurseCTE); isRecursive {
+		// predicates can't be pushed into a recursive CTE, since that would change the rows the recursion sees
+		return newFilter(h, expr)
+	}
+	if sel, isSel := h.Query.(*sqlparser.Select); isSel && sqlparser.ContainsWindowFunc(sel.SelectExprs) {
+		// predicates can't be pushed below window functions, since that would change the rows they see
+		return newFilter(h, expr)
+	}
 	tableInfo, err := ctx.SemTable.TableInfoForExpr(expr)
 	if err != nil {
 		if errors.Is(err, semantics.ErrNotSingleTable) {
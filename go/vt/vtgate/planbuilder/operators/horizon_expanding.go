@@ -181,11 +181,17 @@ func createProjectionFromSelect(ctx *plancontext.PlanningContext, horizon *Horiz
 	}
 
 	if qp.NeedsAggregation() {
+		if qp.HasWindow {
+			panic(vterrors.VT12001("window functions combined with aggregation in a sharded query"))
+		}
 		return createProjectionWithAggr(ctx, qp, dt, horizon.src())
 	}
 
 	projX := createProjectionWithoutAggr(ctx, qp, horizon.src())
 	projX.DT = dt
+	if qp.HasWindow {
+		projX.Source = createWindows(ctx, sqlparser.GetFirstSelect(horizon.Query), projX.Source)
+	}
 	return projX
 }
 
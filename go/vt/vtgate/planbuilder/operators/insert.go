@@ -17,6 +17,7 @@ limitations under the License.
 package operators
 
 import (
+	"fmt"
 	"strconv"
 
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
@@ -118,20 +119,38 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 		deleteBeforeInsert = true
 	}
 
-	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+	if ins.Action == sqlparser.ReplaceAct && vTbl.Keyspace.Sharded {
+		// without the keys of the table, the rows clashing with the replaced ones can't be found across shards.
+		panic(vterrors.VT12001("REPLACE INTO with sharded keyspace"))
+	}
 
 	if !deleteBeforeInsert {
-		return insOp
+		return checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
 	}
 
-	rows, isRows := ins.Rows.(sqlparser.Values)
-	if !isRows {
-		panic(vterrors.VT12001("REPLACE INTO using select statement"))
+	if _, isRows := ins.Rows.(sqlparser.Values); !isRows {
+		// the insert operator can add the auto-increment column to the column list, which is not part of the selected rows.
+		columns := sqlparser.Clone(ins.Columns)
+		insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+		if len(columns) == 0 {
+			columns = ins.Columns
+		}
+		return replaceSelectPlan(ctx, ins, columns, vTbl, insOp)
 	}
 
-	pkCompExpr := pkCompExpression(vTbl, ins, rows)
-	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, ins, rows)
+	// the insert operator rewrites the columns and row values of the statement into bind variables
+	// that are only set when the insert executes, so the delete is built from the original statement.
+	origIns := sqlparser.Clone(ins)
+	insOp := checkAndCreateInsertOperator(ctx, ins, vTbl, routing)
+
+	rows := origIns.Rows.(sqlparser.Values)
+	pkCompExpr := pkCompExpression(vTbl, origIns, rows)
+	uniqKeyCompExprs := uniqKeyCompExpressions(vTbl, origIns, rows)
 	whereExpr := getWhereCondExpr(append(uniqKeyCompExprs, pkCompExpr))
+	if whereExpr == nil {
+		// no row can clash with the inserted rows, it is a plain insert.
+		return insOp
+	}
 
 	delStmt := &sqlparser.Delete{
 		Comments:   ins.Comments,
@@ -142,6 +161,84 @@ func createOperatorFromInsert(ctx *plancontext.PlanningContext, ins *sqlparser.I
 	return &Sequential{Sources: []Operator{delOp, insOp}}
 }
 
+// replaceSelectPlan plans a REPLACE INTO ... SELECT as an insert of the selected rows,
+// preceded by the delete of the rows clashing with them on the primary key or any unique key.
+// The delete takes the values of each key from the selected rows in a list bind variable.
+func replaceSelectPlan(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, columns sqlparser.Columns, vTbl *vindexes.Table, insOp Operator) Operator {
+	insSel, ok := insOp.(*InsertSelection)
+	if !ok {
+		if lc, isLC := insOp.(*LockAndComment); isLC {
+			insSel, ok = lc.Source.(*InsertSelection)
+		}
+	}
+	if !ok {
+		panic(vterrors.VT13001(fmt.Sprintf("unexpected operator for REPLACE INTO using select statement: %T", insOp)))
+	}
+
+	var keys [][]sqlparser.IdentifierCI
+	if len(vTbl.PrimaryKey) > 0 {
+		keys = append(keys, vTbl.PrimaryKey)
+	}
+	for _, uniqKey := range vTbl.UniqueKeys {
+		var cols []sqlparser.IdentifierCI
+		for _, expr := range uniqKey {
+			col, isCol := expr.(*sqlparser.ColName)
+			if !isCol {
+				panic(vterrors.VT12001("REPLACE INTO using select statement on a table with functional unique keys"))
+			}
+			cols = append(cols, col.Name)
+		}
+		keys = append(keys, cols)
+	}
+
+	var whereExpr sqlparser.Expr
+	for _, key := range keys {
+		offsets := make([]int, 0, len(key))
+		var colTuple sqlparser.ValTuple
+		for _, col := range key {
+			idx := columns.FindColumn(col)
+			if idx == -1 {
+				if findDefault(vTbl, col) != nil {
+					panic(vterrors.VT12001(fmt.Sprintf("REPLACE INTO using select statement without the key column %s", col.String())))
+				}
+				// If default value is empty, nothing to compare as it will always be false.
+				offsets = nil
+				break
+			}
+			offsets = append(offsets, idx)
+			colTuple = append(colTuple, sqlparser.NewColName(col.String()))
+		}
+		if offsets == nil {
+			continue
+		}
+
+		// optimize for case when there is only single column on left hand side.
+		var lhs sqlparser.Expr = colTuple
+		if len(colTuple) == 1 {
+			lhs = colTuple[0]
+		}
+		compExpr := sqlparser.NewComparisonExpr(sqlparser.InOp, lhs, sqlparser.ListArg(engine.ReplaceKeyVals(len(insSel.ReplaceKeys))), nil)
+		insSel.ReplaceKeys = append(insSel.ReplaceKeys, offsets)
+		if whereExpr == nil {
+			whereExpr = compExpr
+			continue
+		}
+		whereExpr = &sqlparser.OrExpr{Left: whereExpr, Right: compExpr}
+	}
+	if whereExpr == nil {
+		// no row can clash with the selected rows, it is a plain insert.
+		return insOp
+	}
+
+	delStmt := &sqlparser.Delete{
+		Comments:   ins.Comments,
+		TableExprs: sqlparser.TableExprs{sqlparser.Clone(ins.Table)},
+		Where:      sqlparser.NewWhere(sqlparser.WhereClause, whereExpr),
+	}
+	insSel.Replace = createOpFromStmt(ctx, delStmt, false, "")
+	return insOp
+}
+
 func checkAndCreateInsertOperator(ctx *plancontext.PlanningContext, ins *sqlparser.Insert, vTbl *vindexes.Table, routing Routing) Operator {
 	insOp := createInsertOperator(ctx, ins, vTbl, routing)
 
@@ -201,6 +298,9 @@ func pkCompExpression(vTbl *vindexes.Table, ins *sqlparser.Insert, rows sqlparse
 		return nil
 	}
 	pIndexes, pColTuple := findPKIndexes(vTbl, ins)
+	if pIndexes == nil {
+		return nil
+	}
 
 	var pValTuple sqlparser.ValTuple
 	for _, row := range rows {
@@ -241,6 +341,10 @@ func findPKIndexes(vTbl *vindexes.Table, ins *sqlparser.Insert) (pIndexes []pCom
 }
 
 func findDefault(vTbl *vindexes.Table, pCol sqlparser.IdentifierCI) sqlparser.Expr {
+	if vTbl.AutoIncrement != nil && vTbl.AutoIncrement.Column.Equal(pCol) {
+		// the value is generated from the sequence, it cannot clash with an existing row.
+		return nil
+	}
 	for _, column := range vTbl.Columns {
 		if column.Name.Equal(pCol) {
 			return column.Default
@@ -506,9 +610,10 @@ func insertRowsPlan(ctx *plancontext.PlanningContext, insOp *Insert, ins *sqlpar
 			colNum, _ := findOrAddColumn(ins, col)
 			for rowNum, row := range rows {
 				innerpv, err := evalengine.Translate(row[colNum], &evalengine.Config{
-					ResolveType: ctx.TypeForExpr,
-					Collation:   ctx.SemTable.Collation,
-					Environment: ctx.VSchema.Environment(),
+					ResolveType:     ctx.TypeForExpr,
+					Collation:       ctx.SemTable.Collation,
+					Environment:     ctx.VSchema.Environment(),
+					ResolveFunction: ctx.ResolveDeterministicFunction,
 				})
 				if err != nil {
 					panic(err)
@@ -29,25 +29,37 @@ type InsertSelection struct {
 	// ForceNonStreaming when true, select first then insert, this is to avoid locking rows by select for insert.
 	ForceNonStreaming bool
 
+	// Replace deletes the rows clashing with the selected rows before they are inserted, for a REPLACE statement.
+	// ReplaceKeys are the offsets of the columns of each key it deletes on, in the selected rows.
+	Replace     Operator
+	ReplaceKeys [][]int
+
 	noColumns
 	noPredicates
 }
 
 func (is *InsertSelection) Clone(inputs []Operator) Operator {
-	return &InsertSelection{
-		Select:            inputs[0],
-		Insert:            inputs[1],
+	clone := &InsertSelection{
 		ForceNonStreaming: is.ForceNonStreaming,
+		ReplaceKeys:       is.ReplaceKeys,
 	}
+	clone.SetInputs(inputs)
+	return clone
 }
 
 func (is *InsertSelection) Inputs() []Operator {
-	return []Operator{is.Select, is.Insert}
+	if is.Replace == nil {
+		return []Operator{is.Select, is.Insert}
+	}
+	return []Operator{is.Select, is.Insert, is.Replace}
 }
 
 func (is *InsertSelection) SetInputs(inputs []Operator) {
 	is.Select = inputs[0]
 	is.Insert = inputs[1]
+	if len(inputs) > 2 {
+		is.Replace = inputs[2]
+	}
 }
 
 func (is *InsertSelection) ShortDescription() string {
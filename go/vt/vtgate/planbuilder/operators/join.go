@@ -30,6 +30,9 @@ type Join struct {
 	// NormalJoinType, StraightJoinType and LeftJoinType.
 	JoinType sqlparser.JoinType
 
+	// lateral is set when the RHS is a lateral derived table using columns of the LHS
+	lateral *lateral
+
 	noColumns
 }
 
@@ -45,6 +48,7 @@ func (j *Join) Clone(inputs []Operator) Operator {
 		RHS:       inputs[1],
 		Predicate: j.Predicate,
 		JoinType:  j.JoinType,
+		lateral:   j.lateral,
 	}
 }
 
@@ -63,7 +67,7 @@ func (j *Join) SetInputs(ops []Operator) {
 }
 
 func (j *Join) Compact(ctx *plancontext.PlanningContext) (Operator, *ApplyResult) {
-	if !j.JoinType.IsCommutative() {
+	if !j.JoinType.IsCommutative() || j.lateral != nil {
 		// if we can't move tables around, we can't merge these inputs
 		return j, NoRewrite
 	}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// JSONTable is a JSON_TABLE in the FROM clause. When it can't be merged into the route
+// of the tables it uses, it is evaluated at the vtgate level.
+type JSONTable struct {
+	ID semantics.TableSet
+
+	// Expr is the JSON_TABLE. The columns of the tables it depends on have been replaced by arguments.
+	Expr *sqlparser.JSONTableExpr
+
+	// Columns are the columns of the JSON_TABLE that are used by the query
+	Columns []*sqlparser.ColName
+
+	noInputs
+}
+
+var _ Operator = (*JSONTable)(nil)
+
+// createJSONTableJoin creates the join between the LHS and a JSON_TABLE whose document can use the columns of the LHS.
+// The columns are passed in as arguments, so that the JSON_TABLE can be evaluated once for every row of the LHS.
+func createJSONTableJoin(
+	ctx *plancontext.PlanningContext,
+	lhs Operator,
+	tableExpr *sqlparser.JSONTableExpr,
+	joinType sqlparser.JoinType,
+) *Join {
+	if ctx.SemTable.RecursiveDeps(tableExpr.Expr).IsEmpty() {
+		// the JSON_TABLE doesn't use the LHS, so it's joined like any other table
+		return &Join{
+			LHS:      lhs,
+			RHS:      newJSONTableRoute(ctx, tableExpr),
+			JoinType: joinType,
+		}
+	}
+	lhsID := TableID(lhs)
+	rhs := newJSONTable(ctx, tableExpr, lhsID)
+	jc := breakExpressionInLHSandRHS(ctx, tableExpr.Expr, lhsID)
+	var vars []BindVarExpr
+	for _, bve := range jc.LHSExprs {
+		if !slices.ContainsFunc(vars, func(v BindVarExpr) bool { return v.Name == bve.Name }) {
+			vars = append(vars, bve)
+		}
+	}
+
+	rhs.Expr.Expr = jc.RHSExpr
+	return &Join{
+		LHS:      lhs,
+		RHS:      rhs,
+		JoinType: joinType,
+		lateral:  &lateral{vars: vars},
+	}
+}
+
+// newJSONTable creates the operator for the JSON_TABLE. Its document can only use
+// the columns of the outer tables, which come before it in the FROM clause
+func newJSONTable(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JSONTableExpr, outer semantics.TableSet) *JSONTable {
+	if !ctx.SemTable.RecursiveDeps(tableExpr.Expr).IsSolvedBy(outer) {
+		panic(vterrors.VT12001("JSON_TABLE using columns of an outer query"))
+	}
+	return &JSONTable{
+		ID:   ctx.SemTable.TableSetForJSONTable(tableExpr),
+		Expr: sqlparser.CloneRefOfJSONTableExpr(tableExpr),
+	}
+}
+
+// newJSONTableRoute creates the operator for a JSON_TABLE that doesn't use any other table.
+// It doesn't read from any table either, so like the dual table it can be merged into any route.
+func newJSONTableRoute(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JSONTableExpr) *Route {
+	return &Route{
+		Source:  newJSONTable(ctx, tableExpr, semantics.EmptyTableSet()),
+		Routing: &DualRouting{},
+	}
+}
+
+// Clone implements the Operator interface
+func (jt *JSONTable) Clone([]Operator) Operator {
+	klone := *jt
+	klone.Columns = slices.Clone(jt.Columns)
+	return &klone
+}
+
+func (jt *JSONTable) introducesTableID() semantics.TableSet {
+	return jt.ID
+}
+
+// AddPredicate implements the Operator interface
+func (jt *JSONTable) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(jt, expr)
+}
+
+func (jt *JSONTable) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, ae *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := jt.FindCol(ctx, ae.Expr, true)
+		if offset > -1 {
+			return offset
+		}
+	}
+	if _, ok := ae.Expr.(*sqlparser.ColName); !ok {
+		panic(vterrors.VT12001(fmt.Sprintf("evaluating '%s' on a JSON_TABLE", sqlparser.String(ae.Expr))))
+	}
+
+	return addColumn(ctx, jt, ae.Expr)
+}
+
+func (*JSONTable) AddWSColumn(*plancontext.PlanningContext, int, bool) int {
+	panic(vterrors.VT13001("did not expect this method to be called"))
+}
+
+func (jt *JSONTable) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range jt.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (jt *JSONTable) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return slice.Map(jt.Columns, colNameToExpr)
+}
+
+func (jt *JSONTable) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, jt)
+}
+
+func (jt *JSONTable) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (jt *JSONTable) GetColNames() []*sqlparser.ColName {
+	return jt.Columns
+}
+
+func (jt *JSONTable) AddCol(col *sqlparser.ColName) {
+	jt.Columns = append(jt.Columns, col)
+}
+
+func (jt *JSONTable) ShortDescription() string {
+	return fmt.Sprintf("%s AS %s", sqlparser.String(jt.Expr.Expr), jt.Expr.Alias.String())
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// lateral holds how a lateral derived table on the RHS of a join uses the LHS
+type lateral struct {
+	// predicates are the predicates of the derived table that use columns of the LHS, as written in the query.
+	// They are only used to check if the two sides can be merged into a single route.
+	predicates []sqlparser.Expr
+
+	// vars are the columns of the LHS that the derived table uses. They are passed in as arguments.
+	vars []BindVarExpr
+}
+
+var lateralCorrelationErr = vterrors.VT12001("lateral derived table that uses the outer query outside of its WHERE and HAVING predicates")
+
+// getLateralTable returns the table expression if it is a lateral derived table or a JSON_TABLE,
+// which can both use the tables that come before them in the FROM clause
+func getLateralTable(tableExpr sqlparser.TableExpr) sqlparser.TableExpr {
+	switch tableExpr := tableExpr.(type) {
+	case *sqlparser.JSONTableExpr:
+		return tableExpr
+	case *sqlparser.AliasedTableExpr:
+		dt, ok := tableExpr.Expr.(*sqlparser.DerivedTable)
+		if ok && dt.Lateral {
+			return tableExpr
+		}
+	}
+	return nil
+}
+
+func createLateralJoinFromJoinTableExpr(
+	ctx *plancontext.PlanningContext,
+	tableExpr *sqlparser.JoinTableExpr,
+	lhs Operator,
+	lateralTbl sqlparser.TableExpr,
+) Operator {
+	switch tableExpr.Join {
+	case sqlparser.NormalJoinType, sqlparser.StraightJoinType:
+		join := createLateralJoin(ctx, lhs, lateralTbl, tableExpr.Join)
+		return addJoinPredicates(ctx, tableExpr.Condition.On, join)
+	case sqlparser.LeftJoinType:
+		join := createLateralJoin(ctx, lhs, lateralTbl, tableExpr.Join)
+
+		// mark the RHS as outer tables so we know which columns are nullable
+		ctx.OuterTables = ctx.OuterTables.Merge(TableID(join.RHS))
+
+		predicate := tableExpr.Condition.On
+		if subq, _ := getSubQuery(predicate); subq != nil {
+			panic(vterrors.VT12001("subquery in outer join predicate"))
+		}
+		sqlparser.RemoveKeyspaceInCol(predicate)
+		join.Predicate = predicate
+		return join
+	default:
+		if _, isJSONTable := lateralTbl.(*sqlparser.JSONTableExpr); isJSONTable {
+			panic(vterrors.VT12001(fmt.Sprintf("%s with JSON_TABLE", tableExpr.Join.ToString())))
+		}
+		panic(vterrors.VT12001(fmt.Sprintf("%s with a lateral derived table", tableExpr.Join.ToString())))
+	}
+}
+
+// createLateralJoin creates the join between the LHS and the lateral table using it
+func createLateralJoin(
+	ctx *plancontext.PlanningContext,
+	lhs Operator,
+	tableExpr sqlparser.TableExpr,
+	joinType sqlparser.JoinType,
+) *Join {
+	if jt, ok := tableExpr.(*sqlparser.JSONTableExpr); ok {
+		return createJSONTableJoin(ctx, lhs, jt, joinType)
+	}
+	return createLateralDerivedTableJoin(ctx, lhs, tableExpr.(*sqlparser.AliasedTableExpr), joinType)
+}
+
+// createLateralDerivedTableJoin creates the join between the LHS and a lateral derived table using it.
+// The columns of the LHS used in the predicates of the derived table are replaced by arguments,
+// so that the derived table can be evaluated once for every row of the LHS.
+func createLateralDerivedTableJoin(
+	ctx *plancontext.PlanningContext,
+	lhs Operator,
+	tableExpr *sqlparser.AliasedTableExpr,
+	joinType sqlparser.JoinType,
+) *Join {
+	dt := tableExpr.Expr.(*sqlparser.DerivedTable)
+	innerID := findTablesContained(ctx, dt.Select)
+	outerID := TableID(lhs)
+	jpc := &joinPredicateCollector{
+		totalID: innerID.Merge(outerID),
+		subqID:  innerID,
+		outerID: outerID,
+	}
+
+	for _, sel := range sqlparser.GetAllSelects(dt.Select) {
+		sel.Where = inspectLateralPredicates(ctx, jpc, sel.Where)
+		sel.Having = inspectLateralPredicates(ctx, jpc, sel.Having)
+	}
+	if usesOuterColumns(ctx, dt.Select, innerID) {
+		panic(lateralCorrelationErr)
+	}
+
+	var vars []BindVarExpr
+	for _, jc := range jpc.joinColumns {
+		for _, bve := range jc.LHSExprs {
+			if !slices.ContainsFunc(vars, func(v BindVarExpr) bool { return v.Name == bve.Name }) {
+				vars = append(vars, bve)
+			}
+		}
+	}
+
+	return &Join{
+		LHS:      lhs,
+		RHS:      getOperatorFromAliasedTableExpr(ctx, tableExpr, false),
+		JoinType: joinType,
+		lateral: &lateral{
+			predicates: jpc.predicates,
+			vars:       vars,
+		},
+	}
+}
+
+// inspectLateralPredicates rewrites the predicates using columns of the outer query to use arguments instead
+func inspectLateralPredicates(ctx *plancontext.PlanningContext, jpc *joinPredicateCollector, in *sqlparser.Where) *sqlparser.Where {
+	if in == nil {
+		return nil
+	}
+	jpc.remainingPredicates = nil
+	for _, predicate := range sqlparser.SplitAndExpression(nil, in.Expr) {
+		sqlparser.RemoveKeyspaceInCol(predicate)
+		if subq, _ := getSubQuery(predicate); subq != nil && !ctx.SemTable.RecursiveDeps(predicate).IsSolvedBy(jpc.subqID) {
+			panic(lateralCorrelationErr)
+		}
+		jpc.inspectPredicate(ctx, predicate)
+	}
+	in.Expr = sqlparser.AndExpressions(jpc.remainingPredicates...)
+	return in
+}
+
+// usesOuterColumns returns true if the statement still uses columns that are not coming from its own tables
+func usesOuterColumns(ctx *plancontext.PlanningContext, stmt sqlparser.SelectStatement, innerID semantics.TableSet) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if ok && !ctx.SemTable.RecursiveDeps(col).IsSolvedBy(innerID) {
+			found = true
+		}
+		return !found, nil
+	}, stmt)
+	return found
+}
+
+// optimizeLateralJoin merges the lateral derived table with the LHS if they are sent to the same shard,
+// and otherwise plans it as an ApplyJoin that evaluates the derived table for every row of the LHS
+func optimizeLateralJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
+	predicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	if route := mergeLateralJoin(ctx, op, predicates); route != nil {
+		return route, Rewrote("merge lateral derived table into the route of the outer query")
+	}
+
+	join := NewApplyJoin(ctx, Clone(op.LHS), Clone(op.RHS), nil, op.JoinType)
+	join.ExtraLHSVars = slices.Clone(op.lateral.vars)
+	join.lateral = true
+	return pushJoinPredicates(ctx, predicates, join), Rewrote("lateral join to applyJoin")
+}
+
+func mergeLateralJoin(ctx *plancontext.PlanningContext, op *Join, predicates []sqlparser.Expr) *Route {
+	if isJSONTable(op.RHS) {
+		return mergeJSONTable(ctx, op, predicates)
+	}
+	lhsRoute, rhsRoute, routingA, routingB, a, b, sameKeyspace := prepareInputRoutes(op.LHS, op.RHS)
+	if lhsRoute == nil {
+		return nil
+	}
+
+	var routing Routing
+	switch {
+	case a == sharded && b == sharded:
+		// the shards the RHS is sent to depend on the row of the LHS, so we can only merge when
+		// the predicates of the derived table keep it on the same shard as the row of the LHS
+		if !sameKeyspace || !canMergeOnFilters(ctx, lhsRoute, rhsRoute, op.lateral.predicates) {
+			return nil
+		}
+		routing = routingA
+	case b == sharded || a == infoSchema || b == infoSchema:
+		return nil
+	case a == dual:
+		routing = routingB
+	case b == dual:
+		routing = routingA
+	case sameKeyspace:
+		routing = routingA
+	default:
+		return nil
+	}
+
+	join := NewApplyJoin(ctx, lhsRoute.Source, rhsRoute.Source, ctx.SemTable.AndExpressions(predicates...), op.JoinType)
+	join.ExtraLHSVars = op.lateral.vars
+	join.lateral = true
+	return &Route{
+		Source:     join,
+		MergedWith: []*Route{rhsRoute},
+		Routing:    routing,
+	}
+}
+
+// isJSONTable returns true if the operator is a JSON_TABLE, possibly with filters on top of it
+func isJSONTable(op Operator) bool {
+	switch op := op.(type) {
+	case *JSONTable:
+		return true
+	case *Filter:
+		return isJSONTable(op.Source)
+	default:
+		return false
+	}
+}
+
+// mergeJSONTable pushes the JSON_TABLE into the route of the LHS. It doesn't read from any table,
+// so the route can evaluate it no matter which shards it is sent to.
+func mergeJSONTable(ctx *plancontext.PlanningContext, op *Join, predicates []sqlparser.Expr) *Route {
+	lhsRoute, ok := op.LHS.(*Route)
+	if !ok {
+		return nil
+	}
+	join := NewApplyJoin(ctx, lhsRoute.Source, op.RHS, ctx.SemTable.AndExpressions(predicates...), op.JoinType)
+	join.ExtraLHSVars = op.lateral.vars
+	join.lateral = true
+	return &Route{
+		Source:     join,
+		MergedWith: lhsRoute.MergedWith,
+		Routing:    lhsRoute.Routing,
+	}
+}
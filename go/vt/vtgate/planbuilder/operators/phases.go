@@ -17,8 +17,6 @@ limitations under the License.
 package operators
 
 import (
-	"io"
-
 	"vitess.io/vitess/go/slice"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -37,6 +35,7 @@ const (
 	pullDistinctFromUnion
 	delegateAggregation
 	addAggrOrdering
+	addWindowOrdering
 	cleanOutPerfDistinct
 	dmlWithInput
 	subquerySettling
@@ -55,6 +54,8 @@ func (p Phase) String() string {
 		return "split aggregation between vtgate and mysql"
 	case addAggrOrdering:
 		return "optimize aggregations with ORDER BY"
+	case addWindowOrdering:
+		return "add ORDER BY for window functions"
 	case cleanOutPerfDistinct:
 		return "optimize Distinct operations"
 	case subquerySettling:
@@ -74,6 +75,8 @@ func (p Phase) shouldRun(s semantics.QuerySignature) bool {
 		return s.Aggregation
 	case addAggrOrdering:
 		return s.Aggregation
+	case addWindowOrdering:
+		return s.Window
 	case cleanOutPerfDistinct:
 		return s.Distinct
 	case subquerySettling:
@@ -93,6 +96,8 @@ func (p Phase) act(ctx *plancontext.PlanningContext, op Operator) Operator {
 		return enableDelegateAggregation(ctx, op)
 	case addAggrOrdering:
 		return addOrderingForAllAggregations(ctx, op)
+	case addWindowOrdering:
+		return addOrderingForAllWindows(ctx, op)
 	case cleanOutPerfDistinct:
 		return removePerformanceDistinctAboveRoute(ctx, op)
 	case subquerySettling:
@@ -155,11 +160,22 @@ func createDMLWithInput(ctx *plancontext.PlanningContext, op, src Operator, in *
 
 	dm.Source = proj
 
+	// A DML with LIMIT that spans shards resolves its rows with a limited select first,
+	// and the rows of the target table need to stay locked until the DML is applied to them by primary key.
+	// A shared lock taken by the DML already does that, so only an unlocked select is upgraded.
+	_, limited := src.(*Limit)
+
 	var targetTable *Table
 	_ = Visit(src, func(operator Operator) error {
-		if tbl, ok := operator.(*Table); ok && tbl.QTable.ID == in.Target.ID {
-			targetTable = tbl
-			return io.EOF
+		switch operator := operator.(type) {
+		case *Route:
+			if limited && operator.Lock == sqlparser.NoLock && TableID(operator).IsOverlapping(in.Target.ID) {
+				operator.Lock = sqlparser.ForUpdateLock
+			}
+		case *Table:
+			if operator.QTable.ID == in.Target.ID && targetTable == nil {
+				targetTable = operator
+			}
 		}
 		return nil
 	})
@@ -270,6 +286,45 @@ func needsOrdering(ctx *plancontext.PlanningContext, in *Aggregator) bool {
 	return false
 }
 
+func addOrderingForAllWindows(ctx *plancontext.PlanningContext, root Operator) Operator {
+	visitor := func(in Operator, _ semantics.TableSet, isRoot bool) (Operator, *ApplyResult) {
+		window, ok := in.(*Window)
+		if !ok {
+			return in, NoRewrite
+		}
+
+		requiredOrder := window.requiredOrdering()
+		if !needsOrderingFor(ctx, window.Source, requiredOrder) {
+			return in, NoRewrite
+		}
+		window.Source = &Ordering{
+			Source: window.Source,
+			Order:  requiredOrder,
+		}
+		return in, Rewrote("added ordering before window")
+	}
+
+	return BottomUp(root, TableID, visitor, stopAtRoute)
+}
+
+// needsOrderingFor returns true if the input is not already sorted in the required order
+func needsOrderingFor(ctx *plancontext.PlanningContext, src Operator, requiredOrder []OrderBy) bool {
+	if len(requiredOrder) == 0 {
+		return false
+	}
+	srcOrdering := src.GetOrdering(ctx)
+	if len(srcOrdering) < len(requiredOrder) {
+		return true
+	}
+	for idx, order := range requiredOrder {
+		if !ctx.SemTable.EqualsExprWithDeps(srcOrdering[idx].SimplifiedExpr, order.SimplifiedExpr) ||
+			srcOrdering[idx].Inner.Direction != order.Inner.Direction {
+			return true
+		}
+	}
+	return false
+}
+
 func addGroupByOnRHSOfJoin(root Operator) Operator {
 	visitor := func(in Operator, _ semantics.TableSet, isRoot bool) (Operator, *ApplyResult) {
 		join, ok := in.(*ApplyJoin)
@@ -284,16 +339,30 @@ func addGroupByOnRHSOfJoin(root Operator) Operator {
 }
 
 func addLiteralGroupingToRHS(in *ApplyJoin) (Operator, *ApplyResult) {
-	_ = Visit(in.RHS, func(op Operator) error {
-		aggr, isAggr := op.(*Aggregator)
-		if !isAggr {
-			return nil
-		}
-		if len(aggr.Grouping) == 0 {
+	if !in.scalarSubquery {
+		addLiteralGrouping(in.RHS, in.lateral)
+	}
+	return in, NoRewrite
+}
+
+// addLiteralGrouping adds a literal grouping to the aggregations without grouping.
+// With keepOriginal, the aggregations of the derived tables themselves are left as they are,
+// since a lateral derived table using aggregation has to return a row even when there is no input.
+func addLiteralGrouping(op Operator, keepOriginal bool) {
+	switch op := op.(type) {
+	case *Aggregator:
+		if len(op.Grouping) == 0 && !(keepOriginal && op.Original) {
 			gb := sqlparser.NewIntLiteral(".0")
-			aggr.Grouping = append(aggr.Grouping, NewGroupBy(gb))
+			op.Grouping = append(op.Grouping, NewGroupBy(gb))
 		}
-		return nil
-	})
-	return in, NoRewrite
+	case *ApplyJoin:
+		if op.scalarSubquery {
+			// the aggregations of a scalar subquery have to return a row even when there is no input
+			addLiteralGrouping(op.LHS, keepOriginal)
+			return
+		}
+	}
+	for _, input := range op.Inputs() {
+		addLiteralGrouping(input, keepOriginal)
+	}
 }
@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 // Projection is used when we need to evaluate expressions on the vtgate
@@ -228,6 +229,21 @@ func (p *Projection) canPush(ctx *plancontext.PlanningContext) bool {
 	return true
 }
 
+// canPushToKeyspace returns false if one of the projected expressions calls a function
+// that the keyspace does not declare, and that must be evaluated above the route
+func (p *Projection) canPushToKeyspace(ctx *plancontext.PlanningContext, keyspace *vindexes.Keyspace) bool {
+	ap, ok := p.Columns.(AliasedProjections)
+	if !ok {
+		return true
+	}
+	for _, pe := range ap {
+		if !ctx.CanPushToKeyspace(pe.EvalExpr, keyspace) {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *Projection) GetAliasedProjections() (AliasedProjections, error) {
 	switch cols := p.Columns.(type) {
 	case AliasedProjections:
@@ -376,6 +392,10 @@ func (p *Projection) addColumn(
 				ws.Expr = projExpr.EvalExpr
 			}
 		}
+		if ctx.CallsPluginFunction(ws.Expr) {
+			// the expression is evaluated by vtgate, so its weight string has to be as well
+			return p.addProjExpr(newProjExprWithInner(ae, expr))
+		}
 	}
 
 	pe := newProjExprWithInner(ae, expr)
@@ -622,9 +642,10 @@ func (p *Projection) planOffsets(ctx *plancontext.PlanningContext) Operator {
 
 		// for everything else, we'll turn to the evalengine
 		eexpr, err := evalengine.Translate(rewritten, &evalengine.Config{
-			ResolveType: ctx.TypeForExpr,
-			Collation:   ctx.SemTable.Collation,
-			Environment: ctx.VSchema.Environment(),
+			ResolveType:     ctx.TypeForExpr,
+			Collation:       ctx.SemTable.Collation,
+			Environment:     ctx.VSchema.Environment(),
+			ResolveFunction: ctx.ResolveFunction,
 		})
 		if err != nil {
 			panic(err)
@@ -78,6 +78,9 @@ func tryPushProjection(
 ) (Operator, *ApplyResult) {
 	switch src := p.Source.(type) {
 	case *Route:
+		if !p.canPushToKeyspace(ctx, src.Routing.Keyspace()) {
+			return p, NoRewrite
+		}
 		return Swap(p, src, "push projection under route")
 	case *Limit:
 		return Swap(p, src, "push projection under limit")
@@ -194,6 +194,10 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 	}
 
 	rb, isRoute := in.src().(*Route)
+	if isRoute && !ctx.CanPushToKeyspace(in.selectStatement(), rb.Routing.Keyspace()) {
+		// the keyspace does not have some of the functions called by the query
+		return expandHorizon(ctx, in)
+	}
 	if isRoute && rb.IsSingleShard() {
 		return Swap(in, rb, "push horizon into route")
 	}
@@ -205,10 +209,14 @@ func pushOrExpandHorizon(ctx *plancontext.PlanningContext, in *Horizon) (Operato
 	needsOrdering := len(qp.OrderExprs) > 0
 	hasHaving := isSel && sel.Having != nil
 
+	// window functions can only be evaluated by MySQL if every partition lives on a single shard
+	windowsAligned := !isSel || !qp.HasWindow || windowsAlignedWithVindex(ctx, sel, rb)
+
 	canPush := isRoute &&
 		!hasHaving &&
 		!needsOrdering &&
 		!qp.NeedsAggregation() &&
+		windowsAligned &&
 		!in.selectStatement().IsDistinct() &&
 		in.selectStatement().GetLimit() == nil
 
@@ -490,6 +498,9 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 		case *Join, *ApplyJoin, *SubQueryContainer, *SubQuery:
 			// we can't push limits down on either side
 			return SkipChildren
+		case *Window:
+			// window functions need to see all the rows of their partitions
+			return SkipChildren
 		case *Aggregator:
 			if len(op.Grouping) > 0 {
 				// we can't push limits down if we have a group by
@@ -515,6 +526,11 @@ func setUpperLimit(in *Limit) (Operator, *ApplyResult) {
 func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator, *ApplyResult) {
 	switch src := in.Source.(type) {
 	case *Route:
+		for _, order := range in.Order {
+			if !ctx.CanPushToKeyspace(order.Inner, src.Routing.Keyspace()) {
+				return in, NoRewrite
+			}
+		}
 		return Swap(in, src, "push ordering under route")
 	case *Filter:
 		return Swap(in, src, "push ordering under filter")
@@ -529,6 +545,10 @@ func tryPushOrdering(ctx *plancontext.PlanningContext, in *Ordering) (Operator,
 	case *Projection:
 		return pushOrderingUnderProjection(ctx, in, src)
 	case *Aggregator:
+		if src.WithRollup {
+			// the super-aggregate rows have to be sorted with the rest of the rows
+			return in, NoRewrite
+		}
 		if !src.QP.AlignGroupByAndOrderBy(ctx) && !overlaps(ctx, in.Order, src.Grouping) {
 			return in, NoRewrite
 		}
@@ -689,6 +709,9 @@ func tryPushFilter(ctx *plancontext.PlanningContext, in *Filter) (Operator, *App
 	case *Projection:
 		return pushFilterUnderProjection(ctx, in, src)
 	case *Route:
+		if !ctx.CanPushToKeyspace(sqlparser.Exprs(in.Predicates), src.Routing.Keyspace()) {
+			return in, NoRewrite
+		}
 		for _, pred := range in.Predicates {
 			deps := ctx.SemTable.RecursiveDeps(pred)
 			if !isOuterTable(src, deps) {
@@ -732,6 +755,17 @@ func pushFilterUnderProjection(ctx *plancontext.PlanningContext, filter *Filter,
 			return filter, NoRewrite
 		}
 	}
+	if _, isRecursive := projection.Source.(*RecurseCTE); isRecursive && projection.DT != nil {
+		// the recursion has to see all the rows, so predicates on the CTE are evaluated on top of it
+		return filter, NoRewrite
+	}
+	if projection.DT != nil {
+		// below a derived table projection, the columns of the derived table are not available,
+		// so the predicates have to be expressed using the expressions they are aliasing
+		for i, p := range filter.Predicates {
+			filter.Predicates[i] = projection.DT.RewriteExpression(ctx, p)
+		}
+	}
 	return Swap(filter, projection, "push filter under projection")
 
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/vt/sqlparser"
@@ -28,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
 )
 
 type (
@@ -42,6 +44,7 @@ type (
 		// If you change the contents here, please update the toString() method
 		SelectExprs  []SelectExpr
 		HasAggr      bool
+		HasWindow    bool
 		Distinct     bool
 		WithRollup   bool
 		groupByExprs []GroupBy
@@ -85,10 +88,66 @@ type (
 		SubQueryExpression []*SubQuery // Subqueries associated with this aggregation
 
 		PushedDown bool // Whether the aggregation has been pushed down to the next layer
+
+		// HashDistinct is set on distinct aggregations that can't rely on the input being sorted on their arguments,
+		// because they have more than one argument or because there are other distinct aggregations on different values.
+		// The values already aggregated are then kept in a hash set at the vtgate level.
+		HashDistinct bool
+		// Offsets pointing to the arguments of a HashDistinct aggregation or of a GROUP_CONCAT evaluated at the vtgate level,
+		// and to their weight strings (-1 when not needed)
+		ArgOffsets   []int
+		ArgWSOffsets []int
+		// Offsets pointing to the ORDER BY expressions of a GROUP_CONCAT evaluated at the vtgate level, and to their weight strings
+		OrderOffsets   []int
+		OrderWSOffsets []int
 	}
 )
 
+// GroupConcatOrderBy returns the ORDER BY expressions of a GROUP_CONCAT.
+// Like in MySQL, a position in the ORDER BY refers to the arguments of the GROUP_CONCAT.
+func (aggr Aggr) GroupConcatOrderBy() sqlparser.OrderBy {
+	gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+	if !ok {
+		return nil
+	}
+	var orderBy sqlparser.OrderBy
+	for _, order := range gc.OrderBy {
+		expr := order.Expr
+		if lit, ok := expr.(*sqlparser.Literal); ok && lit.Type == sqlparser.IntVal {
+			pos, _ := strconv.Atoi(lit.Val)
+			if pos < 1 || pos > len(gc.Exprs) {
+				panic(vterrors.VT03014(lit.Val, "order clause"))
+			}
+			expr = gc.Exprs[pos-1]
+		}
+		orderBy = append(orderBy, &sqlparser.Order{Expr: expr, Direction: order.Direction})
+	}
+	return orderBy
+}
+
+// needsAllRows returns true for a GROUP_CONCAT with an ORDER BY and no DISTINCT. Its rows have to be sorted
+// together by the vtgate, so they can't be grouped by the shards.
+func (aggr Aggr) needsAllRows() bool {
+	return aggr.OpCode == opcode.AggregateGroupConcat && !aggr.Distinct && len(aggr.GroupConcatOrderBy()) > 0
+}
+
+// needsArgOffsets returns true when the engine needs the offsets of all the arguments of the aggregation,
+// and not only the column being aggregated
+func (aggr Aggr) needsArgOffsets() bool {
+	if aggr.HashDistinct || aggr.OpCode == opcode.AggregateJSONObjectAgg {
+		return true
+	}
+	if aggr.OpCode != opcode.AggregateGroupConcat || aggr.PushedDown {
+		return false
+	}
+	return aggr.Distinct || len(aggr.Func.GetArgs()) > 1 || len(aggr.GroupConcatOrderBy()) > 0
+}
+
 func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
+	if aggr.HashDistinct {
+		// the weight strings of the arguments are planned separately
+		return false
+	}
 	return aggr.OpCode.NeedsComparableValues() && ctx.NeedsWeightString(aggr.Func.GetArg())
 }
 
@@ -186,6 +245,9 @@ func (qp *QueryProjection) addSelectExpressions(ctx *plancontext.PlanningContext
 				col.Aggr = true
 				qp.HasAggr = true
 			}
+			if sqlparser.ContainsWindowFunc(selExp.Expr) {
+				qp.HasWindow = true
+			}
 
 			qp.SelectExprs = append(qp.SelectExprs, col)
 		case *sqlparser.StarExpr:
@@ -241,6 +303,9 @@ func (qp *QueryProjection) addOrderBy(ctx *plancontext.PlanningContext, orderBy
 			Inner:          ctx.SemTable.Clone(order).(*sqlparser.Order),
 			SimplifiedExpr: order.Expr,
 		})
+		if sqlparser.ContainsWindowFunc(order.Expr) {
+			qp.HasWindow = true
+		}
 		canPushSorting = canPushSorting && !ctx.ContainsAggr(order.Expr)
 	}
 }
@@ -442,6 +507,9 @@ func (qp *QueryProjection) extractAggr(
 			return true
 		}
 		if aggr, isAggr := node.(sqlparser.AggrFunc); isAggr {
+			if grouping, isGrouping := aggr.(*sqlparser.GroupingFunc); isGrouping {
+				qp.checkGroupingFunc(ctx, grouping)
+			}
 			ae := aeWrap(aggr)
 			if aggr == aliasedExpr.Expr {
 				ae = aliasedExpr
@@ -469,6 +537,18 @@ func (qp *QueryProjection) extractAggr(
 	}
 }
 
+// checkGroupingFunc makes sure GROUPING() is used with ROLLUP, and only on GROUP BY expressions
+func (qp *QueryProjection) checkGroupingFunc(ctx *plancontext.PlanningContext, f *sqlparser.GroupingFunc) {
+	if !qp.WithRollup {
+		panic(&semantics.InvalidUseOfGroupFunction{})
+	}
+	for idx, arg := range f.Exprs {
+		if !qp.isExprInGroupByExprs(ctx, arg) {
+			panic(vterrors.VT03042(idx + 1))
+		}
+	}
+}
+
 func (qp *QueryProjection) addOrderByToSelect(ctx *plancontext.PlanningContext) {
 orderBy:
 	// We need to return all columns that are being used for ordering
@@ -587,7 +667,8 @@ func (qp *QueryProjection) AlignGroupByAndOrderBy(ctx *plancontext.PlanningConte
 	if qp == nil {
 		return false
 	}
-	if qp.hasCheckedAlignment {
+	if qp.hasCheckedAlignment || qp.WithRollup {
+		// with ROLLUP, the order of the GROUP BY expressions decides the super-aggregate rows
 		return false
 	}
 	qp.hasCheckedAlignment = true
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// RecurseCTE is used to represent a recursive CTE
+type RecurseCTE struct {
+	// Seed is the non-recursive part of the CTE, and Term is the recursive part
+	Seed, Term Operator
+
+	// Def is the table the recursive part uses to reference the CTE
+	Def *semantics.CTETable
+
+	// Vars contains the bind variables the Term needs from the rows of the previous iteration,
+	// and the offset of the column each one of them is fetched from
+	Vars map[string]int
+
+	// Distinct is true for UNION DISTINCT, meaning that rows already produced are not iterated over again
+	Distinct bool
+
+	// Columns are the output columns of the CTE
+	Columns []*sqlparser.AliasedExpr
+
+	// CheckCols is filled in during offset planning when Distinct is set
+	CheckCols []engine.CheckCol
+}
+
+var _ Operator = (*RecurseCTE)(nil)
+
+func newRecurse(def *semantics.CTETable, seed, term Operator, vars map[string]int, distinct bool, columns []*sqlparser.AliasedExpr) *RecurseCTE {
+	return &RecurseCTE{
+		Def:      def,
+		Seed:     seed,
+		Term:     term,
+		Vars:     vars,
+		Distinct: distinct,
+		Columns:  columns,
+	}
+}
+
+func (r *RecurseCTE) Clone(inputs []Operator) Operator {
+	klone := *r
+	klone.Seed = inputs[0]
+	klone.Term = inputs[1]
+	klone.Columns = slices.Clone(r.Columns)
+	klone.CheckCols = slices.Clone(r.CheckCols)
+	return &klone
+}
+
+func (r *RecurseCTE) Inputs() []Operator {
+	return []Operator{r.Seed, r.Term}
+}
+
+func (r *RecurseCTE) SetInputs(operators []Operator) {
+	r.Seed = operators[0]
+	r.Term = operators[1]
+}
+
+func (r *RecurseCTE) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	// the recursion has to see all the rows, so we can't push predicates into it
+	return newFilter(r, expr)
+}
+
+func (r *RecurseCTE) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, expr *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := r.FindCol(ctx, expr.Expr, false)
+		if offset >= 0 {
+			return offset
+		}
+	}
+
+	switch e := expr.Expr.(type) {
+	case *sqlparser.ColName:
+		offset := slices.IndexFunc(r.Def.ColumnNames(), func(name string) bool {
+			return e.Name.EqualString(name)
+		})
+		if offset == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the column '%s' on the recursive CTE", sqlparser.String(e))))
+		}
+		return offset
+	case *sqlparser.WeightStringFuncExpr:
+		argIdx := slices.IndexFunc(r.Columns, func(col *sqlparser.AliasedExpr) bool {
+			return ctx.SemTable.EqualsExprWithDeps(e.Expr, col.Expr)
+		})
+		if argIdx == -1 {
+			panic(vterrors.VT13001(fmt.Sprintf("could not find the argument to the weight_string function: %s", sqlparser.String(e.Expr))))
+		}
+		return r.AddWSColumn(ctx, argIdx, false)
+	default:
+		panic(vterrors.VT12001(fmt.Sprintf("adding '%s' on top of a recursive CTE", sqlparser.String(expr))))
+	}
+}
+
+func (r *RecurseCTE) AddWSColumn(ctx *plancontext.PlanningContext, offset int, _ bool) int {
+	seedOffset := r.Seed.AddWSColumn(ctx, offset, false)
+	termOffset := r.Term.AddWSColumn(ctx, offset, false)
+	if seedOffset != termOffset {
+		panic(vterrors.VT13001("weight_string offsets did not line up for the recursive CTE"))
+	}
+	for len(r.Columns) <= seedOffset {
+		r.Columns = append(r.Columns, aeWrap(&sqlparser.WeightStringFuncExpr{Expr: r.Columns[offset].Expr}))
+	}
+	return seedOffset
+}
+
+func (r *RecurseCTE) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for idx, col := range r.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (r *RecurseCTE) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return r.Columns
+}
+
+func (r *RecurseCTE) GetSelectExprs(*plancontext.PlanningContext) sqlparser.SelectExprs {
+	return slice.Map(r.Columns, func(from *sqlparser.AliasedExpr) sqlparser.SelectExpr { return from })
+}
+
+func (r *RecurseCTE) ShortDescription() string {
+	var vars []string
+	for name, offset := range r.Vars {
+		vars = append(vars, fmt.Sprintf("%s:%d", name, offset))
+	}
+	slices.Sort(vars)
+	desc := strings.Join(vars, ", ")
+	if r.Distinct {
+		return "DISTINCT " + desc
+	}
+	return desc
+}
+
+func (r *RecurseCTE) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (r *RecurseCTE) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	if !r.Distinct {
+		return nil
+	}
+	// only the columns of the CTE are used to decide if a row has been seen before,
+	// not the weight_string columns that have been added for operators on top of this one
+	for idx := range r.Def.ColumnNames() {
+		e := r.Columns[idx].Expr
+		var wsCol *int
+		if ctx.NeedsWeightString(e) {
+			offset := r.AddWSColumn(ctx, idx, false)
+			wsCol = &offset
+		}
+		typ, _ := ctx.TypeForExpr(e)
+		r.CheckCols = append(r.CheckCols, engine.CheckCol{
+			Col:          idx,
+			WsCol:        wsCol,
+			Type:         typ,
+			CollationEnv: ctx.VSchema.Environment().CollationEnv(),
+		})
+	}
+	return nil
+}
@@ -375,7 +375,31 @@ func createRoute(
 	if queryTable.IsInfSchema {
 		return createInfSchemaRoute(ctx, queryTable)
 	}
-	return findVSchemaTableAndCreateRoute(ctx, queryTable, queryTable.Table, true /*planAlternates*/)
+	route := findVSchemaTableAndCreateRoute(ctx, queryTable, queryTable.Table, true /*planAlternates*/)
+	return keepPredicatesAboveRoute(ctx, route)
+}
+
+// keepPredicatesAboveRoute takes the predicates that call functions the keyspace of the route
+// does not declare out of the route, and returns a filter on top of it so vtgate evaluates them
+func keepPredicatesAboveRoute(ctx *plancontext.PlanningContext, route *Route) Operator {
+	table, ok := route.Source.(*Table)
+	if !ok {
+		return route
+	}
+	var pushed, kept []sqlparser.Expr
+	for _, pred := range table.QTable.Predicates {
+		if ctx.CanPushToKeyspace(pred, route.Routing.Keyspace()) {
+			pushed = append(pushed, pred)
+		} else {
+			kept = append(kept, pred)
+		}
+	}
+	if len(kept) == 0 {
+		return route
+	}
+	table.QTable = table.QTable.Clone()
+	table.QTable.Predicates = pushed
+	return newFilter(route, kept...)
 }
 
 // findVSchemaTableAndCreateRoute consults the VSchema to find a suitable
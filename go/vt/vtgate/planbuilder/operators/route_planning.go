@@ -18,7 +18,9 @@ package operators
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"strings"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -53,7 +55,15 @@ func pushDerived(ctx *plancontext.PlanningContext, op *Horizon) (Operator, *Appl
 }
 
 func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyResult) {
-	return mergeOrJoin(ctx, op.LHS, op.RHS, sqlparser.SplitAndExpression(nil, op.Predicate), op.JoinType)
+	if op.lateral != nil {
+		return optimizeLateralJoin(ctx, op)
+	}
+	joinPredicates := sqlparser.SplitAndExpression(nil, op.Predicate)
+	join, result := mergeOrJoin(ctx, op.LHS, op.RHS, joinPredicates, op.JoinType)
+	if hasStatisticsFor(ctx, op.LHS, op.RHS) {
+		join = useHashJoinIfCheaper(ctx, join, op.LHS, op.RHS, joinPredicates, op.JoinType)
+	}
+	return join, result
 }
 
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
@@ -101,7 +111,7 @@ func buildVindexTableForDML(
 
 	tblName, ok := table.Alias.Expr.(sqlparser.TableName)
 	if !ok {
-		panic(vterrors.VT12001("multi shard UPDATE with LIMIT"))
+		panic(vterrors.VT12001(fmt.Sprintf("target of %s is not a table: %s", strings.ToUpper(dmlType), sqlparser.String(table.Alias.Expr))))
 	}
 
 	_, _, _, typ, dest, err := ctx.VSchema.FindTableOrVindex(tblName)
@@ -235,6 +245,7 @@ func findBestJoin(
 	planCache opCacheMap,
 	crossJoinsOK bool,
 ) (bestPlan Operator, lIdx int, rIdx int) {
+	costBased := hasStatistics(ctx, qg)
 	for i, lhs := range plans {
 		for j, rhs := range plans {
 			if i == j {
@@ -247,8 +258,8 @@ func findBestJoin(
 				// cartesian product, which is almost always a bad idea
 				continue
 			}
-			plan := getJoinFor(ctx, planCache, lhs, rhs, joinPredicates)
-			if bestPlan == nil || CostOf(plan) < CostOf(bestPlan) {
+			plan := getJoinFor(ctx, planCache, lhs, rhs, joinPredicates, costBased)
+			if bestPlan == nil || cheaper(ctx, costBased, plan, bestPlan) {
 				bestPlan = plan
 				// remember which plans we based on, so we can remove them later
 				lIdx = i
@@ -259,7 +270,7 @@ func findBestJoin(
 	return bestPlan, lIdx, rIdx
 }
 
-func getJoinFor(ctx *plancontext.PlanningContext, cm opCacheMap, lhs, rhs Operator, joinPredicates []sqlparser.Expr) Operator {
+func getJoinFor(ctx *plancontext.PlanningContext, cm opCacheMap, lhs, rhs Operator, joinPredicates []sqlparser.Expr, costBased bool) Operator {
 	solves := tableSetPair{left: TableID(lhs), right: TableID(rhs)}
 	cachedPlan := cm[solves]
 	if cachedPlan != nil {
@@ -267,6 +278,9 @@ func getJoinFor(ctx *plancontext.PlanningContext, cm opCacheMap, lhs, rhs Operat
 	}
 
 	join, _ := mergeOrJoin(ctx, lhs, rhs, joinPredicates, sqlparser.NormalJoinType)
+	if costBased {
+		join = useHashJoinIfCheaper(ctx, join, lhs, rhs, joinPredicates, sqlparser.NormalJoinType)
+	}
 	cm[solves] = join
 	return join
 }
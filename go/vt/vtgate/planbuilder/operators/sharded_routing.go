@@ -686,9 +686,10 @@ func tryMergeJoinShardedRouting(
 func makeEvalEngineExpr(ctx *plancontext.PlanningContext, n sqlparser.Expr) evalengine.Expr {
 	for _, expr := range ctx.SemTable.GetExprAndEqualities(n) {
 		ee, _ := evalengine.Translate(expr, &evalengine.Config{
-			Collation:   ctx.SemTable.Collation,
-			ResolveType: ctx.TypeForExpr,
-			Environment: ctx.VSchema.Environment(),
+			Collation:       ctx.SemTable.Collation,
+			ResolveType:     ctx.TypeForExpr,
+			Environment:     ctx.VSchema.Environment(),
+			ResolveFunction: ctx.ResolveDeterministicFunction,
 		})
 		if ee != nil {
 			return ee
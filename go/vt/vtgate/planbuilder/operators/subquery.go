@@ -52,6 +52,9 @@ type SubQuery struct {
 	// correlated stores whether this subquery is correlated or not.
 	// We use this information to fail the planning if we are unable to merge the subquery with a route.
 	correlated bool
+	// perRow is set for correlated subqueries that only use the outer query through arguments,
+	// which means that they can be evaluated once for every row of the outer query.
+	perRow bool
 
 	// IsArgument is set to true if the subquery puts the
 	IsArgument bool
@@ -212,7 +215,7 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 		panic(subqueryNotAtTopErr)
 	}
 	if sq.correlated && sq.FilterType != opcode.PulloutExists {
-		panic(correlatedSubqueryErr)
+		return sq.settleCorrelatedFilter(ctx, outer)
 	}
 	if sq.IsArgument {
 		if len(sq.GetMergePredicates()) > 0 {
@@ -225,9 +228,151 @@ func (sq *SubQuery) settle(ctx *plancontext.PlanningContext, outer Operator) Ope
 	return sq.settleFilter(ctx, outer)
 }
 
-var correlatedSubqueryErr = vterrors.VT12001("correlated subquery is only supported for EXISTS")
+var correlatedSubqueryErr = vterrors.VT12001("correlated subquery that uses the outer query outside of its predicates")
+var correlatedMultiRowSubqueryErr = vterrors.VT12001("correlated subquery that can return more than one row outside of EXISTS or IN")
+var correlatedInSubqueryErr = vterrors.VT12001("correlated IN subquery using aggregation, GROUP BY or LIMIT")
 var subqueryNotAtTopErr = vterrors.VT12001("unmergable subquery can not be inside complex expression")
 
+// settleCorrelatedFilter turns correlated IN, NOT IN and NOT EXISTS subqueries into semi and anti joins.
+// The comparison of IN and NOT IN is pushed into the subquery, so that we only need to check if the subquery
+// returns any rows for the current row of the outer query.
+func (sq *SubQuery) settleCorrelatedFilter(ctx *plancontext.PlanningContext, outer Operator) Operator {
+	if !sq.perRow {
+		panic(correlatedSubqueryErr)
+	}
+	if sq.IsArgument {
+		panic(correlatedMultiRowSubqueryErr)
+	}
+	switch sq.FilterType {
+	case opcode.PulloutNotExists:
+	case opcode.PulloutIn:
+		sq.pushComparisonIntoSubquery(ctx, outer, false)
+		sq.FilterType = opcode.PulloutExists
+	case opcode.PulloutNotIn:
+		sq.pushComparisonIntoSubquery(ctx, outer, true)
+		sq.FilterType = opcode.PulloutNotExists
+	default:
+		panic(correlatedMultiRowSubqueryErr)
+	}
+	sq.addLimit()
+	return outer
+}
+
+// pushComparisonIntoSubquery rewrites `x IN (SELECT y ...)` to `EXISTS (SELECT y ... AND y = x)`.
+// For NOT IN, the subquery also matches NULL values on either side, since `x NOT IN (...)` is
+// never true when they are present: `NOT EXISTS (SELECT y ... AND (y = x OR y IS NULL OR x IS NULL))`
+func (sq *SubQuery) pushComparisonIntoSubquery(ctx *plancontext.PlanningContext, outer Operator, negated bool) {
+	sel, ok := sq.originalSubquery.Select.(*sqlparser.Select)
+	if !ok || sel.GroupBy != nil || sel.Limit != nil || ctx.ContainsAggr(sel.SelectExprs) || ctx.ContainsAggr(sel.Having) {
+		panic(correlatedInSubqueryErr)
+	}
+	cmp, ok := sq.OuterPredicate.(*sqlparser.ComparisonExpr)
+	if !ok {
+		panic(correlatedInSubqueryErr)
+	}
+
+	var pred sqlparser.Expr = cmp
+	if negated {
+		pred = &sqlparser.OrExpr{
+			Left: &sqlparser.OrExpr{
+				Left:  cmp,
+				Right: &sqlparser.IsExpr{Left: cmp.Right, Right: sqlparser.IsNullOp},
+			},
+			Right: &sqlparser.IsExpr{Left: cmp.Left, Right: sqlparser.IsNullOp},
+		}
+	}
+	if negated || !sq.hasPredicate(ctx, cmp) {
+		jc := breakExpressionInLHSandRHS(ctx, pred, TableID(outer))
+		sq.Subquery = sq.Subquery.AddPredicate(ctx, jc.RHSExpr)
+		sq.Predicates = append(sq.Predicates, pred)
+	}
+	sq.OuterPredicate = nil
+	// the join columns are recalculated with the new predicate the next time they are needed
+	sq.JoinColumns = nil
+}
+
+// hasPredicate returns true if the subquery is already correlated using the given equality, in either direction
+func (sq *SubQuery) hasPredicate(ctx *plancontext.PlanningContext, cmp *sqlparser.ComparisonExpr) bool {
+	if cmp.Operator != sqlparser.EqualOp {
+		return false
+	}
+	flipped := &sqlparser.ComparisonExpr{Operator: sqlparser.EqualOp, Left: cmp.Right, Right: cmp.Left}
+	for _, pred := range sq.Predicates {
+		if ctx.SemTable.EqualsExprWithDeps(pred, cmp) || ctx.SemTable.EqualsExprWithDeps(pred, flipped) {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsSingleRow returns true when we know that the subquery will never produce more than one row
+func (sq *SubQuery) returnsSingleRow(ctx *plancontext.PlanningContext) bool {
+	sel, ok := sq.originalSubquery.Select.(*sqlparser.Select)
+	if !ok {
+		return false
+	}
+	if sel.GroupBy == nil && ctx.ContainsAggr(sel.SelectExprs) {
+		// aggregation without grouping always returns exactly one row
+		return true
+	}
+	if sel.Limit == nil {
+		return false
+	}
+	lit, ok := sel.Limit.Rowcount.(*sqlparser.Literal)
+	return ok && (lit.Val == "0" || lit.Val == "1")
+}
+
+// canSettleAsJoin returns true for correlated scalar subqueries that we can evaluate per row using an outer join.
+// Only subqueries that are known to return at most one row qualify, since the join does not check
+// that the subquery returns a single row for every row of the outer query.
+func (sq *SubQuery) canSettleAsJoin(ctx *plancontext.PlanningContext) bool {
+	return sq.perRow && sq.FilterType == opcode.PulloutValue && sq.returnsSingleRow(ctx)
+}
+
+// settleAsJoin plans a correlated scalar subquery as a left outer join, where the subquery is evaluated
+// once for every row of the outer side. The value the subquery returns is then a column of the join,
+// and the expressions using the subquery are rewritten to use this column instead.
+func (sq *SubQuery) settleAsJoin(ctx *plancontext.PlanningContext, outer Operator) Operator {
+	join := NewApplyJoin(ctx, outer, sq.Subquery, nil, sqlparser.LeftJoinType)
+	join.scalarSubquery = true
+	// the join has to produce the same columns as the outer side did, so the operators above it keep working
+	for _, col := range outer.GetColumns(ctx) {
+		join.AddColumn(ctx, false, false, col)
+	}
+	columns, err := sq.GetJoinColumns(ctx, outer)
+	if err != nil {
+		panic(err)
+	}
+	for _, jc := range columns {
+		for _, bve := range jc.LHSExprs {
+			if !join.isColNameMovedFromL2R(bve.Name) {
+				join.ExtraLHSVars = append(join.ExtraLHSVars, bve)
+			}
+		}
+	}
+	if sq.IsArgument {
+		// the projection using the value will be rewritten when it's settled
+		return join
+	}
+
+	value := sq.valueExpr()
+	predicate := sqlparser.CopyOnRewrite(sq.Original, dontEnterSubqueries, func(cursor *sqlparser.CopyOnWriteCursor) {
+		if _, ok := cursor.Node().(*sqlparser.Subquery); ok {
+			cursor.Replace(value)
+		}
+	}, nil).(sqlparser.Expr)
+	return newFilter(join, predicate)
+}
+
+// valueExpr returns the expression the subquery is producing the value from
+func (sq *SubQuery) valueExpr() sqlparser.Expr {
+	ae, ok := sq.originalSubquery.Select.GetColumns()[0].(*sqlparser.AliasedExpr)
+	if !ok {
+		panic(vterrors.VT13001("expected an aliased expression in the scalar subquery"))
+	}
+	return ae.Expr
+}
+
 func (sq *SubQuery) addLimit() {
 	// for a correlated subquery, we can add a limit 1 to the subquery
 	sq.Subquery = &Limit{
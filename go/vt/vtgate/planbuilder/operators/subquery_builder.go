@@ -17,6 +17,8 @@ limitations under the License.
 package operators
 
 import (
+	"io"
+
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
@@ -165,11 +167,15 @@ func createSubquery(
 	original = cloneASTAndSemState(ctx, original)
 	originalSq := cloneASTAndSemState(ctx, subq)
 	subqID := findTablesContained(ctx, subq.Select)
+	// when this subquery is nested inside another subquery, the outer tables we are handed
+	// also contain the tables of this subquery, so we remove them again
+	outerID = outerID.Remove(subqID)
 	totalID := subqID.Merge(outerID)
 	sqc := &SubQueryBuilder{totalID: totalID, subqID: subqID, outerID: outerID}
 
 	predicates, joinCols := sqc.inspectStatement(ctx, subq.Select)
 	correlated := !ctx.SemTable.RecursiveDeps(subq).IsEmpty()
+	perRow := correlated && sqc.onlyCorrelatedThroughArguments(ctx, subq.Select, joinCols)
 
 	opInner := translateQueryToOp(ctx, subq.Select)
 
@@ -185,9 +191,44 @@ func createSubquery(
 		TopLevel:         topLevel,
 		JoinColumns:      joinCols,
 		correlated:       correlated,
+		perRow:           perRow,
 	}
 }
 
+// onlyCorrelatedThroughArguments returns true if all the references the subquery has to the outer query
+// are in the predicates that have been rewritten to use arguments instead of the outer columns.
+// When this is the case, the subquery can be evaluated once for every row of the outer query.
+func (sqb *SubQueryBuilder) onlyCorrelatedThroughArguments(
+	ctx *plancontext.PlanningContext,
+	stmt sqlparser.SelectStatement,
+	joinCols []applyJoinColumn,
+) bool {
+	for _, jc := range joinCols {
+		for _, bve := range jc.LHSExprs {
+			if ctx.ContainsAggr(bve.Expr) {
+				// an aggregation over columns of the outer query is evaluated by the outer query
+				return false
+			}
+		}
+	}
+	for _, inner := range sqb.Inner {
+		if !ctx.SemTable.RecursiveDeps(inner.originalSubquery).IsSolvedBy(sqb.subqID) {
+			return false
+		}
+	}
+
+	outerRef := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		col, ok := node.(*sqlparser.ColName)
+		if ok && !ctx.SemTable.RecursiveDeps(col).IsSolvedBy(sqb.subqID) {
+			outerRef = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, stmt)
+	return !outerRef
+}
+
 func (sqb *SubQueryBuilder) inspectWhere(
 	ctx *plancontext.PlanningContext,
 	in *sqlparser.Where,
@@ -42,6 +42,11 @@ func isMergeable(ctx *plancontext.PlanningContext, query sqlparser.SelectStateme
 
 	switch node := query.(type) {
 	case *sqlparser.Select:
+		if sqlparser.ContainsWindowFunc(node.SelectExprs) && !windowsAlignedWithVindex(ctx, node, op) {
+			// window functions partitioned by something other than a unique vindex need the rows from all shards
+			return false
+		}
+
 		if node.GroupBy != nil && len(node.GroupBy.Exprs) > 0 {
 			// iff we are grouping, we need to check that we can perform the grouping inside a single shard, and we check that
 			// by checking that one of the grouping expressions used is a unique single column vindex.
@@ -78,6 +83,10 @@ func settleSubqueries(ctx *plancontext.PlanningContext, op Operator) Operator {
 		case *SubQueryContainer:
 			outer := op.Outer
 			for _, subq := range op.Inner {
+				if subq.canSettleAsJoin(ctx) {
+					outer = subq.settleAsJoin(ctx, outer)
+					continue
+				}
 				subq.Outer = subq.settle(ctx, outer)
 				outer = subq
 			}
@@ -144,6 +153,41 @@ func mergeSubqueryExpr(ctx *plancontext.PlanningContext, pe *ProjExpr) {
 	if rewritten {
 		pe.EvalExpr = newExpr
 	}
+	newExpr, rewritten = rewriteJoinedSubqueryExpr(ctx, se, pe.EvalExpr)
+	if rewritten {
+		pe.EvalExpr = newExpr
+		pe.ColExpr = newExpr
+		pe.Info = nil
+	}
+}
+
+// rewriteJoinedSubqueryExpr replaces the arguments of the subqueries that are evaluated using a join
+// with the expression producing the subquery value, which is a column coming from that join
+func rewriteJoinedSubqueryExpr(ctx *plancontext.PlanningContext, se SubQueryExpression, expr sqlparser.Expr) (sqlparser.Expr, bool) {
+	rewritten := false
+	for _, sq := range se {
+		if sq.isMerged(ctx) || !sq.canSettleAsJoin(ctx) {
+			continue
+		}
+		expr = sqlparser.Rewrite(expr, nil, func(cursor *sqlparser.Cursor) bool {
+			switch expr := cursor.Node().(type) {
+			case *sqlparser.ColName:
+				if !expr.Qualifier.IsEmpty() || expr.Name.String() != sq.ArgName {
+					return true
+				}
+			case *sqlparser.Argument:
+				if expr.Name != sq.ArgName {
+					return true
+				}
+			default:
+				return true
+			}
+			rewritten = true
+			cursor.Replace(sq.valueExpr())
+			return false
+		}).(sqlparser.Expr)
+	}
+	return expr, rewritten
 }
 
 func rewriteMergedSubqueryExpr(ctx *plancontext.PlanningContext, se SubQueryExpression, expr sqlparser.Expr) (sqlparser.Expr, bool) {
@@ -46,6 +46,11 @@ type (
 		// On merging this information will be lost, so subquery merge is blocked.
 		SubQueriesArgOnChangedVindex []string
 
+		// MoveRows is set when the primary vindex columns are updated, the rows are then moved to the
+		// shards of their new keyspace ids. The OwnedVindexQuery selects the new values of the assignments,
+		// followed by all the columns of the rows.
+		MoveRows bool
+
 		VerifyAll bool
 
 		noColumns
@@ -164,7 +169,7 @@ type updColumn struct {
 type updList []updColumn
 
 func createUpdateWithInputOp(ctx *plancontext.PlanningContext, upd *sqlparser.Update) (op Operator) {
-	updClone := ctx.SemTable.Clone(upd).(*sqlparser.Update)
+	updClone := ctx.SemTable.CloneDML(upd).(*sqlparser.Update)
 	upd.Limit = nil
 
 	// Prepare the update expressions list
@@ -381,7 +386,7 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 		Name:   name,
 	}
 
-	cvv, ovq, subQueriesArgOnChangedVindex := getUpdateVindexInformation(ctx, updStmt, targetTbl, assignments)
+	cvv, ovq, subQueriesArgOnChangedVindex, moveRows := getUpdateVindexInformation(ctx, updStmt, targetTbl, assignments)
 
 	updOp := &Update{
 		DMLCommon: &DMLCommon{
@@ -393,6 +398,7 @@ func createUpdateOperator(ctx *plancontext.PlanningContext, updStmt *sqlparser.U
 		Assignments:                  assignments,
 		ChangedVindexValues:          cvv,
 		SubQueriesArgOnChangedVindex: subQueriesArgOnChangedVindex,
+		MoveRows:                     moveRows,
 		VerifyAll:                    ctx.VerifyAllFKs,
 	}
 
@@ -415,14 +421,123 @@ func getUpdateVindexInformation(
 	updStmt *sqlparser.Update,
 	table TargetTable,
 	assignments []SetExpr,
-) (map[string]*engine.VindexValues, *sqlparser.Select, []string) {
+) (map[string]*engine.VindexValues, *sqlparser.Select, []string, bool) {
 	if !table.VTable.Keyspace.Sharded {
-		return nil, nil, nil
+		return nil, nil, nil, false
 	}
 
 	primaryVindex := getVindexInformation(table.ID, table.VTable)
+	if UpdatesPrimaryVindex(table.VTable, updStmt.Exprs) {
+		ownedVindexQuery, subQueriesArg := buildRowMoveQuery(ctx, updStmt, table, primaryVindex, assignments)
+		return nil, ownedVindexQuery, subQueriesArg, true
+	}
 	changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex := buildChangedVindexesValues(ctx, updStmt, table.VTable, primaryVindex.Columns, assignments)
-	return changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex
+	return changedVindexValues, ownedVindexQuery, subQueriesArgOnChangedVindex, false
+}
+
+// UpdatesPrimaryVindex returns true if any of the update expressions assigns a column of the primary vindex of the table
+func UpdatesPrimaryVindex(table *vindexes.Table, exprs sqlparser.UpdateExprs) bool {
+	if !table.Keyspace.Sharded || len(table.ColumnVindexes) == 0 {
+		return false
+	}
+	for _, ue := range exprs {
+		if slices.ContainsFunc(table.ColumnVindexes[0].Columns, ue.Name.Name.Equal) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateRequiresFKChecksOff returns true if the update has to run with foreign key checks off, in which case all the
+// foreign keys are verified on vtgate. That is the case when it sets foreign key columns to non-literal values, and when
+// it moves rows between shards of a table that has foreign keys to handle, since the rows are deleted and re-inserted.
+func UpdateRequiresFKChecksOff(ctx *plancontext.PlanningContext, upd *sqlparser.Update) bool {
+	if !ctx.SemTable.ForeignKeysPresent() {
+		return false
+	}
+	return ctx.SemTable.HasNonLiteralForeignKeyUpdate(upd.Exprs) || movesRowsWithForeignKeys(ctx, upd.Exprs)
+}
+
+func movesRowsWithForeignKeys(ctx *plancontext.PlanningContext, exprs sqlparser.UpdateExprs) bool {
+	for _, ue := range exprs {
+		ti, err := ctx.SemTable.TableInfoForExpr(ue.Name)
+		if err != nil {
+			continue
+		}
+		if vt := ti.GetVindexTable(); vt != nil && UpdatesPrimaryVindex(vt, sqlparser.UpdateExprs{ue}) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildRowMoveQuery builds the query selecting the rows that are moved to other shards because their primary vindex
+// columns are updated. It selects the new values of all the assignments, followed by the columns of the rows that
+// are inserted in the new shards: all the columns of the table but the generated ones, which needs the column list
+// of the table to be authoritative.
+func buildRowMoveQuery(
+	ctx *plancontext.PlanningContext,
+	update *sqlparser.Update,
+	table TargetTable,
+	primaryVindex *vindexes.ColumnVindex,
+	assignments []SetExpr,
+) (*sqlparser.Select, []string) {
+	var selExprs sqlparser.SelectExprs
+	var subQueriesArgs []string
+	for i, assignment := range assignments {
+		if slices.ContainsFunc(primaryVindex.Columns, assignment.Name.Name.Equal) {
+			if _, isNull := assignment.Expr.EvalExpr.(*sqlparser.NullVal); isNull {
+				panic(vterrors.VT12001(fmt.Sprintf("you cannot UPDATE primary vindex columns to NULL; invalid update on vindex: %v", primaryVindex.Name)))
+			}
+		}
+		// the new values are all selected at once, so they can't depend on the columns updated before them
+		for _, previous := range assignments[:i] {
+			if referencesColumn(ctx, assignment.Expr.EvalExpr, previous.Name) {
+				panic(vterrors.VT12001(fmt.Sprintf("'%s' column referenced in update expression '%s' is itself updated", sqlparser.String(previous.Name), sqlparser.String(assignment.Expr.EvalExpr))))
+			}
+		}
+		if sqe, ok := assignment.Expr.Info.(SubQueryExpression); ok {
+			for _, sq := range sqe {
+				subQueriesArgs = append(subQueriesArgs, sq.ArgName)
+			}
+		}
+		selExprs = append(selExprs, aeWrap(assignment.Expr.EvalExpr))
+	}
+
+	if !table.VTable.ColumnListAuthoritative {
+		panic(vterrors.VT12001(fmt.Sprintf("updating the primary vindex columns of a table without an authoritative column list: %s", table.VTable.Name.String())))
+	}
+	for _, col := range table.VTable.Columns {
+		if col.Generated {
+			continue
+		}
+		selExprs = append(selExprs, aeWrap(sqlparser.NewColNameWithQualifier(col.Name.String(), sqlparser.TableName{Name: table.Name.Name})))
+	}
+
+	return &sqlparser.Select{
+		SelectExprs: selExprs,
+		OrderBy:     update.OrderBy,
+		Limit:       update.Limit,
+		Lock:        sqlparser.ForUpdateLock,
+	}, subQueriesArgs
+}
+
+// referencesColumn returns true when the expression reads the column. The columns are compared by name,
+// and by the table they belong to only when it is known for both of them, as the column assigned by
+// an UPDATE is often not qualified.
+func referencesColumn(ctx *plancontext.PlanningContext, expr sqlparser.Expr, col *sqlparser.ColName) bool {
+	colDeps := ctx.SemTable.RecursiveDeps(col)
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		colName, ok := node.(*sqlparser.ColName)
+		if !ok || !colName.Name.Equal(col.Name) {
+			return true, nil
+		}
+		deps := ctx.SemTable.RecursiveDeps(colName)
+		found = deps.IsEmpty() || colDeps.IsEmpty() || deps == colDeps
+		return !found, nil
+	}, expr)
+	return found
 }
 
 func buildFkOperator(ctx *plancontext.PlanningContext, updOp Operator, updClone *sqlparser.Update, parentFks []vindexes.ParentFKInfo, childFks []vindexes.ChildFKInfo, targetTbl TargetTable) Operator {
@@ -1123,9 +1238,10 @@ func createAssignmentExpressions(
 		}
 		found = true
 		pv, err := evalengine.Translate(assignment.Expr.EvalExpr, &evalengine.Config{
-			ResolveType: ctx.TypeForExpr,
-			Collation:   ctx.SemTable.Collation,
-			Environment: ctx.VSchema.Environment(),
+			ResolveType:     ctx.TypeForExpr,
+			Collation:       ctx.SemTable.Collation,
+			Environment:     ctx.VSchema.Environment(),
+			ResolveFunction: ctx.ResolveDeterministicFunction,
 		})
 		if err != nil {
 			panic(invalidUpdateExpr(assignment.Name.Name.String(), assignment.Expr.EvalExpr))
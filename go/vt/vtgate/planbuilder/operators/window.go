@@ -0,0 +1,337 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+type (
+	// Window evaluates window functions at the vtgate level.
+	// It is used when the partitions of a window can span multiple shards.
+	// All window functions handled by a single Window share the same window specification;
+	// queries using multiple windows get one Window operator per specification.
+	Window struct {
+		Source Operator
+
+		// Spec is the window specification, with named windows already resolved
+		Spec *sqlparser.WindowSpecification
+
+		Functions []WindowFunc
+
+		// Columns are the columns produced by this operator.
+		// For each column, Offsets holds either the offset on the input,
+		// or -(i+1) if the column is produced by the window function i.
+		Columns []*sqlparser.AliasedExpr
+		Offsets []int
+
+		// These are only filled in during offset planning
+		Partition []GroupBy
+		Order     []GroupBy
+	}
+
+	// WindowFunc is a single window function evaluated by the Window operator
+	WindowFunc struct {
+		Original *sqlparser.AliasedExpr
+		Func     sqlparser.Expr
+
+		// ArgOffset and DefaultOffset point to the function argument and the LAG/LEAD default value
+		ArgOffset, DefaultOffset int
+	}
+)
+
+func (w *Window) Clone(inputs []Operator) Operator {
+	kopy := *w
+	kopy.Source = inputs[0]
+	kopy.Functions = slices.Clone(w.Functions)
+	kopy.Columns = slices.Clone(w.Columns)
+	kopy.Offsets = slices.Clone(w.Offsets)
+	kopy.Partition = slices.Clone(w.Partition)
+	kopy.Order = slices.Clone(w.Order)
+	return &kopy
+}
+
+func (w *Window) Inputs() []Operator {
+	return []Operator{w.Source}
+}
+
+func (w *Window) SetInputs(operators []Operator) {
+	w.Source = operators[0]
+}
+
+func (w *Window) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	// filtering the input of a window changes the rows the window functions see,
+	// so predicates have to stay above this operator
+	return newFilter(w, expr)
+}
+
+func (w *Window) AddColumn(ctx *plancontext.PlanningContext, reuse bool, gb bool, ae *sqlparser.AliasedExpr) int {
+	if reuse {
+		if offset := w.FindCol(ctx, ae.Expr, false); offset >= 0 {
+			return offset
+		}
+	}
+
+	if w.handles(ctx, ae.Expr) {
+		w.Functions = append(w.Functions, WindowFunc{
+			Original:      ae,
+			Func:          ae.Expr,
+			ArgOffset:     -1,
+			DefaultOffset: -1,
+		})
+		return w.addColumn(ae, -len(w.Functions))
+	}
+
+	offset := w.Source.AddColumn(ctx, reuse, gb, ae)
+	return w.addColumn(ae, offset)
+}
+
+func (w *Window) addColumn(ae *sqlparser.AliasedExpr, offset int) int {
+	w.Columns = append(w.Columns, ae)
+	w.Offsets = append(w.Offsets, offset)
+	return len(w.Columns) - 1
+}
+
+// handles returns true if the expression is a window function using the window specification of this operator
+func (w *Window) handles(ctx *plancontext.PlanningContext, expr sqlparser.Expr) bool {
+	over := sqlparser.GetOverClause(expr)
+	return over != nil && ctx.SemTable.ASTEquals().RefOfWindowSpecification(over.WindowSpec, w.Spec)
+}
+
+func (w *Window) AddWSColumn(ctx *plancontext.PlanningContext, offset int, underRoute bool) int {
+	if len(w.Columns) <= offset {
+		panic(vterrors.VT13001("offset out of range"))
+	}
+	if w.Offsets[offset] < 0 {
+		panic(vterrors.VT12001(fmt.Sprintf("weight_string of window function: %s", sqlparser.String(w.Columns[offset].Expr))))
+	}
+
+	wsExpr := weightStringFor(w.Columns[offset].Expr)
+	if found := w.FindCol(ctx, wsExpr, underRoute); found >= 0 {
+		return found
+	}
+
+	srcOffset := w.Source.AddWSColumn(ctx, w.Offsets[offset], underRoute)
+	return w.addColumn(aeWrap(wsExpr), srcOffset)
+}
+
+func (w *Window) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	for offset, col := range w.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(col.Expr, expr) {
+			return offset
+		}
+	}
+	return -1
+}
+
+func (w *Window) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return w.Columns
+}
+
+func (w *Window) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, w)
+}
+
+func (w *Window) GetOrdering(ctx *plancontext.PlanningContext) []OrderBy {
+	return w.Source.GetOrdering(ctx)
+}
+
+// requiredOrdering returns the ordering the input needs to have:
+// first by the partitioning expressions, and then by the window ordering
+func (w *Window) requiredOrdering() []OrderBy {
+	var order []OrderBy
+	for _, expr := range w.Spec.PartitionClause {
+		order = append(order, OrderBy{
+			Inner:          &sqlparser.Order{Expr: expr, Direction: sqlparser.AscOrder},
+			SimplifiedExpr: expr,
+		})
+	}
+	for _, o := range w.Spec.OrderClause {
+		order = append(order, OrderBy{
+			Inner:          o,
+			SimplifiedExpr: o.Expr,
+		})
+	}
+	return order
+}
+
+func (w *Window) planOffsets(ctx *plancontext.PlanningContext) Operator {
+	planKey := func(expr sqlparser.Expr) GroupBy {
+		key := NewGroupBy(expr)
+		key.ColOffset = w.Source.AddColumn(ctx, true, false, aeWrap(expr))
+		if ctx.NeedsWeightString(expr) {
+			key.WSOffset = w.Source.AddColumn(ctx, true, false, aeWrap(weightStringFor(expr)))
+		}
+		return key
+	}
+	for _, expr := range w.Spec.PartitionClause {
+		w.Partition = append(w.Partition, planKey(expr))
+	}
+	for _, order := range w.Spec.OrderClause {
+		w.Order = append(w.Order, planKey(order.Expr))
+	}
+
+	for i, fn := range w.Functions {
+		arg, def := windowFuncArguments(fn.Func)
+		if arg != nil {
+			w.Functions[i].ArgOffset = w.Source.AddColumn(ctx, true, false, aeWrap(arg))
+		}
+		if def != nil {
+			w.Functions[i].DefaultOffset = w.Source.AddColumn(ctx, true, false, aeWrap(def))
+		}
+	}
+	return nil
+}
+
+// windowFuncArguments returns the expressions a window function needs from its input
+func windowFuncArguments(expr sqlparser.Expr) (arg, def sqlparser.Expr) {
+	switch fn := expr.(type) {
+	case *sqlparser.FirstOrLastValueExpr:
+		return fn.Expr, nil
+	case *sqlparser.NTHValueExpr:
+		return fn.Expr, nil
+	case *sqlparser.LagLeadExpr:
+		return fn.Expr, fn.Default
+	case *sqlparser.CountStar:
+		return nil, nil
+	case sqlparser.AggrFunc:
+		return fn.GetArg(), nil
+	}
+	return nil, nil
+}
+
+func (w *Window) ShortDescription() string {
+	fns := slice.Map(w.Functions, func(fn WindowFunc) string {
+		return sqlparser.String(fn.Original)
+	})
+	return strings.Join(fns, ", ")
+}
+
+// createWindows adds a Window operator for each window specification used in the query.
+// The operators are stacked on top of each other, so each one passes through the
+// window functions calculated by the ones below it.
+func createWindows(ctx *plancontext.PlanningContext, sel *sqlparser.Select, src Operator) Operator {
+	var specs []*sqlparser.WindowSpecification
+	visit := func(node sqlparser.SQLNode) (bool, error) {
+		switch node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		}
+		over := sqlparser.GetOverClause(node)
+		if over == nil {
+			return true, nil
+		}
+		over.WindowSpec = resolveWindowSpec(sel.Windows, over)
+		over.WindowName = sqlparser.IdentifierCI{}
+		if !slices.ContainsFunc(specs, func(spec *sqlparser.WindowSpecification) bool {
+			return ctx.SemTable.ASTEquals().RefOfWindowSpecification(spec, over.WindowSpec)
+		}) {
+			specs = append(specs, over.WindowSpec)
+		}
+		return true, nil
+	}
+	_ = sqlparser.Walk(visit, sel.SelectExprs)
+	_ = sqlparser.Walk(visit, sel.OrderBy)
+
+	for _, spec := range specs {
+		src = &Window{
+			Source: src,
+			Spec:   spec,
+		}
+	}
+	return src
+}
+
+// resolveWindowSpec returns the window specification used by the OVER clause,
+// merging in the definition of any named window it references
+func resolveWindowSpec(windows sqlparser.NamedWindows, over *sqlparser.OverClause) *sqlparser.WindowSpecification {
+	lookup := func(name sqlparser.IdentifierCI) *sqlparser.WindowSpecification {
+		for _, nw := range windows {
+			for _, def := range nw.Windows {
+				if def.Name.Equal(name) {
+					return resolveWindowSpec(windows, &sqlparser.OverClause{WindowSpec: def.WindowSpec})
+				}
+			}
+		}
+		panic(vterrors.VT03012(fmt.Sprintf("window name '%s' is not defined", name.String())))
+	}
+
+	if !over.WindowName.IsEmpty() {
+		return lookup(over.WindowName)
+	}
+
+	spec := over.WindowSpec
+	if spec == nil {
+		return &sqlparser.WindowSpecification{}
+	}
+	if spec.Name.IsEmpty() {
+		return spec
+	}
+
+	// a window specification can build on a named window by adding ordering or a frame
+	base := lookup(spec.Name)
+	merged := &sqlparser.WindowSpecification{
+		PartitionClause: base.PartitionClause,
+		OrderClause:     base.OrderClause,
+		FrameClause:     base.FrameClause,
+	}
+	if len(spec.OrderClause) > 0 {
+		merged.OrderClause = spec.OrderClause
+	}
+	if spec.FrameClause != nil {
+		merged.FrameClause = spec.FrameClause
+	}
+	return merged
+}
+
+// windowsAlignedWithVindex returns true if all the window functions used in the query are partitioned
+// by a unique vindex column. When that is the case, every partition lives on a single shard,
+// and the window functions can be evaluated by MySQL.
+func windowsAlignedWithVindex(ctx *plancontext.PlanningContext, sel *sqlparser.Select, op Operator) bool {
+	aligned := true
+	visit := func(node sqlparser.SQLNode) (bool, error) {
+		if _, isSubq := node.(*sqlparser.Subquery); isSubq {
+			return false, nil
+		}
+		over := sqlparser.GetOverClause(node)
+		if over == nil {
+			return true, nil
+		}
+		spec := resolveWindowSpec(sel.Windows, over)
+		if !slices.ContainsFunc(spec.PartitionClause, func(expr sqlparser.Expr) bool {
+			vindex := findColumnVindex(ctx, op, expr)
+			return vindex != nil && vindex.IsUnique()
+		}) {
+			aligned = false
+			return false, io.EOF
+		}
+		return true, nil
+	}
+	_ = sqlparser.Walk(visit, sel.SelectExprs)
+	if aligned {
+		_ = sqlparser.Walk(visit, sel.OrderBy)
+	}
+	return aligned
+}
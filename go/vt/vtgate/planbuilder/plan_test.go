@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"os"
 	"path/filepath"
@@ -42,6 +43,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
@@ -50,6 +52,50 @@ import (
 
 var expectedDir = "testdata/expected"
 
+func init() {
+	// the Go implementations of the functions declared in vschemas/schema.json
+	evalengine.RegisterFunction("test_concat", &evalengine.UserDefinedFunction{
+		Type:    sqltypes.VarChar,
+		MinArgs: 1,
+		MaxArgs: -1,
+		Call: func(args []sqltypes.Value) (sqltypes.Value, error) {
+			var buf bytes.Buffer
+			for _, arg := range args {
+				if arg.IsNull() {
+					return sqltypes.NULL, nil
+				}
+				buf.Write(arg.Raw())
+			}
+			return sqltypes.NewVarChar(buf.String()), nil
+		},
+	})
+	evalengine.RegisterFunction("test_random", &evalengine.UserDefinedFunction{
+		Type: sqltypes.Int64,
+		Call: func([]sqltypes.Value) (sqltypes.Value, error) {
+			return sqltypes.NewInt64(rand.Int64()), nil
+		},
+	})
+	evalengine.RegisterFunction("test_geo_distance", &evalengine.UserDefinedFunction{
+		Type:    sqltypes.Float64,
+		MinArgs: 4,
+		MaxArgs: 4,
+		Call: func(args []sqltypes.Value) (sqltypes.Value, error) {
+			var coords [4]float64
+			for i, arg := range args {
+				if arg.IsNull() {
+					return sqltypes.NULL, nil
+				}
+				f, err := arg.ToFloat64()
+				if err != nil {
+					return sqltypes.NULL, err
+				}
+				coords[i] = f
+			}
+			return sqltypes.NewFloat64(math.Hypot(coords[2]-coords[0], coords[3]-coords[1])), nil
+		},
+	})
+}
+
 func getTestExpectationDir() string {
 	return filepath.Clean(expectedDir)
 }
@@ -75,16 +121,20 @@ func TestPlanTestSuite(t *testing.T) {
 func (s *planTestSuite) TestPlan() {
 	defer utils.EnsureNoLeaks(s.T())
 	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
-		V:             loadSchema(s.T(), "vschemas/schema.json", true),
-		TabletType_:   topodatapb.TabletType_PRIMARY,
-		SysVarEnabled: true,
-		TestBuilder:   TestBuilder,
-		Env:           vtenv.NewTestEnv(),
+		V:                 loadSchema(s.T(), "vschemas/schema.json", true),
+		TabletType_:       topodatapb.TabletType_PRIMARY,
+		SysVarEnabled:     true,
+		EnableLocalInfile: true,
+		TestBuilder:       TestBuilder,
+		Env:               vtenv.NewTestEnv(),
 	}
 	s.addPKs(vschemaWrapper.V, "user", []string{"user", "music"})
 	s.addPKsProvided(vschemaWrapper.V, "user", []string{"user_extra"}, []string{"id", "user_id"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order"}, []string{"oid", "region_id"})
 	s.addPKsProvided(vschemaWrapper.V, "ordering", []string{"order_event"}, []string{"oid", "ename"})
+	s.addPKsProvided(vschemaWrapper.V, "user", []string{"authoritative"}, []string{"user_id"})
+	// the vschema can't declare generated columns, they are only known from the tracked schema
+	vschemaWrapper.V.Keyspaces["user"].Tables["authoritative"].Columns[2].Generated = true
 
 	// You will notice that some tests expect user.Id instead of user.id.
 	// This is because we now pre-create vindex columns in the symbol
@@ -98,6 +148,7 @@ func (s *planTestSuite) TestPlan() {
 	s.testFile("filter_cases.json", vschemaWrapper, false)
 	s.testFile("postprocess_cases.json", vschemaWrapper, false)
 	s.testFile("select_cases.json", vschemaWrapper, false)
+	s.testFile("window_cases.json", vschemaWrapper, false)
 	s.testFile("symtab_cases.json", vschemaWrapper, false)
 	s.testFile("unsupported_cases.json", vschemaWrapper, false)
 	s.testFile("unknown_schema_cases.json", vschemaWrapper, false)
@@ -120,6 +171,7 @@ func (s *planTestSuite) TestPlan() {
 	s.testFile("vexplain_cases.json", vschemaWrapper, false)
 	s.testFile("misc_cases.json", vschemaWrapper, false)
 	s.testFile("cte_cases.json", vschemaWrapper, false)
+	s.testFile("udf_cases.json", vschemaWrapper, false)
 }
 
 // TestForeignKeyPlanning tests the planning of foreign keys in a managed mode by Vitess.
@@ -165,6 +217,30 @@ func (s *planTestSuite) TestForeignKeyChecksOff() {
 	s.testFile("foreignkey_checks_off_cases.json", vschemaWrapper, false)
 }
 
+// TestCostBasedPlanning tests the join ordering and join algorithm choices made using table statistics.
+func (s *planTestSuite) TestCostBasedPlanning() {
+	vschema := loadSchema(s.T(), "vschemas/schema.json", true)
+	s.setStatistics(vschema)
+	vschemaWrapper := &vschemawrapper.VSchemaWrapper{
+		V:           vschema,
+		TestBuilder: TestBuilder,
+		Env:         vtenv.NewTestEnv(),
+	}
+
+	s.testFile("cost_based_cases.json", vschemaWrapper, false)
+}
+
+func (s *planTestSuite) setStatistics(vschema *vindexes.VSchema) {
+	stats := map[string]*vindexes.TableStatistics{
+		"user":       {Rows: 100000, Cardinality: map[string]uint64{"id": 100000, "name": 50000, "col": 10}},
+		"user_extra": {Rows: 50, Cardinality: map[string]uint64{"user_id": 50, "col": 10}},
+		"music":      {Rows: 1000000, Cardinality: map[string]uint64{"id": 1000000, "user_id": 100000, "genre": 20}},
+	}
+	for tbl, tblStats := range stats {
+		vschema.Keyspaces["user"].Tables[tbl].Statistics = tblStats
+	}
+}
+
 func (s *planTestSuite) setFks(vschema *vindexes.VSchema) {
 	if vschema.Keyspaces["sharded_fk_allow"] != nil {
 		// FK from multicol_tbl2 referencing multicol_tbl1 that is shard scoped.
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plancontext
+
+import (
+	"io"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// FunctionResolver returns an evalengine.FunctionResolver that lets vtgate evaluate
+// the functions declared in the VSchema with a Go plugin.
+func FunctionResolver(vschema VSchema) evalengine.FunctionResolver {
+	return func(fn *sqlparser.FuncExpr) (*evalengine.UserDefinedFunction, bool, error) {
+		decl := vschema.FindFunction(fn.Qualifier.String(), fn.Name.String())
+		if decl == nil || decl.Plugin == "" {
+			return nil, false, nil
+		}
+		udf, ok := evalengine.LookupFunction(decl.Plugin)
+		if !ok {
+			return nil, false, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "plugin %s of function %s is not registered in vtgate", decl.Plugin, decl.Name.String())
+		}
+		return udf, decl.Deterministic, nil
+	}
+}
+
+// ResolveFunction is the evalengine.FunctionResolver of the VSchema being planned against.
+func (ctx *PlanningContext) ResolveFunction(fn *sqlparser.FuncExpr) (*evalengine.UserDefinedFunction, bool, error) {
+	return FunctionResolver(ctx.VSchema)(fn)
+}
+
+// ResolveDeterministicFunction is like ResolveFunction, but only resolves the deterministic
+// functions. It's used for the values that route queries, which are evaluated once by vtgate
+// but once per row by MySQL.
+func (ctx *PlanningContext) ResolveDeterministicFunction(fn *sqlparser.FuncExpr) (*evalengine.UserDefinedFunction, bool, error) {
+	udf, deterministic, err := ctx.ResolveFunction(fn)
+	if err != nil || !deterministic {
+		return nil, false, err
+	}
+	return udf, true, nil
+}
+
+// CallsPluginFunction returns true if the node calls a function declared in the VSchema
+// that vtgate evaluates with a plugin.
+func (ctx *PlanningContext) CallsPluginFunction(node sqlparser.SQLNode) bool {
+	calls := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		fn, ok := node.(*sqlparser.FuncExpr)
+		if !ok {
+			return true, nil
+		}
+		if decl := ctx.VSchema.FindFunction(fn.Qualifier.String(), fn.Name.String()); decl != nil && decl.Plugin != "" {
+			calls = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, node)
+	return calls
+}
+
+// CanPushToKeyspace returns false if the node calls a function that is declared in the
+// VSchema, but not by the given keyspace, and that vtgate can evaluate instead of MySQL.
+func (ctx *PlanningContext) CanPushToKeyspace(node sqlparser.SQLNode, keyspace *vindexes.Keyspace) bool {
+	return !ctx.walkFunctionsOutsideKeyspace(node, keyspace, func(fn *vindexes.Function) bool {
+		return fn.Plugin != ""
+	})
+}
+
+// FunctionOutsideKeyspace returns the first function called by the node that is declared
+// in the VSchema, but not by the given keyspace. A nil keyspace accepts any function.
+func (ctx *PlanningContext) FunctionOutsideKeyspace(node sqlparser.SQLNode, keyspace *vindexes.Keyspace) (outside *vindexes.Function) {
+	ctx.walkFunctionsOutsideKeyspace(node, keyspace, func(fn *vindexes.Function) bool {
+		outside = fn
+		return true
+	})
+	return
+}
+
+// walkFunctionsOutsideKeyspace calls visit for the functions called by the node that are declared
+// in the VSchema, but not by the given keyspace, until visit returns true.
+func (ctx *PlanningContext) walkFunctionsOutsideKeyspace(node sqlparser.SQLNode, keyspace *vindexes.Keyspace, visit func(fn *vindexes.Function) bool) (found bool) {
+	if keyspace == nil {
+		return false
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		fn, ok := node.(*sqlparser.FuncExpr)
+		if !ok {
+			return true, nil
+		}
+		decl := ctx.VSchema.FindFunction(fn.Qualifier.String(), fn.Name.String())
+		if decl == nil || decl.Keyspace.Name == keyspace.Name || ctx.VSchema.FindFunction(keyspace.Name, fn.Name.String()) != nil {
+			return true, nil
+		}
+		if visit(decl) {
+			found = true
+			return false, io.EOF
+		}
+		return true, nil
+	}, node)
+	return
+}
@@ -166,8 +166,17 @@ func (ctx *PlanningContext) SkipJoinPredicates(joinPred sqlparser.Expr) error {
 // KeepPredicateInfo transfers join predicate information from another context.
 // This is useful when nesting queries, ensuring consistent predicate handling across contexts.
 func (ctx *PlanningContext) KeepPredicateInfo(other *PlanningContext) {
+	// the semantic table of this context knows nothing about the expressions of the other one,
+	// so its join predicates are only merged with the ones that are syntactically equal
+outer:
 	for k, v := range other.joinPredicates {
-		ctx.AddJoinPredicates(k, v...)
+		for key, values := range ctx.joinPredicates {
+			if sqlparser.Equals.Expr(k, key) {
+				ctx.joinPredicates[key] = append(values, v...)
+				continue outer
+			}
+		}
+		ctx.joinPredicates[k] = v
 	}
 	for expr := range other.skipPredicates {
 		ctx.skipThesePredicates(expr)
@@ -241,8 +250,9 @@ func (ctx *PlanningContext) calculateTypeFor(e sqlparser.Expr) evalengine.Type {
 			}
 			return ctx.SemTable.TypeForExpr(col)
 		},
-		Collation:   ctx.SemTable.Collation,
-		Environment: ctx.VSchema.Environment(),
+		Collation:       ctx.SemTable.Collation,
+		Environment:     ctx.VSchema.Environment(),
+		ResolveFunction: ctx.ResolveFunction,
 		ResolveColumn: func(name *sqlparser.ColName) (int, error) {
 			// We don't need to resolve the column for type calculation
 			return 0, nil
@@ -345,7 +355,8 @@ func (ctx *PlanningContext) NeedsWeightString(e sqlparser.Expr) bool {
 func (ctx *PlanningContext) IsAggr(e sqlparser.SQLNode) bool {
 	switch node := e.(type) {
 	case sqlparser.AggrFunc:
-		return true
+		// aggregate functions with an OVER clause are window functions
+		return !sqlparser.IsWindowFunc(node)
 	case *sqlparser.FuncExpr:
 		return node.Name.EqualsAnyString(ctx.VSchema.GetAggregateUDFs())
 	}
@@ -355,12 +366,15 @@ func (ctx *PlanningContext) IsAggr(e sqlparser.SQLNode) bool {
 
 func (ctx *PlanningContext) ContainsAggr(e sqlparser.SQLNode) (hasAggr bool) {
 	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
-		switch node.(type) {
+		switch node := node.(type) {
 		case *sqlparser.Offset:
 			// offsets here indicate that a possible aggregation has already been handled by an input,
 			// so we don't need to worry about aggregation in the original
 			return false, nil
 		case sqlparser.AggrFunc:
+			if sqlparser.IsWindowFunc(node) {
+				return true, nil
+			}
 			hasAggr = true
 			return false, io.EOF
 		case *sqlparser.Subquery:
@@ -330,6 +330,11 @@ func (v *vschema) IsShardRoutingEnabled() bool {
 	panic("implement me")
 }
 
+func (v *vschema) IsLocalInfileEnabled() bool {
+	// TODO implement me
+	panic("implement me")
+}
+
 func (v *vschema) IsViewsEnabled() bool {
 	// TODO implement me
 	panic("implement me")
@@ -365,4 +370,8 @@ func (v *vschema) GetAggregateUDFs() []string {
 	panic("implement me")
 }
 
+func (v *vschema) FindFunction(keyspace, name string) *vindexes.Function {
+	return nil
+}
+
 var _ VSchema = (*vschema)(nil)
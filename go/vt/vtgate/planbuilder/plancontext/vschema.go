@@ -79,6 +79,9 @@ type VSchema interface {
 	// IsViewsEnabled returns true if Vitess manages the views.
 	IsViewsEnabled() bool
 
+	// IsLocalInfileEnabled returns true if vtgate reads the files of LOAD DATA LOCAL INFILE from the client.
+	IsLocalInfileEnabled() bool
+
 	// GetUDV returns user defined value from the variable passed.
 	GetUDV(name string) *querypb.BindVariable
 
@@ -96,6 +99,9 @@ type VSchema interface {
 
 	// GetAggregateUDFs returns the list of aggregate UDFs.
 	GetAggregateUDFs() []string
+
+	// FindFunction returns the declaration of a stored or loadable function in the VSchema.
+	FindFunction(keyspace, name string) *vindexes.Function
 }
 
 // PlannerNameToVersion returns the numerical representation of the planner
@@ -79,6 +79,10 @@ func (r *rewriter) rewriteHavingClause(node *sqlparser.Select) {
 	if node.Having == nil {
 		return
 	}
+	if node.GroupBy != nil && node.GroupBy.WithRollup {
+		// the having clause also filters the super-aggregate rows, so it has to stay after the rollup
+		return
+	}
 
 	// for each expression in the having clause, we check if it contains aggregation.
 	// if it does, we keep the expression in the having clause ; and if it does not
@@ -305,8 +305,9 @@ func handleDualSelects(sel *sqlparser.Select, vschema plancontext.VSchema) (engi
 			return nil, vterrors.VT12001(fmt.Sprintf("LOCK function and other expression: [%s] in same select query", sqlparser.String(expr)))
 		}
 		exprs[i], err = evalengine.Translate(expr.Expr, &evalengine.Config{
-			Collation:   vschema.ConnCollation(),
-			Environment: vschema.Environment(),
+			Collation:       vschema.ConnCollation(),
+			Environment:     vschema.Environment(),
+			ResolveFunction: plancontext.FunctionResolver(vschema),
 		})
 		if err != nil {
 			return nil, nil
@@ -0,0 +1,440 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/cache/theine"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vthash"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// resultCacheRetryDelay is the time to wait before restarting the invalidation stream of a keyspace after it stopped.
+var resultCacheRetryDelay = 5 * time.Second
+
+// nonDeterministicFuncs lists the functions whose results do not only depend on their arguments.
+var nonDeterministicFuncs = map[string]bool{
+	"benchmark":      true,
+	"connection_id":  true,
+	"curdate":        true,
+	"current_date":   true,
+	"current_role":   true,
+	"current_time":   true,
+	"current_user":   true,
+	"curtime":        true,
+	"database":       true,
+	"found_rows":     true,
+	"last_insert_id": true,
+	"rand":           true,
+	"random_bytes":   true,
+	"row_count":      true,
+	"schema":         true,
+	"session_user":   true,
+	"sleep":          true,
+	"sysdate":        true,
+	"system_user":    true,
+	"unix_timestamp": true,
+	"user":           true,
+	"utc_date":       true,
+	"utc_time":       true,
+	"utc_timestamp":  true,
+	"uuid":           true,
+	"uuid_short":     true,
+}
+
+type resultCacheStreamer func(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error
+
+// resultCache caches the results of the select queries reading from tables whose changes are streamed
+// to vtgate, either because the tables have result_cache set in the VSchema or because the queries
+// use the RESULT_CACHE comment directive.
+//
+// Every table has a version that is bumped by the row changes and the DDLs streamed from it, and the
+// versions of the tables read by a query are part of the key of its result, so a change makes all
+// the results read before it unreachable. Results are only cached once the stream of their keyspace
+// is positioned on all the shards, and the versions are bumped whenever a stream stops, as changes
+// can be missed until it is restarted.
+//
+// The statements this vtgate executes outside of a transaction bump the versions of the tables they
+// write to as soon as they complete, so a session reads its own writes. The changes committed in an
+// explicit transaction, or through another vtgate, are only reflected once they are streamed.
+type resultCache struct {
+	ctx    context.Context
+	store  *theine.Store[theine.HashKey256, *sqltypes.Result]
+	stream resultCacheStreamer
+
+	mu        sync.Mutex
+	versions  map[string]uint64
+	keyspaces map[string]*resultCacheStream
+}
+
+// resultCacheStream is the invalidation stream of the cached tables of a keyspace.
+type resultCacheStream struct {
+	keyspace string
+	tables   []string
+	ready    bool
+	cancel   context.CancelFunc
+}
+
+func newResultCache(ctx context.Context, memory int64, stream resultCacheStreamer) *resultCache {
+	// when being endtoend tested, disable the doorkeeper to ensure reproducible results
+	doorkeeper := !servenv.TestingEndtoend
+	return &resultCache{
+		ctx:       ctx,
+		store:     theine.NewStore[theine.HashKey256, *sqltypes.Result](memory, doorkeeper),
+		stream:    stream,
+		versions:  make(map[string]uint64),
+		keyspaces: make(map[string]*resultCacheStream),
+	}
+}
+
+// close stops the invalidation streams and releases the cached results.
+func (rc *resultCache) close() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, s := range rc.keyspaces {
+		s.cancel()
+	}
+	rc.keyspaces = make(map[string]*resultCacheStream)
+	rc.store.Close()
+}
+
+// setVSchema streams the changes of the tables that have result_cache set in the vschema.
+func (rc *resultCache) setVSchema(vschema *vindexes.VSchema) {
+	var tables []string
+	for ksName, ks := range vschema.Keyspaces {
+		for tblName, tbl := range ks.Tables {
+			if tbl.ResultCache {
+				tables = append(tables, ksName+"."+tblName)
+			}
+		}
+	}
+	rc.watch(tables)
+}
+
+// watch streams the changes of the given keyspace qualified tables, restarting the stream
+// of their keyspace if it does not include them yet.
+func (rc *resultCache) watch(tables []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	added := make(map[string][]string)
+	for _, table := range tables {
+		keyspace, name, ok := strings.Cut(table, ".")
+		if !ok {
+			continue
+		}
+		if s := rc.keyspaces[keyspace]; s != nil && slices.Contains(s.tables, name) {
+			continue
+		}
+		added[keyspace] = append(added[keyspace], name)
+	}
+	for keyspace, names := range added {
+		rc.startStream(keyspace, names)
+	}
+}
+
+// startStream starts the stream of the keyspace for the given tables and the ones of its previous stream, if any.
+// It must be called with the lock held.
+func (rc *resultCache) startStream(keyspace string, names []string) {
+	tables := names
+	if old := rc.keyspaces[keyspace]; old != nil {
+		old.cancel()
+		tables = append(slices.Clone(old.tables), names...)
+	}
+	slices.Sort(tables)
+	tables = slices.Compact(tables)
+
+	ctx, cancel := context.WithCancel(rc.ctx)
+	s := &resultCacheStream{
+		keyspace: keyspace,
+		tables:   tables,
+		cancel:   cancel,
+	}
+	rc.keyspaces[keyspace] = s
+	rc.invalidate(s)
+	go rc.run(ctx, s)
+}
+
+func (rc *resultCache) run(ctx context.Context, s *resultCacheStream) {
+	filter := &binlogdatapb.Filter{}
+	for _, table := range s.tables {
+		filter.Rules = append(filter.Rules, &binlogdatapb.Rule{Match: table})
+	}
+	for {
+		vgtid := &binlogdatapb.VGtid{
+			ShardGtids: []*binlogdatapb.ShardGtid{{
+				Keyspace: s.keyspace,
+				Gtid:     "current",
+			}},
+		}
+		err := rc.stream(ctx, topodatapb.TabletType_PRIMARY, vgtid, filter, &vtgatepb.VStreamFlags{}, func(events []*binlogdatapb.VEvent) error {
+			rc.apply(s, events)
+			return nil
+		})
+		rc.stopped(s)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warningf("Result cache invalidation stream of keyspace %s stopped, restarting it in %v: %v", s.keyspace, resultCacheRetryDelay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resultCacheRetryDelay):
+		}
+	}
+}
+
+// apply invalidates the results read from the tables changed by the events.
+func (rc *resultCache) apply(s *resultCacheStream, events []*binlogdatapb.VEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, event := range events {
+		switch event.Type {
+		case binlogdatapb.VEventType_ROW:
+			// the vstream manager qualifies the table names with their keyspace.
+			rc.versions[event.RowEvent.TableName]++
+		case binlogdatapb.VEventType_DDL:
+			rc.invalidate(s)
+		case binlogdatapb.VEventType_VGTID:
+			if rc.keyspaces[s.keyspace] == s && positioned(event.Vgtid) {
+				s.ready = true
+			}
+		}
+	}
+}
+
+// stopped invalidates the results read from the tables of a stream that stopped.
+func (rc *resultCache) stopped(s *resultCacheStream) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	s.ready = false
+	rc.invalidate(s)
+}
+
+// invalidate bumps the versions of all the tables of the stream. It must be called with the lock held.
+func (rc *resultCache) invalidate(s *resultCacheStream) {
+	for _, table := range s.tables {
+		rc.versions[s.keyspace+"."+table]++
+	}
+}
+
+// positioned returns true once the streams of all the shards have resolved their current position.
+func positioned(vgtid *binlogdatapb.VGtid) bool {
+	for _, sgtid := range vgtid.GetShardGtids() {
+		if sgtid.Gtid == "" || sgtid.Gtid == "current" {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns the key of the result of the plan executed with the given bind variables. It returns false
+// if the result cannot be cached yet, because the changes of one of the tables it reads are not streamed.
+func (rc *resultCache) key(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable) (theine.HashKey256, bool) {
+	var key theine.HashKey256
+	hasher := vthash.New256()
+
+	rc.mu.Lock()
+	ready := true
+	var unwatched []string
+	for _, table := range plan.TablesUsed {
+		keyspace, name, _ := strings.Cut(table, ".")
+		s := rc.keyspaces[keyspace]
+		if s == nil || !slices.Contains(s.tables, name) {
+			unwatched = append(unwatched, table)
+			continue
+		}
+		ready = ready && s.ready
+		_, _ = hasher.WriteString(table)
+		_, _ = hasher.Write(binary.BigEndian.AppendUint64(nil, rc.versions[table]))
+	}
+	rc.mu.Unlock()
+
+	if len(unwatched) > 0 {
+		rc.watch(unwatched)
+		return key, false
+	}
+	if !ready {
+		return key, false
+	}
+
+	if err := hashExecution(ctx, vcursor, plan, bindVars, hasher); err != nil {
+		return key, false
+	}
+	hasher.Sum(key[:0])
+	return key, true
+}
+
+// hashSystemVariables writes to the hasher the MySQL system variables set in the session. The tablets
+// set them on the connections running the queries, which are not reserved when system settings are
+// enabled, and they can change the results, like time_zone or sql_mode do.
+func hashSystemVariables(safeSession *SafeSession, hasher *vthash.Hasher256) {
+	var sysVars []string
+	safeSession.GetSystemVariables(func(name, value string) {
+		sysVars = append(sysVars, name+"="+value)
+	})
+	sort.Strings(sysVars)
+	for _, sysVar := range sysVars {
+		_, _ = hasher.WriteString("+SysVar:")
+		_, _ = hasher.WriteString(sysVar)
+	}
+}
+
+// hashExecution writes to the hasher what the result of the plan depends on, besides the rows it reads:
+// the plan key of the query, the callers, whose access to the tables is checked by the tablets, the
+// system variables of the session and the bind variables.
+func hashExecution(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable, hasher *vthash.Hasher256) error {
+	vcursor.keyForPlan(ctx, plan.Original, hasher)
+	_, _ = hasher.WriteString("+Caller:")
+	_, _ = hasher.WriteString(callerid.EffectiveCallerIDFromContext(ctx).GetPrincipal())
+	_, _ = hasher.WriteString(",")
+	_, _ = hasher.WriteString(callerid.ImmediateCallerIDFromContext(ctx).GetUsername())
+	hashSystemVariables(vcursor.safeSession, hasher)
+
+	names := make([]string, 0, len(bindVars))
+	for name := range bindVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		bv, err := bindVars[name].MarshalVT()
+		if err != nil {
+			return err
+		}
+		_, _ = hasher.WriteString(name)
+		_, _ = hasher.Write(bv)
+	}
+	return nil
+}
+
+// written bumps the versions of the tables a statement executed by this vtgate wrote to, once its
+// changes are committed, so that the results read after it do not depend on when its changes are
+// streamed back.
+func (rc *resultCache) written(tables []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, table := range tables {
+		if _, ok := rc.versions[table]; ok {
+			rc.versions[table]++
+		}
+	}
+}
+
+// get returns the cached result for the key.
+func (rc *resultCache) get(key theine.HashKey256) (*sqltypes.Result, bool) {
+	return rc.store.Get(key, 0)
+}
+
+// set caches the result of the plan under the key computed before executing it, unless one of the
+// tables it read from changed in the meantime.
+func (rc *resultCache) set(ctx context.Context, vcursor *vcursorImpl, plan *engine.Plan, bindVars map[string]*querypb.BindVariable, key theine.HashKey256, qr *sqltypes.Result) {
+	if current, ok := rc.key(ctx, vcursor, plan, bindVars); !ok || current != key {
+		return
+	}
+	rc.store.Set(key, qr, qr.CachedSize(true), 0)
+}
+
+// isResultCacheable returns true if the results of the planned statement only depend on the rows
+// of the tables it reads, and if either the statement has the RESULT_CACHE directive or all these
+// tables have result_cache set in the vschema.
+func isResultCacheable(stmt sqlparser.Statement, plan *engine.Plan, vschema *vindexes.VSchema) bool {
+	if plan.Type != sqlparser.StmtSelect || len(plan.TablesUsed) == 0 || vschema == nil || !plan.Deterministic {
+		return false
+	}
+	directive := sqlparser.ResultCacheDirective(stmt)
+	for _, table := range plan.TablesUsed {
+		keyspace, name, ok := strings.Cut(table, ".")
+		if !ok || name == "dual" || sqlparser.SystemSchema(keyspace) {
+			return false
+		}
+		ks := vschema.Keyspaces[keyspace]
+		if ks == nil {
+			return false
+		}
+		if directive {
+			continue
+		}
+		if tbl := ks.Tables[name]; tbl == nil || !tbl.ResultCache {
+			return false
+		}
+	}
+	return true
+}
+
+// isDeterministic returns true if the statement takes no locks and has no functions or variables
+// whose values depend on anything else than the rows it reads.
+func isDeterministic(stmt sqlparser.Statement) bool {
+	deterministic := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			deterministic = deterministic && node.Lock == sqlparser.NoLock && node.Into == nil
+		case *sqlparser.Union:
+			deterministic = deterministic && node.Lock == sqlparser.NoLock && node.Into == nil
+		case *sqlparser.FuncExpr:
+			deterministic = deterministic && !nonDeterministicFuncs[node.Name.Lowered()]
+		case *sqlparser.CurTimeFuncExpr, *sqlparser.LockingFunc, *sqlparser.PerformanceSchemaFuncExpr,
+			*sqlparser.GTIDFuncExpr, *sqlparser.Variable, *sqlparser.Nextval:
+			deterministic = false
+		}
+		return deterministic, nil
+	}, stmt)
+	return deterministic
+}
+
+// resultCacheKey returns the key of the result of the plan in the result cache, and false if the
+// result cannot be served from it.
+func (e *Executor) resultCacheKey(ctx context.Context, safeSession *SafeSession, plan *engine.Plan, vcursor *vcursorImpl, bindVars map[string]*querypb.BindVariable) (theine.HashKey256, bool) {
+	if e.resultCache == nil || !plan.ResultCache {
+		return theine.HashKey256{}, false
+	}
+	// The rows read in a transaction or with a reserved connection depend on the session, and the
+	// replicas lag behind the invalidation streams, which come from the primaries.
+	if safeSession.InTransaction() || safeSession.InReservedConn() || vcursor.TabletType() != topodatapb.TabletType_PRIMARY {
+		return theine.HashKey256{}, false
+	}
+	return e.resultCache.key(ctx, vcursor, plan, bindVars)
+}
+
+// enableResultCache serves the results of the cacheable plans from the result cache.
+func (e *Executor) enableResultCache(rc *resultCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resultCache = rc
+	if e.vschema != nil {
+		rc.setVSchema(e.vschema)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/logstats"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// fakeResultCacheStreamer positions the streams it is asked for and then sends them the events written to it.
+type fakeResultCacheStreamer struct {
+	mu      sync.Mutex
+	filters map[string]*binlogdatapb.Filter
+	events  chan []*binlogdatapb.VEvent
+}
+
+func newFakeResultCacheStreamer() *fakeResultCacheStreamer {
+	return &fakeResultCacheStreamer{
+		filters: make(map[string]*binlogdatapb.Filter),
+		events:  make(chan []*binlogdatapb.VEvent),
+	}
+}
+
+func (f *fakeResultCacheStreamer) stream(ctx context.Context, tabletType topodatapb.TabletType, vgtid *binlogdatapb.VGtid,
+	filter *binlogdatapb.Filter, flags *vtgatepb.VStreamFlags, send func(events []*binlogdatapb.VEvent) error) error {
+	keyspace := vgtid.ShardGtids[0].Keyspace
+	f.mu.Lock()
+	f.filters[keyspace] = filter
+	f.mu.Unlock()
+
+	err := send([]*binlogdatapb.VEvent{{
+		Type: binlogdatapb.VEventType_VGTID,
+		Vgtid: &binlogdatapb.VGtid{ShardGtids: []*binlogdatapb.ShardGtid{
+			{Keyspace: keyspace, Shard: "-80", Gtid: "MySQL56/a:1-10"},
+			{Keyspace: keyspace, Shard: "80-", Gtid: "MySQL56/b:1-10"},
+		}},
+	}})
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events := <-f.events:
+			if err := send(events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *fakeResultCacheStreamer) filter(keyspace string) *binlogdatapb.Filter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filters[keyspace]
+}
+
+func waitForResultCacheReady(t *testing.T, rc *resultCache, keyspace string) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		s := rc.keyspaces[keyspace]
+		return s != nil && s.ready
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestResultCacheInvalidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamer := newFakeResultCacheStreamer()
+	rc := newResultCache(ctx, 1024*1024, streamer.stream)
+	defer rc.close()
+	rc.setVSchema(&vindexes.VSchema{Keyspaces: map[string]*vindexes.KeyspaceSchema{
+		"ks": {Tables: map[string]*vindexes.Table{
+			"t1": {ResultCache: true},
+			"t2": {},
+		}},
+	}})
+	waitForResultCacheReady(t, rc, "ks")
+	assert.Equal(t, []*binlogdatapb.Rule{{Match: "t1"}}, streamer.filter("ks").Rules)
+
+	version := func(table string) uint64 {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		return rc.versions[table]
+	}
+
+	before := version("ks.t1")
+	streamer.events <- []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: "ks.t1"},
+	}}
+	assert.Eventually(t, func() bool { return version("ks.t1") == before+1 }, 5*time.Second, 10*time.Millisecond)
+
+	before = version("ks.t1")
+	streamer.events <- []*binlogdatapb.VEvent{{
+		Type:      binlogdatapb.VEventType_DDL,
+		Statement: "alter table t1 add column c int",
+	}}
+	assert.Eventually(t, func() bool { return version("ks.t1") == before+1 }, 5*time.Second, 10*time.Millisecond)
+
+	// watching another table of the keyspace restarts its stream with both tables.
+	rc.watch([]string{"ks.t2"})
+	waitForResultCacheReady(t, rc, "ks")
+	assert.Eventually(t, func() bool {
+		return len(streamer.filter("ks").Rules) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []*binlogdatapb.Rule{{Match: "t1"}, {Match: "t2"}}, streamer.filter("ks").Rules)
+}
+
+func TestResultCacheExecute(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+
+	streamer := newFakeResultCacheStreamer()
+	rc := newResultCache(ctx, 1024*1024, streamer.stream)
+	executor.enableResultCache(rc)
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	query := "select /*vt+ RESULT_CACHE */ id from main1 where id = 1"
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")})
+
+	// the first execution starts streaming the table, its result cannot be cached until the stream is positioned.
+	_, err := executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	waitForResultCacheReady(t, rc, KsTestUnsharded)
+
+	sbclookup.SetResults([]*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2"),
+	})
+	before := sbclookup.ExecCount.Load()
+	// the doorkeeper of the cache only admits the results that were set before.
+	for i := 0; i < 2; i++ {
+		qr, err := executorExec(ctx, executor, session, query, nil)
+		require.NoError(t, err)
+		assert.Equal(t, `[[INT64(2)]]`, fmt.Sprintf("%v", qr.Rows))
+	}
+	assert.EqualValues(t, before+2, sbclookup.ExecCount.Load())
+
+	// served from the cache.
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "3")})
+	qr, err := executorExec(ctx, executor, session, query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(2)]]`, fmt.Sprintf("%v", qr.Rows))
+	assert.EqualValues(t, before+2, sbclookup.ExecCount.Load())
+
+	// a row change of the table invalidates the cached result.
+	streamer.events <- []*binlogdatapb.VEvent{{
+		Type:     binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{TableName: KsTestUnsharded + ".main1"},
+	}}
+	assert.Eventually(t, func() bool {
+		qr, err = executorExec(ctx, executor, session, query, nil)
+		return err == nil && fmt.Sprintf("%v", qr.Rows) == `[[INT64(3)]]`
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// queries in a transaction are not served from the cache.
+	sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "4")})
+	txSession := &vtgatepb.Session{TargetString: "@primary"}
+	qr, err = executorExec(ctx, executor, txSession, query, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `[[INT64(4)]]`, fmt.Sprintf("%v", qr.Rows))
+}
+
+func TestResultCacheSession(t *testing.T) {
+	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
+
+	streamer := newFakeResultCacheStreamer()
+	rc := newResultCache(ctx, 1024*1024, streamer.stream)
+	executor.enableResultCache(rc)
+
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	query := "select /*vt+ RESULT_CACHE */ id from main1 where id = 1"
+	exec := func(session *vtgatepb.Session, result string) {
+		sbclookup.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), result)})
+		_, err := executorExec(ctx, executor, session, query, nil)
+		require.NoError(t, err)
+	}
+	cached := func(session *vtgatepb.Session) bool {
+		before := sbclookup.ExecCount.Load()
+		exec(session, "0")
+		return sbclookup.ExecCount.Load() == before
+	}
+
+	exec(session, "1")
+	waitForResultCacheReady(t, rc, KsTestUnsharded)
+	// the doorkeeper of the cache only admits the results that were set before.
+	exec(session, "1")
+	exec(session, "1")
+	require.True(t, cached(session))
+
+	// the system variables of the session are part of the key, whether or not they are sent as SET_VAR hints.
+	plan, vcursor := resultCachePlan(t, executor, session, query)
+	key, ok := rc.key(ctx, vcursor, plan, nil)
+	require.True(t, ok)
+	tzSession := NewSafeSession(&vtgatepb.Session{TargetString: "@primary", Autocommit: true})
+	tzSession.SetSystemVariable("time_zone", "'+08:00'")
+	vcursor, err := newVCursorImpl(tzSession, makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, executor.serv, false, executor.pv)
+	require.NoError(t, err)
+	tzKey, ok := rc.key(ctx, vcursor, plan, nil)
+	require.True(t, ok)
+	assert.NotEqual(t, key, tzKey)
+
+	// a write executed by this vtgate invalidates the results read before it,
+	// without waiting for its change to be streamed.
+	_, err = executorExec(ctx, executor, session, "insert into main1(id) values (2)", nil)
+	require.NoError(t, err)
+	assert.False(t, cached(session))
+}
+
+// resultCachePlan plans the query for the session.
+func resultCachePlan(t *testing.T, executor *Executor, session *vtgatepb.Session, query string) (*engine.Plan, *vcursorImpl) {
+	vcursor, err := newVCursorImpl(NewSafeSession(session), makeComments(""), executor, nil, executor.vm, executor.VSchema(), executor.resolver.resolver, executor.serv, false, executor.pv)
+	require.NoError(t, err)
+	stmt, reservedVars, err := parseAndValidateQuery(query, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	ctx := context.Background()
+	plan, err := executor.getPlan(ctx, vcursor, query, stmt, makeComments(""), map[string]*querypb.BindVariable{}, reservedVars, executor.normalize, logstats.NewLogStats(ctx, "Test", "", "", nil))
+	require.NoError(t, err)
+	return plan, vcursor
+}
+
+func TestIsResultCacheable(t *testing.T) {
+	vschema := &vindexes.VSchema{Keyspaces: map[string]*vindexes.KeyspaceSchema{
+		"ks": {Tables: map[string]*vindexes.Table{
+			"t1": {ResultCache: true},
+			"t2": {},
+		}},
+	}}
+	tcases := []struct {
+		query  string
+		tables []string
+		want   bool
+	}{
+		{query: "select a from t1", tables: []string{"ks.t1"}, want: true},
+		{query: "select a from t2", tables: []string{"ks.t2"}, want: false},
+		{query: "select /*vt+ RESULT_CACHE */ a from t2", tables: []string{"ks.t2"}, want: true},
+		{query: "select a from t1 join t2", tables: []string{"ks.t1", "ks.t2"}, want: false},
+		{query: "select a from t1 for update", tables: []string{"ks.t1"}, want: false},
+		{query: "select a, now() from t1", tables: []string{"ks.t1"}, want: false},
+		{query: "select a from t1 where b < rand()", tables: []string{"ks.t1"}, want: false},
+		{query: "select a, @x from t1", tables: []string{"ks.t1"}, want: false},
+		{query: "select a from t1 union select a from t1 lock in share mode", tables: []string{"ks.t1"}, want: false},
+		{query: "select concat(a, 'x') from t1", tables: []string{"ks.t1"}, want: true},
+		{query: "select next 2 values from t1", tables: []string{"ks.t1"}, want: false},
+		{query: "select /*vt+ RESULT_CACHE */ table_name from information_schema.tables", tables: []string{"information_schema.tables"}, want: false},
+		{query: "select /*vt+ RESULT_CACHE */ 1 from dual", want: false},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tc := range tcases {
+		t.Run(tc.query, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.query)
+			require.NoError(t, err)
+			plan := &engine.Plan{Type: sqlparser.StmtSelect, TablesUsed: tc.tables, Deterministic: isDeterministic(stmt)}
+			assert.Equal(t, tc.want, isResultCacheable(stmt, plan, vschema))
+		})
+	}
+}
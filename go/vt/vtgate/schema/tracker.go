@@ -28,6 +28,7 @@ import (
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
@@ -47,9 +48,15 @@ type (
 		tables *tableMap
 		views  *viewMap
 		udfs   map[keyspaceStr][]string
+		stats  map[keyspaceStr]map[tableNameStr]*vindexes.TableStatistics
 		ctx    context.Context
 		signal func() // a function that we'll call whenever we have new schema data
 
+		// statsRefreshInterval is how often the statistics are reloaded from the latest serving primary
+		// seen for each keyspace, in statsTablets. The statistics change without any schema change.
+		statsRefreshInterval time.Duration
+		statsTablets         map[keyspaceStr]*discovery.TabletHealth
+
 		// map of keyspace currently tracked
 		tracked      map[keyspaceStr]*updateController
 		consumeDelay time.Duration
@@ -61,8 +68,9 @@ type (
 // defaultConsumeDelay is the default time, the updateController will wait before checking the schema fetch request queue.
 const defaultConsumeDelay = 1 * time.Second
 
-// NewTracker creates the tracker object.
-func NewTracker(ch chan *discovery.TabletHealth, enableViews, enableUDFs bool, parser *sqlparser.Parser) *Tracker {
+// NewTracker creates the tracker object. When statistics are enabled and statsRefreshInterval is not zero,
+// they are refreshed at that interval.
+func NewTracker(ch chan *discovery.TabletHealth, enableViews, enableUDFs, enableStatistics bool, statsRefreshInterval time.Duration, parser *sqlparser.Parser) *Tracker {
 	t := &Tracker{
 		ctx:          context.Background(),
 		ch:           ch,
@@ -78,6 +86,11 @@ func NewTracker(ch chan *discovery.TabletHealth, enableViews, enableUDFs bool, p
 	if enableUDFs {
 		t.udfs = map[keyspaceStr][]string{}
 	}
+	if enableStatistics {
+		t.stats = map[keyspaceStr]map[tableNameStr]*vindexes.TableStatistics{}
+		t.statsRefreshInterval = statsRefreshInterval
+		t.statsTablets = map[keyspaceStr]*discovery.TabletHealth{}
+	}
 	return t
 }
 
@@ -95,6 +108,7 @@ func (t *Tracker) LoadKeyspace(conn queryservice.QueryService, target *querypb.T
 	if err != nil {
 		return err
 	}
+	t.loadStatistics(conn, target)
 
 	t.tracked[target.Keyspace].setLoaded(true)
 	return nil
@@ -183,6 +197,60 @@ func (t *Tracker) loadUDFs(conn queryservice.QueryService, target *querypb.Targe
 	return nil
 }
 
+const (
+	// tableRowsQuery returns the number of rows estimated by MySQL for every table of the database
+	tableRowsQuery = "select table_name, table_rows from information_schema.tables where table_schema = database() and table_type = 'BASE TABLE'"
+	// indexCardinalityQuery returns the number of distinct values estimated by MySQL for the first column of every index
+	indexCardinalityQuery = "select table_name, column_name, max(cardinality) from information_schema.statistics where table_schema = database() and seq_in_index = 1 group by table_name, column_name"
+)
+
+// loadStatistics loads the statistics of the tables of the keyspace. The planner can do without them,
+// so when they cannot be fetched, the error is logged and the previous statistics are kept.
+func (t *Tracker) loadStatistics(conn queryservice.QueryService, target *querypb.Target) {
+	if t.stats == nil {
+		// This happens only when statistics are not enabled.
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := map[tableNameStr]*vindexes.TableStatistics{}
+	qr, err := conn.Execute(t.ctx, target, tableRowsQuery, nil, 0, 0, nil)
+	if err != nil {
+		log.Warningf("error fetching table statistics for %v, keeping the previous ones: %v", target.Keyspace, err)
+		return
+	}
+	for _, row := range qr.Rows {
+		rows, err := row[1].ToUint64()
+		if err != nil {
+			// the row count is NULL when MySQL has no estimate for the table
+			continue
+		}
+		stats[row[0].ToString()] = &vindexes.TableStatistics{Rows: rows}
+	}
+
+	qr, err = conn.Execute(t.ctx, target, indexCardinalityQuery, nil, 0, 0, nil)
+	if err != nil {
+		log.Warningf("error fetching index statistics for %v, keeping the previous ones: %v", target.Keyspace, err)
+		return
+	}
+	for _, row := range qr.Rows {
+		tblStats := stats[row[0].ToString()]
+		cardinality, err := row[2].ToUint64()
+		if tblStats == nil || err != nil {
+			continue
+		}
+		if tblStats.Cardinality == nil {
+			tblStats.Cardinality = map[string]uint64{}
+		}
+		tblStats.Cardinality[strings.ToLower(row[1].ToString())] = cardinality
+	}
+
+	t.stats[target.Keyspace] = stats
+	log.Infof("finished loading statistics of %d tables for keyspace %s", len(stats), target.Keyspace)
+}
+
 // Start starts the schema tracking.
 func (t *Tracker) Start() {
 	log.Info("Starting schema tracking")
@@ -198,12 +266,66 @@ func (t *Tracker) Start() {
 				}
 				ksUpdater := t.getKeyspaceUpdateController(th)
 				ksUpdater.add(th)
+				t.setStatisticsTablet(th)
 			case <-ctx.Done():
 				// closing of the channel happens outside the scope of the tracker. It is the responsibility of the one who created this tracker.
 				return
 			}
 		}
 	}(ctx, t)
+
+	if t.stats != nil && t.statsRefreshInterval > 0 {
+		go t.refreshStatistics(ctx)
+	}
+}
+
+// setStatisticsTablet records the latest serving primary of the keyspace, that the statistics are refreshed from.
+func (t *Tracker) setStatisticsTablet(th *discovery.TabletHealth) {
+	if t.statsTablets == nil || th.Target.TabletType != topodatapb.TabletType_PRIMARY {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if th.Serving {
+		t.statsTablets[th.Target.Keyspace] = th
+	} else {
+		delete(t.statsTablets, th.Target.Keyspace)
+	}
+}
+
+// refreshStatistics reloads the statistics of the keyspaces at every statsRefreshInterval until the context is done.
+func (t *Tracker) refreshStatistics(ctx context.Context) {
+	ticker := time.NewTicker(t.statsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.refreshAllStatistics()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refreshAllStatistics reloads the statistics of the keyspaces that are loaded, and signals the new schema data.
+func (t *Tracker) refreshAllStatistics() {
+	t.mu.Lock()
+	tablets := make([]*discovery.TabletHealth, 0, len(t.statsTablets))
+	for ks, th := range t.statsTablets {
+		if controller := t.tracked[ks]; controller != nil && controller.isLoaded() {
+			tablets = append(tablets, th)
+		}
+	}
+	signal := t.signal
+	t.mu.Unlock()
+
+	for _, th := range tablets {
+		t.loadStatistics(th.Conn, th.Target)
+	}
+	if len(tablets) > 0 && signal != nil {
+		signal()
+	}
 }
 
 // getKeyspaceUpdateController returns the updateController for the given keyspace
@@ -306,6 +428,18 @@ func (t *Tracker) UDFs(ks string) []string {
 	return slices.Clone(t.udfs[ks])
 }
 
+// Statistics returns the statistics collected for the tables of the keyspace, or nil when they are not tracked.
+func (t *Tracker) Statistics(ks string) map[string]*vindexes.TableStatistics {
+	if t.stats == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.stats[ks])
+}
+
 func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
 	success := true
 	if th.Stats.TableSchemaChanged != nil {
@@ -315,6 +449,11 @@ func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
 		return false
 	}
 
+	// the statistics of the keyspace are refreshed whenever its tables change
+	if len(th.Stats.TableSchemaChanged) > 0 {
+		t.loadStatistics(th.Conn, th.Target)
+	}
+
 	// there is view definition change in the tablet
 	if th.Stats.ViewSchemaChanged != nil {
 		success = t.updatedViewSchema(th)
@@ -385,6 +524,7 @@ func getColumns(tblSpec *sqlparser.TableSpec) []vindexes.Column {
 				CollationName: colCollation,
 				Default:       column.Type.Options.Default,
 				Invisible:     column.Type.Invisible(),
+				Generated:     column.Type.Options.As != nil,
 				Size:          int32(size),
 				Scale:         int32(scale),
 				Nullable:      nullable,
@@ -18,6 +18,7 @@ package schema
 
 import (
 	"context"
+	"errors"
 	"os"
 	"sync"
 	"testing"
@@ -83,7 +84,7 @@ func TestTrackingUnHealthyTablet(t *testing.T) {
 
 	sbc := sandboxconn.NewSandboxConn(tablet)
 	ch := make(chan *discovery.TabletHealth)
-	tracker := NewTracker(ch, false, false, sqlparser.NewTestParser())
+	tracker := NewTracker(ch, false, false, false, 0, sqlparser.NewTestParser())
 	tracker.consumeDelay = 1 * time.Millisecond
 	tracker.Start()
 	defer tracker.Stop()
@@ -200,7 +201,7 @@ func TestTableTracking(t *testing.T) {
 			tbl("t3", "create table t3(id datetime primary key)"),
 		),
 		tables(
-			tbl("t4", "create table t4(name varchar(50) primary key)"),
+			tbl("t4", "create table t4(name varchar(50) primary key, upper_name varchar(50) as (upper(name)))"),
 		),
 	}
 
@@ -232,7 +233,7 @@ func TestTableTracking(t *testing.T) {
 			"t1": {{Name: sqlparser.NewIdentifierCI("id"), Type: querypb.Type_INT64, CollationName: "binary", Nullable: true}, {Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true}, {Name: sqlparser.NewIdentifierCI("email"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: false, Default: &sqlparser.Literal{Val: "a@b.com"}}},
 			"T1": {{Name: sqlparser.NewIdentifierCI("id"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true}, {Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true}},
 			"t3": {{Name: sqlparser.NewIdentifierCI("id"), Type: querypb.Type_DATETIME, CollationName: "binary", Size: 0, Nullable: true}},
-			"t4": {{Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true}},
+			"t4": {{Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true}, {Name: sqlparser.NewIdentifierCI("upper_name"), Type: querypb.Type_VARCHAR, Size: 50, Nullable: true, Generated: true}},
 		},
 	}}
 
@@ -438,6 +439,95 @@ func TestUDFRetrieval(t *testing.T) {
 	testTracker(t, true, schemaDefResult, testcases)
 }
 
+// TestStatisticsRetrieval tests that the tracker is able to retrieve the row count and index cardinality of tables.
+func TestStatisticsRetrieval(t *testing.T) {
+	tracker := NewTracker(nil, false, false, true, 0, sqlparser.NewTestParser())
+	target := &querypb.Target{Cell: cell, Keyspace: keyspace, Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}
+	tablet := &topodatapb.Tablet{Keyspace: target.Keyspace, Shard: target.Shard, Type: target.TabletType}
+	sbc := sandboxconn.NewSandboxConn(tablet)
+
+	sbc.SetResults([]*sqltypes.Result{
+		tableRows("t1|1000", "t2|10", "t3|null"),
+		cardinality("t1|id|1000", "t1|Name|50", "t2|id|10", "t2|t1_id|null", "t3|id|5"),
+	})
+	require.NoError(t, tracker.AddNewKeyspace(sbc, target))
+	assert.Equal(t, map[string]*vindexes.TableStatistics{
+		"t1": {Rows: 1000, Cardinality: map[string]uint64{"id": 1000, "name": 50}},
+		"t2": {Rows: 10, Cardinality: map[string]uint64{"id": 10}},
+	}, tracker.Statistics(keyspace))
+
+	// the statistics are reloaded when a table changes
+	sbc.SetResults([]*sqltypes.Result{
+		tableRows("t1|2000"),
+		cardinality("t1|id|2000"),
+	})
+	require.True(t, tracker.updateSchema(&discovery.TabletHealth{
+		Conn:   sbc,
+		Tablet: tablet,
+		Target: target,
+		Stats:  &querypb.RealtimeStats{TableSchemaChanged: []string{"t2"}},
+	}))
+	assert.Equal(t, map[string]*vindexes.TableStatistics{
+		"t1": {Rows: 2000, Cardinality: map[string]uint64{"id": 2000}},
+	}, tracker.Statistics(keyspace))
+	assert.Nil(t, tracker.Statistics("unknown"))
+
+	// the schema is still updated when the statistics cannot be fetched, and the previous ones are kept
+	sbc.EphemeralShardErr = errors.New("statistics unavailable")
+	require.True(t, tracker.updateSchema(&discovery.TabletHealth{
+		Conn:   sbc,
+		Tablet: tablet,
+		Target: target,
+		Stats:  &querypb.RealtimeStats{TableSchemaChanged: []string{"t1"}},
+	}))
+	assert.Equal(t, map[string]*vindexes.TableStatistics{
+		"t1": {Rows: 2000, Cardinality: map[string]uint64{"id": 2000}},
+	}, tracker.Statistics(keyspace))
+
+	// nothing is tracked when statistics are disabled
+	assert.Nil(t, NewTracker(nil, false, false, false, 0, sqlparser.NewTestParser()).Statistics(keyspace))
+}
+
+// TestStatisticsRefresh tests that the statistics are refreshed from the latest serving primary of the keyspace.
+func TestStatisticsRefresh(t *testing.T) {
+	tracker := NewTracker(nil, false, false, true, time.Minute, sqlparser.NewTestParser())
+	target := &querypb.Target{Cell: cell, Keyspace: keyspace, Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}
+	tablet := &topodatapb.Tablet{Keyspace: target.Keyspace, Shard: target.Shard, Type: target.TabletType}
+	sbc := sandboxconn.NewSandboxConn(tablet)
+
+	sbc.SetResults([]*sqltypes.Result{tableRows("t1|1000"), cardinality("t1|id|1000")})
+	require.NoError(t, tracker.AddNewKeyspace(sbc, target))
+	signals := 0
+	tracker.RegisterSignalReceiver(func() {
+		signals++
+	})
+
+	tracker.setStatisticsTablet(&discovery.TabletHealth{Conn: sbc, Tablet: tablet, Target: target, Serving: true})
+	sbc.SetResults([]*sqltypes.Result{tableRows("t1|2000"), cardinality("t1|id|2000")})
+	tracker.refreshAllStatistics()
+	assert.Equal(t, map[string]*vindexes.TableStatistics{
+		"t1": {Rows: 2000, Cardinality: map[string]uint64{"id": 2000}},
+	}, tracker.Statistics(keyspace))
+	assert.Equal(t, 1, signals)
+
+	// the statistics are not refreshed once the primary is not serving
+	tracker.setStatisticsTablet(&discovery.TabletHealth{Conn: sbc, Tablet: tablet, Target: target, Serving: false})
+	sbc.SetResults([]*sqltypes.Result{tableRows("t1|3000"), cardinality("t1|id|3000")})
+	tracker.refreshAllStatistics()
+	assert.Equal(t, map[string]*vindexes.TableStatistics{
+		"t1": {Rows: 2000, Cardinality: map[string]uint64{"id": 2000}},
+	}, tracker.Statistics(keyspace))
+	assert.Equal(t, 1, signals)
+}
+
+func tableRows(rows ...string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("TABLE_NAME|TABLE_ROWS", "varchar|uint64"), rows...)
+}
+
+func cardinality(rows ...string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields("TABLE_NAME|COLUMN_NAME|max(cardinality)", "varchar|varchar|int64"), rows...)
+}
+
 func udfs(udfs ...*querypb.UDFInfo) sandboxconn.SchemaResult {
 	return sandboxconn.SchemaResult{
 		TablesAndViews: map[string]string{},
@@ -470,7 +560,7 @@ type testCases struct {
 
 func testTracker(t *testing.T, enableUDFs bool, schemaDefResult []sandboxconn.SchemaResult, tcases []testCases) {
 	ch := make(chan *discovery.TabletHealth)
-	tracker := NewTracker(ch, true, enableUDFs, sqlparser.NewTestParser())
+	tracker := NewTracker(ch, true, enableUDFs, false, 0, sqlparser.NewTestParser())
 	tracker.consumeDelay = 1 * time.Millisecond
 	tracker.Start()
 	defer tracker.Stop()
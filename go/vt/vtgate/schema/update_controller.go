@@ -176,6 +176,12 @@ func (u *updateController) setLoaded(loaded bool) {
 	u.loaded = loaded
 }
 
+func (u *updateController) isLoaded() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.loaded
+}
+
 func (u *updateController) setIgnore(i bool) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
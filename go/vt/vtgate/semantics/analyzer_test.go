@@ -532,6 +532,113 @@ func TestScopeForSubqueries(t *testing.T) {
 	}
 }
 
+func TestScopeForLateralDerivedTables(t *testing.T) {
+	tcases := []struct {
+		sql  string
+		deps TableSet
+	}{
+		{
+			sql:  `select 1 from x as t, lateral (select z.col1 from z where z.col2 = t.col2) as d`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x as t join lateral (select z.col1 from z where z.col2 = t.col2) as d`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x as t left join lateral (select z.col1 from z where z.col2 = t.col2) as d on true`,
+			deps: TS0,
+		}, {
+			sql:  `select 1 from x as t, lateral (select z.col1 from z where z.col2 = col3) as d`,
+			deps: TS1,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, semTable := parseAndAnalyze(t, tc.sql, "d")
+			sel, _ := stmt.(*sqlparser.Select)
+
+			var dt *sqlparser.DerivedTable
+			_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+				if derived, ok := node.(*sqlparser.DerivedTable); ok {
+					dt = derived
+				}
+				return dt == nil, nil
+			}, sel)
+			require.NotNil(t, dt)
+
+			cmp := dt.Select.(*sqlparser.Select).Where.Expr.(*sqlparser.ComparisonExpr)
+			assert.Equal(t, tc.deps, semTable.RecursiveDeps(cmp.Right))
+		})
+	}
+
+	t.Run("tables after the lateral derived table are not visible", func(t *testing.T) {
+		parse, err := sqlparser.NewTestParser().Parse(`select 1 from x, lateral (select y.col1 from y where y.col2 = z.col2) as d, z`)
+		require.NoError(t, err)
+		st, err := Analyze(parse, "d", fakeSchemaInfo())
+		require.NoError(t, err)
+		require.EqualError(t, st.NotUnshardedErr, "column 'z.col2' not found")
+	})
+
+	t.Run("derived tables without lateral can't use the outer tables", func(t *testing.T) {
+		parse, err := sqlparser.NewTestParser().Parse(`select 1 from x as t, (select z.col1 from z where z.col2 = t.col2) as d`)
+		require.NoError(t, err)
+		st, err := Analyze(parse, "d", fakeSchemaInfo())
+		require.NoError(t, err)
+		require.EqualError(t, st.NotUnshardedErr, "column 't.col2' not found")
+	})
+}
+
+func TestScopeForJSONTable(t *testing.T) {
+	tcases := []struct {
+		sql     string
+		docDeps TableSet
+		colDeps TableSet
+	}{
+		{
+			sql:     `select jt.a from json_table('[1]', '$[*]' columns(a int path '$')) as jt`,
+			docDeps: NoTables,
+			colDeps: TS0,
+		}, {
+			sql:     `select jt.a from x as t, json_table(t.col1, '$[*]' columns(a int path '$')) as jt`,
+			docDeps: TS0,
+			colDeps: TS1,
+		}, {
+			sql:     `select a from x as t join json_table(t.col1, '$[*]' columns(a int path '$')) as jt`,
+			docDeps: TS0,
+			colDeps: TS1,
+		}, {
+			sql:     `select b from x as t, json_table(t.col1, '$[*]' columns(a int path '$', nested path '$.n[*]' columns (b int path '$'))) as jt`,
+			docDeps: TS0,
+			colDeps: TS1,
+		},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.sql, func(t *testing.T) {
+			stmt, semTable := parseAndAnalyze(t, tc.sql, "d")
+			sel, _ := stmt.(*sqlparser.Select)
+
+			var jt *sqlparser.JSONTableExpr
+			_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+				if tbl, ok := node.(*sqlparser.JSONTableExpr); ok {
+					jt = tbl
+				}
+				return jt == nil, nil
+			}, sel)
+			require.NotNil(t, jt)
+
+			assert.Equal(t, tc.docDeps, semTable.RecursiveDeps(jt.Expr))
+			assert.Equal(t, tc.colDeps, semTable.RecursiveDeps(extract(sel, 0)))
+		})
+	}
+
+	t.Run("tables after the JSON_TABLE are not visible to the document", func(t *testing.T) {
+		parse, err := sqlparser.NewTestParser().Parse(`select 1 from json_table(z.col1, '$[*]' columns(a int path '$')) as jt, z`)
+		require.NoError(t, err)
+		st, err := Analyze(parse, "d", fakeSchemaInfo())
+		require.NoError(t, err)
+		require.EqualError(t, st.NotUnshardedErr, "column 'z.col1' not found")
+	})
+}
+
 func TestSubqueryOrderByBinding(t *testing.T) {
 	queries := []struct {
 		query    string
@@ -872,9 +979,6 @@ func TestInvalidQueries(t *testing.T) {
 	}, {
 		sql:  "select is_free_lock('xyz') from user",
 		serr: "is_free_lock('xyz') allowed only with dual",
-	}, {
-		sql: "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR )) as jt",
-		err: &JSONTablesError{},
 	}, {
 		sql:             "select does_not_exist from t1",
 		notUnshardedErr: "column 'does_not_exist' not found in table 't1'",
@@ -888,8 +992,23 @@ func TestInvalidQueries(t *testing.T) {
 		sql:  "select 1 from t1 where (id, id) in (select 1, 2, 3)",
 		serr: "Operand should contain 2 column(s)",
 	}, {
-		sql:  "WITH RECURSIVE cte (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM cte WHERE n < 5) SELECT * FROM cte",
-		serr: "VT12001: unsupported: recursive common table expression",
+		sql:  "with recursive cte (n) as (select n + 1 from cte where n < 5) select * from cte",
+		serr: "VT03034: Recursive Common Table Expression 'cte' should contain a UNION",
+	}, {
+		sql:  "with recursive cte (n) as (select n + 1 from cte where n < 5 union all select 1) select * from cte",
+		serr: "VT03035: Recursive Common Table Expression 'cte' should have one or more non-recursive query blocks followed by one or more recursive ones",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select count(n) from cte) select * from cte",
+		serr: "VT03036: Recursive Common Table Expression 'cte' can contain neither aggregation nor window functions in recursive query block",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select cte.n + 1 from t1 left join cte on t1.id = cte.n) select * from cte",
+		serr: "VT03037: In recursive query block of Recursive Common Table Expression 'cte', the recursive table must neither be in the right argument of a LEFT JOIN, nor be forced to be non-first with join order hints",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select n + 1 from cte where n in (select n from cte)) select * from cte",
+		serr: "VT03038: In recursive query block of Recursive Common Table Expression 'cte', the recursive table must be referenced only once, and not in any subquery",
+	}, {
+		sql:  "with recursive cte (n) as (select 1 union all select n + 1 from cte order by n limit 5) select * from cte",
+		serr: "VT12001: unsupported: ORDER BY / LIMIT over UNION in recursive Common Table Expression",
 	}, {
 		sql:  "with x as (select 1), x as (select 1) select * from x",
 		serr: "VT03013: not unique table/alias: 'x'",
@@ -1007,6 +1126,10 @@ func TestScopingWithWITH(t *testing.T) {
 		}, {
 			query:        "select uu.id from (select id as col from t1) uu",
 			errorMessage: "column 'uu.id' not found",
+		}, {
+			query:     "with recursive c(id) as (select x from user union all select id + 1 from c where id < 5) select id from c",
+			recursive: MergeTableSets(TS0, TS1),
+			direct:    TS2,
 		}, {
 			query:     "select uu.id from (select id from t1) as uu where exists (select * from t2 as uu where uu.id = uu.uid)",
 			direct:    TS2,
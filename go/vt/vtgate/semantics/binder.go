@@ -347,6 +347,11 @@ func (b *binder) resolveColumnInHaving(colName *sqlparser.ColName, current *scop
 		return dependency{}, notFoundErr
 	}
 
+	if localDeps, err := b.resolveColumnInScope(current.parent, colName, allowMulti); err == nil && localDeps.empty() {
+		// the column comes from an outer query, so it has a single value for every row of this query
+		return deps, nil
+	}
+
 	sel := current.stmt.(*sqlparser.Select) // we can be sure of this, since HAVING doesn't exist on UNION
 	if selDeps := b.searchInSelectExpressions(colName, deps, sel); selDeps.direct.NotEmpty() {
 		return selDeps, nil
@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// CTETable is the table a recursive common table expression references from its recursive part.
+// It holds the rows produced by the previous iteration of the recursion, and only exists at the vtgate level.
+type CTETable struct {
+	tableName string
+	ASTNode   *sqlparser.AliasedTableExpr
+
+	// Union is the definition of the CTE. The left side is the non-recursive seed,
+	// and the right side is the recursive part that references this table.
+	Union *sqlparser.Union
+
+	columnNames     []string
+	types           []evalengine.Type
+	isAuthoritative bool
+}
+
+var _ TableInfo = (*CTETable)(nil)
+
+func newCTETable(node *sqlparser.AliasedTableExpr, union *sqlparser.Union, cols sqlparser.Columns, info unionInfo) *CTETable {
+	tbl := &CTETable{
+		tableName:       node.As.String(),
+		ASTNode:         node,
+		Union:           union,
+		types:           info.types,
+		isAuthoritative: info.isAuthoritative,
+	}
+	for i, expr := range info.exprs {
+		ae, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			continue
+		}
+		if len(cols) > i {
+			tbl.columnNames = append(tbl.columnNames, cols[i].String())
+			continue
+		}
+		tbl.columnNames = append(tbl.columnNames, ae.ColumnName())
+	}
+	return tbl
+}
+
+// dependencies implements the TableInfo interface
+func (c *CTETable) dependencies(colName string, org originable) (dependencies, error) {
+	directDeps := org.tableSetFor(c.ASTNode)
+	for i, name := range c.columnNames {
+		if !strings.EqualFold(name, colName) {
+			continue
+		}
+		var typ evalengine.Type
+		if i < len(c.types) {
+			typ = c.types[i]
+		}
+		return createCertain(directDeps, directDeps, typ), nil
+	}
+
+	if c.authoritative() {
+		return &nothing{}, nil
+	}
+
+	return createUncertain(directDeps, directDeps), nil
+}
+
+// IsInfSchema implements the TableInfo interface
+func (c *CTETable) IsInfSchema() bool {
+	return false
+}
+
+func (c *CTETable) matches(name sqlparser.TableName) bool {
+	return c.tableName == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+func (c *CTETable) authoritative() bool {
+	return c.isAuthoritative
+}
+
+// Name implements the TableInfo interface
+func (c *CTETable) Name() (sqlparser.TableName, error) {
+	return c.ASTNode.TableName()
+}
+
+func (c *CTETable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return c.ASTNode
+}
+
+func (c *CTETable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (c *CTETable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+func (c *CTETable) getColumns(bool) []ColumnInfo {
+	cols := make([]ColumnInfo, 0, len(c.columnNames))
+	for i, col := range c.columnNames {
+		info := ColumnInfo{Name: col}
+		if i < len(c.types) {
+			info.Type = c.types[i]
+		}
+		cols = append(cols, info)
+	}
+	return cols
+}
+
+// GetTables implements the TableInfo interface
+func (c *CTETable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(c.ASTNode)
+}
+
+// GetExprFor implements the TableInfo interface
+func (c *CTETable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.BadFieldError, "Unknown column '%s' in 'field list'", s)
+}
+
+// ColumnNames returns the names of the columns of the CTE
+func (c *CTETable) ColumnNames() []string {
+	return c.columnNames
+}
+
+// recursiveReference is the place in the recursive part of a CTE where the CTE references itself
+type recursiveReference struct {
+	union   *sqlparser.Union
+	columns sqlparser.Columns
+}
+
+// isRecursiveCTE returns true if the CTE definition references its own alias
+func isRecursiveCTE(cte *sqlparser.CommonTableExpr) bool {
+	return len(findCTEReferences(cte.Subquery.Select, cte.ID, true)) > 0
+}
+
+// findCTEReferences returns all the table expressions inside the node that are referencing the CTE name.
+// When inSubqueries is false, derived tables and subqueries are not searched.
+func findCTEReferences(node sqlparser.SQLNode, name sqlparser.IdentifierCS, inSubqueries bool) (refs []*sqlparser.AliasedTableExpr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node.(type) {
+		case *sqlparser.DerivedTable, *sqlparser.Subquery:
+			return inSubqueries, nil
+		}
+		aet, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tbl, ok := aet.Expr.(sqlparser.TableName)
+		if ok && tbl.Qualifier.IsEmpty() && tbl.Name == name {
+			refs = append(refs, aet)
+		}
+		return true, nil
+	}, node)
+	return
+}
+
+// checkRecursiveCTE makes sure that the recursive CTE is written in a way that we can evaluate iteratively:
+// a UNION of non-recursive query blocks, followed by a single recursive query block
+func checkRecursiveCTE(cte *sqlparser.CommonTableExpr) error {
+	name := cte.ID.String()
+	union, ok := cte.Subquery.Select.(*sqlparser.Union)
+	if !ok {
+		return vterrors.VT03034(name)
+	}
+	if len(union.OrderBy) > 0 || union.Limit != nil {
+		return vterrors.VT12001("ORDER BY / LIMIT over UNION in recursive Common Table Expression")
+	}
+	if len(findCTEReferences(union.Left, cte.ID, true)) > 0 {
+		if len(findCTEReferences(union.Right, cte.ID, true)) == 0 {
+			return vterrors.VT03035(name)
+		}
+		return vterrors.VT12001("multiple recursive query blocks in recursive Common Table Expression")
+	}
+
+	sel, ok := union.Right.(*sqlparser.Select)
+	if !ok {
+		return vterrors.VT12001("parenthesized recursive query block in recursive Common Table Expression")
+	}
+	refs := findCTEReferences(sel, cte.ID, true)
+	direct := findCTEReferences(sqlparser.TableExprs(sel.From), cte.ID, false)
+	if len(refs) != 1 || len(direct) != 1 {
+		return vterrors.VT03038(name)
+	}
+	if sqlparser.ContainsAggregation(sel.SelectExprs) || sqlparser.ContainsWindowFunc(sel.SelectExprs) || sel.GroupBy != nil {
+		return vterrors.VT03036(name)
+	}
+	if sel.Distinct || len(sel.OrderBy) > 0 || sel.Limit != nil {
+		return vterrors.VT12001("ORDER BY / LIMIT / SELECT DISTINCT in recursive query block of Common Table Expression")
+	}
+	if onInnerSideOfOuterJoin(sel.From, refs[0]) {
+		return vterrors.VT03037(name)
+	}
+	return nil
+}
+
+// onInnerSideOfOuterJoin returns true if the table is on the side of an outer join that can be null extended
+func onInnerSideOfOuterJoin(exprs sqlparser.TableExprs, tbl *sqlparser.AliasedTableExpr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		join, ok := node.(*sqlparser.JoinTableExpr)
+		if !ok {
+			return !found, nil
+		}
+		var inner sqlparser.TableExpr
+		switch join.Join {
+		case sqlparser.LeftJoinType, sqlparser.NaturalLeftJoinType:
+			inner = join.RightExpr
+		case sqlparser.RightJoinType, sqlparser.NaturalRightJoinType:
+			inner = join.LeftExpr
+		default:
+			return true, nil
+		}
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if node == tbl {
+				found = true
+			}
+			return !found, nil
+		}, inner)
+		return !found, nil
+	}, exprs)
+	return found
+}
@@ -37,6 +37,10 @@ type earlyRewriter struct {
 	aliasMapCache   map[*sqlparser.Select]map[string]exprContainer
 	tables          *tableCollector
 
+	// recursiveCTEs holds the common table expressions that reference themselves,
+	// and whether their definition has been used in the query already
+	recursiveCTEs map[*sqlparser.CommonTableExpr]bool
+
 	// reAnalyze is used when we are running in the late stage, after the other parts of semantic analysis
 	// have happened, and we are introducing or changing the AST. We invoke it so all parts of the query have been
 	// typed, scoped and bound correctly
@@ -135,6 +139,10 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if !ok || tbl.Qualifier.NotEmpty() {
 		return nil
 	}
+	if _, isRecursiveRef := r.tables.recursiveRefs[node]; isRecursiveRef {
+		// this is the recursive CTE referencing itself - the table collector will take care of it
+		return nil
+	}
 	scope := r.scoper.currentScope()
 	cte := scope.findCTE(tbl.Name.String())
 	if cte == nil {
@@ -143,8 +151,25 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	if node.As.IsEmpty() {
 		node.As = tbl.Name
 	}
+	sel := cte.Subquery.Select
+	if used, recursive := r.recursiveCTEs[cte]; recursive {
+		// every use of a recursive CTE gets its own copy of the definition,
+		// so the self-reference inside it can be tied to this specific use
+		union := sel.(*sqlparser.Union)
+		if used {
+			union = cloneRecursiveCTE(union)
+		}
+		r.recursiveCTEs[cte] = true
+		for _, ref := range findCTEReferences(union.Right, cte.ID, false) {
+			if ref.As.IsEmpty() {
+				ref.As = cte.ID
+			}
+			r.tables.recursiveRefs[ref] = recursiveReference{union: union, columns: cte.Columns}
+		}
+		sel = union
+	}
 	node.Expr = &sqlparser.DerivedTable{
-		Select: cte.Subquery.Select,
+		Select: sel,
 	}
 	if len(cte.Columns) > 0 {
 		node.Columns = cte.Columns
@@ -152,10 +177,29 @@ func (r *earlyRewriter) handleAliasedTable(node *sqlparser.AliasedTableExpr) err
 	return nil
 }
 
+// cloneRecursiveCTE makes a copy of the CTE definition that does not share any expressions with the original.
+// sqlparser.Clone does not copy column names, so we do that here to be able to bind them to a different table.
+func cloneRecursiveCTE(union *sqlparser.Union) *sqlparser.Union {
+	return sqlparser.Rewrite(sqlparser.Clone(union), nil, func(cursor *sqlparser.Cursor) bool {
+		if col, ok := cursor.Node().(*sqlparser.ColName); ok {
+			newCol := *col
+			cursor.Replace(&newCol)
+		}
+		return true
+	}).(*sqlparser.Union)
+}
+
 func (r *earlyRewriter) handleWith(node *sqlparser.With) error {
 	scope := r.scoper.currentScope()
 	for _, cte := range node.CTEs {
-		err := scope.addCTE(cte)
+		recursive := node.Recursive && isRecursiveCTE(cte)
+		if recursive {
+			if err := checkRecursiveCTE(cte); err != nil {
+				return err
+			}
+			r.recursiveCTEs[cte] = false
+		}
+		err := scope.addCTE(cte, recursive)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// JSONTable is the table produced by a JSON_TABLE expression in the FROM clause.
+// Its columns are declared in the expression, so it is always authoritative.
+type JSONTable struct {
+	tableName string
+
+	// ASTNode is not part of the query, it only exists so the JSON_TABLE can be identified like any other table
+	ASTNode *sqlparser.AliasedTableExpr
+	Node    *sqlparser.JSONTableExpr
+
+	columnNames []string
+	types       []evalengine.Type
+}
+
+var _ TableInfo = (*JSONTable)(nil)
+
+func newJSONTable(node *sqlparser.JSONTableExpr, coll collations.ID) *JSONTable {
+	tbl := &JSONTable{
+		tableName: node.Alias.String(),
+		ASTNode: &sqlparser.AliasedTableExpr{
+			Expr: sqlparser.NewTableName(node.Alias.String()),
+			As:   node.Alias,
+		},
+		Node: node,
+	}
+	tbl.addColumns(node.Columns, coll)
+	return tbl
+}
+
+// addColumns adds the columns of the JSON_TABLE, including the ones declared in NESTED PATH clauses
+func (j *JSONTable) addColumns(columns []*sqlparser.JtColumnDefinition, coll collations.ID) {
+	for _, col := range columns {
+		switch {
+		case col.JtOrdinal != nil:
+			j.columnNames = append(j.columnNames, col.JtOrdinal.Name.String())
+			j.types = append(j.types, evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID))
+		case col.JtPath != nil:
+			ct := col.JtPath.Type
+			typ := ct.SQLType()
+			values := evalengine.EnumSetValues(ct.EnumValues)
+			j.columnNames = append(j.columnNames, col.JtPath.Name.String())
+			j.types = append(j.types, evalengine.NewTypeEx(typ, collations.CollationForType(typ, coll), true, intOrZero(ct.Length), intOrZero(ct.Scale), &values))
+		case col.JtNestedPath != nil:
+			j.addColumns(col.JtNestedPath.Columns, coll)
+		}
+	}
+}
+
+func intOrZero(i *int) int32 {
+	if i == nil {
+		return 0
+	}
+	return int32(*i)
+}
+
+// dependencies implements the TableInfo interface
+func (j *JSONTable) dependencies(colName string, org originable) (dependencies, error) {
+	directDeps := org.tableSetFor(j.ASTNode)
+	for i, name := range j.columnNames {
+		if strings.EqualFold(name, colName) {
+			return createCertain(directDeps, directDeps, j.types[i]), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// IsInfSchema implements the TableInfo interface
+func (j *JSONTable) IsInfSchema() bool {
+	return false
+}
+
+func (j *JSONTable) matches(name sqlparser.TableName) bool {
+	return j.tableName == name.Name.String() && name.Qualifier.IsEmpty()
+}
+
+func (j *JSONTable) authoritative() bool {
+	return true
+}
+
+// Name implements the TableInfo interface
+func (j *JSONTable) Name() (sqlparser.TableName, error) {
+	return j.ASTNode.TableName()
+}
+
+func (j *JSONTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return j.ASTNode
+}
+
+func (j *JSONTable) canShortCut() shortCut {
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (j *JSONTable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+func (j *JSONTable) getColumns(bool) []ColumnInfo {
+	cols := make([]ColumnInfo, 0, len(j.columnNames))
+	for i, col := range j.columnNames {
+		cols = append(cols, ColumnInfo{Name: col, Type: j.types[i]})
+	}
+	return cols
+}
+
+// GetTables implements the TableInfo interface
+func (j *JSONTable) getTableSet(org originable) TableSet {
+	return org.tableSetFor(j.ASTNode)
+}
+
+// GetExprFor implements the TableInfo interface
+func (j *JSONTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.BadFieldError, "Unknown column '%s' in 'field list'", s)
+}
@@ -73,8 +73,16 @@ func (s *scoper) down(cursor *sqlparser.Cursor) error {
 		s.pushSelectScope(node)
 	case *sqlparser.Union:
 		s.pushUnionScope(node)
+	case *sqlparser.JSONTableExpr:
+		// the JSON document can use the tables that come before the JSON_TABLE in the FROM clause
+		s.enterJoinScope(cursor)
+		s.pushLateralScope()
 	case sqlparser.TableExpr:
 		s.enterJoinScope(cursor)
+	case *sqlparser.DerivedTable:
+		if node.Lateral {
+			s.pushLateralScope()
+		}
 	case sqlparser.SelectExprs:
 		s.copySelectExprs(cursor, node)
 	case sqlparser.OrderBy:
@@ -182,6 +190,19 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 	}
 }
 
+// pushLateralScope makes the tables that come before a lateral derived table in the
+// FROM clause visible to the query of the derived table.
+func (s *scoper) pushLateralScope() {
+	currScope := s.currentScope()
+	nScope := newScope(currScope)
+	if sel, isSel := currScope.stmt.(*sqlparser.Select); isSel && !currScope.stmtScope {
+		// the join scope only contains the tables of the current join,
+		// so we add the tables that come before it in the FROM clause
+		nScope.tables = append(nScope.tables, s.rScope[sel].tables...)
+	}
+	s.push(nScope)
+}
+
 func (s *scoper) pushSelectScope(node *sqlparser.Select) {
 	currScope := newScope(s.currentScope())
 	currScope.stmtScope = true
@@ -239,7 +260,14 @@ func (s *scoper) up(cursor *sqlparser.Cursor) error {
 		s.popScope()
 	case sqlparser.AggrFunc:
 		s.currentScope().inHavingAggr = false
+	case *sqlparser.DerivedTable:
+		if node.Lateral {
+			s.popScope()
+		}
 	case sqlparser.TableExpr:
+		if _, isJSONTable := node.(*sqlparser.JSONTableExpr); isJSONTable {
+			s.popScope()
+		}
 		if isParentSelect(cursor) {
 			curScope := s.currentScope()
 			s.popScope()
@@ -342,12 +370,17 @@ func newScope(parent *scope) *scope {
 	}
 }
 
-func (s *scope) addCTE(cte *sqlparser.CommonTableExpr) error {
+func (s *scope) addCTE(cte *sqlparser.CommonTableExpr, recursive bool) error {
 	name := cte.ID.String()
 	_, exists := s.ctes[name]
 	if exists {
 		return vterrors.VT03013(name)
 	}
+	if recursive {
+		// recursive CTEs are expected to use their own alias
+		s.ctes[name] = cte
+		return nil
+	}
 	if err := checkForInvalidAliasUse(cte, name); err != nil {
 		return err
 	}
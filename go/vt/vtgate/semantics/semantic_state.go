@@ -83,6 +83,7 @@ type (
 		HashJoin    bool
 		SubQueries  bool
 		Union       bool
+		Window      bool
 	}
 
 	// SemTable contains semantic analysis information about the query.
@@ -576,6 +577,16 @@ func (st *SemTable) TableSetFor(t *sqlparser.AliasedTableExpr) TableSet {
 	return EmptyTableSet()
 }
 
+// TableSetForJSONTable returns the TableSet of the JSON_TABLE
+func (st *SemTable) TableSetForJSONTable(node *sqlparser.JSONTableExpr) TableSet {
+	for idx, t := range st.Tables {
+		if jt, ok := t.(*JSONTable); ok && jt.Node == node {
+			return SingleTableSet(idx)
+		}
+	}
+	return EmptyTableSet()
+}
+
 // ReplaceTableSetFor replaces the given single TabletSet with the new *sqlparser.AliasedTableExpr
 func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExpr) {
 	if st == nil {
@@ -595,6 +606,8 @@ func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExp
 		tbl.ASTNode = t
 	case *DerivedTable:
 		tbl.ASTNode = t
+	case *CTETable:
+		tbl.ASTNode = t
 	}
 }
 
@@ -607,6 +620,18 @@ func (st *SemTable) TableInfoFor(id TableSet) (TableInfo, error) {
 	return st.Tables[offset], nil
 }
 
+// RecursiveCTE returns the table the recursive part of the union reads from,
+// or nil if the union is not the definition of a recursive CTE
+func (st *SemTable) RecursiveCTE(union *sqlparser.Union) *CTETable {
+	for _, table := range st.Tables {
+		cte, ok := table.(*CTETable)
+		if ok && cte.Union == union {
+			return cte
+		}
+	}
+	return nil
+}
+
 // RecursiveDeps return the table dependencies of the expression.
 func (st *SemTable) RecursiveDeps(expr sqlparser.Expr) TableSet {
 	return st.Recursive.dependencies(expr)
@@ -943,7 +968,31 @@ func (st *SemTable) ASTEquals() *sqlparser.Comparator {
 }
 
 func (st *SemTable) Clone(n sqlparser.SQLNode) sqlparser.SQLNode {
-	return sqlparser.CopyOnRewrite(n, nil, func(cursor *sqlparser.CopyOnWriteCursor) {
+	return st.clone(n, nil)
+}
+
+// CloneDML clones a DML statement like Clone does. When the statement has common table expressions,
+// the derived tables they were rewritten to are not copied, as they are looked up by their
+// AliasedTableExpr while the DML is planned.
+func (st *SemTable) CloneDML(stmt sqlparser.Statement) sqlparser.Statement {
+	var with *sqlparser.With
+	switch stmt := stmt.(type) {
+	case *sqlparser.Update:
+		with = stmt.With
+	case *sqlparser.Delete:
+		with = stmt.With
+	}
+	if with == nil {
+		return st.Clone(stmt).(sqlparser.Statement)
+	}
+	return st.clone(stmt, func(node, _ sqlparser.SQLNode) bool {
+		_, isDerived := node.(*sqlparser.DerivedTable)
+		return !isDerived
+	}).(sqlparser.Statement)
+}
+
+func (st *SemTable) clone(n sqlparser.SQLNode, pre func(node, parent sqlparser.SQLNode) bool) sqlparser.SQLNode {
+	return sqlparser.CopyOnRewrite(n, pre, func(cursor *sqlparser.CopyOnWriteCursor) {
 		expr, isExpr := cursor.Node().(sqlparser.Expr)
 		if !isExpr {
 			return
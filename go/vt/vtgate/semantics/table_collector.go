@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"fmt"
+	"slices"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 
@@ -38,6 +39,9 @@ type tableCollector struct {
 	org       originable
 	unionInfo map[*sqlparser.Union]unionInfo
 	done      map[*sqlparser.AliasedTableExpr]TableInfo
+
+	// recursiveRefs are the places where recursive CTEs reference themselves
+	recursiveRefs map[*sqlparser.AliasedTableExpr]recursiveReference
 }
 
 type earlyTableCollector struct {
@@ -64,11 +68,28 @@ func (etc *earlyTableCollector) up(cursor *sqlparser.Cursor) {
 	case *sqlparser.With:
 		for _, cte := range node.CTEs {
 			etc.withTables[cte.ID] = nil
+			if node.Recursive {
+				etc.forgetCTEReferences(cte)
+			}
 		}
 	}
 
 }
 
+// forgetCTEReferences removes the tables a recursive CTE uses to reference itself.
+// They were collected before we knew that they point to the CTE and not to a real table.
+func (etc *earlyTableCollector) forgetCTEReferences(cte *sqlparser.CommonTableExpr) {
+	for _, ref := range findCTEReferences(cte.Subquery.Select, cte.ID, true) {
+		if _, found := etc.done[ref]; !found {
+			continue
+		}
+		delete(etc.done, ref)
+		etc.Tables = slices.DeleteFunc(etc.Tables, func(info TableInfo) bool {
+			return info.GetAliasedTableExpr() == ref
+		})
+	}
+}
+
 func (etc *earlyTableCollector) visitAliasedTableExpr(aet *sqlparser.AliasedTableExpr) {
 	tbl, ok := aet.Expr.(sqlparser.TableName)
 	if !ok {
@@ -79,13 +100,14 @@ func (etc *earlyTableCollector) visitAliasedTableExpr(aet *sqlparser.AliasedTabl
 
 func (etc *earlyTableCollector) newTableCollector(scoper *scoper, org originable) *tableCollector {
 	return &tableCollector{
-		Tables:    etc.Tables,
-		scoper:    scoper,
-		si:        etc.si,
-		currentDb: etc.currentDb,
-		unionInfo: map[*sqlparser.Union]unionInfo{},
-		done:      etc.done,
-		org:       org,
+		Tables:        etc.Tables,
+		scoper:        scoper,
+		si:            etc.si,
+		currentDb:     etc.currentDb,
+		unionInfo:     map[*sqlparser.Union]unionInfo{},
+		done:          etc.done,
+		org:           org,
+		recursiveRefs: map[*sqlparser.AliasedTableExpr]recursiveReference{},
 	}
 }
 
@@ -113,6 +135,8 @@ func (tc *tableCollector) up(cursor *sqlparser.Cursor) error {
 		return tc.visitAliasedTableExpr(node)
 	case *sqlparser.Union:
 		return tc.visitUnion(node)
+	case *sqlparser.JSONTableExpr:
+		return tc.visitJSONTable(node)
 	case *sqlparser.RowAlias:
 		ins, ok := cursor.Parent().(*sqlparser.Insert)
 		if !ok {
@@ -134,6 +158,15 @@ func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr
 	return nil
 }
 
+func (tc *tableCollector) visitJSONTable(node *sqlparser.JSONTableExpr) error {
+	tableInfo := newJSONTable(node, tc.org.collationEnv().DefaultConnectionCharset())
+	tc.Tables = append(tc.Tables, tableInfo)
+
+	// the scoper has not yet left the scope used by the JSON document,
+	// so the table is added to the scope the JSON_TABLE is part of
+	return tc.scoper.currentScope().parent.addTable(tableInfo)
+}
+
 func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 	firstSelect := sqlparser.GetFirstSelect(union)
 	expanded, selectExprs := getColumnNames(firstSelect.SelectExprs)
@@ -299,6 +332,10 @@ func buildDerivedTable(colNames []string, rowAlias *sqlparser.RowAlias, types []
 }
 
 func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sqlparser.TableName) (err error) {
+	if ref, isRecursiveRef := tc.recursiveRefs[node]; isRecursiveRef {
+		return tc.addCTETable(node, ref)
+	}
+
 	var tableInfo TableInfo
 	var found bool
 
@@ -315,6 +352,35 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 	return scope.addTable(tableInfo)
 }
 
+// addCTETable adds the table that the recursive part of a CTE uses to read the rows produced by the previous iteration
+func (tc *tableCollector) addCTETable(node *sqlparser.AliasedTableExpr, ref recursiveReference) error {
+	var info unionInfo
+	switch seed := ref.union.Left.(type) {
+	case *sqlparser.Union:
+		info = tc.unionInfo[seed]
+	case *sqlparser.Select:
+		info.isAuthoritative, info.exprs = getColumnNames(seed.SelectExprs)
+		for _, expr := range seed.SelectExprs {
+			ae, ok := expr.(*sqlparser.AliasedExpr)
+			if !ok {
+				continue
+			}
+			_, _, typ := tc.org.depsForExpr(ae.Expr)
+			info.types = append(info.types, typ)
+		}
+	default:
+		return vterrors.VT13001(fmt.Sprintf("unexpected seed in recursive CTE: %T", seed))
+	}
+	if len(ref.columns) > 0 && info.isAuthoritative && len(ref.columns) != len(info.exprs) {
+		return vterrors.VT03033()
+	}
+
+	tableInfo := newCTETable(node, ref.union, ref.columns, info)
+	tc.Tables = append(tc.Tables, tableInfo)
+	scope := tc.scoper.currentScope()
+	return scope.addTable(tableInfo)
+}
+
 func getTableInfo(node *sqlparser.AliasedTableExpr, t sqlparser.TableName, si SchemaInformation, currentDb string) (TableInfo, error) {
 	var tbl *vindexes.Table
 	var vindex vindexes.Vindex
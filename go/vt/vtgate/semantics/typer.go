@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -61,10 +62,35 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.ArgumentLessWindowExpr:
+		switch node.Type {
+		case sqlparser.CumeDistExprType, sqlparser.PercentRankExprType:
+			t.m[node] = evalengine.NewTypeEx(sqltypes.Float64, collations.CollationBinaryID, false, 0, 0, nil)
+		default:
+			t.m[node] = evalengine.NewTypeEx(sqltypes.Uint64, collations.CollationBinaryID, false, 0, 0, nil)
+		}
+	case *sqlparser.NtileExpr:
+		t.m[node] = evalengine.NewTypeEx(sqltypes.Uint64, collations.CollationBinaryID, true, 0, 0, nil)
+	case *sqlparser.FirstOrLastValueExpr:
+		t.setNullableTypeFrom(node, node.Expr)
+	case *sqlparser.NTHValueExpr:
+		t.setNullableTypeFrom(node, node.Expr)
+	case *sqlparser.LagLeadExpr:
+		t.setNullableTypeFrom(node, node.Expr)
 	}
 	return nil
 }
 
+// setNullableTypeFrom types a window function that returns values of its argument,
+// or NULL when no row is available in the window frame
+func (t *typer) setNullableTypeFrom(node, arg sqlparser.Expr) {
+	typ, ok := t.m[arg]
+	if !ok {
+		return
+	}
+	t.m[node] = evalengine.NewTypeEx(typ.Type(), typ.Collation(), true, typ.Size(), typ.Scale(), typ.Values())
+}
+
 func (t *typer) setTypeFor(node *sqlparser.ColName, typ evalengine.Type) {
 	t.m[node] = typ
 }
@@ -114,10 +114,12 @@ type vcursorImpl struct {
 	// A nil value represents that no foreign_key_checks value was provided.
 	fkChecksState       *bool
 	ignoreMaxMemoryRows bool
-	vschema             *vindexes.VSchema
-	vm                  VSchemaOperator
-	semTable            *semantics.SemTable
-	warnShardedOnly     bool // when using sharded only features, a warning will be warnings field
+	// memoryBudget is shared by all the primitives of the query. It is nil when --query-memory-budget is not set.
+	memoryBudget    *engine.MemoryBudget
+	vschema         *vindexes.VSchema
+	vm              VSchemaOperator
+	semTable        *semantics.SemTable
+	warnShardedOnly bool // when using sharded only features, a warning will be warnings field
 
 	warnings []*querypb.QueryWarning // any warnings that are accumulated during the planning phase are stored here
 	pv       plancontext.PlannerVersion
@@ -174,6 +176,10 @@ func newVCursorImpl(
 		warmingReadsPct = executor.warmingReadsPercent
 		warmingReadsChan = executor.warmingReadsChannel
 	}
+	var memoryBudget *engine.MemoryBudget
+	if queryMemoryBudget > 0 {
+		memoryBudget = engine.NewMemoryBudget(queryMemoryBudget)
+	}
 	return &vcursorImpl{
 		safeSession:         safeSession,
 		keyspace:            keyspace,
@@ -191,6 +197,7 @@ func newVCursorImpl(
 		pv:                  pv,
 		warmingReadsPercent: warmingReadsPct,
 		warmingReadsChannel: warmingReadsChan,
+		memoryBudget:        memoryBudget,
 	}, nil
 }
 
@@ -229,6 +236,21 @@ func (vc *vcursorImpl) MaxMemoryRows() int {
 	return maxMemoryRows
 }
 
+// CTEMaxRecursionDepth returns the default maximum number of iterations a recursive CTE can run.
+func (vc *vcursorImpl) CTEMaxRecursionDepth() int {
+	return cteMaxRecursionDepth
+}
+
+// MemoryBudget returns the memory budget of the query, or nil when intermediate results are never spilled to disk.
+func (vc *vcursorImpl) MemoryBudget() *engine.MemoryBudget {
+	return vc.memoryBudget
+}
+
+// SpillDir returns the directory where intermediate results are spilled to disk.
+func (vc *vcursorImpl) SpillDir() string {
+	return spillDir
+}
+
 // ExceedsMaxMemoryRows returns a boolean indicating whether the maxMemoryRows value has been exceeded.
 // Returns false if the max memory rows override directive is set to true.
 func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
@@ -250,6 +272,11 @@ func (vc *vcursorImpl) IsShardRoutingEnabled() bool {
 	return enableShardRouting
 }
 
+// IsLocalInfileEnabled implements the VSchema interface.
+func (vc *vcursorImpl) IsLocalInfileEnabled() bool {
+	return mysqlServerLocalInfile
+}
+
 // FindTable finds the specified table. If the keyspace what specified in the input, it gets used as qualifier.
 // Otherwise, the keyspace from the request is used, if one was provided.
 func (vc *vcursorImpl) FindTable(name sqlparser.TableName) (*vindexes.Table, string, topodatapb.TabletType, key.Destination, error) {
@@ -1079,6 +1106,10 @@ func (vc *vcursorImpl) GetAggregateUDFs() []string {
 	return vc.vschema.GetAggregateUDFs()
 }
 
+func (vc *vcursorImpl) FindFunction(keyspace, name string) *vindexes.Function {
+	return vc.vschema.FindFunction(keyspace, name)
+}
+
 // ParseDestinationTarget parses destination target string and sets default keyspace if possible.
 func parseDestinationTarget(targetString string, vschema *vindexes.VSchema) (string, topodatapb.TabletType, key.Destination, error) {
 	destKeyspace, destTabletType, dest, err := topoprotopb.ParseDestination(targetString, defaultTabletType)
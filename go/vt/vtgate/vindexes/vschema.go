@@ -69,11 +69,16 @@ type VSchema struct {
 	// table is uniquely named, the value will be the qualified Table object
 	// with the keyspace where this table exists. If multiple keyspaces have a
 	// table with the same name, the value will be a `nil`.
-	globalTables         map[string]*Table
-	uniqueVindexes       map[string]Vindex
+	globalTables   map[string]*Table
+	uniqueVindexes map[string]Vindex
+	// globalFunctions contains the functions declared by all keyspaces. As with
+	// globalTables, the value is `nil` for functions declared by multiple keyspaces.
+	globalFunctions      map[string]*Function
 	Keyspaces            map[string]*KeyspaceSchema `json:"keyspaces"`
 	ShardRoutingRules    map[string]string          `json:"shard_routing_rules"`
 	KeyspaceRoutingRules map[string]string          `json:"keyspace_routing_rules"`
+	// PlanBaselines contains the query plan baselines by normalized query.
+	PlanBaselines map[string]*vschemapb.PlanBaseline `json:"plan_baselines,omitempty"`
 	// created is the time when the VSchema object was created. Used to detect if a cached
 	// copy of the vschema is stale.
 	created time.Time
@@ -110,6 +115,8 @@ type Table struct {
 	Columns                 []Column               `json:"columns,omitempty"`
 	Pinned                  []byte                 `json:"pinned,omitempty"`
 	ColumnListAuthoritative bool                   `json:"column_list_authoritative,omitempty"`
+	// ResultCache is set if vtgate can cache the results of the queries reading from this table.
+	ResultCache bool `json:"result_cache,omitempty"`
 	// ReferencedBy is an inverse mapping of tables in other keyspaces that
 	// reference this table via Source.
 	//
@@ -128,6 +135,28 @@ type Table struct {
 	// MySQL error message: ERROR 3756 (HY000): The primary key cannot be a functional index
 	PrimaryKey sqlparser.Columns `json:"primary_key,omitempty"`
 	UniqueKeys []sqlparser.Exprs `json:"unique_keys,omitempty"`
+
+	// Statistics are used by the planner to estimate the cost of a plan. It is nil when the schema tracker
+	// has not collected any statistics for the table.
+	Statistics *TableStatistics `json:"statistics,omitempty"`
+}
+
+// TableStatistics contains the row count and index cardinality estimated by MySQL for a table.
+// They are collected on a single shard. For sharded keyspaces, the row count is scaled by the number
+// of shards, while the cardinality is the one of the shard.
+type TableStatistics struct {
+	Rows uint64 `json:"rows"`
+	// Cardinality is the estimated number of distinct values of the columns that are the first column
+	// of an index, keyed by their lowercase name.
+	Cardinality map[string]uint64 `json:"cardinality,omitempty"`
+}
+
+// ColumnCardinality returns the estimated number of distinct values in the column, or 0 when it is unknown
+func (ts *TableStatistics) ColumnCardinality(column sqlparser.IdentifierCI) uint64 {
+	if ts == nil {
+		return 0
+	}
+	return ts.Cardinality[column.Lowered()]
 }
 
 // GetTableName gets the sqlparser.TableName for the vindex Table.
@@ -192,7 +221,9 @@ type Column struct {
 	Default       sqlparser.Expr         `json:"default,omitempty"`
 
 	// Invisible marks this as a column that will not be automatically included in `*` projections
-	Invisible bool  `json:"invisible,omitempty"`
+	Invisible bool `json:"invisible,omitempty"`
+	// Generated marks this as a generated column, whose value can't be set. It is only known from the tracked schema.
+	Generated bool  `json:"generated,omitempty"`
 	Size      int32 `json:"size,omitempty"`
 	Scale     int32 `json:"scale,omitempty"`
 	Nullable  bool  `json:"nullable,omitempty"`
@@ -206,6 +237,7 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name      string   `json:"name"`
 		Type      string   `json:"type,omitempty"`
 		Invisible bool     `json:"invisible,omitempty"`
+		Generated bool     `json:"generated,omitempty"`
 		Default   string   `json:"default,omitempty"`
 		Size      int32    `json:"size,omitempty"`
 		Scale     int32    `json:"scale,omitempty"`
@@ -215,6 +247,7 @@ func (col *Column) MarshalJSON() ([]byte, error) {
 		Name:      col.Name.String(),
 		Type:      querypb.Type_name[int32(col.Type)],
 		Invisible: col.Invisible,
+		Generated: col.Generated,
 		Size:      col.Size,
 		Scale:     col.Scale,
 		Nullable:  col.Nullable,
@@ -248,6 +281,10 @@ type KeyspaceSchema struct {
 
 	// These are the UDFs that exist in the schema and are aggregations
 	AggregateUDFs []string
+
+	// Functions contains the stored and loadable functions declared by the
+	// keyspace, by lowercase name.
+	Functions map[string]*Function
 }
 
 type ksJSON struct {
@@ -258,6 +295,7 @@ type ksJSON struct {
 	Views           map[string]string          `json:"views,omitempty"`
 	Error           string                     `json:"error,omitempty"`
 	MultiTenantSpec *vschemapb.MultiTenantSpec `json:"multi_tenant_spec,omitempty"`
+	Functions       map[string]*Function       `json:"functions,omitempty"`
 }
 
 // findTable looks for the table with the requested tablename in the keyspace.
@@ -291,6 +329,7 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 		ForeignKeyMode:  ks.ForeignKeyMode.String(),
 		Vindexes:        ks.Vindexes,
 		MultiTenantSpec: ks.MultiTenantSpec,
+		Functions:       ks.Functions,
 	}
 	if ks.Error != nil {
 		ksJ.Error = ks.Error.Error()
@@ -311,6 +350,18 @@ type AutoIncrement struct {
 	Sequence *Table                 `json:"sequence"`
 }
 
+// Function is a stored or loadable function declared by a keyspace.
+type Function struct {
+	Name     sqlparser.IdentifierCI `json:"name"`
+	Keyspace *Keyspace              `json:"-"`
+	// Deterministic is set if the function always returns the same result for
+	// the same arguments.
+	Deterministic bool `json:"deterministic,omitempty"`
+	// Plugin is the name under which the Go implementation of the function is
+	// registered with the evalengine, if any.
+	Plugin string `json:"plugin,omitempty"`
+}
+
 type Source struct {
 	sqlparser.TableName
 }
@@ -334,10 +385,12 @@ func BuildVSchema(source *vschemapb.SrvVSchema, parser *sqlparser.Parser) (vsche
 	// buildGlobalTables before buildReferences so that buildReferences can
 	// resolve sources which reference global tables.
 	buildGlobalTables(source, vschema)
+	buildGlobalFunctions(source, vschema)
 	buildReferences(source, vschema)
 	buildRoutingRule(source, vschema, parser)
 	buildShardRoutingRule(source, vschema)
 	buildKeyspaceRoutingRule(source, vschema)
+	buildPlanBaselines(source, vschema)
 	// Resolve auto-increments after routing rules are built since sequence tables also obey routing rules.
 	resolveAutoIncrement(source, vschema, parser)
 	return vschema
@@ -385,9 +438,34 @@ func buildKeyspaces(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlp
 		}
 		vschema.Keyspaces[ksname] = ksvschema
 		ksvschema.Error = buildTables(ks, vschema, ksvschema, parser)
+		if ksvschema.Error == nil {
+			ksvschema.Error = buildFunctions(ks, ksvschema)
+		}
 	}
 }
 
+func buildFunctions(ks *vschemapb.Keyspace, ksvschema *KeyspaceSchema) error {
+	for _, fn := range ks.Functions {
+		if fn.Name == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "missing name for function in keyspace %s", ksvschema.Keyspace.Name)
+		}
+		name := sqlparser.NewIdentifierCI(fn.Name)
+		if _, ok := ksvschema.Functions[name.Lowered()]; ok {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate function %s in keyspace %s", fn.Name, ksvschema.Keyspace.Name)
+		}
+		if ksvschema.Functions == nil {
+			ksvschema.Functions = make(map[string]*Function, len(ks.Functions))
+		}
+		ksvschema.Functions[name.Lowered()] = &Function{
+			Name:          name,
+			Keyspace:      ksvschema.Keyspace,
+			Deterministic: fn.Deterministic,
+			Plugin:        fn.Plugin,
+		}
+	}
+	return nil
+}
+
 // replaceUnspecifiedForeignKeyMode replaces the default value of the foreign key mode enum with the default we want to keep.
 func replaceUnspecifiedForeignKeyMode(fkMode vschemapb.Keyspace_ForeignKeyMode) vschemapb.Keyspace_ForeignKeyMode {
 	if fkMode == vschemapb.Keyspace_unspecified {
@@ -437,6 +515,26 @@ func (vschema *VSchema) AddUDF(ksname, udfName string) error {
 	return nil
 }
 
+func buildGlobalFunctions(source *vschemapb.SrvVSchema, vschema *VSchema) {
+	for ksname, ks := range source.Keyspaces {
+		// As for tables, the functions of a keyspace that requires explicit
+		// routing can only be found by qualifying them.
+		if ks.RequireExplicitRouting {
+			continue
+		}
+		for name, fn := range vschema.Keyspaces[ksname].Functions {
+			if vschema.globalFunctions == nil {
+				vschema.globalFunctions = make(map[string]*Function)
+			}
+			if _, ok := vschema.globalFunctions[name]; ok {
+				vschema.globalFunctions[name] = nil
+			} else {
+				vschema.globalFunctions[name] = fn
+			}
+		}
+	}
+}
+
 func buildGlobalTables(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	for ksname, ks := range source.Keyspaces {
 		ksvschema := vschema.Keyspaces[ksname]
@@ -608,6 +706,7 @@ func buildTables(ks *vschemapb.Keyspace, vschema *VSchema, ksvschema *KeyspaceSc
 			Name:                    sqlparser.NewIdentifierCS(tname),
 			Keyspace:                keyspace,
 			ColumnListAuthoritative: table.ColumnListAuthoritative,
+			ResultCache:             table.ResultCache,
 		}
 		switch table.Type {
 		case "":
@@ -1025,6 +1124,32 @@ func buildKeyspaceRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	vschema.KeyspaceRoutingRules = rulesMap
 }
 
+func buildPlanBaselines(source *vschemapb.SrvVSchema, vschema *VSchema) {
+	baselines := source.GetPlanBaselines().GetBaselines()
+	if len(baselines) == 0 {
+		return
+	}
+	vschema.PlanBaselines = make(map[string]*vschemapb.PlanBaseline, len(baselines))
+	for _, baseline := range baselines {
+		vschema.PlanBaselines[baseline.Query] = baseline
+	}
+}
+
+// FindFunction returns the declaration of the function with the given name, or nil
+// if it's not declared. If a keyspace is specified, only the functions of that keyspace
+// are searched. Otherwise, the function is only found if a single keyspace declares it.
+func (vschema *VSchema) FindFunction(keyspace, name string) *Function {
+	name = strings.ToLower(name)
+	if keyspace == "" {
+		return vschema.globalFunctions[name]
+	}
+	ks, ok := vschema.Keyspaces[keyspace]
+	if !ok {
+		return nil
+	}
+	return ks.Functions[name]
+}
+
 // FindTable returns a pointer to the Table. If a keyspace is specified, only tables
 // from that keyspace are searched. If the specified keyspace is unsharded
 // and no tables matched, it's considered valid: FindTable will construct a table
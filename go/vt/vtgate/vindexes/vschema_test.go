@@ -580,6 +580,88 @@ func TestVSchemaColumnListAuthoritative(t *testing.T) {
 	assertColumn(t, t1.Columns[1], "c2", sqltypes.VarChar)
 }
 
+func TestVSchemaResultCache(t *testing.T) {
+	good := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ResultCache: true},
+					"t2": {}}}}}
+
+	got := BuildVSchema(&good, sqlparser.NewTestParser())
+
+	t1, err := got.FindTable("unsharded", "t1")
+	require.NoError(t, err)
+	assert.True(t, t1.ResultCache)
+	t2, err := got.FindTable("unsharded", "t2")
+	require.NoError(t, err)
+	assert.False(t, t2.ResultCache)
+}
+
+func TestVSchemaFunctions(t *testing.T) {
+	good := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ksa": {
+				Functions: []*vschemapb.Function{
+					{Name: "Geo_Distance", Deterministic: true, Plugin: "geo_distance"},
+					{Name: "next_id"},
+					{Name: "shared"},
+				},
+			},
+			"ksb": {
+				Functions: []*vschemapb.Function{
+					{Name: "shared", Deterministic: true},
+				},
+			},
+		},
+	}
+
+	got := BuildVSchema(&good, sqlparser.NewTestParser())
+	require.NoError(t, got.Keyspaces["ksa"].Error)
+	require.NoError(t, got.Keyspaces["ksb"].Error)
+
+	fn := got.FindFunction("", "geo_distance")
+	require.NotNil(t, fn)
+	assert.Equal(t, "Geo_Distance", fn.Name.String())
+	assert.Equal(t, "ksa", fn.Keyspace.Name)
+	assert.True(t, fn.Deterministic)
+	assert.Equal(t, "geo_distance", fn.Plugin)
+
+	fn = got.FindFunction("", "NEXT_ID")
+	require.NotNil(t, fn)
+	assert.False(t, fn.Deterministic)
+	assert.Empty(t, fn.Plugin)
+
+	// functions declared by several keyspaces must be qualified
+	assert.Nil(t, got.FindFunction("", "shared"))
+	fn = got.FindFunction("ksb", "shared")
+	require.NotNil(t, fn)
+	assert.Equal(t, "ksb", fn.Keyspace.Name)
+	assert.True(t, fn.Deterministic)
+
+	assert.Nil(t, got.FindFunction("", "unknown"))
+	assert.Nil(t, got.FindFunction("ksb", "next_id"))
+	assert.Nil(t, got.FindFunction("unknown", "next_id"))
+}
+
+func TestVSchemaFunctionsFail(t *testing.T) {
+	bad := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"dup": {
+				Functions: []*vschemapb.Function{{Name: "fn"}, {Name: "FN"}},
+			},
+			"noname": {
+				Functions: []*vschemapb.Function{{Plugin: "fn"}},
+			},
+		},
+	}
+
+	got := BuildVSchema(&bad, sqlparser.NewTestParser())
+	require.EqualError(t, got.Keyspaces["dup"].Error, "duplicate function FN in keyspace dup")
+	require.EqualError(t, got.Keyspaces["noname"].Error, "missing name for function in keyspace noname")
+}
+
 func TestVSchemaColumnsFail(t *testing.T) {
 	good := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
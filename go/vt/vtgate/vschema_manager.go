@@ -18,6 +18,7 @@ package vtgate
 
 import (
 	"context"
+	"slices"
 	"sync"
 
 	"vitess.io/vitess/go/vt/graph"
@@ -53,6 +54,7 @@ type SchemaInfo interface {
 	Tables(ks string) map[string]*vindexes.TableInfo
 	Views(ks string) map[string]sqlparser.SelectStatement
 	UDFs(ks string) []string
+	Statistics(ks string) map[string]*vindexes.TableStatistics
 }
 
 // GetCurrentSrvVschema returns a copy of the latest SrvVschema from the
@@ -205,6 +207,7 @@ func (vm *VSchemaManager) updateFromSchema(vschema *vindexes.VSchema) {
 		vm.updateTableInfo(vschema, ks, ksName)
 		vm.updateViewInfo(ks, ksName)
 		vm.updateUDFsInfo(ks, ksName)
+		vm.updateStatistics(ks, ksName)
 	}
 }
 
@@ -273,6 +276,46 @@ func (vm *VSchemaManager) updateUDFsInfo(ks *vindexes.KeyspaceSchema, ksName str
 	ks.AggregateUDFs = vm.schema.UDFs(ksName)
 }
 
+// updateStatistics sets the statistics collected by the schema tracker on the tables of the keyspace.
+// They are collected on a single shard, so the row counts of a sharded keyspace are scaled by its
+// number of shards, to be comparable with the ones of the other keyspaces.
+func (vm *VSchemaManager) updateStatistics(ks *vindexes.KeyspaceSchema, ksName string) {
+	stats := vm.schema.Statistics(ksName)
+	if len(stats) == 0 {
+		return
+	}
+	shards := uint64(1)
+	if ks.Keyspace.Sharded {
+		shards = vm.shardCount(ksName)
+	}
+	for tblName, tblStats := range stats {
+		if tbl := ks.Tables[tblName]; tbl != nil {
+			tbl.Statistics = &vindexes.TableStatistics{
+				Rows:        tblStats.Rows * shards,
+				Cardinality: tblStats.Cardinality,
+			}
+		}
+	}
+}
+
+// shardCount returns the number of shards serving the primary tablets of the keyspace, or 1 when it is not known.
+func (vm *VSchemaManager) shardCount(ksName string) uint64 {
+	if vm.serv == nil {
+		return 1
+	}
+	srvKeyspace, err := vm.serv.GetSrvKeyspace(context.Background(), vm.cell, ksName)
+	if err != nil {
+		log.Warningf("error getting the shards of keyspace %s, its table statistics are not scaled: %v", ksName, err)
+		return 1
+	}
+	for _, partition := range srvKeyspace.GetPartitions() {
+		if partition.ServedType == topodatapb.TabletType_PRIMARY && len(partition.ShardReferences) > 0 {
+			return uint64(len(partition.ShardReferences))
+		}
+	}
+	return 1
+}
+
 func markErrorIfCyclesInFk(vschema *vindexes.VSchema) {
 	for ksName, ks := range vschema.Keyspaces {
 		// Only check cyclic foreign keys for keyspaces that have
@@ -347,6 +390,14 @@ func setColumns(ks *vindexes.KeyspaceSchema, tblName string, columns []vindexes.
 	if !vTbl.ColumnListAuthoritative {
 		vTbl.Columns = columns
 		vTbl.ColumnListAuthoritative = true
+		return ks.Tables[tblName]
+	}
+	// the vschema can't declare generated columns, so they are marked from the tracked schema
+	for i, col := range vTbl.Columns {
+		idx := slices.IndexFunc(columns, func(c vindexes.Column) bool { return c.Name.Equal(col.Name) })
+		if idx >= 0 {
+			vTbl.Columns[i].Generated = columns[idx].Generated
+		}
 	}
 	return ks.Tables[tblName]
 }
@@ -7,8 +7,10 @@ import (
 
 	"vitess.io/vitess/go/test/utils"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo/srvtopotest"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -31,6 +33,10 @@ func TestVSchemaUpdate(t *testing.T) {
 	tblCol1 := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols1, ColumnListAuthoritative: true}
 	tblCol2 := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols2, ColumnListAuthoritative: true}
 	tblCol2NA := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: cols2}
+	tblCol2Gen := &vindexes.Table{Name: sqlparser.NewIdentifierCS("tbl"), Keyspace: ks, Columns: []vindexes.Column{
+		cols2[0],
+		{Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Nullable: true, Generated: true},
+	}, ColumnListAuthoritative: true}
 
 	vindexTable_multicol_t1 := &vindexes.Table{
 		Name:                    sqlparser.NewIdentifierCS("multicol_t1"),
@@ -161,6 +167,17 @@ func TestVSchemaUpdate(t *testing.T) {
 		schema: map[string]*vindexes.TableInfo{"tbl": {Columns: cols1}},
 		// schema tracker will be ignored for authoritative tables.
 		expected: makeTestVSchema("ks", false, map[string]*vindexes.Table{"tbl": tblCol2}),
+	}, {
+		name: "1 Schematracking - 1 srvVSchema (have columns) authoritative with generated columns",
+		srvVschema: makeTestSrvVSchema("ks", false, map[string]*vschemapb.Table{
+			"tbl": {
+				Columns:                 []*vschemapb.Column{{Name: "uid", Type: querypb.Type_INT64}, {Name: "name", Type: querypb.Type_VARCHAR}},
+				ColumnListAuthoritative: true,
+			},
+		}),
+		schema: map[string]*vindexes.TableInfo{"tbl": {Columns: []vindexes.Column{{Name: sqlparser.NewIdentifierCI("name"), Type: querypb.Type_VARCHAR, Generated: true}}}},
+		// only the generated columns are taken from the schema tracker for authoritative tables.
+		expected: makeTestVSchema("ks", false, map[string]*vindexes.Table{"tbl": tblCol2Gen}),
 	}, {
 		name:     "srvVschema received as nil",
 		schema:   map[string]*vindexes.TableInfo{"tbl": {Columns: cols1}},
@@ -470,6 +487,56 @@ func TestVSchemaUDFsUpdate(t *testing.T) {
 	utils.MustMatch(t, vs, vm.currentVschema, "currentVschema does not match Vschema")
 }
 
+// TestVSchemaStatisticsUpdate tests that the table statistics are set on the tables in the VSchema.
+func TestVSchemaStatisticsUpdate(t *testing.T) {
+	vm := &VSchemaManager{}
+	var vs *vindexes.VSchema
+	vm.subscriber = func(vschema *vindexes.VSchema, _ *VSchemaStats) {
+		vs = vschema
+	}
+	t1Stats := &vindexes.TableStatistics{Rows: 100, Cardinality: map[string]uint64{"id": 100}}
+	vm.schema = &fakeSchema{
+		t: map[string]*vindexes.TableInfo{
+			"t1": {Columns: []vindexes.Column{{Name: sqlparser.NewIdentifierCI("id"), Type: querypb.Type_INT64}}},
+		},
+		stats: map[string]*vindexes.TableStatistics{
+			"t1":      t1Stats,
+			"missing": {Rows: 10},
+		},
+	}
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {Sharded: false},
+		},
+	}, nil)
+
+	tables := vs.Keyspaces["ks"].Tables
+	require.Len(t, tables, 1)
+	require.Equal(t, t1Stats, tables["t1"].Statistics)
+
+	// the statistics of a sharded keyspace are collected on one of its shards, so its row counts are scaled
+	serv := srvtopotest.NewPassthroughSrvTopoServer()
+	serv.SrvKeyspace = &topodatapb.SrvKeyspace{Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{{
+		ServedType:      topodatapb.TabletType_PRIMARY,
+		ShardReferences: []*topodatapb.ShardReference{{Name: "-80"}, {Name: "80-"}},
+	}}}
+	vm.serv = serv
+	vm.VSchemaUpdate(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"ks": {
+				Sharded:  true,
+				Vindexes: map[string]*vschemapb.Vindex{"hash": {Type: "hash"}},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {ColumnVindexes: []*vschemapb.ColumnVindex{{Column: "id", Name: "hash"}}},
+				},
+			},
+		},
+	}, nil)
+
+	require.Equal(t, &vindexes.TableStatistics{Rows: 200, Cardinality: t1Stats.Cardinality}, vs.Keyspaces["ks"].Tables["t1"].Statistics)
+	require.EqualValues(t, 100, t1Stats.Rows)
+}
+
 func TestMarkErrorIfCyclesInFk(t *testing.T) {
 	ksName := "ks"
 	keyspace := &vindexes.Keyspace{
@@ -845,8 +912,9 @@ func makeTestSrvVSchema(ks string, sharded bool, tbls map[string]*vschemapb.Tabl
 }
 
 type fakeSchema struct {
-	t    map[string]*vindexes.TableInfo
-	udfs []string
+	t     map[string]*vindexes.TableInfo
+	udfs  []string
+	stats map[string]*vindexes.TableStatistics
 }
 
 func (f *fakeSchema) Tables(string) map[string]*vindexes.TableInfo {
@@ -857,5 +925,8 @@ func (f *fakeSchema) Views(string) map[string]sqlparser.SelectStatement {
 	return nil
 }
 func (f *fakeSchema) UDFs(string) []string { return f.udfs }
+func (f *fakeSchema) Statistics(string) map[string]*vindexes.TableStatistics {
+	return f.stats
+}
 
 var _ SchemaInfo = (*fakeSchema)(nil)
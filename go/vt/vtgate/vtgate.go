@@ -73,6 +73,26 @@ var (
 	maxPayloadSize  int
 	warnPayloadSize int
 
+	// cteMaxRecursionDepth is the maximum number of iterations a recursive CTE can run at the vtgate level
+	cteMaxRecursionDepth = 1000
+
+	// queryMemoryBudget is the number of bytes of intermediate results a query can keep in memory before
+	// spilling them to spillDir. When it is 0, nothing is spilled and max_memory_rows applies.
+	queryMemoryBudget int64
+	spillDir          string
+
+	// enableQueryConsolidator merges the executions of the identical read-only queries running at the same time.
+	enableQueryConsolidator bool
+
+	// resultCacheMemory is the number of bytes of select results vtgate can cache. When it is 0, nothing is cached.
+	resultCacheMemory int64
+
+	// planWarmupFile is the file in which the most frequently executed queries are persisted to be planned
+	// ahead of their execution. When it is empty, no plan is warmed up.
+	planWarmupFile     string
+	planWarmupQueries  = 1000
+	planWarmupInterval = time.Minute
+
 	noScatter          bool
 	enableShardRouting bool
 
@@ -100,6 +120,9 @@ var (
 	enableSchemaChangeSignal = true
 	enableViews              bool
 	enableUdfs               bool
+	enableTableStatistics    bool
+	// tableStatisticsRefreshInterval is how often the tracked table statistics are refreshed
+	tableStatisticsRefreshInterval = 5 * time.Minute
 
 	// vtgate views flags
 	queryTimeout int
@@ -128,6 +151,14 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.Int64Var(&queryPlanCacheMemory, "gate_query_cache_memory", queryPlanCacheMemory, "gate server query cache size in bytes, maximum amount of memory to be cached. vtgate analyzes every incoming query and generate a query plan, these plans are being cached in a lru cache. This config controls the capacity of the lru cache.")
 	fs.IntVar(&maxMemoryRows, "max_memory_rows", maxMemoryRows, "Maximum number of rows that will be held in memory for intermediate results as well as the final result.")
 	fs.IntVar(&warnMemoryRows, "warn_memory_rows", warnMemoryRows, "Warning threshold for in-memory results. A row count higher than this amount will cause the VtGateWarnings.ResultsExceeded counter to be incremented.")
+	fs.IntVar(&cteMaxRecursionDepth, "cte-max-recursion-depth", cteMaxRecursionDepth, "Default maximum number of iterations a recursive common table expression evaluated at the vtgate level can run before the query is aborted, used when the session does not set cte_max_recursion_depth.")
+	fs.Int64Var(&queryMemoryBudget, "query-memory-budget", queryMemoryBudget, "Maximum number of bytes of intermediate results that the sorts, hash joins, distincts and distinct aggregations of a query can keep in memory before spilling them to local disk. When 0, nothing is spilled and max_memory_rows applies.")
+	fs.BoolVar(&enableQueryConsolidator, "enable-query-consolidator", enableQueryConsolidator, "Merge the identical read-only queries running at the same time outside of transactions, so that only one of them is sent to the shards and the others wait for its result.")
+	fs.Int64Var(&resultCacheMemory, "result-cache-memory", resultCacheMemory, "Maximum number of bytes of select results cached by vtgate for the tables with result_cache set in the VSchema and the queries with the RESULT_CACHE comment directive. The cached results are invalidated by the row changes streamed from the primaries. When 0, nothing is cached.")
+	fs.StringVar(&planWarmupFile, "plan-cache-warmup-file", planWarmupFile, "File in which vtgate persists its most frequently executed queries, to plan them in the background when it starts, before reporting healthy, and when the VSchema changes. When empty, no plan is warmed up.")
+	fs.IntVar(&planWarmupQueries, "plan-cache-warmup-queries", planWarmupQueries, "Maximum number of queries persisted in --plan-cache-warmup-file.")
+	fs.DurationVar(&planWarmupInterval, "plan-cache-warmup-interval", planWarmupInterval, "How often the most frequently executed queries are persisted in --plan-cache-warmup-file.")
+	fs.StringVar(&spillDir, "spill-dir", spillDir, "Directory where intermediate results are spilled to disk when a query exceeds its --query-memory-budget. Defaults to the temporary directory of the system.")
 	fs.StringVar(&defaultDDLStrategy, "ddl_strategy", defaultDDLStrategy, "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	fs.StringVar(&dbDDLPlugin, "dbddl_plugin", dbDDLPlugin, "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
 	fs.BoolVar(&noScatter, "no_scatter", noScatter, "when set to true, the planner will fail instead of producing a plan that includes scatter queries")
@@ -150,6 +181,8 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&messageStreamGracePeriod, "message_stream_grace_period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
 	fs.BoolVar(&enableViews, "enable-views", enableViews, "Enable views support in vtgate.")
 	fs.BoolVar(&enableUdfs, "track-udfs", enableUdfs, "Track UDFs in vtgate.")
+	fs.BoolVar(&enableTableStatistics, "track-table-statistics", enableTableStatistics, "Track the row count and index cardinality of tables in vtgate, and use them to choose the join order and join algorithm of queries.")
+	fs.DurationVar(&tableStatisticsRefreshInterval, "table-statistics-refresh-interval", tableStatisticsRefreshInterval, "How often the table statistics tracked with --track-table-statistics are refreshed. Zero refreshes them only when the schema changes.")
 	fs.BoolVar(&allowKillStmt, "allow-kill-statement", allowKillStmt, "Allows the execution of kill statement")
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
@@ -309,7 +342,7 @@ func Init(
 	var si SchemaInfo // default nil
 	var st *vtschema.Tracker
 	if enableSchemaChangeSignal {
-		st = vtschema.NewTracker(gw.hc.Subscribe(), enableViews, enableUdfs, env.Parser())
+		st = vtschema.NewTracker(gw.hc.Subscribe(), enableViews, enableUdfs, enableTableStatistics, tableStatisticsRefreshInterval, env.Parser())
 		addKeyspacesToTracker(ctx, srvResolver, st, gw)
 		si = st
 	}
@@ -332,6 +365,37 @@ func Init(
 		warmingReadsPercent,
 	)
 
+	if enableQueryConsolidator {
+		executor.enableConsolidator()
+	}
+	if planWarmupFile != "" {
+		if err := executor.enablePlanWarmup(planWarmupFile, planWarmupQueries, planWarmupInterval); err != nil {
+			log.Fatalf("error initializing the plan cache warm-up: %v", err)
+		}
+	}
+	if resultCacheMemory > 0 {
+		rc := newResultCache(ctx, resultCacheMemory, vsm.VStream)
+		executor.enableResultCache(rc)
+		stats.NewGaugeFunc("ResultCacheLength", "Result cache length", func() int64 {
+			return int64(rc.store.Len())
+		})
+		stats.NewGaugeFunc("ResultCacheSize", "Result cache size", func() int64 {
+			return int64(rc.store.UsedCapacity())
+		})
+		stats.NewGaugeFunc("ResultCacheCapacity", "Result cache capacity", func() int64 {
+			return int64(rc.store.MaxCapacity())
+		})
+		stats.NewCounterFunc("ResultCacheEvictions", "Result cache evictions", func() int64 {
+			return rc.store.Metrics.Evicted()
+		})
+		stats.NewCounterFunc("ResultCacheHits", "Result cache hits", func() int64 {
+			return rc.store.Metrics.Hits()
+		})
+		stats.NewCounterFunc("ResultCacheMisses", "Result cache misses", func() int64 {
+			return rc.store.Metrics.Misses()
+		})
+	}
+
 	if err := executor.defaultQueryLogger(); err != nil {
 		log.Fatalf("error initializing query logger: %v", err)
 	}
@@ -440,6 +504,9 @@ func (vtg *VTGate) registerDebugHealthHandler() {
 // IsHealthy returns nil if server is healthy.
 // Otherwise, it returns an error indicating the reason.
 func (vtg *VTGate) IsHealthy() error {
+	if pw := vtg.executor.planWarmup; pw != nil && !pw.isWarmed() {
+		return vterrors.New(vtrpcpb.Code_UNAVAILABLE, "plan cache warm-up in progress")
+	}
 	return nil
 }
 